@@ -0,0 +1,11 @@
+// Package api holds the OpenAPI 3 specification of the HTTP API. The server serves it and
+// checks it against its routes and payload types at startup; pkg/apiclient is generated
+// from it.
+package api
+
+import _ "embed"
+
+// Spec is the OpenAPI document, in JSON
+//
+//go:embed openapi.json
+var Spec []byte
@@ -1,17 +1,29 @@
 package main
 
 import (
+	"context"
 	"fmt"
 	"log"
 	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
 
 	"github.com/gorilla/mux"
 	"github.com/rs/cors"
+	"hex_go/api"
+	"hex_go/internal/application/authorization"
 	"hex_go/internal/application/services"
+	"hex_go/internal/domain/entities"
+	"hex_go/internal/domain/ports"
 	"hex_go/internal/infrastructure/controllers"
 	"hex_go/internal/infrastructure/persistence"
 	"hex_go/pkg/config"
+	"hex_go/pkg/notifier"
 	"hex_go/pkg/rabbitmq"
+	"hex_go/pkg/report"
+	"hex_go/pkg/webhook"
 )
 
 func main() {
@@ -25,38 +37,202 @@ func main() {
 	}
 	defer db.Close()
 
+	// Load the access policy
+	policy, err := authorization.LoadPolicy(cfg.PolicyFile)
+	if err != nil {
+		log.Fatalf("Failed to load access policy: %v", err)
+	}
+
 	// Initialize repository
-	repository := persistence.NewMySQLRepository(db)
+	repository := persistence.NewMySQLRepository(db, cfg.AlertFetchConcurrency)
+	deviceRepository := persistence.NewMySQLDeviceRepository(db)
+	alertRepository := persistence.NewMySQLAlertRepository(db)
+	locationRepository := persistence.NewMySQLLocationRepository(db)
+	organizationRepository := persistence.NewMySQLOrganizationRepository(db)
+	apiKeyRepository := persistence.NewMySQLAPIKeyRepository(db)
+	auditRepository := persistence.NewMySQLAuditRepository(db)
+	webhookRepository := persistence.NewMySQLWebhookRepository(db)
+	notificationRepository := persistence.NewMySQLNotificationRepository(db)
+	escalationRepository := persistence.NewMySQLEscalationRepository(db)
+	incidentRepository := persistence.NewMySQLIncidentRepository(db)
+	maintenanceRepository := persistence.NewMySQLMaintenanceRepository(db)
+	calibrationRepository := persistence.NewMySQLCalibrationRepository(db)
+	anomalyRepository := persistence.NewMySQLAnomalyRepository(db)
+	aggregateRepository := persistence.NewMySQLAggregateRepository(db)
+	reportRepository := persistence.NewMySQLReportRepository(db)
 
-	// Initialize RabbitMQ client
+	// Initialize RabbitMQ client. The port stays a nil interface when RabbitMQ is
+	// unavailable so the services can tell that publishing is disabled.
+	var messageQueue ports.MessageQueuePort
 	rabbitClient, err := rabbitmq.NewRabbitMQClient(cfg)
 	if err != nil {
 		log.Printf("Warning: Failed to connect to RabbitMQ: %v", err)
 		log.Printf("Continuing without RabbitMQ integration")
-		rabbitClient = nil
 	} else {
 		defer rabbitClient.Close()
+		messageQueue = rabbitClient
+	}
+
+	// Initialize notification channels. A channel without configuration is left out and
+	// its recipients are skipped.
+	var notifiers []ports.NotifierPort
+	var reportMailer ports.ReportMailerPort
+	if cfg.SMTPHost != "" {
+		smtpNotifier, err := notifier.NewSMTPNotifier(notifier.SMTPConfig{
+			Host:     cfg.SMTPHost,
+			Port:     cfg.SMTPPort,
+			User:     cfg.SMTPUser,
+			Password: cfg.SMTPPassword,
+			From:     cfg.SMTPFrom,
+			Timeout:  cfg.SMTPTimeout,
+		})
+		if err != nil {
+			log.Fatalf("Failed to configure email notifications: %v", err)
+		}
+		notifiers = append(notifiers, smtpNotifier)
+		reportMailer = smtpNotifier
+	} else {
+		log.Printf("SMTP_HOST not set, email notifications disabled")
+	}
+	if cfg.SMSGatewayURL != "" {
+		notifiers = append(notifiers, notifier.NewSMSNotifier(notifier.SMSConfig{
+			URL:     cfg.SMSGatewayURL,
+			Token:   cfg.SMSGatewayToken,
+			From:    cfg.SMSFrom,
+			Timeout: cfg.NotifierTimeout,
+		}))
+	}
+	if cfg.TelegramBotToken != "" {
+		notifiers = append(notifiers, notifier.NewTelegramNotifier(notifier.TelegramConfig{
+			APIURL:  cfg.TelegramAPIURL,
+			Token:   cfg.TelegramBotToken,
+			Timeout: cfg.NotifierTimeout,
+		}))
+	}
+	if cfg.FCMCredentialsFile != "" {
+		fcmNotifier, err := notifier.NewFCMNotifier(notifier.FCMConfig{
+			CredentialsFile: cfg.FCMCredentialsFile,
+			URL:             cfg.FCMURL,
+			TokenURL:        cfg.FCMTokenURL,
+			Timeout:         cfg.NotifierTimeout,
+		})
+		if err != nil {
+			log.Fatalf("Failed to configure push notifications: %v", err)
+		}
+		notifiers = append(notifiers, fcmNotifier)
 	}
 
 	// Initialize service
-	sensorService := services.NewSensorService(repository, rabbitClient)
+	auditService := services.NewAuditService(auditRepository)
+	webhookDispatcher := services.NewWebhookDispatcher(webhookRepository, webhook.NewClient(cfg.WebhookTimeout, cfg.WebhookAllowPrivate), auditService,
+		services.WebhookDeliveryPolicy{
+			MaxAttempts:  cfg.WebhookMaxAttempts,
+			RetryBase:    cfg.WebhookRetryBase,
+			DisableAfter: cfg.WebhookDisableAfter,
+			PollInterval: cfg.WebhookPollInterval,
+		})
+	webhookService := services.NewWebhookService(webhookRepository, deviceRepository, locationRepository, webhookDispatcher, auditService)
+	notificationService := services.NewNotificationService(notificationRepository, deviceRepository, locationRepository, auditService,
+		cfg.DigestPollInterval, notifiers...)
+	escalationService := services.NewEscalationService(escalationRepository, alertRepository, deviceRepository, locationRepository,
+		notificationRepository, auditService, cfg.EscalationPollInterval, notifiers...)
+	alertDebounce := entities.AlertDebounce{
+		Ventana:           cfg.AlertDedupWindow,
+		VentanaOscilacion: cfg.AlertFlapWindow,
+		UmbralEntrada:     cfg.AlertFlapEnter,
+		UmbralSalida:      cfg.AlertFlapExit,
+	}
+	if alertDebounce.UmbralSalida > alertDebounce.UmbralEntrada {
+		log.Fatalf("ALERT_FLAP_EXIT (%d) must not be above ALERT_FLAP_ENTER (%d)", cfg.AlertFlapExit, cfg.AlertFlapEnter)
+	}
+	incidentService := services.NewIncidentService(incidentRepository, deviceRepository, auditService, cfg.IncidentWindow,
+		notificationService, escalationService)
+	// The incident goes first so webhooks see the incident of the alert
+	alertService := services.NewAlertService(alertRepository, maintenanceRepository, messageQueue, auditService, alertDebounce,
+		incidentService, webhookService)
+	if cfg.CalibrationDrift < 0 || cfg.CalibrationDrift >= 1 {
+		log.Fatalf("CALIBRATION_DRIFT (%g) must be at least 0 and below 1", cfg.CalibrationDrift)
+	}
+	calibrationService := services.NewCalibrationService(calibrationRepository, deviceRepository, auditService,
+		cfg.CalibrationBurnIn, cfg.CalibrationDrift, map[string]float64{
+			entities.SensorTypeMQ2:   cfg.MQ2AlarmPPM,
+			entities.SensorTypeMQ135: cfg.MQ135AlarmPPM,
+		})
+	if cfg.AnomalyAlpha < 0 || cfg.AnomalyAlpha >= 1 {
+		log.Fatalf("ANOMALY_ALPHA (%g) must be at least 0 and below 1", cfg.AnomalyAlpha)
+	}
+	anomalyService := services.NewAnomalyService(anomalyRepository, alertService, entities.AnomalyDetector{
+		Alfa:          cfg.AnomalyAlpha,
+		UmbralZ:       cfg.AnomalyZScore,
+		Calentamiento: cfg.AnomalyWarmup,
+		TasaSubida:    cfg.AnomalyRiseRate,
+		VentanaSubida: cfg.AnomalyRiseWindow,
+	}, cfg.AnomalySnapshotInterval)
+	sensorService := services.NewSensorService(repository, deviceRepository, alertService, calibrationService, anomalyService,
+		auditService, messageQueue)
+	deviceService := services.NewDeviceService(deviceRepository, auditService)
+	locationService := services.NewLocationService(locationRepository, deviceRepository, auditService)
+	organizationService := services.NewOrganizationService(organizationRepository, deviceRepository, auditService)
+	maintenanceService := services.NewMaintenanceService(maintenanceRepository, deviceRepository, locationRepository, auditService)
+	aggregateService := services.NewAggregateService(aggregateRepository, cfg.AggregateRawMaxRange, cfg.AggregateRollupDelay,
+		cfg.AggregateRollupInterval)
+	reportService := services.NewReportService(reportRepository, locationRepository, notificationRepository, report.NewPDFRenderer(),
+		reportMailer, cfg.ReportPollInterval)
+	apiKeyService := services.NewAPIKeyService(apiKeyRepository, organizationRepository, auditService)
+
+	// Start background workers
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	deviceMonitor := services.NewDeviceMonitor(deviceRepository, alertService, auditService, cfg.DeviceOfflineAfter, cfg.DeviceSweepInterval)
+	go deviceMonitor.Run(ctx)
+	go webhookDispatcher.Run(ctx)
+	go escalationService.Run(ctx)
+	go notificationService.Run(ctx)
+	go anomalyService.Run(ctx)
+	go aggregateService.Run(ctx)
+	go reportService.Run(ctx)
 
-	// Initialize controller
-	sensorController := controllers.NewSensorController(sensorService)
+	// Initialize controllers
+	routes := &routeControllers{
+		sensor:       controllers.NewSensorController(sensorService),
+		device:       controllers.NewDeviceController(deviceService),
+		location:     controllers.NewLocationController(locationService),
+		organization: controllers.NewOrganizationController(organizationService),
+		apiKey:       controllers.NewAPIKeyController(apiKeyService),
+		audit:        controllers.NewAuditController(auditService),
+		webhook:      controllers.NewWebhookController(webhookService),
+		notification: controllers.NewNotificationController(notificationService),
+		alert:        controllers.NewAlertController(alertService),
+		escalation:   controllers.NewEscalationController(escalationService),
+		incident:     controllers.NewIncidentController(incidentService),
+		maintenance:  controllers.NewMaintenanceController(maintenanceService),
+		calibration:  controllers.NewCalibrationController(calibrationService),
+		aggregate:    controllers.NewAggregateController(aggregateService),
+		report:       controllers.NewReportController(reportService),
+		openAPI:      controllers.NewOpenAPIController(api.Spec),
+	}
 
 	// Set up router
 	router := mux.NewRouter()
 
+	// Tag every request with an ID and the client IP for the audit log
+	requestInfoMiddleware := controllers.NewRequestInfoMiddleware(cfg.TrustProxyHeaders)
+	router.Use(requestInfoMiddleware.Middleware)
+
+	// Every route is named so the access policy can refer to it
+	authMiddleware := controllers.NewAuthMiddleware(policy, apiKeyService, deviceService, cfg.LegacyUserQuery)
+	router.Use(authMiddleware.Middleware)
+
 	// Define routes
-	router.HandleFunc("/api/sensors", sensorController.CreateSensorData).Methods("POST")
-	router.HandleFunc("/api/alerts", sensorController.GetUserAlerts).Methods("GET")
+	registerRoutes(router, routes)
 
 	// Set up CORS middleware
 	c := cors.New(cors.Options{
 		AllowedOrigins:   []string{"*"}, // Allow all origins
 		AllowedMethods:   []string{"GET", "POST", "PUT", "DELETE", "OPTIONS"},
-		AllowedHeaders:   []string{"Accept", "Authorization", "Content-Type", "X-CSRF-Token"},
-		ExposedHeaders:   []string{"Link"},
+		AllowedHeaders:   []string{"Accept", "Authorization", "Content-Type", "X-CSRF-Token", "X-User-ID", "X-Device-Serial", "X-Device-Token", "X-API-Key", "X-Request-ID"},
+		ExposedHeaders:   []string{"Link", "X-Request-ID"},
 		AllowCredentials: true,
 		MaxAge:           300, // Maximum value not ignored by any of major browsers
 	})
@@ -66,6 +242,28 @@ func main() {
 
 	// Start server
 	serverAddr := fmt.Sprintf(":%s", cfg.ServerPort)
+	server := &http.Server{Addr: serverAddr, Handler: handler}
+
+	// Shut the server down when a termination signal cancels the context
+	go func() {
+		<-ctx.Done()
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+		if err := server.Shutdown(shutdownCtx); err != nil {
+			log.Printf("Error shutting down server: %v", err)
+		}
+	}()
+
 	log.Printf("Server starting on %s", serverAddr)
-	log.Fatal(http.ListenAndServe(serverAddr, handler))
+	if err := server.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+		log.Fatal(err)
+	}
+	log.Printf("Server stopped")
+
+	// Keep what the anomaly detector learned since its last snapshot
+	snapshotCtx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	if err := anomalyService.Snapshot(snapshotCtx); err != nil {
+		log.Printf("Error storing anomaly detector state: %v", err)
+	}
 }
\ No newline at end of file
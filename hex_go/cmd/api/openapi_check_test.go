@@ -0,0 +1,356 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/gorilla/mux"
+
+	"hex_go/internal/domain/entities"
+	"hex_go/internal/infrastructure/controllers"
+)
+
+// openAPITypes are the Go types the schemas of the specification describe, by the name
+// their x-go-type gives
+var openAPITypes = typesByName(
+	entities.SensorDataRequest{}, entities.Alert{}, entities.AlertGroup{}, entities.Incident{}, entities.IncidentEvent{},
+	entities.Device{}, entities.ClaimedDevice{}, entities.ClaimDeviceRequest{}, entities.UpdateDeviceRequest{}, entities.TransferDeviceRequest{},
+	entities.HeartbeatRequest{}, entities.AggregateSeries{}, entities.AggregateBucket{}, entities.AggregateStats{},
+	entities.SensorCalibration{}, entities.CalibrationRequest{}, entities.Location{}, entities.CreateLocationRequest{},
+	entities.UpdateLocationRequest{}, entities.AssignLocationRequest{}, entities.LocationStatus{}, entities.DeviceStatus{},
+	entities.Organization{}, entities.CreateOrganizationRequest{}, entities.Membership{}, entities.UpdateMemberRequest{},
+	entities.Invitation{}, entities.InviteMemberRequest{}, entities.SharedDevice{}, entities.ShareDeviceRequest{},
+	entities.AccessAuditEntry{}, entities.APIKey{}, entities.IssuedAPIKey{}, entities.CreateAPIKeyRequest{},
+	entities.Webhook{}, entities.CreatedWebhook{}, entities.CreateWebhookRequest{}, entities.UpdateWebhookRequest{},
+	entities.WebhookDelivery{}, entities.NotificationRecipient{}, entities.CreateRecipientRequest{},
+	entities.NotificationChannelSettings{}, entities.NotificationDelivery{}, entities.NotificationSettings{},
+	entities.QuietHours{}, entities.EscalationPolicy{}, entities.EscalationPolicyRequest{}, entities.EscalationStep{},
+	entities.Escalation{}, entities.MaintenanceWindow{}, entities.MaintenanceWindowRequest{}, entities.AuditEntry{},
+	entities.AuditVerification{}, entities.ValidationError{}, controllers.Problem{},
+)
+
+// openAPIEnums are the value lists the enums of the specification copy, by the name
+// their x-go-enum gives
+var openAPIEnums = map[string][]string{
+	"entities.AggregateBuckets":      entities.AggregateBuckets,
+	"entities.AlertTypes":            entities.AlertTypes,
+	"entities.APIKeyScopes":          entities.APIKeyScopes,
+	"entities.DigestModes":           entities.DigestModes,
+	"entities.ExportFormats":         entities.ExportFormats,
+	"entities.IncidentStates":        entities.IncidentStates,
+	"entities.LocationLevels":        entities.LocationLevels,
+	"entities.NotificationChannels":  entities.NotificationChannels,
+	"entities.NotificationLanguages": entities.NotificationLanguages,
+	"entities.OrganizationRoles":     entities.OrganizationRoles,
+	"entities.SensorTypes":           entities.SensorTypes,
+	"entities.Severities":            entities.Severities,
+}
+
+// openAPIDocument is the part of an OpenAPI document the check reads
+type openAPIDocument struct {
+	Paths      map[string]map[string]*openAPIOperation `json:"paths"`
+	Components struct {
+		Parameters map[string]*openAPIParameter `json:"parameters"`
+		Schemas    map[string]*openAPISchema    `json:"schemas"`
+	} `json:"components"`
+}
+
+type openAPIOperation struct {
+	RouteName  string              `json:"x-route-name"`
+	Parameters []*openAPIParameter `json:"parameters"`
+}
+
+type openAPIParameter struct {
+	Ref    string         `json:"$ref"`
+	Name   string         `json:"name"`
+	In     string         `json:"in"`
+	Schema *openAPISchema `json:"schema"`
+}
+
+type openAPISchema struct {
+	Ref                  string                    `json:"$ref"`
+	Type                 string                    `json:"type"`
+	Properties           map[string]*openAPISchema `json:"properties"`
+	Items                *openAPISchema            `json:"items"`
+	AdditionalProperties *openAPISchema            `json:"additionalProperties"`
+	AllOf                []*openAPISchema          `json:"allOf"`
+	Enum                 []string                  `json:"enum"`
+	GoType               string                    `json:"x-go-type"`
+	GoEnum               string                    `json:"x-go-enum"`
+}
+
+// routeVariable matches the variables of a path template once their patterns are removed
+var routeVariable = regexp.MustCompile(`{(\w+)}`)
+
+// validateOpenAPI checks that the specification describes the API the router serves:
+// every named route is an operation of the same path, method and x-route-name and every
+// operation a route; path parameters match the route variables; the schemas list the
+// JSON fields of the Go types named by their x-go-type with compatible types; and enums
+// hold the values of the Go lists named by their x-go-enum.
+func validateOpenAPI(spec []byte, router *mux.Router) error {
+	var doc openAPIDocument
+	if err := json.Unmarshal(spec, &doc); err != nil {
+		return fmt.Errorf("error parsing OpenAPI spec: %w", err)
+	}
+
+	var problems []string
+	seen := make(map[*openAPIOperation]bool)
+	err := router.Walk(func(route *mux.Route, router *mux.Router, ancestors []*mux.Route) error {
+		name := route.GetName()
+		template, err := route.GetPathTemplate()
+		if err != nil || name == "" {
+			return nil
+		}
+		methods, err := route.GetMethods()
+		if err != nil {
+			problems = append(problems, fmt.Sprintf("route %s has no methods", name))
+			return nil
+		}
+
+		path := stripRoutePatterns(template)
+		for _, method := range methods {
+			operation := doc.Paths[path][strings.ToLower(method)]
+			if operation == nil {
+				problems = append(problems, fmt.Sprintf("route %s (%s %s) is missing from the spec", name, method, path))
+				continue
+			}
+			seen[operation] = true
+			if operation.RouteName != name {
+				problems = append(problems, fmt.Sprintf("%s %s has x-route-name %q, the route is named %s", method, path, operation.RouteName, name))
+			}
+			problems = append(problems, checkPathParameters(&doc, method, path, operation)...)
+		}
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("error walking routes: %w", err)
+	}
+
+	for _, path := range sortedKeys(doc.Paths) {
+		for _, method := range sortedKeys(doc.Paths[path]) {
+			if operation := doc.Paths[path][method]; !seen[operation] {
+				problems = append(problems, fmt.Sprintf("%s %s has no route", strings.ToUpper(method), path))
+			}
+		}
+	}
+
+	for _, name := range sortedKeys(doc.Components.Schemas) {
+		schema := doc.Components.Schemas[name]
+		if schema.GoType != "" {
+			goType, ok := openAPITypes[schema.GoType]
+			if !ok {
+				problems = append(problems, fmt.Sprintf("schema %s names unknown type %s", name, schema.GoType))
+			} else {
+				problems = append(problems, checkSchemaFields(&doc, name, schema, goType)...)
+			}
+		}
+		problems = append(problems, checkEnums("schema "+name, schema)...)
+	}
+	for _, name := range sortedKeys(doc.Components.Parameters) {
+		problems = append(problems, checkEnums("parameter "+name, doc.Components.Parameters[name].Schema)...)
+	}
+	for _, path := range sortedKeys(doc.Paths) {
+		for _, method := range sortedKeys(doc.Paths[path]) {
+			for _, parameter := range doc.Paths[path][method].Parameters {
+				where := fmt.Sprintf("parameter %s of %s %s", parameter.Name, strings.ToUpper(method), path)
+				problems = append(problems, checkEnums(where, parameter.Schema)...)
+			}
+		}
+	}
+
+	if len(problems) > 0 {
+		return fmt.Errorf("OpenAPI spec does not match the API:\n\t%s", strings.Join(problems, "\n\t"))
+	}
+	return nil
+}
+
+// stripRoutePatterns removes the patterns of the variables of a mux path template, which
+// may hold braces themselves, such as {month:[0-9]{4}-[0-9]{2}}
+func stripRoutePatterns(template string) string {
+	var b strings.Builder
+	depth := 0
+	skipping := false
+	for _, c := range template {
+		switch {
+		case c == '{':
+			depth++
+		case c == '}':
+			depth--
+			if depth == 0 {
+				skipping = false
+			}
+		case c == ':' && depth == 1:
+			skipping = true
+		}
+		if !skipping || (c == '}' && depth == 0) {
+			b.WriteRune(c)
+		}
+	}
+	return b.String()
+}
+
+// checkPathParameters compares the path parameters of an operation with the variables of its path
+func checkPathParameters(doc *openAPIDocument, method, path string, operation *openAPIOperation) []string {
+	var declared []string
+	for _, parameter := range operation.Parameters {
+		if parameter.Ref != "" {
+			parameter = doc.Components.Parameters[strings.TrimPrefix(parameter.Ref, "#/components/parameters/")]
+			if parameter == nil {
+				return []string{fmt.Sprintf("%s %s refers to a missing parameter", method, path)}
+			}
+		}
+		if parameter.In == "path" {
+			declared = append(declared, parameter.Name)
+		}
+	}
+
+	var variables []string
+	for _, match := range routeVariable.FindAllStringSubmatch(path, -1) {
+		variables = append(variables, match[1])
+	}
+	sort.Strings(declared)
+	sort.Strings(variables)
+	if strings.Join(declared, ",") != strings.Join(variables, ",") {
+		return []string{fmt.Sprintf("%s %s declares path parameters %v, the route has %v", method, path, declared, variables)}
+	}
+	return nil
+}
+
+// checkSchemaFields compares the properties of a schema with the JSON fields of a struct
+func checkSchemaFields(doc *openAPIDocument, name string, schema *openAPISchema, goType reflect.Type) []string {
+	var problems []string
+	fields := jsonFields(goType)
+	for _, field := range sortedKeys(fields) {
+		property, ok := schema.Properties[field]
+		if !ok {
+			problems = append(problems, fmt.Sprintf("schema %s lacks field %s of %s", name, field, goType))
+			continue
+		}
+		if !compatible(doc, property, fields[field]) {
+			problems = append(problems, fmt.Sprintf("schema %s gives field %s a type that does not fit %s", name, field, fields[field]))
+		}
+	}
+	for _, property := range sortedKeys(schema.Properties) {
+		if _, ok := fields[property]; !ok {
+			problems = append(problems, fmt.Sprintf("schema %s has property %s that %s lacks", name, property, goType))
+		}
+	}
+	return problems
+}
+
+// compatible reports whether values of the Go type are encoded as the schema describes
+func compatible(doc *openAPIDocument, schema *openAPISchema, t reflect.Type) bool {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	if len(schema.AllOf) == 1 {
+		schema = schema.AllOf[0]
+	}
+	if schema.Ref != "" {
+		target := doc.Components.Schemas[strings.TrimPrefix(schema.Ref, "#/components/schemas/")]
+		return target != nil && openAPITypes[target.GoType] == t
+	}
+
+	switch schema.Type {
+	case "":
+		return true
+	case "string":
+		return t.Kind() == reflect.String || t == reflect.TypeOf(time.Time{})
+	case "integer":
+		switch t.Kind() {
+		case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+			return t != reflect.TypeOf(time.Duration(0))
+		}
+		return false
+	case "number":
+		return t.Kind() == reflect.Float32 || t.Kind() == reflect.Float64
+	case "boolean":
+		return t.Kind() == reflect.Bool
+	case "array":
+		return t.Kind() == reflect.Slice && t != reflect.TypeOf(json.RawMessage{}) && schema.Items != nil &&
+			compatible(doc, schema.Items, t.Elem())
+	case "object":
+		if schema.AdditionalProperties != nil {
+			return t.Kind() == reflect.Map && compatible(doc, schema.AdditionalProperties, t.Elem())
+		}
+		return t.Kind() == reflect.Struct || t.Kind() == reflect.Map
+	}
+	return false
+}
+
+// checkEnums compares every enum within a schema with the Go list it copies
+func checkEnums(where string, schema *openAPISchema) []string {
+	if schema == nil {
+		return nil
+	}
+
+	var problems []string
+	if schema.GoEnum != "" {
+		values, ok := openAPIEnums[schema.GoEnum]
+		switch {
+		case !ok:
+			problems = append(problems, fmt.Sprintf("%s names unknown enum %s", where, schema.GoEnum))
+		case strings.Join(values, ",") != strings.Join(schema.Enum, ","):
+			problems = append(problems, fmt.Sprintf("%s lists %v, %s holds %v", where, schema.Enum, schema.GoEnum, values))
+		}
+	}
+	for _, property := range sortedKeys(schema.Properties) {
+		problems = append(problems, checkEnums(where+"."+property, schema.Properties[property])...)
+	}
+	problems = append(problems, checkEnums(where+"[]", schema.Items)...)
+	problems = append(problems, checkEnums(where+"{}", schema.AdditionalProperties)...)
+	return problems
+}
+
+// jsonFields returns the types of the fields of a struct by their JSON name, including
+// those of embedded structs
+func jsonFields(t reflect.Type) map[string]reflect.Type {
+	fields := make(map[string]reflect.Type)
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		tag := field.Tag.Get("json")
+		if field.Anonymous && tag == "" {
+			embedded := field.Type
+			if embedded.Kind() == reflect.Ptr {
+				embedded = embedded.Elem()
+			}
+			for name, fieldType := range jsonFields(embedded) {
+				fields[name] = fieldType
+			}
+			continue
+		}
+		if !field.IsExported() || tag == "-" {
+			continue
+		}
+		name := strings.Split(tag, ",")[0]
+		if name == "" {
+			name = field.Name
+		}
+		fields[name] = field.Type
+	}
+	return fields
+}
+
+// typesByName indexes the types of values by their package and type name, such as "entities.Alert"
+func typesByName(values ...interface{}) map[string]reflect.Type {
+	types := make(map[string]reflect.Type, len(values))
+	for _, value := range values {
+		t := reflect.TypeOf(value)
+		types[t.String()] = t
+	}
+	return types
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
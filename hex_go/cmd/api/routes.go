@@ -0,0 +1,108 @@
+package main
+
+import (
+	"github.com/gorilla/mux"
+	"hex_go/internal/infrastructure/controllers"
+)
+
+// routeControllers holds the controllers serving the routes of the API
+type routeControllers struct {
+	sensor       *controllers.SensorController
+	device       *controllers.DeviceController
+	location     *controllers.LocationController
+	organization *controllers.OrganizationController
+	apiKey       *controllers.APIKeyController
+	audit        *controllers.AuditController
+	webhook      *controllers.WebhookController
+	notification *controllers.NotificationController
+	alert        *controllers.AlertController
+	escalation   *controllers.EscalationController
+	incident     *controllers.IncidentController
+	maintenance  *controllers.MaintenanceController
+	calibration  *controllers.CalibrationController
+	aggregate    *controllers.AggregateController
+	report       *controllers.ReportController
+	openAPI      *controllers.OpenAPIController
+}
+
+// registerRoutes defines the routes of the API. Every route is named so the access policy
+// and the OpenAPI spec can refer to it.
+func registerRoutes(router *mux.Router, c *routeControllers) {
+	router.HandleFunc("/api/sensors", c.sensor.CreateSensorData).Methods("POST").Name("sensors.create")
+	router.HandleFunc("/api/alerts", c.sensor.GetUserAlerts).Methods("GET").Name("alerts.list")
+	router.HandleFunc("/api/alerts/export", c.alert.ExportAlerts).Methods("GET").Name("alerts.export")
+	router.HandleFunc("/api/alerts/{id}/acknowledge", c.alert.AcknowledgeAlert).Methods("POST").Name("alerts.acknowledge")
+	router.HandleFunc("/api/alerts/{id}/escalations", c.escalation.ListAlertEscalations).Methods("GET").Name("alerts.escalations")
+	router.HandleFunc("/api/incidents", c.incident.ListIncidents).Methods("GET").Name("incidents.list")
+	router.HandleFunc("/api/incidents/{id}", c.incident.GetIncident).Methods("GET").Name("incidents.get")
+	router.HandleFunc("/api/incidents/{id}/acknowledge", c.incident.AcknowledgeIncident).Methods("POST").Name("incidents.acknowledge")
+	router.HandleFunc("/api/incidents/{id}/resolve", c.incident.ResolveIncident).Methods("POST").Name("incidents.resolve")
+	router.HandleFunc("/api/devices", c.device.ListDevices).Methods("GET").Name("devices.list")
+	router.HandleFunc("/api/devices", c.device.ClaimDevice).Methods("POST").Name("devices.claim")
+	router.HandleFunc("/api/devices/{numeroSerie}", c.device.GetDevice).Methods("GET").Name("devices.get")
+	router.HandleFunc("/api/devices/{numeroSerie}", c.device.UpdateDevice).Methods("PUT").Name("devices.update")
+	router.HandleFunc("/api/devices/{numeroSerie}", c.device.DeleteDevice).Methods("DELETE").Name("devices.delete")
+	router.HandleFunc("/api/devices/{numeroSerie}/transfer", c.device.TransferDevice).Methods("POST").Name("devices.transfer")
+	router.HandleFunc("/api/devices/{numeroSerie}/token", c.device.RotateDeviceToken).Methods("POST").Name("devices.token")
+	router.HandleFunc("/api/devices/{numeroSerie}/heartbeat", c.device.RecordHeartbeat).Methods("POST").Name("devices.heartbeat")
+	router.HandleFunc("/api/devices/{numeroSerie}/sensors/{type}/aggregates", c.aggregate.GetAggregates).Methods("GET").Name("devices.aggregates")
+	router.HandleFunc("/api/devices/{numeroSerie}/calibration", c.calibration.ListCalibrations).Methods("GET").Name("calibration.list")
+	router.HandleFunc("/api/devices/{numeroSerie}/calibration/{sensor}", c.calibration.UpdateCalibration).Methods("PUT").Name("calibration.update")
+	router.HandleFunc("/api/devices/{numeroSerie}/calibration/{sensor}/reset", c.calibration.ResetCalibration).Methods("POST").Name("calibration.reset")
+	router.HandleFunc("/api/devices/{numeroSerie}/location", c.location.AssignDevice).Methods("PUT").Name("devices.location")
+	router.HandleFunc("/api/locations", c.location.ListLocations).Methods("GET").Name("locations.list")
+	router.HandleFunc("/api/locations", c.location.CreateLocation).Methods("POST").Name("locations.create")
+	router.HandleFunc("/api/locations/{id}", c.location.GetLocation).Methods("GET").Name("locations.get")
+	router.HandleFunc("/api/locations/{id}", c.location.UpdateLocation).Methods("PUT").Name("locations.update")
+	router.HandleFunc("/api/locations/{id}", c.location.DeleteLocation).Methods("DELETE").Name("locations.delete")
+	router.HandleFunc("/api/locations/{id}/status", c.location.GetLocationStatus).Methods("GET").Name("locations.status")
+	router.HandleFunc("/api/reports/{site:[0-9]+}/{month:[0-9]{4}-[0-9]{2}}.pdf", c.report.GetReport).Methods("GET").Name("reports.get")
+	router.HandleFunc("/api/organizations", c.organization.ListOrganizations).Methods("GET").Name("organizations.list")
+	router.HandleFunc("/api/organizations", c.organization.CreateOrganization).Methods("POST").Name("organizations.create")
+	router.HandleFunc("/api/organizations/{id}/members", c.organization.ListMembers).Methods("GET").Name("organizations.members.list")
+	router.HandleFunc("/api/organizations/{id}/members/{memberId}", c.organization.UpdateMember).Methods("PUT").Name("organizations.members.update")
+	router.HandleFunc("/api/organizations/{id}/members/{memberId}", c.organization.RemoveMember).Methods("DELETE").Name("organizations.members.delete")
+	router.HandleFunc("/api/organizations/{id}/invitations", c.organization.ListOrganizationInvitations).Methods("GET").Name("organizations.invitations.list")
+	router.HandleFunc("/api/organizations/{id}/invitations", c.organization.InviteMember).Methods("POST").Name("organizations.invitations.create")
+	router.HandleFunc("/api/organizations/{id}/invitations/{invitationId}", c.organization.RevokeInvitation).Methods("DELETE").Name("organizations.invitations.delete")
+	router.HandleFunc("/api/organizations/{id}/devices", c.organization.ListSharedDevices).Methods("GET").Name("organizations.devices.list")
+	router.HandleFunc("/api/organizations/{id}/devices", c.organization.ShareDevice).Methods("POST").Name("organizations.devices.share")
+	router.HandleFunc("/api/organizations/{id}/devices/{numeroSerie}", c.organization.UnshareDevice).Methods("DELETE").Name("organizations.devices.unshare")
+	router.HandleFunc("/api/organizations/{id}/access-audit", c.organization.ListAccessAudit).Methods("GET").Name("organizations.audit")
+	router.HandleFunc("/api/organizations/{id}/api-keys", c.apiKey.ListAPIKeys).Methods("GET").Name("organizations.apikeys.list")
+	router.HandleFunc("/api/organizations/{id}/api-keys", c.apiKey.CreateAPIKey).Methods("POST").Name("organizations.apikeys.create")
+	router.HandleFunc("/api/organizations/{id}/api-keys/{keyId}", c.apiKey.RevokeAPIKey).Methods("DELETE").Name("organizations.apikeys.delete")
+	router.HandleFunc("/api/webhooks", c.webhook.ListWebhooks).Methods("GET").Name("webhooks.list")
+	router.HandleFunc("/api/webhooks", c.webhook.CreateWebhook).Methods("POST").Name("webhooks.create")
+	router.HandleFunc("/api/webhooks/{id}", c.webhook.GetWebhook).Methods("GET").Name("webhooks.get")
+	router.HandleFunc("/api/webhooks/{id}", c.webhook.UpdateWebhook).Methods("PUT").Name("webhooks.update")
+	router.HandleFunc("/api/webhooks/{id}", c.webhook.DeleteWebhook).Methods("DELETE").Name("webhooks.delete")
+	router.HandleFunc("/api/webhooks/{id}/test", c.webhook.SendTestEvent).Methods("POST").Name("webhooks.test")
+	router.HandleFunc("/api/webhooks/{id}/deliveries", c.webhook.ListDeliveries).Methods("GET").Name("webhooks.deliveries")
+	router.HandleFunc("/api/me/notification-recipients", c.notification.ListRecipients).Methods("GET").Name("notifications.recipients.list")
+	router.HandleFunc("/api/me/notification-recipients", c.notification.CreateRecipient).Methods("POST").Name("notifications.recipients.create")
+	router.HandleFunc("/api/me/notification-recipients/{id}", c.notification.DeleteRecipient).Methods("DELETE").Name("notifications.recipients.delete")
+	router.HandleFunc("/api/me/notification-channels", c.notification.GetChannelSettings).Methods("GET").Name("notifications.channels.get")
+	router.HandleFunc("/api/me/notification-channels", c.notification.UpdateChannelSettings).Methods("PUT").Name("notifications.channels.update")
+	router.HandleFunc("/api/me/notification-deliveries", c.notification.ListDeliveries).Methods("GET").Name("notifications.deliveries")
+	router.HandleFunc("/api/me/notification-settings", c.notification.GetSettings).Methods("GET").Name("notifications.settings.get")
+	router.HandleFunc("/api/me/notification-settings", c.notification.UpdateSettings).Methods("PUT").Name("notifications.settings.update")
+	router.HandleFunc("/api/escalation-policies", c.escalation.ListPolicies).Methods("GET").Name("escalations.list")
+	router.HandleFunc("/api/escalation-policies", c.escalation.CreatePolicy).Methods("POST").Name("escalations.create")
+	router.HandleFunc("/api/escalation-policies/{id}", c.escalation.GetPolicy).Methods("GET").Name("escalations.get")
+	router.HandleFunc("/api/escalation-policies/{id}", c.escalation.UpdatePolicy).Methods("PUT").Name("escalations.update")
+	router.HandleFunc("/api/escalation-policies/{id}", c.escalation.DeletePolicy).Methods("DELETE").Name("escalations.delete")
+	router.HandleFunc("/api/maintenance-windows", c.maintenance.ListWindows).Methods("GET").Name("maintenance.list")
+	router.HandleFunc("/api/maintenance-windows", c.maintenance.CreateWindow).Methods("POST").Name("maintenance.create")
+	router.HandleFunc("/api/maintenance-windows/{id}", c.maintenance.GetWindow).Methods("GET").Name("maintenance.get")
+	router.HandleFunc("/api/maintenance-windows/{id}", c.maintenance.UpdateWindow).Methods("PUT").Name("maintenance.update")
+	router.HandleFunc("/api/maintenance-windows/{id}", c.maintenance.DeleteWindow).Methods("DELETE").Name("maintenance.delete")
+	router.HandleFunc("/api/maintenance-windows/{id}/end", c.maintenance.EndWindow).Methods("POST").Name("maintenance.end")
+	router.HandleFunc("/api/audit", c.audit.ListEntries).Methods("GET").Name("audit.list")
+	router.HandleFunc("/api/audit/verify", c.audit.VerifyChain).Methods("GET").Name("audit.verify")
+	router.HandleFunc("/api/invitations", c.organization.ListMyInvitations).Methods("GET").Name("invitations.list")
+	router.HandleFunc("/api/invitations/{invitationId}/accept", c.organization.AcceptInvitation).Methods("POST").Name("invitations.accept")
+	router.HandleFunc("/api/invitations/{invitationId}/decline", c.organization.DeclineInvitation).Methods("POST").Name("invitations.decline")
+	router.HandleFunc("/api/openapi.json", c.openAPI.GetSpec).Methods("GET").Name("docs.spec")
+	router.HandleFunc("/api/docs", c.openAPI.GetDocs).Methods("GET").Name("docs.ui")
+}
@@ -0,0 +1,64 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/gorilla/mux"
+	"hex_go/api"
+	"hex_go/internal/application/authorization"
+	"hex_go/internal/infrastructure/controllers"
+)
+
+// newTestRouter registers the routes with controllers that have no services behind them,
+// enough to inspect the routes without serving them
+func newTestRouter() *mux.Router {
+	router := mux.NewRouter()
+	registerRoutes(router, &routeControllers{
+		sensor:       controllers.NewSensorController(nil),
+		device:       controllers.NewDeviceController(nil),
+		location:     controllers.NewLocationController(nil),
+		organization: controllers.NewOrganizationController(nil),
+		apiKey:       controllers.NewAPIKeyController(nil),
+		audit:        controllers.NewAuditController(nil),
+		webhook:      controllers.NewWebhookController(nil),
+		notification: controllers.NewNotificationController(nil),
+		alert:        controllers.NewAlertController(nil),
+		escalation:   controllers.NewEscalationController(nil),
+		incident:     controllers.NewIncidentController(nil),
+		maintenance:  controllers.NewMaintenanceController(nil),
+		calibration:  controllers.NewCalibrationController(nil),
+		aggregate:    controllers.NewAggregateController(nil),
+		report:       controllers.NewReportController(nil),
+		openAPI:      controllers.NewOpenAPIController(api.Spec),
+	})
+	return router
+}
+
+func TestOpenAPISpecDescribesRoutes(t *testing.T) {
+	if err := validateOpenAPI(api.Spec, newTestRouter()); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestPolicyCoversRoutes(t *testing.T) {
+	policy, err := authorization.LoadPolicy("")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = newTestRouter().Walk(func(route *mux.Route, router *mux.Router, ancestors []*mux.Route) error {
+		template, _ := route.GetPathTemplate()
+		name := route.GetName()
+		if name == "" {
+			t.Errorf("route %s has no name", template)
+			return nil
+		}
+		if _, ok := policy.RoutePermission(name); !ok {
+			t.Errorf("route %s (%s) has no permission in the default policy and would always be denied", name, template)
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+}
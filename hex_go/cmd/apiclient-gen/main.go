@@ -0,0 +1,605 @@
+// Command apiclient-gen writes the types and operations of pkg/apiclient from the
+// OpenAPI specification. It supports the subset of OpenAPI 3 the specification uses:
+// named object schemas, JSON bodies, path and query parameters, and JSON or file responses.
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"go/format"
+	"log"
+	"os"
+	"sort"
+	"strings"
+	"unicode"
+)
+
+// httpMethods are the operations of a path item, in the order they are written
+var httpMethods = []string{"get", "put", "post", "delete", "patch"}
+
+// initialisms are the words written in capitals in Go names
+var initialisms = map[string]bool{"api": true, "http": true, "id": true, "ip": true, "json": true, "ppm": true, "url": true}
+
+// keywordNames replace the parameter names that are Go keywords
+var keywordNames = map[string]string{"type": "typ", "func": "fn", "range": "rng"}
+
+type document struct {
+	Paths      orderedMap[*pathItem] `json:"paths"`
+	Components struct {
+		Parameters map[string]*parameter `json:"parameters"`
+		Schemas    orderedMap[*schema]   `json:"schemas"`
+	} `json:"components"`
+}
+
+type pathItem map[string]*operation
+
+type operation struct {
+	OperationID string               `json:"operationId"`
+	Summary     string               `json:"summary"`
+	Description string               `json:"description"`
+	Parameters  []*parameter         `json:"parameters"`
+	RequestBody *requestBody         `json:"requestBody"`
+	Responses   map[string]*response `json:"responses"`
+}
+
+type parameter struct {
+	Ref         string  `json:"$ref"`
+	Name        string  `json:"name"`
+	In          string  `json:"in"`
+	Description string  `json:"description"`
+	Schema      *schema `json:"schema"`
+}
+
+type requestBody struct {
+	Required bool                  `json:"required"`
+	Content  map[string]*mediaType `json:"content"`
+}
+
+type response struct {
+	Ref     string                `json:"$ref"`
+	Content map[string]*mediaType `json:"content"`
+}
+
+type mediaType struct {
+	Schema *schema `json:"schema"`
+}
+
+type schema struct {
+	Ref                  string              `json:"$ref"`
+	Type                 string              `json:"type"`
+	Format               string              `json:"format"`
+	Description          string              `json:"description"`
+	Nullable             bool                `json:"nullable"`
+	Required             []string            `json:"required"`
+	Properties           orderedMap[*schema] `json:"properties"`
+	Items                *schema             `json:"items"`
+	AdditionalProperties *schema             `json:"additionalProperties"`
+	AllOf                []*schema           `json:"allOf"`
+	Enum                 []string            `json:"enum"`
+}
+
+// orderedMap is a JSON object that keeps the order of its keys
+type orderedMap[V any] struct {
+	Keys   []string
+	Values map[string]V
+}
+
+func (m *orderedMap[V]) UnmarshalJSON(data []byte) error {
+	var values map[string]V
+	if err := json.Unmarshal(data, &values); err != nil {
+		return err
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	if _, err := decoder.Token(); err != nil {
+		return err
+	}
+	var keys []string
+	for decoder.More() {
+		key, err := decoder.Token()
+		if err != nil {
+			return err
+		}
+		keys = append(keys, key.(string))
+		var skip json.RawMessage
+		if err := decoder.Decode(&skip); err != nil {
+			return err
+		}
+	}
+	m.Keys, m.Values = keys, values
+	return nil
+}
+
+func main() {
+	specPath := flag.String("spec", "api/openapi.json", "OpenAPI specification to read")
+	outPath := flag.String("out", "pkg/apiclient/generated.go", "Go file to write")
+	pkg := flag.String("package", "apiclient", "package of the generated file")
+	flag.Parse()
+
+	data, err := os.ReadFile(*specPath)
+	if err != nil {
+		log.Fatalf("Failed to read spec: %v", err)
+	}
+	var doc document
+	if err := json.Unmarshal(data, &doc); err != nil {
+		log.Fatalf("Failed to parse spec: %v", err)
+	}
+
+	g := &generator{doc: &doc, imports: map[string]bool{}}
+	if err := g.generate(); err != nil {
+		log.Fatalf("Failed to generate client: %v", err)
+	}
+
+	var out bytes.Buffer
+	fmt.Fprintf(&out, "// Code generated by apiclient-gen from %s. DO NOT EDIT.\n\n", *specPath)
+	fmt.Fprintf(&out, "package %s\n\n", *pkg)
+	if len(g.imports) > 0 {
+		out.WriteString("import (\n")
+		for _, path := range sortedKeys(g.imports) {
+			fmt.Fprintf(&out, "\t%q\n", path)
+		}
+		out.WriteString(")\n\n")
+	}
+	out.Write(g.types.Bytes())
+	out.Write(g.operations.Bytes())
+
+	source, err := format.Source(out.Bytes())
+	if err != nil {
+		log.Fatalf("Failed to format generated client: %v\n%s", err, out.Bytes())
+	}
+	if err := os.WriteFile(*outPath, source, 0o644); err != nil {
+		log.Fatalf("Failed to write client: %v", err)
+	}
+}
+
+type generator struct {
+	doc        *document
+	imports    map[string]bool
+	types      bytes.Buffer
+	operations bytes.Buffer
+}
+
+func (g *generator) generate() error {
+	for _, name := range g.doc.Components.Schemas.Keys {
+		if err := g.writeType(name, g.doc.Components.Schemas.Values[name]); err != nil {
+			return fmt.Errorf("schema %s: %w", name, err)
+		}
+	}
+	for _, path := range g.doc.Paths.Keys {
+		item := g.doc.Paths.Values[path]
+		for _, method := range httpMethods {
+			if op, ok := (*item)[method]; ok {
+				if err := g.writeOperation(method, path, op); err != nil {
+					return fmt.Errorf("%s %s: %w", strings.ToUpper(method), path, err)
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// writeType writes the struct of a component schema
+func (g *generator) writeType(name string, s *schema) error {
+	if s.Type != "object" || s.Properties.Values == nil {
+		return fmt.Errorf("only object schemas with properties are supported")
+	}
+
+	writeComment(&g.types, typeComment(name, s.Description))
+	fmt.Fprintf(&g.types, "type %s struct {\n", name)
+	required := make(map[string]bool)
+	for _, property := range s.Required {
+		required[property] = true
+	}
+	for _, property := range s.Properties.Keys {
+		ps := s.Properties.Values[property]
+		goType, err := g.goType(ps)
+		if err != nil {
+			return fmt.Errorf("property %s: %w", property, err)
+		}
+		if comment := propertyComment(ps); comment != "" {
+			fmt.Fprintf(&g.types, "\t// %s\n", comment)
+		}
+		tag := property
+		if !required[property] {
+			tag += ",omitempty"
+		}
+		fmt.Fprintf(&g.types, "\t%s %s `json:%q`\n", goName(property), goType, tag)
+	}
+	g.types.WriteString("}\n\n")
+	return nil
+}
+
+// goType returns the Go type of a schema; references to object schemas are pointers
+// when nullable and in collections
+func (g *generator) goType(s *schema) (string, error) {
+	if len(s.AllOf) == 1 && s.AllOf[0].Ref != "" {
+		return "*" + refName(s.AllOf[0].Ref), nil
+	}
+	if s.Ref != "" {
+		return refName(s.Ref), nil
+	}
+
+	var goType string
+	switch s.Type {
+	case "":
+		return "interface{}", nil
+	case "string":
+		goType = "string"
+		if s.Format == "date-time" {
+			g.imports["time"] = true
+			goType = "time.Time"
+		}
+	case "integer":
+		goType = "int"
+		if s.Format == "int64" {
+			goType = "int64"
+		}
+	case "number":
+		goType = "float64"
+	case "boolean":
+		goType = "bool"
+	case "array":
+		if s.Items == nil {
+			return "", fmt.Errorf("array without items")
+		}
+		item, err := g.elementType(s.Items)
+		if err != nil {
+			return "", err
+		}
+		return "[]" + item, nil
+	case "object":
+		if s.Properties.Values != nil {
+			return "", fmt.Errorf("inline objects are not supported; name the schema")
+		}
+		if s.AdditionalProperties == nil {
+			return "map[string]interface{}", nil
+		}
+		value, err := g.elementType(s.AdditionalProperties)
+		if err != nil {
+			return "", err
+		}
+		return "map[string]" + value, nil
+	default:
+		return "", fmt.Errorf("unsupported type %q", s.Type)
+	}
+	if s.Nullable {
+		return "*" + goType, nil
+	}
+	return goType, nil
+}
+
+// elementType returns the type of the items of an array or values of a map
+func (g *generator) elementType(s *schema) (string, error) {
+	if s.Ref != "" {
+		return "*" + refName(s.Ref), nil
+	}
+	return g.goType(s)
+}
+
+// writeOperation writes the method of an operation, and the struct of its query parameters
+func (g *generator) writeOperation(method, path string, op *operation) error {
+	if op.OperationID == "" {
+		return fmt.Errorf("operation without operationId")
+	}
+	name := goName(op.OperationID)
+
+	var pathParams, queryParams []*parameter
+	for _, p := range op.Parameters {
+		if p.Ref != "" {
+			p = g.doc.Components.Parameters[strings.TrimPrefix(p.Ref, "#/components/parameters/")]
+			if p == nil {
+				return fmt.Errorf("missing parameter")
+			}
+		}
+		switch p.In {
+		case "path":
+			pathParams = append(pathParams, p)
+		case "query":
+			queryParams = append(queryParams, p)
+		}
+	}
+
+	args := []string{"ctx context.Context"}
+	g.imports["context"] = true
+	urlPath := path
+	var pathArgs []string
+	for _, p := range pathParams {
+		arg := goArgName(p.Name)
+		placeholder := "{" + p.Name + "}"
+		if !strings.Contains(urlPath, placeholder) {
+			return fmt.Errorf("path parameter %s is not in the path", p.Name)
+		}
+		if p.Schema != nil && p.Schema.Type == "integer" {
+			goType := "int"
+			if p.Schema.Format == "int64" {
+				goType = "int64"
+			}
+			args = append(args, arg+" "+goType)
+			urlPath = strings.Replace(urlPath, placeholder, "%d", 1)
+			pathArgs = append(pathArgs, arg)
+		} else {
+			args = append(args, arg+" string")
+			urlPath = strings.Replace(urlPath, placeholder, "%s", 1)
+			pathArgs = append(pathArgs, "url.PathEscape("+arg+")")
+			g.imports["net/url"] = true
+		}
+	}
+
+	paramsType := ""
+	if len(queryParams) > 0 {
+		paramsType = name + "Params"
+		if err := g.writeParams(paramsType, op.OperationID, queryParams); err != nil {
+			return err
+		}
+		args = append(args, "params *"+paramsType)
+	}
+
+	body := "nil"
+	if op.RequestBody != nil {
+		media := op.RequestBody.Content["application/json"]
+		if media == nil || media.Schema == nil || media.Schema.Ref == "" {
+			return fmt.Errorf("only JSON bodies of named schemas are supported")
+		}
+		args = append(args, "body *"+refName(media.Schema.Ref))
+		body = "body"
+	}
+
+	result, download, err := g.result(op)
+	if err != nil {
+		return err
+	}
+
+	w := &g.operations
+	writeComment(w, name+" "+sentence(op.Summary))
+	if op.Description != "" {
+		w.WriteString("//\n")
+		writeComment(w, op.Description)
+	}
+	if download {
+		w.WriteString("// The caller must close the returned body.\n")
+	}
+	returns := "error"
+	if result != "" {
+		returns = "(" + result + ", error)"
+	}
+	fmt.Fprintf(w, "func (c *Client) %s(%s) %s {\n", name, strings.Join(args, ", "), returns)
+
+	if len(pathArgs) > 0 {
+		g.imports["fmt"] = true
+		fmt.Fprintf(w, "\tpath := fmt.Sprintf(%q, %s)\n", urlPath, strings.Join(pathArgs, ", "))
+	} else {
+		fmt.Fprintf(w, "\tpath := %q\n", urlPath)
+	}
+	query := "nil"
+	if paramsType != "" {
+		query = "params.values()"
+	}
+
+	httpMethod := strings.ToUpper(method)
+	switch {
+	case download:
+		fmt.Fprintf(w, "\treturn c.download(ctx, %q, path, %s)\n", httpMethod, query)
+	case result == "":
+		fmt.Fprintf(w, "\treturn c.do(ctx, %q, path, %s, %s, nil)\n", httpMethod, query, body)
+	case strings.HasPrefix(result, "*"):
+		fmt.Fprintf(w, "\tout := new(%s)\n", strings.TrimPrefix(result, "*"))
+		fmt.Fprintf(w, "\tif err := c.do(ctx, %q, path, %s, %s, out); err != nil {\n\t\treturn nil, err\n\t}\n", httpMethod, query, body)
+		w.WriteString("\treturn out, nil\n")
+	default:
+		fmt.Fprintf(w, "\tvar out %s\n", result)
+		fmt.Fprintf(w, "\tif err := c.do(ctx, %q, path, %s, %s, &out); err != nil {\n\t\treturn nil, err\n\t}\n", httpMethod, query, body)
+		w.WriteString("\treturn out, nil\n")
+	}
+	w.WriteString("}\n\n")
+	return nil
+}
+
+// result returns the Go type of the successful response of an operation, "" when it has
+// no body, and whether the body is a file returned as is
+func (g *generator) result(op *operation) (string, bool, error) {
+	for _, status := range []string{"200", "201", "202", "204"} {
+		resp, ok := op.Responses[status]
+		if !ok {
+			continue
+		}
+		if len(resp.Content) == 0 {
+			return "", false, nil
+		}
+		media, ok := resp.Content["application/json"]
+		if !ok {
+			g.imports["io"] = true
+			return "io.ReadCloser", true, nil
+		}
+		if media.Schema.Ref != "" {
+			return "*" + refName(media.Schema.Ref), false, nil
+		}
+		goType, err := g.goType(media.Schema)
+		return goType, false, err
+	}
+	return "", false, fmt.Errorf("no successful response")
+}
+
+// writeParams writes the struct of the query parameters of an operation and the method
+// encoding them; zero values are left out
+func (g *generator) writeParams(name, operationID string, params []*parameter) error {
+	g.imports["net/url"] = true
+	w := &g.types
+	fmt.Fprintf(w, "// %s are the query parameters of %s\n", name, goName(operationID))
+	fmt.Fprintf(w, "type %s struct {\n", name)
+	for _, p := range params {
+		goType, err := g.goType(p.Schema)
+		if err != nil {
+			return fmt.Errorf("parameter %s: %w", p.Name, err)
+		}
+		switch goType {
+		case "string", "int", "int64", "bool":
+		default:
+			return fmt.Errorf("parameter %s: unsupported type %s", p.Name, goType)
+		}
+		comment := p.Description
+		if len(p.Schema.Enum) > 0 {
+			comment = joinSentences(comment, "One of "+strings.Join(p.Schema.Enum, ", "))
+		}
+		if comment != "" {
+			fmt.Fprintf(w, "\t// %s\n", comment)
+		}
+		fmt.Fprintf(w, "\t%s %s\n", goName(p.Name), goType)
+	}
+	w.WriteString("}\n\n")
+
+	fmt.Fprintf(w, "func (p *%s) values() url.Values {\n", name)
+	w.WriteString("\tquery := url.Values{}\n\tif p == nil {\n\t\treturn query\n\t}\n")
+	for _, p := range params {
+		field := "p." + goName(p.Name)
+		goType, _ := g.goType(p.Schema)
+		switch goType {
+		case "string":
+			fmt.Fprintf(w, "\tif %s != \"\" {\n\t\tquery.Set(%q, %s)\n\t}\n", field, p.Name, field)
+		case "int":
+			g.imports["strconv"] = true
+			fmt.Fprintf(w, "\tif %s != 0 {\n\t\tquery.Set(%q, strconv.Itoa(%s))\n\t}\n", field, p.Name, field)
+		case "int64":
+			g.imports["strconv"] = true
+			fmt.Fprintf(w, "\tif %s != 0 {\n\t\tquery.Set(%q, strconv.FormatInt(%s, 10))\n\t}\n", field, p.Name, field)
+		case "bool":
+			fmt.Fprintf(w, "\tif %s {\n\t\tquery.Set(%q, \"true\")\n\t}\n", field, p.Name)
+		}
+	}
+	w.WriteString("\treturn query\n}\n\n")
+	return nil
+}
+
+// propertyComment documents a property with its description and allowed values
+func propertyComment(s *schema) string {
+	comment := s.Description
+	enum := s.Enum
+	if s.Items != nil && len(s.Items.Enum) > 0 {
+		enum = s.Items.Enum
+	}
+	if len(enum) > 0 {
+		comment = joinSentences(comment, "One of "+strings.Join(enum, ", "))
+	}
+	return comment
+}
+
+// writeComment writes text as a comment wrapped at about 90 columns
+func writeComment(w *bytes.Buffer, text string) {
+	line := "//"
+	for _, word := range strings.Fields(text) {
+		if len(line)+1+len(word) > 90 && line != "//" {
+			w.WriteString(line + "\n")
+			line = "//"
+		}
+		line += " " + word
+	}
+	w.WriteString(line + "\n")
+}
+
+// typeComment documents a schema, as in "Alert is an alert raised by..."
+func typeComment(name, description string) string {
+	for _, article := range []string{"A ", "An ", "The "} {
+		if strings.HasPrefix(description, article) {
+			return name + " is " + lowerFirst(description)
+		}
+	}
+	return name + ": " + description
+}
+
+// sentence turns an imperative summary such as "List devices" into the third person
+func sentence(summary string) string {
+	words := strings.SplitN(summary, " ", 2)
+	verb := strings.ToLower(words[0])
+	switch {
+	case strings.HasSuffix(verb, "s"), strings.HasSuffix(verb, "sh"), strings.HasSuffix(verb, "ch"), strings.HasSuffix(verb, "x"):
+		verb += "es"
+	default:
+		verb += "s"
+	}
+	if len(words) == 1 {
+		return verb
+	}
+	return verb + " " + words[1]
+}
+
+func joinSentences(a, b string) string {
+	if a == "" {
+		return b
+	}
+	return strings.TrimSuffix(a, ".") + ". " + b
+}
+
+func lowerFirst(s string) string {
+	for i, r := range s {
+		return string(unicode.ToLower(r)) + s[i+len(string(r)):]
+	}
+	return s
+}
+
+func refName(ref string) string {
+	return ref[strings.LastIndex(ref, "/")+1:]
+}
+
+// goName turns a JSON or operation name such as "numero_serie" or "listAPIKeys" into
+// an exported Go name such as "NumeroSerie" or "ListAPIKeys"
+func goName(name string) string {
+	var b strings.Builder
+	for _, word := range splitWords(name) {
+		if initialisms[strings.ToLower(word)] {
+			b.WriteString(strings.ToUpper(word))
+			continue
+		}
+		b.WriteString(strings.ToUpper(word[:1]) + word[1:])
+	}
+	return b.String()
+}
+
+// goArgName turns a parameter name such as "memberId" into an argument name such as "memberID"
+func goArgName(name string) string {
+	if replacement, ok := keywordNames[name]; ok {
+		return replacement
+	}
+	words := splitWords(name)
+	var b strings.Builder
+	for i, word := range words {
+		switch {
+		case i == 0:
+			b.WriteString(strings.ToLower(word))
+		case initialisms[strings.ToLower(word)]:
+			b.WriteString(strings.ToUpper(word))
+		default:
+			b.WriteString(strings.ToUpper(word[:1]) + word[1:])
+		}
+	}
+	return b.String()
+}
+
+// splitWords splits snake_case and camelCase names into words, keeping runs of capitals
+// such as "API" in "listAPIKeys" together
+func splitWords(name string) []string {
+	var words []string
+	for _, part := range strings.FieldsFunc(name, func(r rune) bool { return r == '_' || r == '-' || r == '.' }) {
+		runes := []rune(part)
+		start := 0
+		for i := 1; i < len(runes); i++ {
+			lowerToUpper := unicode.IsLower(runes[i-1]) && unicode.IsUpper(runes[i])
+			acronymEnd := i+1 < len(runes) && unicode.IsUpper(runes[i-1]) && unicode.IsUpper(runes[i]) && unicode.IsLower(runes[i+1])
+			if lowerToUpper || acronymEnd {
+				words = append(words, string(runes[start:i]))
+				start = i
+			}
+		}
+		words = append(words, string(runes[start:]))
+	}
+	return words
+}
+
+func sortedKeys(m map[string]bool) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
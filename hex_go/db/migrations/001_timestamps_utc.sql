@@ -0,0 +1,20 @@
+-- Per-device timezone used to interpret local timestamps sent by the boards
+ALTER TABLE ESP32
+    ADD COLUMN zona_horaria VARCHAR(64) NULL DEFAULT NULL;
+
+-- Readings are stored as UTC DATETIME values; an open activation has no deactivation date
+ALTER TABLE KY_026
+    MODIFY fecha_activacion DATETIME NOT NULL,
+    MODIFY fecha_desactivacion DATETIME NULL;
+
+ALTER TABLE MQ_2
+    MODIFY fecha_activacion DATETIME NOT NULL,
+    MODIFY fecha_desactivacion DATETIME NULL;
+
+ALTER TABLE MQ_135
+    MODIFY fecha_activacion DATETIME NOT NULL,
+    MODIFY fecha_desactivacion DATETIME NULL;
+
+ALTER TABLE DHT_22
+    MODIFY fecha_activacion DATETIME NOT NULL,
+    MODIFY fecha_desactivacion DATETIME NULL;
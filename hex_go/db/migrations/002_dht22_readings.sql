@@ -0,0 +1,14 @@
+-- Typed DHT_22 readings; estado keeps the legacy "temperatura,humedad" text
+ALTER TABLE DHT_22
+    ADD COLUMN temperatura DECIMAL(5,2) NULL,
+    ADD COLUMN humedad DECIMAL(5,2) NULL,
+    ADD COLUMN indice_calor DECIMAL(5,2) NULL,
+    ADD COLUMN punto_rocio DECIMAL(5,2) NULL;
+
+-- Backfill the numeric columns from legacy rows
+UPDATE DHT_22
+SET temperatura = CAST(SUBSTRING_INDEX(estado, ',', 1) AS DECIMAL(5,2)),
+    humedad = CAST(SUBSTRING_INDEX(estado, ',', -1) AS DECIMAL(5,2))
+WHERE temperatura IS NULL AND estado LIKE '%,%';
+
+CREATE INDEX idx_dht22_temperatura ON DHT_22 (numero_serie, temperatura);
@@ -0,0 +1,11 @@
+-- Device registry: details of each ESP32 board and the claim code used to register it.
+-- Boards are provisioned with idUser NULL and the SHA-256 of the code printed on them.
+ALTER TABLE ESP32
+    MODIFY idUser INT NULL,
+    ADD COLUMN nombre VARCHAR(100) NULL,
+    ADD COLUMN ubicacion VARCHAR(255) NULL,
+    ADD COLUMN sensores_instalados VARCHAR(255) NULL,
+    ADD COLUMN version_firmware VARCHAR(32) NULL,
+    ADD COLUMN codigo_reclamo_hash CHAR(64) NULL,
+    ADD COLUMN fecha_registro DATETIME NULL,
+    ADD UNIQUE INDEX uq_esp32_numero_serie (numero_serie);
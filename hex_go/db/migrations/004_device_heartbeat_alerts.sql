@@ -0,0 +1,18 @@
+-- Last time a board sent a heartbeat or a reading, and whether it is considered online
+ALTER TABLE ESP32
+    ADD COLUMN ultima_conexion DATETIME NULL,
+    ADD COLUMN en_linea TINYINT(1) NOT NULL DEFAULT 0,
+    ADD INDEX idx_esp32_en_linea (en_linea, ultima_conexion);
+
+-- Every alert raised by the service: sensor activations and device problems
+CREATE TABLE alertas (
+    idAlerta BIGINT AUTO_INCREMENT PRIMARY KEY,
+    numero_serie VARCHAR(64) NOT NULL,
+    tipo VARCHAR(32) NOT NULL,
+    severidad VARCHAR(16) NOT NULL,
+    mensaje VARCHAR(255) NOT NULL,
+    valor DOUBLE NULL,
+    estado VARCHAR(16) NOT NULL DEFAULT 'activa',
+    fecha_creacion DATETIME NOT NULL,
+    INDEX idx_alertas_dispositivo (numero_serie, fecha_creacion)
+);
@@ -0,0 +1,18 @@
+-- Site → building → floor → room hierarchy. ruta is the materialised path of IDs
+-- from the site down to the node (e.g. "/3/12/40/") used to select whole subtrees.
+CREATE TABLE ubicaciones (
+    idUbicacion BIGINT AUTO_INCREMENT PRIMARY KEY,
+    idPadre BIGINT NULL,
+    nivel VARCHAR(16) NOT NULL,
+    nombre VARCHAR(100) NOT NULL,
+    direccion VARCHAR(255) NULL,
+    idUser INT NOT NULL,
+    ruta VARCHAR(255) NOT NULL,
+    INDEX idx_ubicaciones_ruta (ruta),
+    INDEX idx_ubicaciones_usuario (idUser),
+    CONSTRAINT fk_ubicaciones_padre FOREIGN KEY (idPadre) REFERENCES ubicaciones (idUbicacion)
+);
+
+ALTER TABLE ESP32
+    ADD COLUMN idUbicacion BIGINT NULL,
+    ADD CONSTRAINT fk_esp32_ubicacion FOREIGN KEY (idUbicacion) REFERENCES ubicaciones (idUbicacion);
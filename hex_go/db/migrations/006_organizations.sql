@@ -0,0 +1,51 @@
+-- Organisations (households, facility teams) whose members share devices
+CREATE TABLE organizaciones (
+    idOrganizacion BIGINT AUTO_INCREMENT PRIMARY KEY,
+    nombre VARCHAR(100) NOT NULL,
+    fecha_creacion DATETIME NOT NULL
+);
+
+CREATE TABLE miembros_organizacion (
+    idOrganizacion BIGINT NOT NULL,
+    idUser INT NOT NULL,
+    rol VARCHAR(16) NOT NULL,
+    fecha_alta DATETIME NOT NULL,
+    PRIMARY KEY (idOrganizacion, idUser),
+    INDEX idx_miembros_usuario (idUser),
+    CONSTRAINT fk_miembros_organizacion FOREIGN KEY (idOrganizacion) REFERENCES organizaciones (idOrganizacion)
+);
+
+CREATE TABLE invitaciones (
+    idInvitacion BIGINT AUTO_INCREMENT PRIMARY KEY,
+    idOrganizacion BIGINT NOT NULL,
+    idUserInvitado INT NOT NULL,
+    rol VARCHAR(16) NOT NULL,
+    estado VARCHAR(16) NOT NULL,
+    invitado_por INT NOT NULL,
+    fecha_creacion DATETIME NOT NULL,
+    fecha_expiracion DATETIME NOT NULL,
+    INDEX idx_invitaciones_invitado (idUserInvitado, estado),
+    CONSTRAINT fk_invitaciones_organizacion FOREIGN KEY (idOrganizacion) REFERENCES organizaciones (idOrganizacion)
+);
+
+CREATE TABLE dispositivos_compartidos (
+    idOrganizacion BIGINT NOT NULL,
+    numero_serie VARCHAR(64) NOT NULL,
+    compartido_por INT NOT NULL,
+    fecha_alta DATETIME NOT NULL,
+    PRIMARY KEY (idOrganizacion, numero_serie),
+    INDEX idx_compartidos_dispositivo (numero_serie),
+    CONSTRAINT fk_compartidos_organizacion FOREIGN KEY (idOrganizacion) REFERENCES organizaciones (idOrganizacion)
+);
+
+-- Every change to who can access an organisation or its devices
+CREATE TABLE auditoria_accesos (
+    idAuditoria BIGINT AUTO_INCREMENT PRIMARY KEY,
+    idOrganizacion BIGINT NOT NULL,
+    actor INT NOT NULL,
+    accion VARCHAR(32) NOT NULL,
+    objetivo VARCHAR(100) NOT NULL,
+    detalle VARCHAR(255) NULL,
+    fecha DATETIME NOT NULL,
+    INDEX idx_auditoria_accesos_organizacion (idOrganizacion, fecha)
+);
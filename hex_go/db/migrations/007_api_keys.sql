@@ -0,0 +1,18 @@
+-- API keys let integrations read the alerts of an organisation. Only the SHA-256 of a
+-- key is stored; the prefix is the public part used to look it up.
+CREATE TABLE claves_api (
+    idClaveApi BIGINT AUTO_INCREMENT PRIMARY KEY,
+    idOrganizacion BIGINT NOT NULL,
+    nombre VARCHAR(100) NOT NULL,
+    prefijo VARCHAR(16) NOT NULL,
+    hash CHAR(64) NOT NULL,
+    scopes VARCHAR(255) NOT NULL,
+    creada_por INT NOT NULL,
+    fecha_creacion DATETIME NOT NULL,
+    fecha_expiracion DATETIME NOT NULL,
+    ultimo_uso DATETIME NULL,
+    revocada BOOLEAN NOT NULL DEFAULT FALSE,
+    UNIQUE KEY uq_claves_api_prefijo (prefijo),
+    INDEX idx_claves_api_organizacion (idOrganizacion),
+    CONSTRAINT fk_claves_api_organizacion FOREIGN KEY (idOrganizacion) REFERENCES organizaciones (idOrganizacion)
+);
@@ -0,0 +1,37 @@
+-- Append-only log of every state-changing operation. Each entry stores the hash of the
+-- entry before it, so editing or deleting a row breaks the chain from that row on.
+CREATE TABLE registro_auditoria (
+    idRegistro BIGINT AUTO_INCREMENT PRIMARY KEY,
+    tipo_actor VARCHAR(16) NOT NULL,
+    id_actor VARCHAR(64) NOT NULL,
+    accion VARCHAR(64) NOT NULL,
+    recurso VARCHAR(32) NOT NULL,
+    id_recurso VARCHAR(100) NOT NULL,
+    -- Kept as text rather than JSON so the stored bytes are exactly the hashed bytes
+    antes LONGTEXT NULL,
+    despues LONGTEXT NULL,
+    id_solicitud VARCHAR(64) NULL,
+    ip VARCHAR(45) NULL,
+    fecha DATETIME(6) NOT NULL,
+    hash_anterior CHAR(64) NOT NULL,
+    hash CHAR(64) NOT NULL,
+    INDEX idx_registro_auditoria_actor (tipo_actor, id_actor),
+    INDEX idx_registro_auditoria_recurso (recurso, id_recurso),
+    INDEX idx_registro_auditoria_fecha (fecha)
+);
+
+-- Single row holding the last entry of the chain. Appending locks it, which keeps
+-- concurrent appends in order.
+CREATE TABLE cadena_auditoria (
+    id TINYINT PRIMARY KEY,
+    idRegistro BIGINT NOT NULL,
+    hash CHAR(64) NOT NULL
+);
+
+INSERT INTO cadena_auditoria (id, idRegistro, hash) VALUES (1, 0, '');
+
+CREATE TRIGGER registro_auditoria_sin_cambios BEFORE UPDATE ON registro_auditoria
+FOR EACH ROW SIGNAL SQLSTATE '45000' SET MESSAGE_TEXT = 'registro_auditoria is append-only';
+
+CREATE TRIGGER registro_auditoria_sin_borrado BEFORE DELETE ON registro_auditoria
+FOR EACH ROW SIGNAL SQLSTATE '45000' SET MESSAGE_TEXT = 'registro_auditoria is append-only';
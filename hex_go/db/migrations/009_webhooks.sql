@@ -0,0 +1,34 @@
+-- Webhooks push the alerts of a device, or of every device below a location, to a URL
+CREATE TABLE webhooks (
+    idWebhook BIGINT AUTO_INCREMENT PRIMARY KEY,
+    idUser INT NOT NULL,
+    url VARCHAR(500) NOT NULL,
+    secreto VARCHAR(128) NOT NULL,
+    numero_serie VARCHAR(64) NULL,
+    idUbicacion BIGINT NULL,
+    tipos VARCHAR(255) NOT NULL DEFAULT '',
+    activo BOOLEAN NOT NULL DEFAULT TRUE,
+    fallos_consecutivos INT NOT NULL DEFAULT 0,
+    fecha_creacion DATETIME NOT NULL,
+    INDEX idx_webhooks_usuario (idUser),
+    INDEX idx_webhooks_dispositivo (numero_serie),
+    INDEX idx_webhooks_ubicacion (idUbicacion)
+);
+
+-- Every event sent or to be sent to a webhook, with the outcome of the last attempt
+CREATE TABLE entregas_webhook (
+    idEntrega BIGINT AUTO_INCREMENT PRIMARY KEY,
+    idWebhook BIGINT NOT NULL,
+    evento VARCHAR(32) NOT NULL,
+    payload TEXT NOT NULL,
+    estado VARCHAR(16) NOT NULL,
+    intentos INT NOT NULL DEFAULT 0,
+    proximo_intento DATETIME NULL,
+    codigo_http INT NULL,
+    error VARCHAR(500) NULL,
+    fecha_creacion DATETIME NOT NULL,
+    fecha_entrega DATETIME NULL,
+    INDEX idx_entregas_pendientes (estado, proximo_intento),
+    INDEX idx_entregas_webhook (idWebhook, fecha_creacion),
+    CONSTRAINT fk_entregas_webhook FOREIGN KEY (idWebhook) REFERENCES webhooks (idWebhook) ON DELETE CASCADE
+);
@@ -0,0 +1,10 @@
+-- Addresses each user wants alerts sent to, per channel and language
+CREATE TABLE destinatarios_notificacion (
+    idDestinatario BIGINT AUTO_INCREMENT PRIMARY KEY,
+    idUser INT NOT NULL,
+    canal VARCHAR(16) NOT NULL,
+    direccion VARCHAR(255) NOT NULL,
+    idioma CHAR(2) NOT NULL,
+    fecha_creacion DATETIME NOT NULL,
+    UNIQUE KEY uq_destinatarios (idUser, canal, direccion)
+);
@@ -0,0 +1,25 @@
+-- Channels each user is notified by per alert severity. Users without rows get the
+-- default: critical alerts on every channel.
+CREATE TABLE canales_notificacion (
+    idUser INT NOT NULL,
+    severidad VARCHAR(16) NOT NULL,
+    canales VARCHAR(255) NOT NULL DEFAULT '',
+    PRIMARY KEY (idUser, severidad)
+);
+
+-- Every notification sent to a recipient, whatever the channel
+CREATE TABLE entregas_notificacion (
+    idEntrega BIGINT AUTO_INCREMENT PRIMARY KEY,
+    idAlerta BIGINT NOT NULL,
+    idDestinatario BIGINT NULL,
+    idUser INT NOT NULL,
+    canal VARCHAR(16) NOT NULL,
+    direccion VARCHAR(255) NOT NULL,
+    estado VARCHAR(16) NOT NULL,
+    error VARCHAR(500) NULL,
+    fecha_creacion DATETIME NOT NULL,
+    INDEX idx_entregas_notificacion_usuario (idUser, idEntrega),
+    INDEX idx_entregas_notificacion_alerta (idAlerta),
+    CONSTRAINT fk_entregas_destinatario FOREIGN KEY (idDestinatario)
+        REFERENCES destinatarios_notificacion (idDestinatario) ON DELETE SET NULL
+);
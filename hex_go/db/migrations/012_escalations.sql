@@ -0,0 +1,52 @@
+-- Who acknowledged an alert, and when
+ALTER TABLE alertas
+    ADD COLUMN reconocida_por INT NULL,
+    ADD COLUMN fecha_reconocimiento DATETIME NULL;
+
+-- Escalation policies contact people in order while alerts of a device, or of every
+-- device below a location, stay unacknowledged
+CREATE TABLE politicas_escalacion (
+    idPolitica BIGINT AUTO_INCREMENT PRIMARY KEY,
+    idUser INT NOT NULL,
+    nombre VARCHAR(100) NOT NULL,
+    numero_serie VARCHAR(64) NULL,
+    idUbicacion BIGINT NULL,
+    severidades VARCHAR(64) NOT NULL DEFAULT '',
+    activa BOOLEAN NOT NULL DEFAULT TRUE,
+    fecha_creacion DATETIME NOT NULL,
+    INDEX idx_politicas_usuario (idUser),
+    INDEX idx_politicas_dispositivo (numero_serie),
+    INDEX idx_politicas_ubicacion (idUbicacion)
+);
+
+CREATE TABLE pasos_escalacion (
+    idPolitica BIGINT NOT NULL,
+    orden INT NOT NULL,
+    nombre VARCHAR(100) NOT NULL,
+    espera_minutos INT NOT NULL,
+    canal VARCHAR(16) NOT NULL,
+    direccion VARCHAR(255) NOT NULL,
+    idioma CHAR(2) NOT NULL,
+    PRIMARY KEY (idPolitica, orden),
+    CONSTRAINT fk_pasos_politica FOREIGN KEY (idPolitica) REFERENCES politicas_escalacion (idPolitica) ON DELETE CASCADE
+);
+
+-- Policies running for an alert; the scheduler notifies the next step once it is due
+CREATE TABLE escalaciones (
+    idEscalacion BIGINT AUTO_INCREMENT PRIMARY KEY,
+    idAlerta BIGINT NOT NULL,
+    idPolitica BIGINT NOT NULL,
+    siguiente_paso INT NOT NULL DEFAULT 0,
+    proximo_paso DATETIME NULL,
+    estado VARCHAR(16) NOT NULL,
+    fecha_creacion DATETIME NOT NULL,
+    fecha_fin DATETIME NULL,
+    UNIQUE KEY uq_escalaciones (idAlerta, idPolitica),
+    INDEX idx_escalaciones_pendientes (estado, proximo_paso),
+    CONSTRAINT fk_escalaciones_politica FOREIGN KEY (idPolitica) REFERENCES politicas_escalacion (idPolitica) ON DELETE CASCADE
+);
+
+-- Notifications sent to escalation contacts share the delivery log
+ALTER TABLE entregas_notificacion
+    ADD COLUMN idEscalacion BIGINT NULL,
+    ADD INDEX idx_entregas_notificacion_escalacion (idEscalacion);
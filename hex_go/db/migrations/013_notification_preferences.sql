@@ -0,0 +1,26 @@
+-- When each user wants to be notified; users without a row get every notification
+-- right away
+CREATE TABLE preferencias_notificacion (
+    idUser INT PRIMARY KEY,
+    zona_horaria VARCHAR(64) NOT NULL DEFAULT 'UTC',
+    silencio_inicio CHAR(5) NULL,
+    silencio_fin CHAR(5) NULL,
+    resumen VARCHAR(8) NOT NULL DEFAULT 'none',
+    hora_resumen TINYINT NOT NULL DEFAULT 8
+);
+
+-- Non-critical notifications held back for quiet hours or a digest. A digest worker
+-- claims the due rows of a recipient with a batch ID, sends them and deletes them.
+CREATE TABLE resumen_pendiente (
+    idPendiente BIGINT AUTO_INCREMENT PRIMARY KEY,
+    idDestinatario BIGINT NOT NULL,
+    idAlerta BIGINT NOT NULL,
+    enviar_despues DATETIME NOT NULL,
+    lote CHAR(32) NULL,
+    reclamado_hasta DATETIME NULL,
+    fecha_creacion DATETIME NOT NULL,
+    INDEX idx_resumen_pendiente (enviar_despues),
+    INDEX idx_resumen_lote (lote),
+    CONSTRAINT fk_resumen_destinatario FOREIGN KEY (idDestinatario)
+        REFERENCES destinatarios_notificacion (idDestinatario) ON DELETE CASCADE
+);
@@ -0,0 +1,15 @@
+-- Repeated activations of the same device and sensor are merged into one alert episode:
+-- how many activations it has seen, the last one, and whether the sensor is flapping
+ALTER TABLE alertas
+    ADD COLUMN ocurrencias INT NOT NULL DEFAULT 1,
+    ADD COLUMN fecha_ultima DATETIME NULL,
+    ADD COLUMN oscilante BOOLEAN NOT NULL DEFAULT FALSE,
+    ADD COLUMN ventana_inicio DATETIME NULL,
+    ADD COLUMN ventana_ocurrencias INT NOT NULL DEFAULT 1,
+    ADD INDEX idx_alertas_episodio (numero_serie, tipo, estado, idAlerta);
+
+UPDATE alertas SET fecha_ultima = fecha_creacion, ventana_inicio = fecha_creacion;
+
+ALTER TABLE alertas
+    MODIFY fecha_ultima DATETIME NOT NULL,
+    MODIFY ventana_inicio DATETIME NOT NULL;
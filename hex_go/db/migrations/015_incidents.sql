@@ -0,0 +1,37 @@
+-- Incidents group the alerts raised in the same room, or by the same device when it has
+-- no location, within a short time of each other. clave is the correlation key of the
+-- incident while it still takes alerts; only one incident per key can take them.
+CREATE TABLE incidentes (
+    idIncidente BIGINT AUTO_INCREMENT PRIMARY KEY,
+    clave VARCHAR(96) NULL,
+    idUbicacion BIGINT NULL,
+    numero_serie VARCHAR(64) NULL,
+    severidad VARCHAR(16) NOT NULL,
+    estado VARCHAR(16) NOT NULL DEFAULT 'abierto',
+    total_alertas INT NOT NULL DEFAULT 0,
+    fecha_inicio DATETIME NOT NULL,
+    fecha_ultima DATETIME NOT NULL,
+    reconocido_por INT NULL,
+    fecha_reconocimiento DATETIME NULL,
+    resuelto_por INT NULL,
+    fecha_resolucion DATETIME NULL,
+    UNIQUE KEY uq_incidentes_clave (clave),
+    INDEX idx_incidentes_estado (estado, fecha_inicio)
+);
+
+-- Timeline of each incident
+CREATE TABLE eventos_incidente (
+    idEvento BIGINT AUTO_INCREMENT PRIMARY KEY,
+    idIncidente BIGINT NOT NULL,
+    tipo VARCHAR(32) NOT NULL,
+    idAlerta BIGINT NULL,
+    idUser INT NULL,
+    detalle VARCHAR(255) NOT NULL DEFAULT '',
+    fecha DATETIME NOT NULL,
+    INDEX idx_eventos_incidente (idIncidente, idEvento),
+    CONSTRAINT fk_eventos_incidente FOREIGN KEY (idIncidente) REFERENCES incidentes (idIncidente) ON DELETE CASCADE
+);
+
+ALTER TABLE alertas
+    ADD COLUMN idIncidente BIGINT NULL,
+    ADD INDEX idx_alertas_incidente (idIncidente);
@@ -0,0 +1,19 @@
+-- Maintenance windows suppress the alerts of a device, or of every device below a
+-- location, while technicians test the detectors
+CREATE TABLE ventanas_mantenimiento (
+    idMantenimiento BIGINT AUTO_INCREMENT PRIMARY KEY,
+    idUser INT NOT NULL,
+    nombre VARCHAR(100) NOT NULL,
+    numero_serie VARCHAR(64) NULL,
+    idUbicacion BIGINT NULL,
+    inicio DATETIME NOT NULL,
+    fin DATETIME NOT NULL,
+    fecha_creacion DATETIME NOT NULL,
+    INDEX idx_mantenimiento_usuario (idUser, fin),
+    INDEX idx_mantenimiento_periodo (fin, inicio)
+);
+
+-- Alerts raised during a maintenance window are stored as suppressed tests
+ALTER TABLE alertas
+    ADD COLUMN suprimida BOOLEAN NOT NULL DEFAULT FALSE,
+    ADD COLUMN idMantenimiento BIGINT NULL;
@@ -0,0 +1,21 @@
+-- Per-device calibration of the MQ gas sensors: offset and gain applied to the raw
+-- value, and the clean air baseline learned during the burn-in period
+CREATE TABLE calibraciones (
+    numero_serie VARCHAR(64) NOT NULL,
+    sensor VARCHAR(16) NOT NULL,
+    offset_valor DOUBLE NOT NULL DEFAULT 0,
+    ganancia DOUBLE NOT NULL DEFAULT 1,
+    linea_base DOUBLE NOT NULL DEFAULT 0,
+    muestras INT NOT NULL DEFAULT 0,
+    inicio_aprendizaje DATETIME NOT NULL,
+    fin_aprendizaje DATETIME NOT NULL,
+    fecha_actualizacion DATETIME NOT NULL,
+    PRIMARY KEY (numero_serie, sensor)
+);
+
+-- Calibrated concentration of each gas reading, NULL while the baseline is learned
+ALTER TABLE MQ_2
+    ADD COLUMN ppm DOUBLE NULL;
+
+ALTER TABLE MQ_135
+    ADD COLUMN ppm DOUBLE NULL;
@@ -0,0 +1,13 @@
+-- Snapshot of the anomaly detector state of each sensor of a device, kept in memory
+-- and stored periodically so a restart does not learn the usual values again
+CREATE TABLE estados_anomalia (
+    numero_serie VARCHAR(64) NOT NULL,
+    sensor VARCHAR(16) NOT NULL,
+    media DOUBLE NOT NULL,
+    varianza DOUBLE NOT NULL,
+    muestras INT NOT NULL,
+    subida_valor DOUBLE NOT NULL DEFAULT 0,
+    subida_fecha DATETIME NULL,
+    fecha_actualizacion DATETIME NOT NULL,
+    PRIMARY KEY (numero_serie, sensor)
+);
@@ -0,0 +1,31 @@
+-- Hourly rollups of the numeric columns of every sensor table, used for the aggregates of
+-- long ranges. lecturas counts the readings of the hour, cantidad those with a value.
+CREATE TABLE agregados_hora (
+    numero_serie VARCHAR(64) NOT NULL,
+    sensor VARCHAR(16) NOT NULL,
+    inicio DATETIME NOT NULL,
+    campo VARCHAR(32) NOT NULL,
+    lecturas INT NOT NULL,
+    cantidad INT NOT NULL,
+    suma DOUBLE NOT NULL DEFAULT 0,
+    minimo DOUBLE NULL,
+    maximo DOUBLE NULL,
+    PRIMARY KEY (numero_serie, sensor, inicio, campo),
+    INDEX idx_agregados_hora_sensor (sensor, inicio)
+);
+
+-- End of the hours already rolled up per sensor type
+CREATE TABLE progreso_agregados (
+    sensor VARCHAR(16) PRIMARY KEY,
+    hasta DATETIME NOT NULL
+);
+
+-- Raw aggregates read one device over a range, the rollup job every device over an hour
+CREATE INDEX idx_ky026_serie_fecha ON KY_026 (numero_serie, fecha_activacion);
+CREATE INDEX idx_ky026_fecha ON KY_026 (fecha_activacion);
+CREATE INDEX idx_mq2_serie_fecha ON MQ_2 (numero_serie, fecha_activacion);
+CREATE INDEX idx_mq2_fecha ON MQ_2 (fecha_activacion);
+CREATE INDEX idx_mq135_serie_fecha ON MQ_135 (numero_serie, fecha_activacion);
+CREATE INDEX idx_mq135_fecha ON MQ_135 (fecha_activacion);
+CREATE INDEX idx_dht22_serie_fecha ON DHT_22 (numero_serie, fecha_activacion);
+CREATE INDEX idx_dht22_fecha ON DHT_22 (fecha_activacion);
@@ -0,0 +1,18 @@
+-- Periods a device spent offline, from its last contact until it reported again; fin
+-- stays NULL while it is still offline
+CREATE TABLE desconexiones (
+    idDesconexion BIGINT AUTO_INCREMENT PRIMARY KEY,
+    numero_serie VARCHAR(64) NOT NULL,
+    inicio DATETIME NOT NULL,
+    fin DATETIME NULL,
+    INDEX idx_desconexiones_abiertas (numero_serie, fin),
+    INDEX idx_desconexiones_inicio (inicio)
+);
+
+-- Monthly safety reports delivered per site, so each month is emailed once
+CREATE TABLE informes_mensuales (
+    idUbicacion BIGINT NOT NULL,
+    mes CHAR(7) NOT NULL,
+    fecha_envio DATETIME NOT NULL,
+    PRIMARY KEY (idUbicacion, mes)
+);
@@ -0,0 +1,4 @@
+-- Device tokens: the SHA-256 of the token issued to a board when it is claimed. Boards
+-- send the token in X-Device-Token; a released board has none until it is claimed again.
+ALTER TABLE ESP32
+    ADD COLUMN token_hash CHAR(64) NULL;
@@ -3,13 +3,13 @@ module hex_go
 go 1.20
 
 require (
+	github.com/go-pdf/fpdf v0.9.0
 	github.com/go-sql-driver/mysql v1.7.1
 	github.com/gorilla/mux v1.8.0
 	github.com/joho/godotenv v1.5.1
+	github.com/rs/cors v1.11.1
+	github.com/streadway/amqp v1.1.0
+	golang.org/x/sync v0.10.0
 )
 
-require (
-	github.com/golang-jwt/jwt/v4 v4.5.2 // indirect
-	github.com/rs/cors v1.11.1 // indirect
-	github.com/streadway/amqp v1.1.0 // indirect
-)
+require github.com/golang-jwt/jwt/v4 v4.5.2 // indirect
@@ -0,0 +1,125 @@
+package authorization
+
+import (
+	_ "embed"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strconv"
+
+	"hex_go/internal/domain/entities"
+)
+
+// AnyPermission grants every permission to a role
+const AnyPermission = "*"
+
+//go:embed default_policy.json
+var defaultPolicy []byte
+
+// Policy maps roles to permissions and routes to the permission they require. It is
+// loaded from a JSON file so rights can be changed without a new build.
+type Policy struct {
+	// DefaultUserRoles are given to every authenticated user
+	DefaultUserRoles []string `json:"default_user_roles"`
+	// UserRoles grants extra roles to specific users, keyed by user ID
+	UserRoles map[string][]string `json:"user_roles"`
+	// Roles lists the permissions of each role
+	Roles map[string][]string `json:"roles"`
+	// Routes maps a mux route name to the permission it requires
+	Routes map[string]string `json:"routes"`
+}
+
+// LoadPolicy reads the policy from path, or the built-in policy when path is empty
+func LoadPolicy(path string) (*Policy, error) {
+	data := defaultPolicy
+	if path != "" {
+		var err error
+		if data, err = os.ReadFile(path); err != nil {
+			return nil, fmt.Errorf("error reading policy file: %w", err)
+		}
+	}
+
+	var policy Policy
+	if err := json.Unmarshal(data, &policy); err != nil {
+		return nil, fmt.Errorf("error parsing policy: %w", err)
+	}
+	if err := policy.validate(); err != nil {
+		return nil, err
+	}
+
+	return &policy, nil
+}
+
+// RolesFor returns the roles of a caller: the anonymous role, the device role, or the
+// default user roles plus any roles granted to that user. API keys have no roles; they
+// only hold the scopes they were issued with.
+func (p *Policy) RolesFor(principal *entities.Principal) []string {
+	switch {
+	case principal.IsAPIKey():
+		return []string{}
+	case principal.IsUser():
+		roles := append([]string{}, p.DefaultUserRoles...)
+		return append(roles, p.UserRoles[strconv.Itoa(principal.UserID)]...)
+	case principal.IsDevice():
+		return []string{entities.PrincipalDevice}
+	default:
+		return []string{entities.PrincipalAnonymous}
+	}
+}
+
+// Allows reports whether any role of the caller grants the permission, or for API keys
+// whether the key was issued with that scope
+func (p *Policy) Allows(principal *entities.Principal, permission string) bool {
+	if principal.IsAPIKey() {
+		for _, scope := range principal.Scopes {
+			if scope == permission {
+				return true
+			}
+		}
+		return false
+	}
+
+	for _, role := range principal.Roles {
+		for _, granted := range p.Roles[role] {
+			if granted == permission || granted == AnyPermission {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// RoutePermission returns the permission required by a named route
+func (p *Policy) RoutePermission(route string) (string, bool) {
+	permission, ok := p.Routes[route]
+	return permission, ok
+}
+
+// validate checks that every role referenced by the policy is defined
+func (p *Policy) validate() error {
+	for _, role := range p.DefaultUserRoles {
+		if _, ok := p.Roles[role]; !ok {
+			return fmt.Errorf("policy: default user role %q is not defined", role)
+		}
+	}
+	for user, roles := range p.UserRoles {
+		if _, err := strconv.Atoi(user); err != nil {
+			return fmt.Errorf("policy: user_roles key %q is not a user ID", user)
+		}
+		for _, role := range roles {
+			if _, ok := p.Roles[role]; !ok {
+				return fmt.Errorf("policy: role %q of user %s is not defined", role, user)
+			}
+		}
+	}
+	return nil
+}
+
+// CheckDeviceCaller makes sure a device only acts on its own behalf: when the caller is
+// a device firmware, numeroSerie must be that device's serial number
+func CheckDeviceCaller(principal *entities.Principal, numeroSerie string) error {
+	if principal.IsDevice() && principal.NumeroSerie != numeroSerie {
+		return fmt.Errorf("device %s cannot act as %s: %w", principal.NumeroSerie, numeroSerie, entities.ErrForbidden)
+	}
+	return nil
+}
@@ -0,0 +1,245 @@
+package authorization
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"hex_go/internal/domain/entities"
+)
+
+// testPolicy is a small policy covering every kind of caller
+func testPolicy() *Policy {
+	return &Policy{
+		DefaultUserRoles: []string{"resident"},
+		UserRoles:        map[string][]string{"7": {"admin"}},
+		Roles: map[string][]string{
+			"anonymous": {"docs:read"},
+			"device":    {"readings:create"},
+			"resident":  {"alerts:read", "devices:read"},
+			"admin":     {AnyPermission},
+		},
+		Routes: map[string]string{
+			"sensors.create": "readings:create",
+			"alerts.list":    "alerts:read",
+			"docs.spec":      "docs:read",
+		},
+	}
+}
+
+func TestRolesFor(t *testing.T) {
+	policy := testPolicy()
+
+	tests := []struct {
+		name      string
+		principal *entities.Principal
+		want      []string
+	}{
+		{"nil caller", nil, []string{"anonymous"}},
+		{"anonymous", &entities.Principal{Kind: entities.PrincipalAnonymous}, []string{"anonymous"}},
+		{"device", &entities.Principal{Kind: entities.PrincipalDevice, NumeroSerie: "ESP32-0001"}, []string{"device"}},
+		{"user", &entities.Principal{Kind: entities.PrincipalUser, UserID: 3}, []string{"resident"}},
+		{"user with extra roles", &entities.Principal{Kind: entities.PrincipalUser, UserID: 7}, []string{"resident", "admin"}},
+		{"api key", &entities.Principal{Kind: entities.PrincipalAPIKey, Scopes: []string{"alerts:read"}}, []string{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := policy.RolesFor(tt.principal); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("RolesFor() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRolesForDoesNotShareDefaultRoles(t *testing.T) {
+	policy := testPolicy()
+	policy.DefaultUserRoles = make([]string, 1, 4)
+	policy.DefaultUserRoles[0] = "resident"
+
+	policy.RolesFor(&entities.Principal{Kind: entities.PrincipalUser, UserID: 7})
+	roles := policy.RolesFor(&entities.Principal{Kind: entities.PrincipalUser, UserID: 3})
+	if !reflect.DeepEqual(roles, []string{"resident"}) {
+		t.Errorf("RolesFor() = %v, want the default roles only", roles)
+	}
+}
+
+func TestAllows(t *testing.T) {
+	policy := testPolicy()
+
+	tests := []struct {
+		name       string
+		principal  *entities.Principal
+		permission string
+		want       bool
+	}{
+		{"anonymous reads docs", &entities.Principal{Roles: []string{"anonymous"}}, "docs:read", true},
+		{"anonymous posts readings", &entities.Principal{Roles: []string{"anonymous"}}, "readings:create", false},
+		{"device posts readings", &entities.Principal{Kind: entities.PrincipalDevice, Roles: []string{"device"}}, "readings:create", true},
+		{"device reads alerts", &entities.Principal{Kind: entities.PrincipalDevice, Roles: []string{"device"}}, "alerts:read", false},
+		{"resident reads alerts", &entities.Principal{Kind: entities.PrincipalUser, Roles: []string{"resident"}}, "alerts:read", true},
+		{"resident manages devices", &entities.Principal{Kind: entities.PrincipalUser, Roles: []string{"resident"}}, "devices:manage", false},
+		{"admin has any permission", &entities.Principal{Kind: entities.PrincipalUser, Roles: []string{"resident", "admin"}}, "audit:read", true},
+		{"undefined role", &entities.Principal{Kind: entities.PrincipalUser, Roles: []string{"ghost"}}, "alerts:read", false},
+		{"api key with the scope", &entities.Principal{Kind: entities.PrincipalAPIKey, Scopes: []string{"alerts:read"}}, "alerts:read", true},
+		{"api key without the scope", &entities.Principal{Kind: entities.PrincipalAPIKey, Scopes: []string{"alerts:read"}}, "devices:read", false},
+		{"api key ignores roles", &entities.Principal{Kind: entities.PrincipalAPIKey, Roles: []string{"admin"}}, "alerts:read", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := policy.Allows(tt.principal, tt.permission); got != tt.want {
+				t.Errorf("Allows(%q) = %v, want %v", tt.permission, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRoutePermission(t *testing.T) {
+	policy := testPolicy()
+
+	tests := []struct {
+		route      string
+		permission string
+		known      bool
+	}{
+		{"sensors.create", "readings:create", true},
+		{"alerts.list", "alerts:read", true},
+		{"alerts.delete", "", false},
+		{"", "", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.route, func(t *testing.T) {
+			permission, known := policy.RoutePermission(tt.route)
+			if permission != tt.permission || known != tt.known {
+				t.Errorf("RoutePermission(%q) = %q, %v, want %q, %v", tt.route, permission, known, tt.permission, tt.known)
+			}
+		})
+	}
+}
+
+func TestLoadPolicy(t *testing.T) {
+	dir := t.TempDir()
+	write := func(name, content string) string {
+		path := filepath.Join(dir, name)
+		if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+			t.Fatal(err)
+		}
+		return path
+	}
+
+	tests := []struct {
+		name    string
+		path    string
+		wantErr bool
+	}{
+		{"built-in policy", "", false},
+		{"valid file", write("valid.json", `{"default_user_roles": ["resident"], "user_roles": {"7": ["resident"]}, "roles": {"resident": []}}`), false},
+		{"missing file", filepath.Join(dir, "missing.json"), true},
+		{"invalid JSON", write("invalid.json", `{"roles": `), true},
+		{"undefined default role", write("default.json", `{"default_user_roles": ["resident"], "roles": {}}`), true},
+		{"user key not an ID", write("key.json", `{"user_roles": {"alice": []}, "roles": {}}`), true},
+		{"undefined user role", write("role.json", `{"user_roles": {"7": ["admin"]}, "roles": {}}`), true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := LoadPolicy(tt.path)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("LoadPolicy() error = %v, want error %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestDefaultPolicyDevicesOnly(t *testing.T) {
+	policy, err := LoadPolicy("")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	anonymous := &entities.Principal{Kind: entities.PrincipalAnonymous}
+	anonymous.Roles = policy.RolesFor(anonymous)
+	device := &entities.Principal{Kind: entities.PrincipalDevice, NumeroSerie: "ESP32-0001"}
+	device.Roles = policy.RolesFor(device)
+
+	for _, route := range []string{"sensors.create", "devices.heartbeat"} {
+		permission, ok := policy.RoutePermission(route)
+		if !ok {
+			t.Fatalf("route %s is not in the default policy", route)
+		}
+		if policy.Allows(anonymous, permission) {
+			t.Errorf("anonymous callers are allowed %s", route)
+		}
+		if !policy.Allows(device, permission) {
+			t.Errorf("devices are not allowed %s", route)
+		}
+	}
+}
+
+func TestDefaultPolicyResidents(t *testing.T) {
+	policy, err := LoadPolicy("")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	resident := &entities.Principal{Kind: entities.PrincipalUser, UserID: 3}
+	resident.Roles = policy.RolesFor(resident)
+
+	tests := []struct {
+		name  string
+		route string
+		want  bool
+	}{
+		{"POST /api/devices", "devices.claim", true},
+		{"PUT /api/devices/{numeroSerie}/location", "devices.location", true},
+		{"POST /api/locations", "locations.create", true},
+		{"POST /api/organizations", "organizations.create", true},
+		{"POST /api/webhooks", "webhooks.create", true},
+		{"POST /api/escalation-policies", "escalations.create", true},
+		{"POST /api/maintenance-windows", "maintenance.create", true},
+		{"POST /api/sensors", "sensors.create", false},
+		{"GET /api/audit", "audit.list", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			permission, ok := policy.RoutePermission(tt.route)
+			if !ok {
+				t.Fatalf("route %s is not in the default policy", tt.route)
+			}
+			if got := policy.Allows(resident, permission); got != tt.want {
+				t.Errorf("resident allowed %s = %v, want %v", tt.route, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCheckDeviceCaller(t *testing.T) {
+	tests := []struct {
+		name      string
+		principal *entities.Principal
+		serial    string
+		wantErr   bool
+	}{
+		{"own serial", &entities.Principal{Kind: entities.PrincipalDevice, NumeroSerie: "ESP32-0001"}, "ESP32-0001", false},
+		{"other serial", &entities.Principal{Kind: entities.PrincipalDevice, NumeroSerie: "ESP32-0001"}, "ESP32-0002", true},
+		{"user", &entities.Principal{Kind: entities.PrincipalUser, UserID: 3}, "ESP32-0002", false},
+		{"no caller", nil, "ESP32-0002", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := CheckDeviceCaller(tt.principal, tt.serial)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("CheckDeviceCaller() error = %v, want error %v", err, tt.wantErr)
+			}
+			if err != nil && !errors.Is(err, entities.ErrForbidden) {
+				t.Errorf("CheckDeviceCaller() error = %v, want ErrForbidden", err)
+			}
+		})
+	}
+}
@@ -0,0 +1,154 @@
+package services
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"hex_go/internal/application/validation"
+	"hex_go/internal/domain/entities"
+	"hex_go/internal/domain/ports"
+)
+
+// rollupChunk bounds the hours rolled up in one transaction while catching up
+const rollupChunk = 24 * time.Hour
+
+// AggregateService summarises readings per time bucket for charts. Ranges up to
+// rawMaxRange are read from the sensor tables; longer ones with buckets of whole hours
+// use the hourly rollups, which a background job keeps up to date, plus the readings
+// after the last rolled up hour.
+type AggregateService struct {
+	repo        ports.AggregateRepositoryPort
+	rawMaxRange time.Duration
+	delay       time.Duration
+	interval    time.Duration
+}
+
+// NewAggregateService creates the aggregate service. An hour is rolled up once delay has
+// passed after it, so that readings arriving late are still counted.
+func NewAggregateService(repo ports.AggregateRepositoryPort, rawMaxRange, delay, interval time.Duration) *AggregateService {
+	return &AggregateService{
+		repo:        repo,
+		rawMaxRange: rawMaxRange,
+		delay:       delay,
+		interval:    interval,
+	}
+}
+
+// AggregateReadings returns the readings of one sensor of a device summarised per bucket.
+// Devices the caller cannot read are reported as not found.
+func (s *AggregateService) AggregateReadings(ctx context.Context, userID int, query *entities.AggregateQuery) (*entities.AggregateSeries, error) {
+	if err := validation.ValidateAggregateQuery(query, time.Now()); err != nil {
+		return nil, err
+	}
+	visible, err := s.repo.DeviceVisible(ctx, query.NumeroSerie, userID, query.OrganizationID)
+	if err != nil {
+		return nil, err
+	}
+	if !visible {
+		return nil, entities.ErrNotFound
+	}
+
+	size, _ := entities.AggregateBucketSize(query.Bucket)
+	fields := entities.AggregateFields(query.Sensor)
+	desde, hasta := *query.Desde, *query.Hasta
+	series := &entities.AggregateSeries{
+		NumeroSerie: query.NumeroSerie,
+		Sensor:      query.Sensor,
+		Bucket:      query.Bucket,
+		Desde:       desde,
+		Hasta:       hasta,
+		Campos:      fields,
+		Fuente:      entities.AggregateSourceRaw,
+	}
+
+	// The rollups cover the hours up to their progress, the readings the rest
+	var found []*entities.AggregateBucket
+	split := desde
+	if hasta.Sub(desde) > s.rawMaxRange && size%time.Hour == 0 {
+		progress, err := s.repo.RollupProgress(ctx, query.Sensor)
+		if err != nil {
+			return nil, err
+		}
+		if progress.After(desde) {
+			split = progress
+			if split.After(hasta) {
+				split = hasta
+			}
+			found, err = s.repo.AggregateRollups(ctx, query.NumeroSerie, query.Sensor, desde, desde, split, size)
+			if err != nil {
+				return nil, err
+			}
+			series.Fuente = entities.AggregateSourceRollup
+		}
+	}
+	if split.Before(hasta) {
+		raw, err := s.repo.AggregateReadings(ctx, query.NumeroSerie, query.Sensor, desde, split, hasta, size)
+		if err != nil {
+			return nil, err
+		}
+		found = append(found, raw...)
+	}
+
+	series.Buckets = entities.FillBuckets(desde, hasta, size, fields, found)
+	return series, nil
+}
+
+// Run rolls up the finished hours every interval until ctx is cancelled
+func (s *AggregateService) Run(ctx context.Context) {
+	log.Printf("Reading rollups started: every %s, %s after each hour", s.interval, s.delay)
+
+	ticker := time.NewTicker(s.interval)
+	defer ticker.Stop()
+
+	for {
+		// Catch up right away after a restart or on the first run
+		if err := s.Rollup(ctx); err != nil {
+			log.Printf("Error rolling up readings: %v", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			log.Printf("Reading rollups stopped")
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// Rollup rolls up the hours finished since the last run for every sensor type
+func (s *AggregateService) Rollup(ctx context.Context) error {
+	end := time.Now().UTC().Add(-s.delay).Truncate(time.Hour)
+
+	for _, sensor := range entities.SensorTypes {
+		progress, err := s.repo.RollupProgress(ctx, sensor)
+		if err != nil {
+			return err
+		}
+		if progress.IsZero() {
+			first, err := s.repo.FirstReading(ctx, sensor)
+			if err != nil {
+				return err
+			}
+			if first.IsZero() {
+				continue
+			}
+			progress = first.UTC().Truncate(time.Hour)
+		}
+
+		for progress.Before(end) {
+			next := progress.Add(rollupChunk)
+			if next.After(end) {
+				next = end
+			}
+			if err := s.repo.RollupHours(ctx, sensor, progress, next); err != nil {
+				return err
+			}
+			progress = next
+		}
+	}
+	return nil
+}
+
+// Verify interface implementation
+var _ ports.AggregateServicePort = (*AggregateService)(nil)
@@ -0,0 +1,191 @@
+package services
+
+import (
+	"context"
+	"log"
+	"strconv"
+	"time"
+
+	"hex_go/internal/application/validation"
+	"hex_go/internal/domain/entities"
+	"hex_go/internal/domain/ports"
+)
+
+// episodeMergeAttempts bounds the retries when concurrent activations of the same
+// device and sensor are merged into an episode at once
+const episodeMergeAttempts = 3
+
+type AlertService struct {
+	repo            ports.AlertRepositoryPort
+	maintenanceRepo ports.MaintenanceRepositoryPort
+	messageQueue    ports.MessageQueuePort
+	audit           ports.AuditServicePort
+	debounce        entities.AlertDebounce
+	subscribers     []ports.AlertSubscriberPort
+}
+
+func NewAlertService(repo ports.AlertRepositoryPort, maintenanceRepo ports.MaintenanceRepositoryPort, messageQueue ports.MessageQueuePort, audit ports.AuditServicePort, debounce entities.AlertDebounce, subscribers ...ports.AlertSubscriberPort) ports.AlertServicePort {
+	return &AlertService{
+		repo:            repo,
+		maintenanceRepo: maintenanceRepo,
+		messageQueue:    messageQueue,
+		audit:           audit,
+		debounce:        debounce,
+		subscribers:     subscribers,
+	}
+}
+
+// RaiseAlert stores a new alert, publishes it to the message queue and hands it to
+// the subscribers such as webhooks. An activation that repeats the open alert of its
+// device and sensor is merged into it instead, and alert is set to that episode;
+// subscribers are not told again. Alerts raised during a maintenance window are stored
+// and published as suppressed, and subscribers are not told at all.
+func (s *AlertService) RaiseAlert(ctx context.Context, alert *entities.Alert) error {
+	if alert.FechaCreacion.IsZero() {
+		alert.FechaCreacion = time.Now().UTC()
+	}
+	if alert.Estado == "" {
+		alert.Estado = entities.AlertStateActive
+	}
+	s.checkMaintenance(ctx, alert)
+
+	merged, err := s.mergeIntoEpisode(ctx, alert)
+	if err != nil {
+		return err
+	}
+	if merged {
+		return nil
+	}
+
+	s.debounce.StartEpisode(alert)
+	if err := s.repo.CreateAlert(ctx, alert); err != nil {
+		return err
+	}
+
+	if s.messageQueue != nil {
+		if err := s.messageQueue.PublishAlert(alert); err != nil {
+			// The alert is already stored, so a broker outage must not lose it
+			log.Printf("Error publishing alert %d: %v", alert.ID, err)
+		}
+	}
+
+	if alert.Suprimida {
+		log.Printf("Alert %d of device %s suppressed by maintenance window %d", alert.ID, alert.NumeroSerie, *alert.IDMantenimiento)
+		return nil
+	}
+	for _, subscriber := range s.subscribers {
+		subscriber.AlertRaised(ctx, alert)
+	}
+
+	return nil
+}
+
+// checkMaintenance marks the alert as suppressed when a maintenance window covers its
+// device at the time it was raised. When the windows cannot be read the alert goes out:
+// a missed fire is worse than a notified test.
+func (s *AlertService) checkMaintenance(ctx context.Context, alert *entities.Alert) {
+	window, err := s.maintenanceRepo.FindActiveWindow(ctx, alert.NumeroSerie, alert.FechaCreacion)
+	if err == entities.ErrNotFound {
+		return
+	}
+	if err != nil {
+		log.Printf("Error checking maintenance windows of device %s: %v", alert.NumeroSerie, err)
+		return
+	}
+
+	alert.Suprimida = true
+	alert.IDMantenimiento = &window.ID
+}
+
+// mergeIntoEpisode counts the activation in the open alert of its device and sensor when
+// it is recent enough, and reports whether it did. The episode is published again when
+// it starts or stops flapping.
+func (s *AlertService) mergeIntoEpisode(ctx context.Context, alert *entities.Alert) (bool, error) {
+	if !s.debounce.Enabled() {
+		return false, nil
+	}
+
+	for attempt := 0; attempt < episodeMergeAttempts; attempt++ {
+		episode, err := s.repo.FindOpenEpisode(ctx, alert.NumeroSerie, alert.Tipo)
+		if err == entities.ErrNotFound {
+			return false, nil
+		}
+		if err != nil {
+			return false, err
+		}
+		// Tests during maintenance and real activations are kept apart
+		if episode.Suprimida != alert.Suprimida || !s.debounce.Absorbs(episode, alert.FechaCreacion) {
+			return false, nil
+		}
+
+		seen := episode.Ocurrencias
+		if !alert.FechaCreacion.Before(episode.FechaUltima) {
+			episode.Valor = alert.Valor
+		}
+		flapping := s.debounce.Merge(episode, alert.FechaCreacion)
+
+		err = s.repo.UpdateEpisode(ctx, episode, seen)
+		if err == entities.ErrConflict {
+			// Another activation got there first, or the alert was resolved
+			continue
+		}
+		if err != nil {
+			return false, err
+		}
+
+		*alert = *episode
+		if flapping {
+			if alert.Oscilante {
+				log.Printf("Alert %d: %s of device %s is flapping", alert.ID, alert.Tipo, alert.NumeroSerie)
+			} else {
+				log.Printf("Alert %d: %s of device %s stopped flapping", alert.ID, alert.Tipo, alert.NumeroSerie)
+			}
+			if s.messageQueue != nil {
+				if err := s.messageQueue.PublishAlert(alert); err != nil {
+					log.Printf("Error publishing alert %d: %v", alert.ID, err)
+				}
+			}
+		}
+		return true, nil
+	}
+
+	log.Printf("Alert of device %s kept changing while merging %s activation, raising a new one", alert.NumeroSerie, alert.Tipo)
+	return false, nil
+}
+
+// AcknowledgeAlert records that the user is dealing with an active alert of one of the
+// devices they can see. Viewers of a shared device may not acknowledge its alerts.
+// Escalations of the alert stop at their next step.
+func (s *AlertService) AcknowledgeAlert(ctx context.Context, userID int, id int64) (*entities.Alert, error) {
+	alert, role, err := s.repo.GetUserAlert(ctx, userID, id)
+	if err != nil {
+		return nil, err
+	}
+	if !entities.CanRespondToAlerts(role) {
+		return nil, entities.ErrForbidden
+	}
+	if alert.Estado != entities.AlertStateActive {
+		return nil, entities.ErrConflict
+	}
+	before := *alert
+
+	now := time.Now().UTC()
+	if err := s.repo.AcknowledgeAlert(ctx, id, userID, now); err != nil {
+		return nil, err
+	}
+	alert.Estado = entities.AlertStateAcknowledged
+	alert.ReconocidaPor = &userID
+	alert.FechaReconocimiento = &now
+	s.audit.Record(ctx, entities.AuditAlertAcknowledged, entities.ResourceAlert, strconv.FormatInt(id, 10), &before, alert)
+
+	return alert, nil
+}
+
+// ExportAlerts streams the alert history of the devices the user sees, or the devices
+// shared with the organisation of an API key
+func (s *AlertService) ExportAlerts(ctx context.Context, userID int, query *entities.AlertExportQuery, fn func(*entities.Alert) error) error {
+	if err := validation.ValidateAlertExportQuery(query); err != nil {
+		return err
+	}
+	return s.repo.StreamUserAlerts(ctx, userID, query, fn)
+}
@@ -0,0 +1,166 @@
+package services
+
+import (
+	"context"
+	"log"
+	"strings"
+	"sync"
+	"time"
+
+	"hex_go/internal/domain/entities"
+	"hex_go/internal/domain/ports"
+)
+
+// anomalyKey identifies the state of one sensor of a device
+type anomalyKey struct {
+	numeroSerie string
+	sensor      string
+}
+
+// AnomalyService follows every reading with the anomaly detector and raises an anomaly
+// alert through the normal alert path when one stands out. The detector state is kept
+// in memory and stored every interval; with several instances each keeps its own state
+// and the last snapshot stored wins, which only costs some history on a restart.
+type AnomalyService struct {
+	repo     ports.AnomalyRepositoryPort
+	alerts   ports.AlertServicePort
+	detector entities.AnomalyDetector
+	interval time.Duration
+
+	mu     sync.Mutex
+	states map[anomalyKey]*entities.AnomalyState
+	dirty  map[anomalyKey]bool
+}
+
+func NewAnomalyService(repo ports.AnomalyRepositoryPort, alerts ports.AlertServicePort, detector entities.AnomalyDetector, interval time.Duration) *AnomalyService {
+	return &AnomalyService{
+		repo:     repo,
+		alerts:   alerts,
+		detector: detector,
+		interval: interval,
+		states:   make(map[anomalyKey]*entities.AnomalyState),
+		dirty:    make(map[anomalyKey]bool),
+	}
+}
+
+// ObserveReading adds a reading to the state of its sensor and raises an anomaly alert
+// explaining what stood out, if anything did
+func (s *AnomalyService) ObserveReading(ctx context.Context, reading *entities.SensorReading) {
+	if !s.detector.Enabled() {
+		return
+	}
+	value, ok := entities.AnomalyMetric(reading)
+	if !ok {
+		return
+	}
+
+	key := anomalyKey{numeroSerie: reading.NumeroSerie, sensor: reading.Sensor}
+	if err := s.load(ctx, key); err != nil {
+		log.Printf("Error loading %s anomaly state of device %s: %v", reading.Sensor, reading.NumeroSerie, err)
+		return
+	}
+
+	s.mu.Lock()
+	findings := s.detector.Observe(s.states[key], value, reading.FechaActivacion)
+	s.dirty[key] = true
+	s.mu.Unlock()
+
+	if len(findings) == 0 {
+		return
+	}
+
+	alert := &entities.Alert{
+		NumeroSerie:   reading.NumeroSerie,
+		Tipo:          entities.AlertTypeAnomaly,
+		Severidad:     entities.SeverityWarning,
+		Mensaje:       "Anomaly: " + strings.Join(findings, "; "),
+		Valor:         &value,
+		FechaCreacion: reading.FechaActivacion,
+	}
+	if err := s.alerts.RaiseAlert(ctx, alert); err != nil {
+		log.Printf("Error raising anomaly alert for device %s: %v", reading.NumeroSerie, err)
+	}
+}
+
+// load puts the stored state of a sensor in memory, or a new one, unless it is there
+// already. The lock is not held while querying so that other sensors are not held up.
+func (s *AnomalyService) load(ctx context.Context, key anomalyKey) error {
+	s.mu.Lock()
+	_, ok := s.states[key]
+	s.mu.Unlock()
+	if ok {
+		return nil
+	}
+
+	state, err := s.repo.GetAnomalyState(ctx, key.numeroSerie, key.sensor)
+	if err == entities.ErrNotFound {
+		state = &entities.AnomalyState{NumeroSerie: key.numeroSerie, Sensor: key.sensor}
+	} else if err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, ok := s.states[key]; !ok {
+		// A concurrent reading of the same sensor may have loaded it meanwhile
+		s.states[key] = state
+	}
+	return nil
+}
+
+// Run stores the states changed since the last snapshot every interval until ctx is
+// cancelled. The caller takes the last snapshot once no more readings come in.
+func (s *AnomalyService) Run(ctx context.Context) {
+	if !s.detector.Enabled() {
+		log.Printf("Anomaly detection disabled")
+		return
+	}
+	log.Printf("Anomaly detector started, storing its state every %s", s.interval)
+
+	ticker := time.NewTicker(s.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			log.Printf("Anomaly detector stopped")
+			return
+		case <-ticker.C:
+			if err := s.Snapshot(ctx); err != nil {
+				log.Printf("Error storing anomaly detector state: %v", err)
+			}
+		}
+	}
+}
+
+// Snapshot stores the states changed since the last snapshot
+func (s *AnomalyService) Snapshot(ctx context.Context) error {
+	s.mu.Lock()
+	keys := make([]anomalyKey, 0, len(s.dirty))
+	states := make([]*entities.AnomalyState, 0, len(s.dirty))
+	for key := range s.dirty {
+		// Copies, as readings keep changing the states while they are stored
+		state := *s.states[key]
+		keys = append(keys, key)
+		states = append(states, &state)
+	}
+	s.dirty = make(map[anomalyKey]bool)
+	s.mu.Unlock()
+
+	if len(states) == 0 {
+		return nil
+	}
+	if err := s.repo.SaveAnomalyStates(ctx, states); err != nil {
+		// Try again with the next snapshot
+		s.mu.Lock()
+		for _, key := range keys {
+			s.dirty[key] = true
+		}
+		s.mu.Unlock()
+		return err
+	}
+	return nil
+}
+
+// Verify interface implementation
+var _ ports.AnomalyServicePort = (*AnomalyService)(nil)
@@ -0,0 +1,191 @@
+package services
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"fmt"
+	"log"
+	"strconv"
+	"strings"
+	"time"
+
+	"hex_go/internal/application/validation"
+	"hex_go/internal/domain/entities"
+	"hex_go/internal/domain/ports"
+)
+
+const (
+	// apiKeyPrefix starts every key so leaked keys are easy to recognise
+	apiKeyPrefix = "sfk"
+	// defaultAPIKeyLifetime applies when a key is created without an expiry
+	defaultAPIKeyLifetime = 365 * 24 * time.Hour
+	// apiKeyTouchInterval limits how often the last use of a key is written
+	apiKeyTouchInterval = time.Minute
+)
+
+type APIKeyService struct {
+	repo    ports.APIKeyRepositoryPort
+	orgRepo ports.OrganizationRepositoryPort
+	audit   ports.AuditServicePort
+}
+
+func NewAPIKeyService(repo ports.APIKeyRepositoryPort, orgRepo ports.OrganizationRepositoryPort, audit ports.AuditServicePort) ports.APIKeyServicePort {
+	return &APIKeyService{
+		repo:    repo,
+		orgRepo: orgRepo,
+		audit:   audit,
+	}
+}
+
+// CreateAPIKey issues a key for an organisation the user manages. The raw key is only
+// part of the response; the repository keeps its SHA-256.
+func (s *APIKeyService) CreateAPIKey(ctx context.Context, userID int, orgID int64, req *entities.CreateAPIKeyRequest) (*entities.IssuedAPIKey, error) {
+	if err := validation.ValidateCreateAPIKey(req); err != nil {
+		return nil, err
+	}
+	if _, err := organizationManager(ctx, s.orgRepo, orgID, userID); err != nil {
+		return nil, err
+	}
+
+	prefijo, err := randomHex(6)
+	if err != nil {
+		return nil, err
+	}
+	secreto, err := randomHex(24)
+	if err != nil {
+		return nil, err
+	}
+	rawKey := apiKeyPrefix + "_" + prefijo + "_" + secreto
+
+	lifetime := defaultAPIKeyLifetime
+	if req.ExpiraEnDias > 0 {
+		lifetime = time.Duration(req.ExpiraEnDias) * 24 * time.Hour
+	}
+
+	now := time.Now().UTC()
+	key := &entities.APIKey{
+		IDOrganizacion:  orgID,
+		Nombre:          strings.TrimSpace(req.Nombre),
+		Prefijo:         prefijo,
+		Scopes:          uniqueStrings(req.Scopes),
+		CreadaPor:       userID,
+		FechaCreacion:   now,
+		FechaExpiracion: now.Add(lifetime),
+		Hash:            hashSecret(rawKey),
+	}
+	audit := &entities.AccessAuditEntry{
+		IDOrganizacion: orgID,
+		Actor:          userID,
+		Accion:         entities.AuditAPIKeyCreated,
+		Detalle:        strings.Join(key.Scopes, ","),
+		Fecha:          now,
+	}
+
+	if err := s.repo.CreateAPIKey(ctx, key, audit); err != nil {
+		return nil, err
+	}
+	s.audit.Record(ctx, entities.AuditAPIKeyCreated, entities.ResourceAPIKey, strconv.FormatInt(key.ID, 10), nil, key)
+
+	return &entities.IssuedAPIKey{APIKey: key, Clave: rawKey}, nil
+}
+
+// ListAPIKeys returns the keys of an organisation the user manages
+func (s *APIKeyService) ListAPIKeys(ctx context.Context, userID int, orgID int64) ([]*entities.APIKey, error) {
+	if _, err := organizationManager(ctx, s.orgRepo, orgID, userID); err != nil {
+		return nil, err
+	}
+	return s.repo.ListAPIKeys(ctx, orgID)
+}
+
+// RevokeAPIKey stops a key from authenticating any further request
+func (s *APIKeyService) RevokeAPIKey(ctx context.Context, userID int, orgID int64, keyID int64) error {
+	if _, err := organizationManager(ctx, s.orgRepo, orgID, userID); err != nil {
+		return err
+	}
+
+	audit := &entities.AccessAuditEntry{
+		IDOrganizacion: orgID,
+		Actor:          userID,
+		Accion:         entities.AuditAPIKeyRevoked,
+		Objetivo:       apiKeyTarget(keyID),
+		Fecha:          time.Now().UTC(),
+	}
+	if err := s.repo.RevokeAPIKey(ctx, orgID, keyID, audit); err != nil {
+		return err
+	}
+	s.audit.Record(ctx, entities.AuditAPIKeyRevoked, entities.ResourceAPIKey, strconv.FormatInt(keyID, 10),
+		map[string]bool{"revocada": false}, map[string]bool{"revocada": true})
+	return nil
+}
+
+// Authenticate finds the key by its prefix and compares the hashes in constant time.
+// Unknown, malformed, revoked and expired keys all yield entities.ErrUnauthorized.
+func (s *APIKeyService) Authenticate(ctx context.Context, rawKey string) (*entities.APIKey, error) {
+	parts := strings.Split(strings.TrimSpace(rawKey), "_")
+	if len(parts) != 3 || parts[0] != apiKeyPrefix || parts[1] == "" || parts[2] == "" {
+		return nil, invalidAPIKey()
+	}
+
+	key, err := s.repo.GetAPIKeyByPrefix(ctx, parts[1])
+	if err == entities.ErrNotFound {
+		return nil, invalidAPIKey()
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	hash := hashSecret(strings.TrimSpace(rawKey))
+	if subtle.ConstantTimeCompare([]byte(hash), []byte(key.Hash)) != 1 {
+		return nil, invalidAPIKey()
+	}
+
+	now := time.Now().UTC()
+	if !key.IsUsable(now) {
+		return nil, invalidAPIKey()
+	}
+
+	// Failing to record the last use must not reject a valid key
+	if err := s.repo.TouchAPIKey(ctx, key.ID, now, now.Add(-apiKeyTouchInterval)); err != nil {
+		log.Printf("Error recording use of API key %s: %v", key.Prefijo, err)
+	}
+
+	return key, nil
+}
+
+// hashSecret is the SHA-256 stored for API keys and device tokens
+func hashSecret(secret string) string {
+	sum := sha256.Sum256([]byte(secret))
+	return hex.EncodeToString(sum[:])
+}
+
+// invalidAPIKey does not tell apart unknown, revoked and expired keys
+func invalidAPIKey() error {
+	return fmt.Errorf("invalid API key: %w", entities.ErrUnauthorized)
+}
+
+func randomHex(n int) (string, error) {
+	buf := make([]byte, n)
+	if _, err := rand.Read(buf); err != nil {
+		return "", fmt.Errorf("error generating secret: %w", err)
+	}
+	return hex.EncodeToString(buf), nil
+}
+
+func uniqueStrings(values []string) []string {
+	seen := make(map[string]bool, len(values))
+	unique := make([]string, 0, len(values))
+	for _, v := range values {
+		if !seen[v] {
+			seen[v] = true
+			unique = append(unique, v)
+		}
+	}
+	return unique
+}
+
+func apiKeyTarget(id int64) string {
+	return fmt.Sprintf("api_key:%d", id)
+}
@@ -0,0 +1,130 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"log"
+	"time"
+
+	"hex_go/internal/application/validation"
+	"hex_go/internal/domain/entities"
+	"hex_go/internal/domain/ports"
+)
+
+// auditVerifyBatch is how many entries are read at a time when checking the chain
+const auditVerifyBatch = 500
+
+type AuditService struct {
+	repo ports.AuditRepositoryPort
+}
+
+func NewAuditService(repo ports.AuditRepositoryPort) ports.AuditServicePort {
+	return &AuditService{
+		repo: repo,
+	}
+}
+
+// Record appends a change to the audit log. The actor is the principal of ctx, or the
+// system when there is none. The change has already happened, so a failure is only logged.
+func (s *AuditService) Record(ctx context.Context, action, resource, resourceID string, before, after interface{}) {
+	tipoActor, idActor := entities.PrincipalFromContext(ctx).Actor()
+	info := entities.RequestInfoFromContext(ctx)
+
+	entry := &entities.AuditEntry{
+		TipoActor:   tipoActor,
+		IDActor:     idActor,
+		Accion:      action,
+		Recurso:     resource,
+		IDRecurso:   resourceID,
+		Antes:       auditSnapshot(before),
+		Despues:     auditSnapshot(after),
+		IDSolicitud: info.ID,
+		IP:          info.IP,
+		// Stored with microsecond precision, so hash what the database will return
+		Fecha: time.Now().UTC().Truncate(time.Microsecond),
+	}
+
+	// A client hanging up right after the change must not lose its audit entry
+	if err := s.repo.AppendAuditEntry(detach(ctx), entry); err != nil {
+		log.Printf("Error recording audit entry %s %s:%s: %v", action, resource, resourceID, err)
+	}
+}
+
+// ListEntries returns the audit entries matching a query, newest first
+func (s *AuditService) ListEntries(ctx context.Context, query *entities.AuditQuery) ([]*entities.AuditEntry, error) {
+	if err := validation.ValidateAuditQuery(query); err != nil {
+		return nil, err
+	}
+	return s.repo.ListAuditEntries(ctx, query)
+}
+
+// VerifyChain recomputes every hash from the first entry on. It reports the first entry
+// whose content or link no longer matches, and a chain whose newest entries were removed.
+func (s *AuditService) VerifyChain(ctx context.Context) (*entities.AuditVerification, error) {
+	headID, headHash, err := s.repo.GetAuditChainHead(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &entities.AuditVerification{Valida: true}
+	var lastID int64
+	prevHash := ""
+	for {
+		entries, err := s.repo.ListAuditChain(ctx, lastID, auditVerifyBatch)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, entry := range entries {
+			if entry.HashAnterior != prevHash || entry.ComputeHash() != entry.Hash {
+				return brokenChain(result, entry.ID), nil
+			}
+			prevHash = entry.Hash
+			lastID = entry.ID
+			result.Revisadas++
+		}
+
+		if len(entries) < auditVerifyBatch {
+			break
+		}
+	}
+
+	if lastID != headID || prevHash != headHash {
+		return brokenChain(result, headID), nil
+	}
+
+	result.UltimoHash = prevHash
+	return result, nil
+}
+
+func brokenChain(result *entities.AuditVerification, id int64) *entities.AuditVerification {
+	result.Valida = false
+	result.IDRota = &id
+	return result
+}
+
+// auditSnapshot stores a value as JSON, leaving nil values empty
+func auditSnapshot(value interface{}) json.RawMessage {
+	if value == nil {
+		return nil
+	}
+	data, err := json.Marshal(value)
+	if err != nil {
+		log.Printf("Error encoding audit snapshot: %v", err)
+		return nil
+	}
+	return data
+}
+
+// detachedContext keeps the values of a context but not its cancellation or deadline
+type detachedContext struct {
+	context.Context
+}
+
+func (detachedContext) Deadline() (time.Time, bool) { return time.Time{}, false }
+func (detachedContext) Done() <-chan struct{}       { return nil }
+func (detachedContext) Err() error                  { return nil }
+
+func detach(ctx context.Context) context.Context {
+	return detachedContext{ctx}
+}
@@ -0,0 +1,178 @@
+package services
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"hex_go/internal/application/validation"
+	"hex_go/internal/domain/entities"
+	"hex_go/internal/domain/ports"
+)
+
+// CalibrationService converts the raw values of the gas sensors into concentrations with
+// a calibration per device and sensor, learned from the readings themselves
+type CalibrationService struct {
+	repo       ports.CalibrationRepositoryPort
+	deviceRepo ports.DeviceRepositoryPort
+	audit      ports.AuditServicePort
+	burnIn     time.Duration
+	drift      float64
+	thresholds map[string]float64
+}
+
+// NewCalibrationService creates the calibration service. A sensor learns its baseline
+// for burnIn after its first reading or a reset; afterwards readings below the alarm
+// threshold of their sensor type, in ppm, move the baseline by the drift fraction.
+func NewCalibrationService(repo ports.CalibrationRepositoryPort, deviceRepo ports.DeviceRepositoryPort, audit ports.AuditServicePort, burnIn time.Duration, drift float64, thresholds map[string]float64) *CalibrationService {
+	return &CalibrationService{
+		repo:       repo,
+		deviceRepo: deviceRepo,
+		audit:      audit,
+		burnIn:     burnIn,
+		drift:      drift,
+		thresholds: thresholds,
+	}
+}
+
+// CalibrateReading sets the concentration of a gas reading once its sensor has learned
+// its baseline, and reports whether the reading reaches the alarm threshold. Readings
+// of sensors still learning, or whose calibration cannot be read, are alarms as before.
+func (s *CalibrationService) CalibrateReading(ctx context.Context, reading *entities.SensorReading) bool {
+	if !entities.IsCalibratedSensor(reading.Sensor) {
+		return true
+	}
+
+	calibration, err := s.calibration(ctx, reading.NumeroSerie, reading.Sensor)
+	if err != nil {
+		log.Printf("Error fetching %s calibration of device %s: %v", reading.Sensor, reading.NumeroSerie, err)
+		return true
+	}
+
+	now := time.Now().UTC()
+	seen := calibration.Muestras
+	alarm := true
+	if calibration.Learning(now) {
+		calibration.Learn(reading.Estado, now)
+	} else {
+		ppm, ok := calibration.PPM(reading.Estado)
+		if !ok {
+			// Every sample of the burn-in was saturated, so there is no clean air to
+			// compare with. The baseline is learned again meanwhile.
+			s.relearn(ctx, calibration, now)
+			return true
+		}
+		reading.PPM = &ppm
+		if threshold, ok := s.thresholds[reading.Sensor]; ok && ppm < threshold {
+			alarm = false
+		}
+		if alarm || s.drift <= 0 {
+			// A gas reading is no clean air for the baseline to follow
+			return alarm
+		}
+		calibration.Drift(reading.Estado, now, s.drift)
+	}
+
+	// A sample lost to a concurrent reading of the same sensor makes no difference
+	if err := s.repo.UpdateCalibration(ctx, calibration, seen); err != nil && err != entities.ErrConflict {
+		log.Printf("Error updating %s calibration of device %s: %v", reading.Sensor, reading.NumeroSerie, err)
+	}
+	return alarm
+}
+
+// relearn restarts the burn-in of a calibration without a usable baseline, keeping its
+// offset and gain
+func (s *CalibrationService) relearn(ctx context.Context, calibration *entities.SensorCalibration, now time.Time) {
+	log.Printf("%s calibration of device %s has no baseline, learning it again", calibration.Sensor, calibration.NumeroSerie)
+	calibration.Restart(calibration.Offset, calibration.Ganancia, now, s.burnIn)
+	if err := s.repo.SaveCalibration(ctx, calibration); err != nil {
+		log.Printf("Error restarting %s calibration of device %s: %v", calibration.Sensor, calibration.NumeroSerie, err)
+	}
+}
+
+// ListCalibrations returns the calibration of every gas sensor of a device seen so far
+func (s *CalibrationService) ListCalibrations(ctx context.Context, userID int, numeroSerie string) ([]*entities.SensorCalibration, error) {
+	if err := s.checkOwner(ctx, userID, numeroSerie); err != nil {
+		return nil, err
+	}
+	return s.repo.ListDeviceCalibrations(ctx, numeroSerie)
+}
+
+// UpdateCalibration sets the offset and gain of a sensor. The baseline is learned again
+// with them, since it is measured on the corrected values.
+func (s *CalibrationService) UpdateCalibration(ctx context.Context, userID int, numeroSerie, sensor string, req *entities.CalibrationRequest) (*entities.SensorCalibration, error) {
+	if err := validation.ValidateCalibration(sensor, req); err != nil {
+		return nil, err
+	}
+	return s.restart(ctx, userID, numeroSerie, sensor, req.Offset, req.Ganancia, entities.AuditCalibrationUpdated)
+}
+
+// ResetCalibration removes the offset and gain of a sensor and learns its baseline again,
+// such as after replacing the sensor
+func (s *CalibrationService) ResetCalibration(ctx context.Context, userID int, numeroSerie, sensor string) (*entities.SensorCalibration, error) {
+	if err := validation.ValidateCalibrationSensor(sensor); err != nil {
+		return nil, err
+	}
+	return s.restart(ctx, userID, numeroSerie, sensor, 0, 1, entities.AuditCalibrationReset)
+}
+
+// restart stores a calibration that learns its baseline from now on
+func (s *CalibrationService) restart(ctx context.Context, userID int, numeroSerie, sensor string, offset, gain float64, action string) (*entities.SensorCalibration, error) {
+	if err := s.checkOwner(ctx, userID, numeroSerie); err != nil {
+		return nil, err
+	}
+
+	var before *entities.SensorCalibration
+	calibration, err := s.repo.GetCalibration(ctx, numeroSerie, sensor)
+	switch {
+	case err == entities.ErrNotFound:
+		calibration = &entities.SensorCalibration{NumeroSerie: numeroSerie, Sensor: sensor}
+	case err != nil:
+		return nil, err
+	default:
+		previous := *calibration
+		before = &previous
+	}
+
+	calibration.Restart(offset, gain, time.Now().UTC(), s.burnIn)
+	if err := s.repo.SaveCalibration(ctx, calibration); err != nil {
+		return nil, err
+	}
+	s.audit.Record(ctx, action, entities.ResourceCalibration, numeroSerie+"/"+sensor, before, calibration)
+
+	return calibration, nil
+}
+
+// calibration returns the calibration of a sensor, starting one on its first reading
+func (s *CalibrationService) calibration(ctx context.Context, numeroSerie, sensor string) (*entities.SensorCalibration, error) {
+	calibration, err := s.repo.GetCalibration(ctx, numeroSerie, sensor)
+	if err != entities.ErrNotFound {
+		return calibration, err
+	}
+
+	calibration = entities.NewSensorCalibration(numeroSerie, sensor, time.Now().UTC(), s.burnIn)
+	err = s.repo.CreateCalibration(ctx, calibration)
+	if err == entities.ErrConflict {
+		// Another reading started it first
+		return s.repo.GetCalibration(ctx, numeroSerie, sensor)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return calibration, nil
+}
+
+// checkOwner hides the devices of other users
+func (s *CalibrationService) checkOwner(ctx context.Context, userID int, numeroSerie string) error {
+	device, err := s.deviceRepo.GetDevice(ctx, numeroSerie)
+	if err != nil {
+		return err
+	}
+	if device.IDUser == nil || *device.IDUser != userID {
+		return entities.ErrNotFound
+	}
+	return nil
+}
+
+// Verify interface implementation
+var _ ports.CalibrationServicePort = (*CalibrationService)(nil)
@@ -0,0 +1,96 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	"hex_go/internal/domain/entities"
+	"hex_go/internal/domain/ports"
+)
+
+// DeviceMonitor periodically looks for devices that stopped sending heartbeats and
+// readings, marks them offline and raises a device offline alert for each of them
+type DeviceMonitor struct {
+	repo         ports.DeviceRepositoryPort
+	alerts       ports.AlertServicePort
+	audit        ports.AuditServicePort
+	offlineAfter time.Duration
+	interval     time.Duration
+}
+
+func NewDeviceMonitor(repo ports.DeviceRepositoryPort, alerts ports.AlertServicePort, audit ports.AuditServicePort, offlineAfter, interval time.Duration) *DeviceMonitor {
+	return &DeviceMonitor{
+		repo:         repo,
+		alerts:       alerts,
+		audit:        audit,
+		offlineAfter: offlineAfter,
+		interval:     interval,
+	}
+}
+
+// Run sweeps every interval until ctx is cancelled
+func (m *DeviceMonitor) Run(ctx context.Context) {
+	log.Printf("Device monitor started: offline after %s, sweeping every %s", m.offlineAfter, m.interval)
+
+	ticker := time.NewTicker(m.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			log.Printf("Device monitor stopped")
+			return
+		case <-ticker.C:
+			if err := m.Sweep(ctx); err != nil {
+				log.Printf("Error sweeping offline devices: %v", err)
+			}
+		}
+	}
+}
+
+// Sweep marks every device not seen within offlineAfter as offline and raises its alert
+func (m *DeviceMonitor) Sweep(ctx context.Context) error {
+	lastSeenBefore := time.Now().UTC().Add(-m.offlineAfter)
+
+	devices, err := m.repo.ListOfflineCandidates(ctx, lastSeenBefore)
+	if err != nil {
+		return err
+	}
+
+	for _, device := range devices {
+		changed, err := m.repo.MarkDeviceOffline(ctx, device.NumeroSerie, lastSeenBefore)
+		if err != nil {
+			return err
+		}
+		if !changed {
+			// Another sweeper got there first or the device reported in meanwhile
+			continue
+		}
+
+		log.Printf("Device %s is offline, last seen %s", device.NumeroSerie, device.UltimaConexion.Format(time.RFC3339))
+		m.audit.Record(ctx, entities.AuditDeviceOffline, entities.ResourceDevice, device.NumeroSerie,
+			map[string]bool{"en_linea": true}, map[string]bool{"en_linea": false})
+
+		alert := &entities.Alert{
+			NumeroSerie: device.NumeroSerie,
+			Tipo:        entities.AlertTypeDeviceOffline,
+			Severidad:   entities.SeverityCritical,
+			Mensaje:     offlineMessage(device),
+		}
+		if err := m.alerts.RaiseAlert(ctx, alert); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func offlineMessage(device *entities.Device) string {
+	name := device.Nombre
+	if name == "" {
+		name = device.NumeroSerie
+	}
+	return fmt.Sprintf("Device %s has not reported since %s", name, device.UltimaConexion.Format(time.RFC3339))
+}
@@ -0,0 +1,256 @@
+package services
+
+import (
+	"context"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"fmt"
+	"strings"
+	"time"
+
+	"hex_go/internal/application/authorization"
+	"hex_go/internal/application/validation"
+	"hex_go/internal/domain/entities"
+	"hex_go/internal/domain/ports"
+)
+
+// deviceTokenPrefix starts every device token so leaked tokens are easy to recognise
+const deviceTokenPrefix = "sfd"
+
+type DeviceService struct {
+	repo  ports.DeviceRepositoryPort
+	audit ports.AuditServicePort
+}
+
+func NewDeviceService(repo ports.DeviceRepositoryPort, audit ports.AuditServicePort) ports.DeviceServicePort {
+	return &DeviceService{
+		repo:  repo,
+		audit: audit,
+	}
+}
+
+// ClaimDevice registers an unclaimed board for a user after checking its claim code and
+// issues the token the board authenticates with. The raw token is only part of the
+// response; the repository keeps its SHA-256.
+func (s *DeviceService) ClaimDevice(ctx context.Context, userID int, req *entities.ClaimDeviceRequest) (*entities.ClaimedDevice, error) {
+	if err := validation.ValidateClaimDevice(req); err != nil {
+		return nil, err
+	}
+
+	device, err := s.repo.GetDevice(ctx, req.NumeroSerie)
+	if err == entities.ErrNotFound {
+		return nil, invalidClaimCode()
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if device.CodigoReclamoHash == "" || !claimCodeMatches(req.CodigoReclamo, device.CodigoReclamoHash) {
+		return nil, invalidClaimCode()
+	}
+	if device.IDUser != nil {
+		return nil, fmt.Errorf("device %s is already registered: %w", req.NumeroSerie, entities.ErrConflict)
+	}
+
+	now := time.Now().UTC()
+	device.IDUser = &userID
+	device.Nombre = strings.TrimSpace(req.Nombre)
+	device.Ubicacion = strings.TrimSpace(req.Ubicacion)
+	device.SensoresInstalados = req.SensoresInstalados
+	if device.SensoresInstalados == nil {
+		device.SensoresInstalados = []string{}
+	}
+	device.VersionFirmware = req.VersionFirmware
+	device.ZonaHoraria = req.ZonaHoraria
+	device.FechaRegistro = &now
+	token, err := newDeviceToken()
+	if err != nil {
+		return nil, err
+	}
+	device.TokenHash = hashSecret(token)
+
+	if err := s.repo.ClaimDevice(ctx, device); err != nil {
+		if err == entities.ErrConflict {
+			return nil, fmt.Errorf("device %s is already registered: %w", req.NumeroSerie, err)
+		}
+		return nil, err
+	}
+	s.audit.Record(ctx, entities.AuditDeviceClaimed, entities.ResourceDevice, device.NumeroSerie, nil, device)
+
+	return &entities.ClaimedDevice{Device: device, Token: token}, nil
+}
+
+// RotateDeviceToken issues a new token for a device owned by the user. The previous
+// token stops working at once.
+func (s *DeviceService) RotateDeviceToken(ctx context.Context, userID int, numeroSerie string) (*entities.ClaimedDevice, error) {
+	device, err := s.ownedDevice(ctx, userID, numeroSerie)
+	if err != nil {
+		return nil, err
+	}
+
+	token, err := newDeviceToken()
+	if err != nil {
+		return nil, err
+	}
+	device.TokenHash = hashSecret(token)
+	if err := s.repo.SetDeviceToken(ctx, numeroSerie, userID, device.TokenHash); err != nil {
+		return nil, err
+	}
+	s.audit.Record(ctx, entities.AuditDeviceTokenRotated, entities.ResourceDevice, numeroSerie, nil, nil)
+
+	return &entities.ClaimedDevice{Device: device, Token: token}, nil
+}
+
+// AuthenticateDevice checks the token a board sent with its serial number, comparing the
+// hashes in constant time. Unknown boards, boards without a token and wrong tokens all
+// yield entities.ErrUnauthorized.
+func (s *DeviceService) AuthenticateDevice(ctx context.Context, numeroSerie, token string) error {
+	device, err := s.repo.GetDevice(ctx, numeroSerie)
+	if err == entities.ErrNotFound {
+		return invalidDeviceToken()
+	}
+	if err != nil {
+		return err
+	}
+
+	hash := hashSecret(strings.TrimSpace(token))
+	if device.TokenHash == "" || subtle.ConstantTimeCompare([]byte(hash), []byte(device.TokenHash)) != 1 {
+		return invalidDeviceToken()
+	}
+	return nil
+}
+
+// GetDevice returns a device owned by the user
+func (s *DeviceService) GetDevice(ctx context.Context, userID int, numeroSerie string) (*entities.Device, error) {
+	return s.ownedDevice(ctx, userID, numeroSerie)
+}
+
+// ListDevices returns every device owned by the user
+func (s *DeviceService) ListDevices(ctx context.Context, userID int) ([]*entities.Device, error) {
+	return s.repo.ListUserDevices(ctx, userID)
+}
+
+// UpdateDevice renames a device or changes its location, sensors, firmware or timezone
+func (s *DeviceService) UpdateDevice(ctx context.Context, userID int, numeroSerie string, req *entities.UpdateDeviceRequest) (*entities.Device, error) {
+	if err := validation.ValidateUpdateDevice(req); err != nil {
+		return nil, err
+	}
+
+	device, err := s.ownedDevice(ctx, userID, numeroSerie)
+	if err != nil {
+		return nil, err
+	}
+	before := *device
+
+	if req.Nombre != nil {
+		device.Nombre = strings.TrimSpace(*req.Nombre)
+	}
+	if req.Ubicacion != nil {
+		device.Ubicacion = strings.TrimSpace(*req.Ubicacion)
+	}
+	if req.SensoresInstalados != nil {
+		device.SensoresInstalados = *req.SensoresInstalados
+	}
+	if req.VersionFirmware != nil {
+		device.VersionFirmware = *req.VersionFirmware
+	}
+	if req.ZonaHoraria != nil {
+		device.ZonaHoraria = *req.ZonaHoraria
+	}
+
+	if err := s.repo.UpdateDevice(ctx, device); err != nil {
+		return nil, err
+	}
+	s.audit.Record(ctx, entities.AuditDeviceUpdated, entities.ResourceDevice, numeroSerie, &before, device)
+
+	return device, nil
+}
+
+// TransferDevice hands a device over to another user
+func (s *DeviceService) TransferDevice(ctx context.Context, userID int, numeroSerie string, req *entities.TransferDeviceRequest) error {
+	if err := validation.ValidateTransferDevice(req); err != nil {
+		return err
+	}
+	if req.NuevoIDUser == userID {
+		return &entities.ValidationError{Field: "nuevo_id_user", Message: "device already belongs to this user"}
+	}
+
+	if _, err := s.ownedDevice(ctx, userID, numeroSerie); err != nil {
+		return err
+	}
+
+	if err := s.repo.TransferDevice(ctx, numeroSerie, userID, req.NuevoIDUser); err != nil {
+		return err
+	}
+	s.audit.Record(ctx, entities.AuditDeviceTransferred, entities.ResourceDevice, numeroSerie,
+		map[string]int{"id_user": userID}, map[string]int{"id_user": req.NuevoIDUser})
+	return nil
+}
+
+// DeleteDevice unregisters a device so it can be claimed again with its claim code
+func (s *DeviceService) DeleteDevice(ctx context.Context, userID int, numeroSerie string) error {
+	device, err := s.ownedDevice(ctx, userID, numeroSerie)
+	if err != nil {
+		return err
+	}
+
+	if err := s.repo.ReleaseDevice(ctx, numeroSerie, userID); err != nil {
+		return err
+	}
+	s.audit.Record(ctx, entities.AuditDeviceReleased, entities.ResourceDevice, numeroSerie, device, nil)
+	return nil
+}
+
+// RecordHeartbeat stores the keep-alive of a board. Unknown boards are rejected.
+func (s *DeviceService) RecordHeartbeat(ctx context.Context, numeroSerie string, req *entities.HeartbeatRequest) error {
+	if err := validation.ValidateHeartbeat(numeroSerie, req); err != nil {
+		return err
+	}
+	if err := authorization.CheckDeviceCaller(entities.PrincipalFromContext(ctx), numeroSerie); err != nil {
+		return err
+	}
+
+	if _, err := s.repo.GetDevice(ctx, numeroSerie); err != nil {
+		return err
+	}
+
+	return s.repo.RecordHeartbeat(ctx, numeroSerie, time.Now().UTC(), req.VersionFirmware)
+}
+
+// ownedDevice loads a device and hides it from users that do not own it
+func (s *DeviceService) ownedDevice(ctx context.Context, userID int, numeroSerie string) (*entities.Device, error) {
+	device, err := s.repo.GetDevice(ctx, numeroSerie)
+	if err != nil {
+		return nil, err
+	}
+	if device.IDUser == nil || *device.IDUser != userID {
+		return nil, entities.ErrNotFound
+	}
+	return device, nil
+}
+
+// claimCodeMatches compares a claim code with the stored SHA-256 hash in constant time
+func claimCodeMatches(code, storedHash string) bool {
+	sum := sha256.Sum256([]byte(strings.TrimSpace(code)))
+	return subtle.ConstantTimeCompare([]byte(hex.EncodeToString(sum[:])), []byte(strings.ToLower(storedHash))) == 1
+}
+
+// newDeviceToken generates the secret a board authenticates with
+func newDeviceToken() (string, error) {
+	secreto, err := randomHex(24)
+	if err != nil {
+		return "", err
+	}
+	return deviceTokenPrefix + "_" + secreto, nil
+}
+
+// invalidDeviceToken does not tell apart unknown boards and wrong tokens
+func invalidDeviceToken() error {
+	return fmt.Errorf("invalid device token: %w", entities.ErrUnauthorized)
+}
+
+// invalidClaimCode does not tell apart unknown boards and wrong codes
+func invalidClaimCode() error {
+	return &entities.ValidationError{Field: "codigo_reclamo", Message: "does not match this device"}
+}
@@ -0,0 +1,127 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"hex_go/internal/domain/entities"
+	"hex_go/internal/domain/ports"
+)
+
+// memoryDevices keeps the boards in memory
+type memoryDevices struct {
+	ports.DeviceRepositoryPort
+	devices map[string]*entities.Device
+}
+
+func (r *memoryDevices) GetDevice(ctx context.Context, numeroSerie string) (*entities.Device, error) {
+	device, ok := r.devices[numeroSerie]
+	if !ok {
+		return nil, entities.ErrNotFound
+	}
+	copied := *device
+	return &copied, nil
+}
+
+func (r *memoryDevices) ClaimDevice(ctx context.Context, device *entities.Device) error {
+	if r.devices[device.NumeroSerie].IDUser != nil {
+		return entities.ErrConflict
+	}
+	copied := *device
+	r.devices[device.NumeroSerie] = &copied
+	return nil
+}
+
+func (r *memoryDevices) SetDeviceToken(ctx context.Context, numeroSerie string, userID int, tokenHash string) error {
+	device := r.devices[numeroSerie]
+	if device.IDUser == nil || *device.IDUser != userID {
+		return entities.ErrConflict
+	}
+	device.TokenHash = tokenHash
+	return nil
+}
+
+// newClaimableDevices returns a repository with one unclaimed board whose claim code is
+// "1234-5678"
+func newClaimableDevices() *memoryDevices {
+	return &memoryDevices{devices: map[string]*entities.Device{
+		"ESP32-0001": {NumeroSerie: "ESP32-0001", CodigoReclamoHash: hashSecret("1234-5678")},
+	}}
+}
+
+func TestDeviceTokens(t *testing.T) {
+	repo := newClaimableDevices()
+	service := NewDeviceService(repo, &recordingAudit{})
+	ctx := context.Background()
+
+	claimed, err := service.ClaimDevice(ctx, 3, &entities.ClaimDeviceRequest{
+		NumeroSerie:   "ESP32-0001",
+		CodigoReclamo: "1234-5678",
+		Nombre:        "Cocina",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasPrefix(claimed.Token, deviceTokenPrefix+"_") {
+		t.Fatalf("token = %q, want the %s prefix", claimed.Token, deviceTokenPrefix)
+	}
+	if repo.devices["ESP32-0001"].TokenHash == claimed.Token {
+		t.Fatal("the raw token was stored")
+	}
+
+	tests := []struct {
+		name        string
+		numeroSerie string
+		token       string
+		wantErr     bool
+	}{
+		{"issued token", "ESP32-0001", claimed.Token, false},
+		{"no token", "ESP32-0001", "", true},
+		{"wrong token", "ESP32-0001", deviceTokenPrefix + "_0000", true},
+		{"unknown board", "ESP32-0002", claimed.Token, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := service.AuthenticateDevice(ctx, tt.numeroSerie, tt.token)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("AuthenticateDevice() error = %v, want error %v", err, tt.wantErr)
+			}
+			if err != nil && !errors.Is(err, entities.ErrUnauthorized) {
+				t.Errorf("error = %v, want entities.ErrUnauthorized", err)
+			}
+		})
+	}
+}
+
+func TestRotateDeviceToken(t *testing.T) {
+	repo := newClaimableDevices()
+	service := NewDeviceService(repo, &recordingAudit{})
+	ctx := context.Background()
+
+	claimed, err := service.ClaimDevice(ctx, 3, &entities.ClaimDeviceRequest{
+		NumeroSerie:   "ESP32-0001",
+		CodigoReclamo: "1234-5678",
+		Nombre:        "Cocina",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := service.RotateDeviceToken(ctx, 4, "ESP32-0001"); !errors.Is(err, entities.ErrNotFound) {
+		t.Fatalf("rotating the token of another user's device: error = %v, want entities.ErrNotFound", err)
+	}
+
+	rotated, err := service.RotateDeviceToken(ctx, 3, "ESP32-0001")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := service.AuthenticateDevice(ctx, "ESP32-0001", claimed.Token); err == nil {
+		t.Error("the previous token still authenticates")
+	}
+	if err := service.AuthenticateDevice(ctx, "ESP32-0001", rotated.Token); err != nil {
+		t.Errorf("the new token does not authenticate: %v", err)
+	}
+}
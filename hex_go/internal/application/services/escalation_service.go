@@ -0,0 +1,346 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"log"
+	"strconv"
+	"strings"
+	"time"
+
+	"hex_go/internal/application/validation"
+	"hex_go/internal/domain/entities"
+	"hex_go/internal/domain/ports"
+)
+
+const (
+	// escalationBatch is how many due escalations are picked up per round
+	escalationBatch = 50
+	// escalationSendTimeout bounds the notification of one escalation step
+	escalationSendTimeout = 30 * time.Second
+)
+
+// EscalationService manages escalation policies and notifies their steps while alerts
+// stay unacknowledged. Escalations are stored with the time of their next step, so a
+// restart resumes them where they stopped.
+type EscalationService struct {
+	repo             ports.EscalationRepositoryPort
+	alertRepo        ports.AlertRepositoryPort
+	deviceRepo       ports.DeviceRepositoryPort
+	locationRepo     ports.LocationRepositoryPort
+	notificationRepo ports.NotificationRepositoryPort
+	audit            ports.AuditServicePort
+	notifiers        map[string]ports.NotifierPort
+	pollInterval     time.Duration
+	wake             chan struct{}
+}
+
+// NewEscalationService creates the escalation service. It is also an incident subscriber
+// that starts the policies covering the device of the alert that opened an incident or
+// made it more severe.
+func NewEscalationService(repo ports.EscalationRepositoryPort, alertRepo ports.AlertRepositoryPort, deviceRepo ports.DeviceRepositoryPort, locationRepo ports.LocationRepositoryPort, notificationRepo ports.NotificationRepositoryPort, audit ports.AuditServicePort, pollInterval time.Duration, notifiers ...ports.NotifierPort) *EscalationService {
+	return &EscalationService{
+		repo:             repo,
+		alertRepo:        alertRepo,
+		deviceRepo:       deviceRepo,
+		locationRepo:     locationRepo,
+		notificationRepo: notificationRepo,
+		audit:            audit,
+		notifiers:        notifiersByChannel(notifiers),
+		pollInterval:     pollInterval,
+		wake:             make(chan struct{}, 1),
+	}
+}
+
+// CreatePolicy registers an escalation policy for one of the user's devices or locations
+func (s *EscalationService) CreatePolicy(ctx context.Context, userID int, req *entities.EscalationPolicyRequest) (*entities.EscalationPolicy, error) {
+	if err := validation.ValidateEscalationPolicy(req); err != nil {
+		return nil, err
+	}
+	if err := checkOwnedTarget(ctx, s.deviceRepo, s.locationRepo, userID, req.NumeroSerie, req.IDUbicacion); err != nil {
+		return nil, err
+	}
+
+	policy := &entities.EscalationPolicy{
+		IDUser:        userID,
+		Activa:        true,
+		FechaCreacion: time.Now().UTC(),
+	}
+	applyPolicyRequest(policy, req)
+	if err := s.repo.CreatePolicy(ctx, policy); err != nil {
+		return nil, err
+	}
+	s.audit.Record(ctx, entities.AuditEscalationPolicyCreated, entities.ResourceEscalationPolicy, policyResourceID(policy.ID), nil, policy)
+
+	return policy, nil
+}
+
+// ListPolicies returns the escalation policies of the user
+func (s *EscalationService) ListPolicies(ctx context.Context, userID int) ([]*entities.EscalationPolicy, error) {
+	return s.repo.ListUserPolicies(ctx, userID)
+}
+
+// GetPolicy returns an escalation policy of the user
+func (s *EscalationService) GetPolicy(ctx context.Context, userID int, id int64) (*entities.EscalationPolicy, error) {
+	return s.ownedPolicy(ctx, userID, id)
+}
+
+// UpdatePolicy replaces an escalation policy. Running escalations continue with the new
+// steps from the step they reached.
+func (s *EscalationService) UpdatePolicy(ctx context.Context, userID int, id int64, req *entities.EscalationPolicyRequest) (*entities.EscalationPolicy, error) {
+	if err := validation.ValidateEscalationPolicy(req); err != nil {
+		return nil, err
+	}
+
+	policy, err := s.ownedPolicy(ctx, userID, id)
+	if err != nil {
+		return nil, err
+	}
+	if err := checkOwnedTarget(ctx, s.deviceRepo, s.locationRepo, userID, req.NumeroSerie, req.IDUbicacion); err != nil {
+		return nil, err
+	}
+	before := *policy
+
+	applyPolicyRequest(policy, req)
+	if err := s.repo.UpdatePolicy(ctx, policy); err != nil {
+		return nil, err
+	}
+	s.audit.Record(ctx, entities.AuditEscalationPolicyUpdated, entities.ResourceEscalationPolicy, policyResourceID(id), &before, policy)
+
+	return policy, nil
+}
+
+// DeletePolicy removes an escalation policy and stops its escalations
+func (s *EscalationService) DeletePolicy(ctx context.Context, userID int, id int64) error {
+	policy, err := s.ownedPolicy(ctx, userID, id)
+	if err != nil {
+		return err
+	}
+
+	if err := s.repo.DeletePolicy(ctx, id); err != nil {
+		return err
+	}
+	s.audit.Record(ctx, entities.AuditEscalationPolicyDeleted, entities.ResourceEscalationPolicy, policyResourceID(id), policy, nil)
+	return nil
+}
+
+// ListAlertEscalations returns the escalations of an alert the user can see
+func (s *EscalationService) ListAlertEscalations(ctx context.Context, userID int, alertID int64) ([]*entities.Escalation, error) {
+	if _, _, err := s.alertRepo.GetUserAlert(ctx, userID, alertID); err != nil {
+		return nil, err
+	}
+	return s.repo.ListAlertEscalations(ctx, alertID)
+}
+
+// IncidentRaised starts every active policy covering the device of the alert that
+// escalates its severity. The first step is due its delay after the alert; acknowledging
+// the alert or its incident stops the escalation.
+func (s *EscalationService) IncidentRaised(ctx context.Context, incident *entities.Incident, alert *entities.Alert) {
+	policies, err := s.repo.ListPoliciesForDevice(ctx, alert.NumeroSerie)
+	if err != nil {
+		log.Printf("Error finding escalation policies for alert %d: %v", alert.ID, err)
+		return
+	}
+
+	started := false
+	for _, policy := range policies {
+		if !policy.Escalates(alert.Severidad) || len(policy.Pasos) == 0 {
+			continue
+		}
+
+		first := alert.FechaCreacion.Add(time.Duration(policy.Pasos[0].EsperaMinutos) * time.Minute)
+		escalation := &entities.Escalation{
+			IDAlerta:      alert.ID,
+			IDPolitica:    policy.ID,
+			ProximoPaso:   &first,
+			Estado:        entities.EscalationPending,
+			FechaCreacion: time.Now().UTC(),
+		}
+		if err := s.repo.CreateEscalation(ctx, escalation); err != nil && err != entities.ErrConflict {
+			log.Printf("Error starting escalation policy %d for alert %d: %v", policy.ID, alert.ID, err)
+			continue
+		}
+		started = true
+	}
+
+	if started {
+		s.notify()
+	}
+}
+
+// notify asks the scheduler to look for due steps without waiting for the next poll
+func (s *EscalationService) notify() {
+	select {
+	case s.wake <- struct{}{}:
+	default:
+	}
+}
+
+// Run notifies due escalation steps every poll interval, or when an escalation starts,
+// until ctx is cancelled
+func (s *EscalationService) Run(ctx context.Context) {
+	log.Printf("Escalation scheduler started: polling every %s", s.pollInterval)
+
+	ticker := time.NewTicker(s.pollInterval)
+	defer ticker.Stop()
+
+	for {
+		if err := s.EscalateDue(ctx); err != nil {
+			log.Printf("Error escalating alerts: %v", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			log.Printf("Escalation scheduler stopped")
+			return
+		case <-ticker.C:
+		case <-s.wake:
+		}
+	}
+}
+
+// EscalateDue notifies the next step of every due escalation whose alert is still
+// active, and stops the escalations of acknowledged or resolved alerts
+func (s *EscalationService) EscalateDue(ctx context.Context) error {
+	now := time.Now().UTC()
+	escalations, err := s.repo.ListDueEscalations(ctx, now, escalationBatch)
+	if err != nil {
+		return err
+	}
+
+	for _, escalation := range escalations {
+		alert, err := s.alertRepo.GetAlert(ctx, escalation.IDAlerta)
+		if err != nil {
+			return err
+		}
+		if alert.Estado != entities.AlertStateActive {
+			if err := s.repo.StopEscalations(ctx, alert.ID, now); err != nil {
+				return err
+			}
+			continue
+		}
+
+		policy, err := s.repo.GetPolicy(ctx, escalation.IDPolitica)
+		if errors.Is(err, entities.ErrNotFound) {
+			// Deleted meanwhile; its escalations go with it
+			continue
+		}
+		if err != nil {
+			return err
+		}
+
+		step := escalation.SiguientePaso
+		if !policy.Activa || step >= len(policy.Pasos) {
+			if _, err := s.repo.AdvanceEscalation(ctx, escalation, step, nil); err != nil {
+				return err
+			}
+			continue
+		}
+
+		// Move on before notifying so a crash never notifies the same step twice
+		var next *time.Time
+		if step+1 < len(policy.Pasos) {
+			at := now.Add(time.Duration(policy.Pasos[step+1].EsperaMinutos) * time.Minute)
+			next = &at
+		}
+		claimed, err := s.repo.AdvanceEscalation(ctx, escalation, step+1, next)
+		if err != nil {
+			return err
+		}
+		if !claimed {
+			// Another scheduler is notifying it
+			continue
+		}
+
+		s.notifyStep(ctx, escalation, policy, policy.Pasos[step], alert)
+	}
+
+	return nil
+}
+
+// notifyStep sends the alert to the contact of a step and records the outcome in the
+// notification delivery log
+func (s *EscalationService) notifyStep(ctx context.Context, escalation *entities.Escalation, policy *entities.EscalationPolicy, step *entities.EscalationStep, alert *entities.Alert) {
+	recipient := &entities.NotificationRecipient{
+		IDUser:    policy.IDUser,
+		Canal:     step.Canal,
+		Direccion: step.Direccion,
+		Idioma:    step.Idioma,
+	}
+
+	var sendErr error
+	if notifier, ok := s.notifiers[step.Canal]; ok {
+		sendCtx, cancel := context.WithTimeout(ctx, escalationSendTimeout)
+		notification := describeAlert(sendCtx, s.deviceRepo, s.locationRepo, alert)
+		notification.Destinatario = recipient
+		sendErr = notifier.Notify(sendCtx, notification)
+		cancel()
+	} else {
+		sendErr = errors.New("channel " + step.Canal + " is not configured")
+	}
+	if sendErr != nil {
+		log.Printf("Error escalating alert %d to %s by %s: %v", alert.ID, step.Nombre, step.Canal, sendErr)
+	}
+
+	escalationID := escalation.ID
+	delivery := &entities.NotificationDelivery{
+		IDAlerta:      alert.ID,
+		IDEscalacion:  &escalationID,
+		IDUser:        policy.IDUser,
+		Canal:         step.Canal,
+		Direccion:     step.Direccion,
+		Estado:        entities.DeliveryDelivered,
+		FechaCreacion: time.Now().UTC(),
+	}
+	if sendErr != nil {
+		delivery.Estado = entities.DeliveryFailed
+		delivery.Error = sendErr.Error()
+	}
+	if err := s.notificationRepo.RecordDelivery(ctx, delivery); err != nil {
+		log.Printf("Error recording escalation of alert %d: %v", alert.ID, err)
+	}
+}
+
+// ownedPolicy loads a policy and hides it from other users
+func (s *EscalationService) ownedPolicy(ctx context.Context, userID int, id int64) (*entities.EscalationPolicy, error) {
+	policy, err := s.repo.GetPolicy(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if policy.IDUser != userID {
+		return nil, entities.ErrNotFound
+	}
+	return policy, nil
+}
+
+// applyPolicyRequest copies a validated request into a policy
+func applyPolicyRequest(policy *entities.EscalationPolicy, req *entities.EscalationPolicyRequest) {
+	policy.Nombre = strings.TrimSpace(req.Nombre)
+	policy.NumeroSerie = req.NumeroSerie
+	policy.IDUbicacion = req.IDUbicacion
+	policy.Severidades = uniqueStrings(req.Severidades)
+	if req.Activa != nil {
+		policy.Activa = *req.Activa
+	}
+
+	policy.Pasos = make([]*entities.EscalationStep, len(req.Pasos))
+	for i, step := range req.Pasos {
+		policy.Pasos[i] = &entities.EscalationStep{
+			Nombre:        strings.TrimSpace(step.Nombre),
+			EsperaMinutos: step.EsperaMinutos,
+			Canal:         step.Canal,
+			Direccion:     strings.TrimSpace(step.Direccion),
+			Idioma:        step.Idioma,
+		}
+	}
+}
+
+func policyResourceID(id int64) string {
+	return strconv.FormatInt(id, 10)
+}
+
+// Verify interface implementation
+var (
+	_ ports.EscalationServicePort  = (*EscalationService)(nil)
+	_ ports.IncidentSubscriberPort = (*EscalationService)(nil)
+)
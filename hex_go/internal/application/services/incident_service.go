@@ -0,0 +1,211 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strconv"
+	"time"
+
+	"hex_go/internal/application/validation"
+	"hex_go/internal/domain/entities"
+	"hex_go/internal/domain/ports"
+)
+
+// incidentCorrelationAttempts bounds the retries when alerts of the same room are
+// correlated at once
+const incidentCorrelationAttempts = 3
+
+// IncidentService correlates the alerts raised in the same room within a window into
+// incidents, and tells its subscribers, such as notifications and escalations, about
+// incidents instead of every alert
+type IncidentService struct {
+	repo        ports.IncidentRepositoryPort
+	deviceRepo  ports.DeviceRepositoryPort
+	audit       ports.AuditServicePort
+	window      time.Duration
+	subscribers []ports.IncidentSubscriberPort
+}
+
+// NewIncidentService creates the incident service. It is also an alert subscriber; an
+// alert joins the incident of its room while window has not passed since its latest alert.
+func NewIncidentService(repo ports.IncidentRepositoryPort, deviceRepo ports.DeviceRepositoryPort, audit ports.AuditServicePort, window time.Duration, subscribers ...ports.IncidentSubscriberPort) *IncidentService {
+	return &IncidentService{
+		repo:        repo,
+		deviceRepo:  deviceRepo,
+		audit:       audit,
+		window:      window,
+		subscribers: subscribers,
+	}
+}
+
+// ListIncidents returns the latest incidents with one of the user's devices
+func (s *IncidentService) ListIncidents(ctx context.Context, userID int, filter *entities.IncidentFilter) ([]*entities.Incident, error) {
+	if err := validation.ValidateIncidentFilter(filter); err != nil {
+		return nil, err
+	}
+	return s.repo.ListUserIncidents(ctx, userID, filter)
+}
+
+// GetIncident returns an incident with its alerts and timeline
+func (s *IncidentService) GetIncident(ctx context.Context, userID int, id int64) (*entities.Incident, error) {
+	incident, _, err := s.repo.GetUserIncident(ctx, userID, id)
+	return incident, err
+}
+
+// AcknowledgeIncident records that the user is dealing with an open incident. Its alerts
+// are acknowledged with it, which stops their escalations.
+func (s *IncidentService) AcknowledgeIncident(ctx context.Context, userID int, id int64) (*entities.Incident, error) {
+	incident, err := s.respondableIncident(ctx, userID, id)
+	if err != nil {
+		return nil, err
+	}
+	if incident.Estado != entities.IncidentOpen {
+		return nil, entities.ErrConflict
+	}
+
+	if err := s.repo.AcknowledgeIncident(ctx, id, userID, time.Now().UTC()); err != nil {
+		return nil, err
+	}
+	return s.recordChange(ctx, userID, entities.AuditIncidentAcknowledged, incident)
+}
+
+// ResolveIncident closes an incident and its alerts. Later alerts of the room open a
+// new incident.
+func (s *IncidentService) ResolveIncident(ctx context.Context, userID int, id int64) (*entities.Incident, error) {
+	incident, err := s.respondableIncident(ctx, userID, id)
+	if err != nil {
+		return nil, err
+	}
+	if incident.Estado == entities.IncidentResolved {
+		return nil, entities.ErrConflict
+	}
+
+	if err := s.repo.ResolveIncident(ctx, id, userID, time.Now().UTC()); err != nil {
+		return nil, err
+	}
+	return s.recordChange(ctx, userID, entities.AuditIncidentResolved, incident)
+}
+
+// respondableIncident returns an incident the user may act on, or entities.ErrForbidden when
+// they only view the devices of its alerts
+func (s *IncidentService) respondableIncident(ctx context.Context, userID int, id int64) (*entities.Incident, error) {
+	incident, role, err := s.repo.GetUserIncident(ctx, userID, id)
+	if err != nil {
+		return nil, err
+	}
+	if !entities.CanRespondToAlerts(role) {
+		return nil, entities.ErrForbidden
+	}
+	return incident, nil
+}
+
+// recordChange reads an incident back after a change and audits its state before and after
+func (s *IncidentService) recordChange(ctx context.Context, userID int, action string, before *entities.Incident) (*entities.Incident, error) {
+	after, _, err := s.repo.GetUserIncident(ctx, userID, before.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	// The alerts and timeline are not part of the change
+	beforeState, afterState := *before, *after
+	beforeState.Alertas, beforeState.Eventos = nil, nil
+	afterState.Alertas, afterState.Eventos = nil, nil
+	s.audit.Record(ctx, action, entities.ResourceIncident, strconv.FormatInt(before.ID, 10), &beforeState, &afterState)
+
+	return after, nil
+}
+
+// AlertRaised adds the alert to the incident of its room, opening one when there is none
+// within the window, and tells the subscribers when the incident opens or becomes more
+// severe
+func (s *IncidentService) AlertRaised(ctx context.Context, alert *entities.Alert) {
+	device, err := s.deviceRepo.GetDevice(ctx, alert.NumeroSerie)
+	if err != nil {
+		if err != entities.ErrNotFound {
+			log.Printf("Error fetching device %s of alert %d: %v", alert.NumeroSerie, alert.ID, err)
+		}
+		// Without its location the alert is correlated with its device only
+		device = &entities.Device{NumeroSerie: alert.NumeroSerie}
+	}
+
+	incident, raised, err := s.correlate(ctx, device, alert)
+	if err != nil {
+		log.Printf("Error correlating alert %d into an incident: %v", alert.ID, err)
+		return
+	}
+	alert.IDIncidente = &incident.ID
+
+	if raised {
+		for _, subscriber := range s.subscribers {
+			subscriber.IncidentRaised(ctx, incident, alert)
+		}
+	}
+}
+
+// correlate adds the alert to the incident taking the alerts of its room or opens a new
+// one, and reports whether the incident is new or got more severe
+func (s *IncidentService) correlate(ctx context.Context, device *entities.Device, alert *entities.Alert) (*entities.Incident, bool, error) {
+	key := entities.IncidentKey(device)
+
+	for attempt := 0; attempt < incidentCorrelationAttempts; attempt++ {
+		incident, err := s.repo.FindCorrelating(ctx, key)
+		switch {
+		case err == nil && incident.Correlates(alert.FechaCreacion, s.window):
+			raised := entities.MoreSevere(alert.Severidad, incident.Severidad)
+			if raised {
+				incident.Severidad = alert.Severidad
+			}
+			err := s.repo.AddAlert(ctx, incident, key, alert, raised)
+			if err == entities.ErrConflict {
+				// Resolved or released meanwhile
+				continue
+			}
+			if err != nil {
+				return nil, false, err
+			}
+			incident.TotalAlertas++
+			if alert.FechaCreacion.After(incident.FechaUltima) {
+				incident.FechaUltima = alert.FechaCreacion
+			}
+			return incident, raised, nil
+		case err == nil:
+			// The room has been quiet for longer than the window; the incident stays
+			// open for the user but takes no more alerts
+			if err := s.repo.ReleaseKey(ctx, incident.ID, key); err != nil {
+				return nil, false, err
+			}
+		case err != entities.ErrNotFound:
+			return nil, false, err
+		}
+
+		incident = &entities.Incident{
+			IDUbicacion:  device.IDUbicacion,
+			Severidad:    alert.Severidad,
+			Estado:       entities.IncidentOpen,
+			TotalAlertas: 1,
+			FechaInicio:  alert.FechaCreacion,
+			FechaUltima:  alert.FechaCreacion,
+		}
+		if device.IDUbicacion == nil {
+			incident.NumeroSerie = &device.NumeroSerie
+		}
+		err = s.repo.CreateIncident(ctx, incident, key, alert)
+		if err == entities.ErrConflict {
+			// Another alert of the room opened one first
+			continue
+		}
+		if err != nil {
+			return nil, false, err
+		}
+		return incident, true, nil
+	}
+
+	return nil, false, fmt.Errorf("incident of %s kept changing", key)
+}
+
+// Verify interface implementation
+var (
+	_ ports.IncidentServicePort = (*IncidentService)(nil)
+	_ ports.AlertSubscriberPort = (*IncidentService)(nil)
+)
@@ -0,0 +1,204 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"hex_go/internal/application/validation"
+	"hex_go/internal/domain/entities"
+	"hex_go/internal/domain/ports"
+)
+
+type LocationService struct {
+	repo       ports.LocationRepositoryPort
+	deviceRepo ports.DeviceRepositoryPort
+	audit      ports.AuditServicePort
+}
+
+func NewLocationService(repo ports.LocationRepositoryPort, deviceRepo ports.DeviceRepositoryPort, audit ports.AuditServicePort) ports.LocationServicePort {
+	return &LocationService{
+		repo:       repo,
+		deviceRepo: deviceRepo,
+		audit:      audit,
+	}
+}
+
+// CreateLocation adds a site, or a building, floor or room below a location of the level above
+func (s *LocationService) CreateLocation(ctx context.Context, userID int, req *entities.CreateLocationRequest) (*entities.Location, error) {
+	if err := validation.ValidateCreateLocation(req); err != nil {
+		return nil, err
+	}
+
+	if req.IDPadre != nil {
+		parent, err := s.ownedLocation(ctx, userID, *req.IDPadre)
+		if err != nil {
+			if err == entities.ErrNotFound {
+				return nil, &entities.ValidationError{Field: "id_padre", Message: "location not found"}
+			}
+			return nil, err
+		}
+		if expected := entities.ParentLevel(req.Nivel); parent.Nivel != expected {
+			return nil, &entities.ValidationError{
+				Field:   "id_padre",
+				Message: fmt.Sprintf("a %s must be inside a %s, not a %s", req.Nivel, expected, parent.Nivel),
+			}
+		}
+	}
+
+	location := &entities.Location{
+		IDPadre:   req.IDPadre,
+		Nivel:     req.Nivel,
+		Nombre:    strings.TrimSpace(req.Nombre),
+		Direccion: strings.TrimSpace(req.Direccion),
+		IDUser:    userID,
+	}
+	if err := s.repo.CreateLocation(ctx, location); err != nil {
+		return nil, err
+	}
+	s.audit.Record(ctx, entities.AuditLocationCreated, entities.ResourceLocation, locationResourceID(location.ID), nil, location)
+
+	return location, nil
+}
+
+// GetLocation returns a location owned by the user
+func (s *LocationService) GetLocation(ctx context.Context, userID int, id int64) (*entities.Location, error) {
+	return s.ownedLocation(ctx, userID, id)
+}
+
+// ListLocations returns every location of the user, parents before their children
+func (s *LocationService) ListLocations(ctx context.Context, userID int) ([]*entities.Location, error) {
+	return s.repo.ListUserLocations(ctx, userID)
+}
+
+// UpdateLocation renames a location or changes its address
+func (s *LocationService) UpdateLocation(ctx context.Context, userID int, id int64, req *entities.UpdateLocationRequest) (*entities.Location, error) {
+	if err := validation.ValidateUpdateLocation(req); err != nil {
+		return nil, err
+	}
+
+	location, err := s.ownedLocation(ctx, userID, id)
+	if err != nil {
+		return nil, err
+	}
+	before := *location
+
+	if req.Nombre != nil {
+		location.Nombre = strings.TrimSpace(*req.Nombre)
+	}
+	if req.Direccion != nil {
+		location.Direccion = strings.TrimSpace(*req.Direccion)
+	}
+
+	if err := s.repo.UpdateLocation(ctx, location); err != nil {
+		return nil, err
+	}
+	s.audit.Record(ctx, entities.AuditLocationUpdated, entities.ResourceLocation, locationResourceID(id), &before, location)
+
+	return location, nil
+}
+
+// DeleteLocation removes an empty location. Locations that still contain other
+// locations or devices are rejected with entities.ErrConflict.
+func (s *LocationService) DeleteLocation(ctx context.Context, userID int, id int64) error {
+	location, err := s.ownedLocation(ctx, userID, id)
+	if err != nil {
+		return err
+	}
+
+	children, devices, err := s.repo.CountLocationContents(ctx, id)
+	if err != nil {
+		return err
+	}
+	if children > 0 || devices > 0 {
+		return fmt.Errorf("location %d still contains %d locations and %d devices: %w", id, children, devices, entities.ErrConflict)
+	}
+
+	if err := s.repo.DeleteLocation(ctx, id); err != nil {
+		return err
+	}
+	s.audit.Record(ctx, entities.AuditLocationDeleted, entities.ResourceLocation, locationResourceID(id), location, nil)
+	return nil
+}
+
+// AssignDevice places one of the user's devices in one of the user's locations
+func (s *LocationService) AssignDevice(ctx context.Context, userID int, numeroSerie string, req *entities.AssignLocationRequest) error {
+	device, err := s.deviceRepo.GetDevice(ctx, numeroSerie)
+	if err != nil {
+		return err
+	}
+	if device.IDUser == nil || *device.IDUser != userID {
+		return entities.ErrNotFound
+	}
+
+	if req.IDUbicacion != nil {
+		if _, err := s.ownedLocation(ctx, userID, *req.IDUbicacion); err != nil {
+			if err == entities.ErrNotFound {
+				return &entities.ValidationError{Field: "id_ubicacion", Message: "location not found"}
+			}
+			return err
+		}
+	}
+
+	if err := s.repo.AssignDevice(ctx, numeroSerie, req.IDUbicacion); err != nil {
+		return err
+	}
+	s.audit.Record(ctx, entities.AuditDeviceLocated, entities.ResourceDevice, numeroSerie,
+		map[string]*int64{"id_ubicacion": device.IDUbicacion}, map[string]*int64{"id_ubicacion": req.IDUbicacion})
+	return nil
+}
+
+// GetLocationStatus returns the current alert status of every device below a location,
+// for instance a whole site
+func (s *LocationService) GetLocationStatus(ctx context.Context, userID int, id int64) (*entities.LocationStatus, error) {
+	location, err := s.ownedLocation(ctx, userID, id)
+	if err != nil {
+		return nil, err
+	}
+
+	devices, err := s.repo.ListDeviceStatuses(ctx, location)
+	if err != nil {
+		return nil, err
+	}
+
+	status := &entities.LocationStatus{
+		Ubicacion:      location,
+		AlertasActivas: map[string]int{},
+		Dispositivos:   devices,
+	}
+	if status.Dispositivos == nil {
+		status.Dispositivos = []*entities.DeviceStatus{}
+	}
+
+	for _, device := range devices {
+		status.TotalDispositivos++
+		if !device.EnLinea {
+			status.DispositivosFueraDeLinea++
+		}
+		for severity, count := range device.AlertasActivas {
+			status.AlertasActivas[severity] += count
+		}
+		if entities.SeverityRank(device.PeorSeveridad) > entities.SeverityRank(status.PeorSeveridad) {
+			status.PeorSeveridad = device.PeorSeveridad
+		}
+	}
+
+	return status, nil
+}
+
+// ownedLocation loads a location and hides it from users that do not own it
+func (s *LocationService) ownedLocation(ctx context.Context, userID int, id int64) (*entities.Location, error) {
+	location, err := s.repo.GetLocation(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if location.IDUser != userID {
+		return nil, entities.ErrNotFound
+	}
+	return location, nil
+}
+
+func locationResourceID(id int64) string {
+	return strconv.FormatInt(id, 10)
+}
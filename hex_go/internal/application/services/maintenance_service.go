@@ -0,0 +1,153 @@
+package services
+
+import (
+	"context"
+	"strconv"
+	"strings"
+	"time"
+
+	"hex_go/internal/application/validation"
+	"hex_go/internal/domain/entities"
+	"hex_go/internal/domain/ports"
+)
+
+// MaintenanceService manages the maintenance windows that suppress alerts while
+// detectors are tested. Every change to a window is recorded in the audit log.
+type MaintenanceService struct {
+	repo         ports.MaintenanceRepositoryPort
+	deviceRepo   ports.DeviceRepositoryPort
+	locationRepo ports.LocationRepositoryPort
+	audit        ports.AuditServicePort
+}
+
+func NewMaintenanceService(repo ports.MaintenanceRepositoryPort, deviceRepo ports.DeviceRepositoryPort, locationRepo ports.LocationRepositoryPort, audit ports.AuditServicePort) *MaintenanceService {
+	return &MaintenanceService{
+		repo:         repo,
+		deviceRepo:   deviceRepo,
+		locationRepo: locationRepo,
+		audit:        audit,
+	}
+}
+
+// CreateWindow schedules a maintenance window for one of the user's devices or locations,
+// or starts one right away
+func (s *MaintenanceService) CreateWindow(ctx context.Context, userID int, req *entities.MaintenanceWindowRequest) (*entities.MaintenanceWindow, error) {
+	now := time.Now().UTC()
+	if err := validation.ValidateMaintenanceWindow(req, now); err != nil {
+		return nil, err
+	}
+	if err := checkOwnedTarget(ctx, s.deviceRepo, s.locationRepo, userID, req.NumeroSerie, req.IDUbicacion); err != nil {
+		return nil, err
+	}
+
+	window := &entities.MaintenanceWindow{
+		IDUser:        userID,
+		FechaCreacion: now,
+	}
+	applyMaintenanceRequest(window, req, now)
+	if err := s.repo.CreateWindow(ctx, window); err != nil {
+		return nil, err
+	}
+	s.audit.Record(ctx, entities.AuditMaintenanceCreated, entities.ResourceMaintenance, maintenanceResourceID(window.ID), nil, window)
+
+	return window, nil
+}
+
+// ListWindows returns the maintenance windows of the user
+func (s *MaintenanceService) ListWindows(ctx context.Context, userID int, filter *entities.MaintenanceFilter) ([]*entities.MaintenanceWindow, error) {
+	return s.repo.ListUserWindows(ctx, userID, filter, time.Now().UTC())
+}
+
+// GetWindow returns a maintenance window of the user
+func (s *MaintenanceService) GetWindow(ctx context.Context, userID int, id int64) (*entities.MaintenanceWindow, error) {
+	return s.ownedWindow(ctx, userID, id)
+}
+
+// UpdateWindow replaces the name, target and period of a window that is not over yet
+func (s *MaintenanceService) UpdateWindow(ctx context.Context, userID int, id int64, req *entities.MaintenanceWindowRequest) (*entities.MaintenanceWindow, error) {
+	now := time.Now().UTC()
+	if err := validation.ValidateMaintenanceWindow(req, now); err != nil {
+		return nil, err
+	}
+
+	window, err := s.ownedWindow(ctx, userID, id)
+	if err != nil {
+		return nil, err
+	}
+	if !window.Fin.After(now) {
+		return nil, entities.ErrConflict
+	}
+	if err := checkOwnedTarget(ctx, s.deviceRepo, s.locationRepo, userID, req.NumeroSerie, req.IDUbicacion); err != nil {
+		return nil, err
+	}
+	before := *window
+
+	applyMaintenanceRequest(window, req, now)
+	if err := s.repo.UpdateWindow(ctx, window); err != nil {
+		return nil, err
+	}
+	s.audit.Record(ctx, entities.AuditMaintenanceUpdated, entities.ResourceMaintenance, maintenanceResourceID(id), &before, window)
+
+	return window, nil
+}
+
+// EndWindow ends an active window now, once the technicians are done
+func (s *MaintenanceService) EndWindow(ctx context.Context, userID int, id int64) (*entities.MaintenanceWindow, error) {
+	window, err := s.ownedWindow(ctx, userID, id)
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now().UTC()
+	if !window.Active(now) {
+		return nil, entities.ErrConflict
+	}
+	before := *window
+
+	window.Fin = now
+	if err := s.repo.UpdateWindow(ctx, window); err != nil {
+		return nil, err
+	}
+	s.audit.Record(ctx, entities.AuditMaintenanceEnded, entities.ResourceMaintenance, maintenanceResourceID(id), &before, window)
+
+	return window, nil
+}
+
+// DeleteWindow removes a maintenance window. Alerts suppressed by it stay suppressed.
+func (s *MaintenanceService) DeleteWindow(ctx context.Context, userID int, id int64) error {
+	window, err := s.ownedWindow(ctx, userID, id)
+	if err != nil {
+		return err
+	}
+
+	if err := s.repo.DeleteWindow(ctx, id); err != nil {
+		return err
+	}
+	s.audit.Record(ctx, entities.AuditMaintenanceDeleted, entities.ResourceMaintenance, maintenanceResourceID(id), window, nil)
+	return nil
+}
+
+// ownedWindow loads a maintenance window and hides it from other users
+func (s *MaintenanceService) ownedWindow(ctx context.Context, userID int, id int64) (*entities.MaintenanceWindow, error) {
+	window, err := s.repo.GetWindow(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if window.IDUser != userID {
+		return nil, entities.ErrNotFound
+	}
+	return window, nil
+}
+
+func applyMaintenanceRequest(window *entities.MaintenanceWindow, req *entities.MaintenanceWindowRequest, now time.Time) {
+	window.Nombre = strings.TrimSpace(req.Nombre)
+	window.NumeroSerie = req.NumeroSerie
+	window.IDUbicacion = req.IDUbicacion
+	window.Inicio, window.Fin = req.Period(now)
+}
+
+func maintenanceResourceID(id int64) string {
+	return strconv.FormatInt(id, 10)
+}
+
+// Verify interface implementation
+var _ ports.MaintenanceServicePort = (*MaintenanceService)(nil)
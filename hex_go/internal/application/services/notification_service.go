@@ -0,0 +1,479 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"log"
+	"strconv"
+	"strings"
+	"time"
+
+	"hex_go/internal/application/validation"
+	"hex_go/internal/domain/entities"
+	"hex_go/internal/domain/ports"
+)
+
+// notificationTimeout bounds the delivery of the notifications of one alert
+const notificationTimeout = time.Minute
+
+// notificationDeliveryLimit is the number of deliveries shown in a user's delivery log
+const notificationDeliveryLimit = 100
+
+const (
+	// digestBatch is how many recipients with due digests are handled per round
+	digestBatch = 50
+	// digestMaxItems caps the alerts described in one digest; the rest are only counted
+	digestMaxItems = 20
+	// digestLease keeps a claimed digest from being sent by another worker meanwhile
+	digestLease = 5 * time.Minute
+)
+
+type NotificationService struct {
+	repo         ports.NotificationRepositoryPort
+	deviceRepo   ports.DeviceRepositoryPort
+	locationRepo ports.LocationRepositoryPort
+	audit        ports.AuditServicePort
+	notifiers    map[string]ports.NotifierPort
+	pollInterval time.Duration
+}
+
+// NewNotificationService creates the notification service. It is also an incident
+// subscriber that notifies the recipients of a device through the given notifiers;
+// channels without a notifier are skipped. Held back notifications are looked for
+// every pollInterval.
+func NewNotificationService(repo ports.NotificationRepositoryPort, deviceRepo ports.DeviceRepositoryPort, locationRepo ports.LocationRepositoryPort, audit ports.AuditServicePort, pollInterval time.Duration, notifiers ...ports.NotifierPort) *NotificationService {
+	return &NotificationService{
+		repo:         repo,
+		deviceRepo:   deviceRepo,
+		locationRepo: locationRepo,
+		audit:        audit,
+		notifiers:    notifiersByChannel(notifiers),
+		pollInterval: pollInterval,
+	}
+}
+
+// CreateRecipient registers an address the user wants alerts sent to
+func (s *NotificationService) CreateRecipient(ctx context.Context, userID int, req *entities.CreateRecipientRequest) (*entities.NotificationRecipient, error) {
+	if err := validation.ValidateCreateRecipient(req); err != nil {
+		return nil, err
+	}
+
+	recipient := &entities.NotificationRecipient{
+		IDUser:        userID,
+		Canal:         req.Canal,
+		Direccion:     strings.TrimSpace(req.Direccion),
+		Idioma:        req.Idioma,
+		FechaCreacion: time.Now().UTC(),
+	}
+	if err := s.repo.CreateRecipient(ctx, recipient); err != nil {
+		return nil, err
+	}
+	s.audit.Record(ctx, entities.AuditRecipientCreated, entities.ResourceRecipient, recipientResourceID(recipient.ID), nil, recipient)
+
+	return recipient, nil
+}
+
+// ListRecipients returns the recipients of the user
+func (s *NotificationService) ListRecipients(ctx context.Context, userID int) ([]*entities.NotificationRecipient, error) {
+	return s.repo.ListUserRecipients(ctx, userID)
+}
+
+// DeleteRecipient removes a recipient of the user
+func (s *NotificationService) DeleteRecipient(ctx context.Context, userID int, id int64) error {
+	recipient, err := s.repo.GetRecipient(ctx, id)
+	if err != nil {
+		return err
+	}
+	if recipient.IDUser != userID {
+		return entities.ErrNotFound
+	}
+
+	if err := s.repo.DeleteRecipient(ctx, id); err != nil {
+		return err
+	}
+	s.audit.Record(ctx, entities.AuditRecipientDeleted, entities.ResourceRecipient, recipientResourceID(id), recipient, nil)
+	return nil
+}
+
+// GetChannelSettings returns the channels the user is notified by per severity
+func (s *NotificationService) GetChannelSettings(ctx context.Context, userID int) (*entities.NotificationChannelSettings, error) {
+	settings, err := s.repo.GetChannelSettings(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if settings == nil {
+		return entities.DefaultNotificationChannelSettings(), nil
+	}
+	return completeChannelSettings(settings), nil
+}
+
+// UpdateChannelSettings replaces the channels the user is notified by. Severities left
+// out are no longer notified.
+func (s *NotificationService) UpdateChannelSettings(ctx context.Context, userID int, settings *entities.NotificationChannelSettings) (*entities.NotificationChannelSettings, error) {
+	if err := validation.ValidateChannelSettings(settings); err != nil {
+		return nil, err
+	}
+
+	before, err := s.GetChannelSettings(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	after := completeChannelSettings(settings)
+	for severity, channels := range after.Canales {
+		after.Canales[severity] = uniqueStrings(channels)
+	}
+	if err := s.repo.SetChannelSettings(ctx, userID, after); err != nil {
+		return nil, err
+	}
+	s.audit.Record(ctx, entities.AuditNotificationChannelsUpdated, entities.ResourceUser, strconv.Itoa(userID), before, after)
+
+	return after, nil
+}
+
+// ListDeliveries returns the latest notifications sent to the user's recipients
+func (s *NotificationService) ListDeliveries(ctx context.Context, userID int) ([]*entities.NotificationDelivery, error) {
+	return s.repo.ListUserDeliveries(ctx, userID, notificationDeliveryLimit)
+}
+
+// GetSettings returns the notification preferences of the user and the least severe
+// alert sent through each channel
+func (s *NotificationService) GetSettings(ctx context.Context, userID int) (*entities.NotificationSettings, error) {
+	prefs, err := s.preferences(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	channels, err := s.GetChannelSettings(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	return &entities.NotificationSettings{
+		NotificationPreferences: *prefs,
+		SeveridadMinima:         channels.MinimumSeverities(),
+	}, nil
+}
+
+// UpdateSettings replaces the notification preferences of the user. Channels left out
+// of SeveridadMinima keep the alerts they are used for.
+func (s *NotificationService) UpdateSettings(ctx context.Context, userID int, settings *entities.NotificationSettings) (*entities.NotificationSettings, error) {
+	if err := validation.ValidateNotificationSettings(settings); err != nil {
+		return nil, err
+	}
+
+	before, err := s.GetSettings(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	prefs := settings.NotificationPreferences
+	if err := s.repo.SetPreferences(ctx, userID, &prefs); err != nil {
+		return nil, err
+	}
+	if len(settings.SeveridadMinima) > 0 {
+		channels, err := s.GetChannelSettings(ctx, userID)
+		if err != nil {
+			return nil, err
+		}
+		for channel, minimum := range settings.SeveridadMinima {
+			channels.SetMinimumSeverity(channel, minimum)
+		}
+		if err := s.repo.SetChannelSettings(ctx, userID, channels); err != nil {
+			return nil, err
+		}
+	}
+
+	after, err := s.GetSettings(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	s.audit.Record(ctx, entities.AuditNotificationSettingsUpdated, entities.ResourceUser, strconv.Itoa(userID), before, after)
+
+	return after, nil
+}
+
+// preferences returns the stored preferences of the user or the defaults
+func (s *NotificationService) preferences(ctx context.Context, userID int) (*entities.NotificationPreferences, error) {
+	prefs, err := s.repo.GetPreferences(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if prefs == nil {
+		return entities.DefaultNotificationPreferences(), nil
+	}
+	return prefs, nil
+}
+
+// IncidentRaised notifies the recipients of the device of the alert that opened the
+// incident, or made it more severe, through the channels each user chose for its
+// severity. Sending happens in the background so a slow gateway does not hold up the
+// device that reported the reading.
+func (s *NotificationService) IncidentRaised(ctx context.Context, incident *entities.Incident, alert *entities.Alert) {
+	if len(s.notifiers) == 0 {
+		return
+	}
+
+	go func() {
+		notifyCtx, cancel := context.WithTimeout(detach(ctx), notificationTimeout)
+		defer cancel()
+		s.notify(notifyCtx, alert)
+	}()
+}
+
+// notify sends an alert to every recipient of its device whose user chose the channel
+// for the alert severity, and records the outcome of each send. Non-critical alerts are
+// held back during the user's quiet hours or until their next digest.
+func (s *NotificationService) notify(ctx context.Context, alert *entities.Alert) {
+	recipients, err := s.repo.ListDeviceRecipients(ctx, alert.NumeroSerie)
+	if err != nil {
+		log.Printf("Error finding recipients for alert %d: %v", alert.ID, err)
+		return
+	}
+	if len(recipients) == 0 {
+		return
+	}
+
+	var base *entities.Notification
+	settings := make(map[int]*entities.NotificationChannelSettings)
+	prefs := make(map[int]*entities.NotificationPreferences)
+	// Two users may have registered the same address; it only gets one message
+	sent := make(map[string]bool, len(recipients))
+	for _, recipient := range recipients {
+		notifier, ok := s.notifiers[recipient.Canal]
+		if !ok {
+			continue
+		}
+
+		userSettings, ok := settings[recipient.IDUser]
+		if !ok {
+			if userSettings, err = s.GetChannelSettings(ctx, recipient.IDUser); err != nil {
+				log.Printf("Error fetching notification channels of user %d: %v", recipient.IDUser, err)
+				userSettings = entities.DefaultNotificationChannelSettings()
+			}
+			settings[recipient.IDUser] = userSettings
+		}
+		if !userSettings.Allows(alert.Severidad, recipient.Canal) {
+			continue
+		}
+
+		key := recipient.Canal + ":" + strings.ToLower(recipient.Direccion)
+		if sent[key] {
+			continue
+		}
+		sent[key] = true
+
+		if alert.Severidad != entities.SeverityCritical {
+			userPrefs, ok := prefs[recipient.IDUser]
+			if !ok {
+				if userPrefs, err = s.preferences(ctx, recipient.IDUser); err != nil {
+					log.Printf("Error fetching notification preferences of user %d: %v", recipient.IDUser, err)
+					userPrefs = entities.DefaultNotificationPreferences()
+				}
+				prefs[recipient.IDUser] = userPrefs
+			}
+			if at, deferred := userPrefs.DeferUntil(time.Now()); deferred {
+				if err := s.repo.QueueDigestItem(ctx, recipient.ID, alert.ID, at.UTC()); err != nil {
+					log.Printf("Error holding back alert %d for user %d: %v", alert.ID, recipient.IDUser, err)
+				}
+				continue
+			}
+		}
+
+		if base == nil {
+			base = describeAlert(ctx, s.deviceRepo, s.locationRepo, alert)
+		}
+		notification := *base
+		notification.Destinatario = recipient
+		err := notifier.Notify(ctx, &notification)
+		if err != nil {
+			log.Printf("Error sending alert %d by %s to user %d: %v", alert.ID, recipient.Canal, recipient.IDUser, err)
+		}
+		s.recordDelivery(ctx, alert, recipient, err)
+	}
+}
+
+// Run sends the held back notifications that are due every poll interval until ctx is
+// cancelled
+func (s *NotificationService) Run(ctx context.Context) {
+	log.Printf("Notification digests started: polling every %s", s.pollInterval)
+
+	ticker := time.NewTicker(s.pollInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			log.Printf("Notification digests stopped")
+			return
+		case <-ticker.C:
+		}
+
+		if err := s.SendDueDigests(ctx); err != nil {
+			log.Printf("Error sending notification digests: %v", err)
+		}
+	}
+}
+
+// SendDueDigests sends every recipient their due held back notifications as one digest
+func (s *NotificationService) SendDueDigests(ctx context.Context) error {
+	now := time.Now().UTC()
+	recipientIDs, err := s.repo.ListDueDigestRecipients(ctx, now, digestBatch)
+	if err != nil {
+		return err
+	}
+
+	for _, recipientID := range recipientIDs {
+		if err := s.sendDigest(ctx, recipientID, now); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// sendDigest claims and sends the due notifications of one recipient. A digest is tried
+// once, like any other notification, and recorded in the delivery log per alert.
+func (s *NotificationService) sendDigest(ctx context.Context, recipientID int64, now time.Time) error {
+	recipient, err := s.repo.GetRecipient(ctx, recipientID)
+	if err == entities.ErrNotFound {
+		// Removed meanwhile; its held back notifications go with it
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	batch, err := randomHex(16)
+	if err != nil {
+		return err
+	}
+	alerts, err := s.repo.ClaimDigest(ctx, recipientID, now, batch, now.Add(digestLease))
+	if err != nil {
+		return err
+	}
+	if len(alerts) == 0 {
+		// Another worker claimed them first
+		return nil
+	}
+
+	var sendErr error
+	if notifier, ok := s.notifiers[recipient.Canal]; ok {
+		digest := &entities.NotificationDigest{Destinatario: recipient, Total: len(alerts)}
+		latest := alerts
+		if len(latest) > digestMaxItems {
+			latest = latest[len(latest)-digestMaxItems:]
+		}
+		for _, alert := range latest {
+			notification := describeAlert(ctx, s.deviceRepo, s.locationRepo, alert)
+			notification.Destinatario = recipient
+			digest.Notificaciones = append(digest.Notificaciones, notification)
+		}
+
+		sendCtx, cancel := context.WithTimeout(ctx, notificationTimeout)
+		sendErr = notifier.NotifyDigest(sendCtx, digest)
+		cancel()
+	} else {
+		sendErr = errors.New("channel " + recipient.Canal + " is not configured")
+	}
+	if sendErr != nil {
+		log.Printf("Error sending digest of %d alerts by %s to user %d: %v", len(alerts), recipient.Canal, recipient.IDUser, sendErr)
+	}
+
+	for _, alert := range alerts {
+		s.recordDelivery(ctx, alert, recipient, sendErr)
+	}
+	return s.repo.DeleteDigest(ctx, batch)
+}
+
+// recordDelivery logs the outcome of sending an alert to a recipient. A failure to log
+// does not affect the notification, which has already been sent.
+func (s *NotificationService) recordDelivery(ctx context.Context, alert *entities.Alert, recipient *entities.NotificationRecipient, sendErr error) {
+	recipientID := recipient.ID
+	delivery := &entities.NotificationDelivery{
+		IDAlerta:       alert.ID,
+		IDDestinatario: &recipientID,
+		IDUser:         recipient.IDUser,
+		Canal:          recipient.Canal,
+		Direccion:      recipient.Direccion,
+		Estado:         entities.DeliveryDelivered,
+		FechaCreacion:  time.Now().UTC(),
+	}
+	if sendErr != nil {
+		delivery.Estado = entities.DeliveryFailed
+		delivery.Error = sendErr.Error()
+	}
+
+	if err := s.repo.RecordDelivery(ctx, delivery); err != nil {
+		log.Printf("Error recording notification of alert %d: %v", alert.ID, err)
+	}
+}
+
+// notifiersByChannel indexes notifiers by the channel they send through
+func notifiersByChannel(notifiers []ports.NotifierPort) map[string]ports.NotifierPort {
+	byChannel := make(map[string]ports.NotifierPort, len(notifiers))
+	for _, notifier := range notifiers {
+		byChannel[notifier.Channel()] = notifier
+	}
+	return byChannel
+}
+
+// describeAlert looks up the device name, location and local time shown in notifications.
+// Lookup failures only make the notification less detailed.
+func describeAlert(ctx context.Context, deviceRepo ports.DeviceRepositoryPort, locationRepo ports.LocationRepositoryPort, alert *entities.Alert) *entities.Notification {
+	notification := &entities.Notification{
+		Alerta:      alert,
+		Dispositivo: alert.NumeroSerie,
+		Fecha:       alert.FechaCreacion.UTC(),
+	}
+
+	device, err := deviceRepo.GetDevice(ctx, alert.NumeroSerie)
+	if err != nil {
+		log.Printf("Error fetching device %s for alert %d: %v", alert.NumeroSerie, alert.ID, err)
+		return notification
+	}
+
+	if device.Nombre != "" {
+		notification.Dispositivo = device.Nombre
+	}
+	if loc, err := time.LoadLocation(device.ZonaHoraria); err == nil && device.ZonaHoraria != "" {
+		notification.Fecha = alert.FechaCreacion.In(loc)
+	}
+
+	notification.Ubicacion = device.Ubicacion
+	if device.IDUbicacion != nil {
+		path, err := locationRepo.ListLocationPath(ctx, *device.IDUbicacion)
+		if err != nil {
+			log.Printf("Error fetching location of device %s: %v", device.NumeroSerie, err)
+		} else if len(path) > 0 {
+			names := make([]string, len(path))
+			for i, location := range path {
+				names[i] = location.Nombre
+			}
+			notification.Ubicacion = strings.Join(names, " / ")
+		}
+	}
+
+	return notification
+}
+
+// completeChannelSettings gives every severity a channel list, empty when missing
+func completeChannelSettings(settings *entities.NotificationChannelSettings) *entities.NotificationChannelSettings {
+	complete := &entities.NotificationChannelSettings{Canales: make(map[string][]string, len(entities.Severities))}
+	for _, severity := range entities.Severities {
+		channels := settings.Canales[severity]
+		if channels == nil {
+			channels = []string{}
+		}
+		complete.Canales[severity] = channels
+	}
+	return complete
+}
+
+func recipientResourceID(id int64) string {
+	return strconv.FormatInt(id, 10)
+}
+
+// Verify interface implementation
+var (
+	_ ports.NotificationServicePort = (*NotificationService)(nil)
+	_ ports.IncidentSubscriberPort  = (*NotificationService)(nil)
+)
@@ -0,0 +1,394 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"hex_go/internal/application/validation"
+	"hex_go/internal/domain/entities"
+	"hex_go/internal/domain/ports"
+)
+
+// invitationTTL is how long an invitation can be accepted
+const invitationTTL = 7 * 24 * time.Hour
+
+// accessAuditLimit caps the number of access audit entries returned at once
+const accessAuditLimit = 200
+
+type OrganizationService struct {
+	repo       ports.OrganizationRepositoryPort
+	deviceRepo ports.DeviceRepositoryPort
+	audit      ports.AuditServicePort
+}
+
+func NewOrganizationService(repo ports.OrganizationRepositoryPort, deviceRepo ports.DeviceRepositoryPort, audit ports.AuditServicePort) ports.OrganizationServicePort {
+	return &OrganizationService{
+		repo:       repo,
+		deviceRepo: deviceRepo,
+		audit:      audit,
+	}
+}
+
+// CreateOrganization creates an organisation owned by the calling user
+func (s *OrganizationService) CreateOrganization(ctx context.Context, userID int, req *entities.CreateOrganizationRequest) (*entities.Organization, error) {
+	if err := validation.ValidateCreateOrganization(req); err != nil {
+		return nil, err
+	}
+
+	now := time.Now().UTC()
+	org := &entities.Organization{
+		Nombre:        strings.TrimSpace(req.Nombre),
+		FechaCreacion: now,
+		Rol:           entities.RoleOwner,
+	}
+	owner := &entities.Membership{IDUser: userID, Rol: entities.RoleOwner, FechaAlta: now}
+	audit := s.auditEntry(0, userID, entities.AuditOrganizationCreated, userTarget(userID), org.Nombre)
+
+	if err := s.repo.CreateOrganization(ctx, org, owner, audit); err != nil {
+		return nil, err
+	}
+	s.recordAccessChange(ctx, audit)
+
+	return org, nil
+}
+
+// ListOrganizations returns the organisations of the calling user
+func (s *OrganizationService) ListOrganizations(ctx context.Context, userID int) ([]*entities.Organization, error) {
+	return s.repo.ListUserOrganizations(ctx, userID)
+}
+
+// ListMembers returns the members of an organisation the user belongs to
+func (s *OrganizationService) ListMembers(ctx context.Context, userID int, orgID int64) ([]*entities.Membership, error) {
+	if _, err := s.membership(ctx, orgID, userID); err != nil {
+		return nil, err
+	}
+	return s.repo.ListMembers(ctx, orgID)
+}
+
+// UpdateMember changes the role of a member. Only owners can grant or take away the
+// owner role, and the last owner cannot be demoted.
+func (s *OrganizationService) UpdateMember(ctx context.Context, userID int, orgID int64, memberID int, req *entities.UpdateMemberRequest) error {
+	if err := validation.ValidateUpdateMember(req); err != nil {
+		return err
+	}
+
+	caller, err := s.manager(ctx, orgID, userID)
+	if err != nil {
+		return err
+	}
+	member, err := s.repo.GetMembership(ctx, orgID, memberID)
+	if err != nil {
+		return err
+	}
+	if member.Rol == req.Rol {
+		return nil
+	}
+
+	if (member.Rol == entities.RoleOwner || req.Rol == entities.RoleOwner) && caller.Rol != entities.RoleOwner {
+		return fmt.Errorf("only owners can change the owner role: %w", entities.ErrForbidden)
+	}
+	if member.Rol == entities.RoleOwner {
+		if err := s.ensureAnotherOwner(ctx, orgID); err != nil {
+			return err
+		}
+	}
+
+	audit := s.auditEntry(orgID, userID, entities.AuditMemberRoleChanged, userTarget(memberID),
+		fmt.Sprintf("%s -> %s", member.Rol, req.Rol))
+	if err := s.repo.UpdateMemberRole(ctx, orgID, memberID, req.Rol, audit); err != nil {
+		return err
+	}
+	s.recordAccessChange(ctx, audit)
+	return nil
+}
+
+// RemoveMember removes a member. Members can always leave by removing themselves;
+// removing others requires owner or admin rights.
+func (s *OrganizationService) RemoveMember(ctx context.Context, userID int, orgID int64, memberID int) error {
+	var caller *entities.Membership
+	var err error
+	if memberID == userID {
+		caller, err = s.membership(ctx, orgID, userID)
+	} else {
+		caller, err = s.manager(ctx, orgID, userID)
+	}
+	if err != nil {
+		return err
+	}
+
+	member, err := s.repo.GetMembership(ctx, orgID, memberID)
+	if err != nil {
+		return err
+	}
+	if member.Rol == entities.RoleOwner {
+		if caller.Rol != entities.RoleOwner {
+			return fmt.Errorf("only owners can remove an owner: %w", entities.ErrForbidden)
+		}
+		if err := s.ensureAnotherOwner(ctx, orgID); err != nil {
+			return err
+		}
+	}
+
+	audit := s.auditEntry(orgID, userID, entities.AuditMemberRemoved, userTarget(memberID), member.Rol)
+	if err := s.repo.RemoveMember(ctx, orgID, memberID, audit); err != nil {
+		return err
+	}
+	s.recordAccessChange(ctx, audit)
+	return nil
+}
+
+// InviteMember invites a user to join the organisation with a role
+func (s *OrganizationService) InviteMember(ctx context.Context, userID int, orgID int64, req *entities.InviteMemberRequest) (*entities.Invitation, error) {
+	if err := validation.ValidateInviteMember(req); err != nil {
+		return nil, err
+	}
+
+	caller, err := s.manager(ctx, orgID, userID)
+	if err != nil {
+		return nil, err
+	}
+	if req.Rol == entities.RoleOwner && caller.Rol != entities.RoleOwner {
+		return nil, fmt.Errorf("only owners can invite owners: %w", entities.ErrForbidden)
+	}
+
+	now := time.Now().UTC()
+	invitation := &entities.Invitation{
+		IDOrganizacion:  orgID,
+		IDUserInvitado:  req.IDUser,
+		Rol:             req.Rol,
+		Estado:          entities.InvitationPending,
+		InvitadoPor:     userID,
+		FechaCreacion:   now,
+		FechaExpiracion: now.Add(invitationTTL),
+	}
+	audit := s.auditEntry(orgID, userID, entities.AuditInvitationCreated, "",
+		fmt.Sprintf("%s as %s", userTarget(req.IDUser), req.Rol))
+
+	if err := s.repo.CreateInvitation(ctx, invitation, audit); err != nil {
+		return nil, err
+	}
+	s.recordAccessChange(ctx, audit)
+
+	return invitation, nil
+}
+
+// ListOrganizationInvitations returns the invitations sent by an organisation
+func (s *OrganizationService) ListOrganizationInvitations(ctx context.Context, userID int, orgID int64) ([]*entities.Invitation, error) {
+	if _, err := s.manager(ctx, orgID, userID); err != nil {
+		return nil, err
+	}
+	return s.repo.ListOrganizationInvitations(ctx, orgID)
+}
+
+// RevokeInvitation cancels a pending invitation
+func (s *OrganizationService) RevokeInvitation(ctx context.Context, userID int, orgID int64, invitationID int64) error {
+	if _, err := s.manager(ctx, orgID, userID); err != nil {
+		return err
+	}
+
+	invitation, err := s.repo.GetInvitation(ctx, invitationID)
+	if err != nil {
+		return err
+	}
+	if invitation.IDOrganizacion != orgID {
+		return entities.ErrNotFound
+	}
+
+	audit := s.auditEntry(orgID, userID, entities.AuditInvitationRevoked, invitationTarget(invitationID), "")
+	if err := s.repo.CloseInvitation(ctx, invitationID, entities.InvitationRevoked, audit); err != nil {
+		return err
+	}
+	s.recordAccessChange(ctx, audit)
+	return nil
+}
+
+// ListMyInvitations returns the pending invitations of the calling user
+func (s *OrganizationService) ListMyInvitations(ctx context.Context, userID int) ([]*entities.Invitation, error) {
+	return s.repo.ListPendingInvitations(ctx, userID)
+}
+
+// AcceptInvitation makes the calling user a member with the invited role
+func (s *OrganizationService) AcceptInvitation(ctx context.Context, userID int, invitationID int64) error {
+	invitation, err := s.pendingInvitation(ctx, userID, invitationID)
+	if err != nil {
+		return err
+	}
+
+	member := &entities.Membership{
+		IDOrganizacion: invitation.IDOrganizacion,
+		IDUser:         userID,
+		Rol:            invitation.Rol,
+		FechaAlta:      time.Now().UTC(),
+	}
+	audit := s.auditEntry(invitation.IDOrganizacion, userID, entities.AuditInvitationAccepted,
+		invitationTarget(invitationID), invitation.Rol)
+	if err := s.repo.AcceptInvitation(ctx, invitation, member, audit); err != nil {
+		return err
+	}
+	s.recordAccessChange(ctx, audit)
+	return nil
+}
+
+// DeclineInvitation rejects an invitation addressed to the calling user
+func (s *OrganizationService) DeclineInvitation(ctx context.Context, userID int, invitationID int64) error {
+	invitation, err := s.pendingInvitation(ctx, userID, invitationID)
+	if err != nil {
+		return err
+	}
+
+	audit := s.auditEntry(invitation.IDOrganizacion, userID, entities.AuditInvitationDeclined,
+		invitationTarget(invitationID), "")
+	if err := s.repo.CloseInvitation(ctx, invitationID, entities.InvitationDeclined, audit); err != nil {
+		return err
+	}
+	s.recordAccessChange(ctx, audit)
+	return nil
+}
+
+// ShareDevice shares one of the caller's devices with an organisation they manage
+func (s *OrganizationService) ShareDevice(ctx context.Context, userID int, orgID int64, req *entities.ShareDeviceRequest) error {
+	if err := validation.ValidateShareDevice(req); err != nil {
+		return err
+	}
+	if _, err := s.manager(ctx, orgID, userID); err != nil {
+		return err
+	}
+
+	device, err := s.deviceRepo.GetDevice(ctx, req.NumeroSerie)
+	if err != nil {
+		return err
+	}
+	if device.IDUser == nil || *device.IDUser != userID {
+		return entities.ErrNotFound
+	}
+
+	shared := &entities.SharedDevice{
+		IDOrganizacion: orgID,
+		NumeroSerie:    req.NumeroSerie,
+		CompartidoPor:  userID,
+		FechaAlta:      time.Now().UTC(),
+	}
+	audit := s.auditEntry(orgID, userID, entities.AuditDeviceShared, deviceTarget(req.NumeroSerie), "")
+	if err := s.repo.ShareDevice(ctx, shared, audit); err != nil {
+		if err == entities.ErrConflict {
+			return fmt.Errorf("device %s is already shared with this organization: %w", req.NumeroSerie, err)
+		}
+		return err
+	}
+	s.recordAccessChange(ctx, audit)
+
+	return nil
+}
+
+// UnshareDevice stops sharing a device with an organisation
+func (s *OrganizationService) UnshareDevice(ctx context.Context, userID int, orgID int64, numeroSerie string) error {
+	if _, err := s.manager(ctx, orgID, userID); err != nil {
+		return err
+	}
+
+	audit := s.auditEntry(orgID, userID, entities.AuditDeviceUnshared, deviceTarget(numeroSerie), "")
+	if err := s.repo.UnshareDevice(ctx, orgID, numeroSerie, audit); err != nil {
+		return err
+	}
+	s.recordAccessChange(ctx, audit)
+	return nil
+}
+
+// ListSharedDevices returns the devices shared with an organisation the user belongs to
+func (s *OrganizationService) ListSharedDevices(ctx context.Context, userID int, orgID int64) ([]*entities.SharedDevice, error) {
+	if _, err := s.membership(ctx, orgID, userID); err != nil {
+		return nil, err
+	}
+	return s.repo.ListSharedDevices(ctx, orgID)
+}
+
+// ListAccessAudit returns the latest access changes of an organisation the user manages
+func (s *OrganizationService) ListAccessAudit(ctx context.Context, userID int, orgID int64) ([]*entities.AccessAuditEntry, error) {
+	if _, err := s.manager(ctx, orgID, userID); err != nil {
+		return nil, err
+	}
+	return s.repo.ListAccessAudit(ctx, orgID, accessAuditLimit)
+}
+
+// membership returns the caller's membership, hiding organisations they do not belong to
+func (s *OrganizationService) membership(ctx context.Context, orgID int64, userID int) (*entities.Membership, error) {
+	return s.repo.GetMembership(ctx, orgID, userID)
+}
+
+// manager returns the caller's membership if it allows managing the organisation
+func (s *OrganizationService) manager(ctx context.Context, orgID int64, userID int) (*entities.Membership, error) {
+	return organizationManager(ctx, s.repo, orgID, userID)
+}
+
+// organizationManager returns the membership of a user who can manage the organisation
+func organizationManager(ctx context.Context, repo ports.OrganizationRepositoryPort, orgID int64, userID int) (*entities.Membership, error) {
+	m, err := repo.GetMembership(ctx, orgID, userID)
+	if err != nil {
+		return nil, err
+	}
+	if !entities.CanManageOrganization(m.Rol) {
+		return nil, fmt.Errorf("role %s cannot manage the organization: %w", m.Rol, entities.ErrForbidden)
+	}
+	return m, nil
+}
+
+// ensureAnotherOwner fails when an organisation has a single owner left
+func (s *OrganizationService) ensureAnotherOwner(ctx context.Context, orgID int64) error {
+	owners, err := s.repo.CountMembersWithRole(ctx, orgID, entities.RoleOwner)
+	if err != nil {
+		return err
+	}
+	if owners <= 1 {
+		return fmt.Errorf("an organization needs at least one owner: %w", entities.ErrConflict)
+	}
+	return nil
+}
+
+// pendingInvitation loads an invitation addressed to the user that can still be answered
+func (s *OrganizationService) pendingInvitation(ctx context.Context, userID int, invitationID int64) (*entities.Invitation, error) {
+	invitation, err := s.repo.GetInvitation(ctx, invitationID)
+	if err != nil {
+		return nil, err
+	}
+	if invitation.IDUserInvitado != userID {
+		return nil, entities.ErrNotFound
+	}
+	if invitation.Estado != entities.InvitationPending {
+		return nil, fmt.Errorf("invitation is %s: %w", invitation.Estado, entities.ErrConflict)
+	}
+	if time.Now().After(invitation.FechaExpiracion) {
+		return nil, fmt.Errorf("invitation expired on %s: %w", invitation.FechaExpiracion.Format(time.RFC3339), entities.ErrConflict)
+	}
+	return invitation, nil
+}
+
+func (s *OrganizationService) auditEntry(orgID int64, actor int, action, target, detail string) *entities.AccessAuditEntry {
+	return &entities.AccessAuditEntry{
+		IDOrganizacion: orgID,
+		Actor:          actor,
+		Accion:         action,
+		Objetivo:       target,
+		Detalle:        detail,
+		Fecha:          time.Now().UTC(),
+	}
+}
+
+// recordAccessChange copies an access change of an organisation into the audit log
+func (s *OrganizationService) recordAccessChange(ctx context.Context, entry *entities.AccessAuditEntry) {
+	s.audit.Record(ctx, entry.Accion, entities.ResourceOrganization, strconv.FormatInt(entry.IDOrganizacion, 10), nil, entry)
+}
+
+func userTarget(userID int) string {
+	return fmt.Sprintf("user:%d", userID)
+}
+
+func invitationTarget(id int64) string {
+	return fmt.Sprintf("invitation:%d", id)
+}
+
+func deviceTarget(numeroSerie string) string {
+	return "device:" + numeroSerie
+}
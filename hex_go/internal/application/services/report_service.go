@@ -0,0 +1,196 @@
+package services
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"hex_go/internal/application/validation"
+	"hex_go/internal/domain/entities"
+	"hex_go/internal/domain/ports"
+)
+
+// ReportService builds the monthly safety report of each site from the alerts and the
+// connectivity of its devices. Users download the report of any month of their sites;
+// once a month is over, its report is emailed to the email recipients of each site owner.
+type ReportService struct {
+	repo             ports.ReportRepositoryPort
+	locationRepo     ports.LocationRepositoryPort
+	notificationRepo ports.NotificationRepositoryPort
+	renderer         ports.ReportRendererPort
+	mailer           ports.ReportMailerPort
+	interval         time.Duration
+}
+
+// NewReportService creates the report service. A nil mailer disables the monthly emails.
+func NewReportService(repo ports.ReportRepositoryPort, locationRepo ports.LocationRepositoryPort, notificationRepo ports.NotificationRepositoryPort, renderer ports.ReportRendererPort, mailer ports.ReportMailerPort, interval time.Duration) *ReportService {
+	return &ReportService{
+		repo:             repo,
+		locationRepo:     locationRepo,
+		notificationRepo: notificationRepo,
+		renderer:         renderer,
+		mailer:           mailer,
+		interval:         interval,
+	}
+}
+
+// GetReportPDF renders the report of a month of one of the user's sites
+func (s *ReportService) GetReportPDF(ctx context.Context, userID int, siteID int64, month, lang string) ([]byte, error) {
+	if err := validation.ValidateReportLanguage(lang); err != nil {
+		return nil, err
+	}
+
+	site, err := s.locationRepo.GetLocation(ctx, siteID)
+	if err != nil {
+		return nil, err
+	}
+	if site.IDUser != userID || site.Nivel != entities.LocationLevelSite {
+		return nil, entities.ErrNotFound
+	}
+
+	report, err := s.BuildReport(ctx, site, month, time.Now().UTC())
+	if err != nil {
+		return nil, err
+	}
+	return s.renderer.RenderReport(report, lang)
+}
+
+// BuildReport gathers the report of a site for a month
+func (s *ReportService) BuildReport(ctx context.Context, site *entities.Location, month string, now time.Time) (*entities.SiteReport, error) {
+	desde, hasta, err := entities.ReportPeriod(month, now)
+	if err != nil {
+		return nil, err
+	}
+
+	report := &entities.SiteReport{
+		Sitio:      site,
+		Mes:        month,
+		Desde:      desde,
+		Hasta:      hasta,
+		GeneradoEn: now,
+	}
+	if report.Alarmas, err = s.repo.CountAlarms(ctx, site.Ruta, desde, hasta); err != nil {
+		return nil, err
+	}
+	acknowledged, pending, err := s.repo.ListResponseTimes(ctx, site.Ruta, desde, hasta)
+	if err != nil {
+		return nil, err
+	}
+	report.Respuesta = entities.NewReportResponseTimes(acknowledged, pending)
+	if report.Dispositivos, err = s.repo.ListDeviceUptime(ctx, site.Ruta, desde, hasta); err != nil {
+		return nil, err
+	}
+	for _, device := range report.Dispositivos {
+		device.ComputeAvailability()
+	}
+	if report.Ruidosos, err = s.repo.ListNoisyDevices(ctx, site.Ruta, desde, hasta, entities.ReportNoisyDevices); err != nil {
+		return nil, err
+	}
+
+	return report, nil
+}
+
+// Run emails the reports of the month just over every interval until ctx is cancelled
+func (s *ReportService) Run(ctx context.Context) {
+	if s.mailer == nil {
+		log.Printf("Email not configured, monthly reports are not delivered")
+		return
+	}
+	log.Printf("Monthly reports started, checking every %s", s.interval)
+
+	ticker := time.NewTicker(s.interval)
+	defer ticker.Stop()
+
+	for {
+		if err := s.DeliverReports(ctx); err != nil {
+			log.Printf("Error delivering monthly reports: %v", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			log.Printf("Monthly reports stopped")
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// DeliverReports emails the report of the previous month of every site not delivered yet
+func (s *ReportService) DeliverReports(ctx context.Context) error {
+	now := time.Now().UTC()
+	month := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, time.UTC).AddDate(0, -1, 0).Format(entities.ReportMonthLayout)
+
+	sites, err := s.repo.ListSites(ctx)
+	if err != nil {
+		return err
+	}
+	for _, site := range sites {
+		claimed, err := s.repo.ClaimReport(ctx, site.ID, month, now)
+		if err != nil {
+			return err
+		}
+		if !claimed {
+			continue
+		}
+
+		if err := s.deliver(ctx, site, month, now); err != nil {
+			log.Printf("Error delivering %s report of site %d: %v", month, site.ID, err)
+			if err := s.repo.ReleaseReport(ctx, site.ID, month); err != nil {
+				log.Printf("Error releasing %s report of site %d: %v", month, site.ID, err)
+			}
+		}
+	}
+	return nil
+}
+
+// deliver emails the report of a site to every email recipient of its owner, rendering
+// it once per language. It fails only when no recipient got it, so that those who did
+// do not get it twice.
+func (s *ReportService) deliver(ctx context.Context, site *entities.Location, month string, now time.Time) error {
+	recipients, err := s.notificationRepo.ListUserRecipients(ctx, site.IDUser)
+	if err != nil {
+		return err
+	}
+
+	var report *entities.SiteReport
+	pdfs := map[string][]byte{}
+	var lastErr error
+	sent := 0
+	for _, recipient := range recipients {
+		if recipient.Canal != entities.ChannelEmail {
+			continue
+		}
+		if report == nil {
+			if report, err = s.BuildReport(ctx, site, month, now); err != nil {
+				return err
+			}
+		}
+		lang := recipient.Idioma
+		if !entities.IsNotificationLanguage(lang) {
+			lang = entities.LanguageSpanish
+		}
+		if pdfs[lang] == nil {
+			if pdfs[lang], err = s.renderer.RenderReport(report, lang); err != nil {
+				return err
+			}
+		}
+
+		if err := s.mailer.SendReport(ctx, recipient, report, pdfs[lang]); err != nil {
+			log.Printf("Error emailing %s report of site %d to recipient %d: %v", month, site.ID, recipient.ID, err)
+			lastErr = err
+			continue
+		}
+		sent++
+	}
+
+	if sent == 0 && lastErr != nil {
+		return lastErr
+	}
+	if sent > 0 {
+		log.Printf("Delivered %s report of site %d to %d recipients", month, site.ID, sent)
+	}
+	return nil
+}
+
+// Verify interface implementation
+var _ ports.ReportServicePort = (*ReportService)(nil)
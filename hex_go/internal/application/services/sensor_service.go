@@ -1,144 +1,224 @@
 package services
 
 import (
-    "errors"
-    "fmt"
-    "strconv"
-    "hex_go/internal/domain/entities"
-    "hex_go/internal/domain/ports"
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"hex_go/internal/application/authorization"
+	"hex_go/internal/application/validation"
+	"hex_go/internal/domain/entities"
+	"hex_go/internal/domain/ports"
 )
 
 type SensorService struct {
-    repo        ports.SensorRepositoryPort
-    rabbitClient ports.MessageQueuePort
+	repo         ports.SensorRepositoryPort
+	deviceRepo   ports.DeviceRepositoryPort
+	alerts       ports.AlertServicePort
+	calibration  ports.CalibrationServicePort
+	anomalies    ports.AnomalyServicePort
+	audit        ports.AuditServicePort
+	rabbitClient ports.MessageQueuePort
 }
 
-func NewSensorService(repo ports.SensorRepositoryPort, rabbitClient ports.MessageQueuePort) ports.SensorServicePort {
-    return &SensorService{
-        repo:        repo,
-        rabbitClient: rabbitClient,
-    }
+func NewSensorService(repo ports.SensorRepositoryPort, deviceRepo ports.DeviceRepositoryPort, alerts ports.AlertServicePort, calibration ports.CalibrationServicePort, anomalies ports.AnomalyServicePort, audit ports.AuditServicePort, rabbitClient ports.MessageQueuePort) ports.SensorServicePort {
+	return &SensorService{
+		repo:         repo,
+		deviceRepo:   deviceRepo,
+		alerts:       alerts,
+		calibration:  calibration,
+		anomalies:    anomalies,
+		audit:        audit,
+		rabbitClient: rabbitClient,
+	}
 }
 
 // ProcessSensorData processes incoming sensor data, stores it in the database, and publishes to RabbitMQ
-func (s *SensorService) ProcessSensorData(data *entities.SensorDataRequest) error {
+func (s *SensorService) ProcessSensorData(ctx context.Context, data *entities.SensorDataRequest) error {
+	// A device may only post its own readings
+	if err := authorization.CheckDeviceCaller(entities.PrincipalFromContext(ctx), data.NumeroSerie); err != nil {
+		return err
+	}
+
+	loc, err := s.deviceLocation(ctx, data.NumeroSerie)
+	if err != nil {
+		return err
+	}
+
+	// Validate the request and normalise its values before anything is stored
+	reading, err := validation.ValidateSensorData(data, loc, time.Now())
+	if err != nil {
+		return err
+	}
+
+	alarm := s.isActivation(ctx, reading)
+
 	// First, store in database
-	var err error
-	
-	switch data.Sensor {
-	case "KY_026":
-	    // Convert estado to int for KY_026
-	    var estado int
-	    switch v := data.Estado.(type) {
-	    case float64:
-	        estado = int(v)
-	    case int:
-	        estado = v
-	    case string:
-	        var parseErr error
-	        estado, parseErr = strconv.Atoi(v)
-	        if parseErr != nil {
-	            return fmt.Errorf("invalid estado value for KY_026: %v", parseErr)
-	        }
-	    default:
-	        return fmt.Errorf("invalid estado type for KY_026: %T", v)
-	    }
-	    
-	    sensor := &entities.SensorKY026{
-	        FechaActivacion:    data.FechaActivacion,
-	        FechaDesactivacion: data.FechaDesactivacion,
-	        Estado:             estado,
-	        NumeroSerie:        data.NumeroSerie,
-	    }
-	    err = s.repo.CreateKY026(sensor)
-	case "MQ_2":
-	    // Convert estado to int for MQ_2
-	    var estado int
-	    switch v := data.Estado.(type) {
-	    case float64:
-	        estado = int(v)
-	    case int:
-	        estado = v
-	    case string:
-	        var parseErr error
-	        estado, parseErr = strconv.Atoi(v)
-	        if parseErr != nil {
-	            return fmt.Errorf("invalid estado value for MQ_2: %v", parseErr)
-	        }
-	    default:
-	        return fmt.Errorf("invalid estado type for MQ_2: %T", v)
-	    }
-	    
-	    sensor := &entities.SensorMQ2{
-	        FechaActivacion:    data.FechaActivacion,
-	        FechaDesactivacion: data.FechaDesactivacion,
-	        Estado:             estado,
-	        NumeroSerie:        data.NumeroSerie,
-	    }
-	    err = s.repo.CreateMQ2(sensor)
-	case "MQ_135":
-	    // Convert estado to int for MQ_135
-	    var estado int
-	    switch v := data.Estado.(type) {
-	    case float64:
-	        estado = int(v)
-	    case int:
-	        estado = v
-	    case string:
-	        var parseErr error
-	        estado, parseErr = strconv.Atoi(v)
-	        if parseErr != nil {
-	            return fmt.Errorf("invalid estado value for MQ_135: %v", parseErr)
-	        }
-	    default:
-	        return fmt.Errorf("invalid estado type for MQ_135: %T", v)
-	    }
-	    
-	    sensor := &entities.SensorMQ135{
-	        FechaActivacion:    data.FechaActivacion,
-	        FechaDesactivacion: data.FechaDesactivacion,
-	        Estado:             estado,
-	        NumeroSerie:        data.NumeroSerie,
-	    }
-	    err = s.repo.CreateMQ135(sensor)
-	case "DHT_22":
-	    // For DHT_22, convert estado to string
-	    var estadoStr string
-	    switch v := data.Estado.(type) {
-	    case string:
-	        estadoStr = v
-	    case float64:
-	        estadoStr = fmt.Sprintf("%v", v)
-	    case int:
-	        estadoStr = fmt.Sprintf("%d", v)
-	    default:
-	        estadoStr = fmt.Sprintf("%v", v)
-	    }
-	    
-	    sensor := &entities.SensorDHT22{
-	        FechaActivacion:    data.FechaActivacion,
-	        FechaDesactivacion: data.FechaDesactivacion,
-	        Estado:             estadoStr,
-	        NumeroSerie:        data.NumeroSerie,
-	    }
-	    err = s.repo.CreateDHT22(sensor)
+	switch reading.Sensor {
+	case entities.SensorTypeKY026:
+		err = s.repo.CreateKY026(&entities.SensorKY026{
+			FechaActivacion:    reading.FechaActivacion,
+			FechaDesactivacion: reading.FechaDesactivacion,
+			Estado:             reading.Estado,
+			NumeroSerie:        reading.NumeroSerie,
+		})
+	case entities.SensorTypeMQ2:
+		err = s.repo.CreateMQ2(&entities.SensorMQ2{
+			FechaActivacion:    reading.FechaActivacion,
+			FechaDesactivacion: reading.FechaDesactivacion,
+			Estado:             reading.Estado,
+			NumeroSerie:        reading.NumeroSerie,
+			PPM:                reading.PPM,
+		})
+	case entities.SensorTypeMQ135:
+		err = s.repo.CreateMQ135(&entities.SensorMQ135{
+			FechaActivacion:    reading.FechaActivacion,
+			FechaDesactivacion: reading.FechaDesactivacion,
+			Estado:             reading.Estado,
+			NumeroSerie:        reading.NumeroSerie,
+			PPM:                reading.PPM,
+		})
+	case entities.SensorTypeDHT22:
+		err = s.repo.CreateDHT22(&entities.SensorDHT22{
+			FechaActivacion:    reading.FechaActivacion,
+			FechaDesactivacion: reading.FechaDesactivacion,
+			Estado:             reading.EstadoTexto,
+			NumeroSerie:        reading.NumeroSerie,
+			Temperatura:        reading.Temperatura,
+			Humedad:            reading.Humedad,
+			IndiceCalor:        reading.IndiceCalor,
+			PuntoRocio:         reading.PuntoRocio,
+		})
 	default:
 		return errors.New("sensor type not supported")
 	}
-	
+
 	if err != nil {
 		return err
 	}
-	
+	s.audit.Record(ctx, entities.AuditReadingCreated, entities.ResourceReading, reading.NumeroSerie, nil, reading)
+
+	// Every reading proves the device is alive
+	if err := s.deviceRepo.RecordHeartbeat(ctx, reading.NumeroSerie, time.Now().UTC(), ""); err != nil {
+		log.Printf("Error updating last seen time of device %s: %v", reading.NumeroSerie, err)
+	}
+
+	// Raise the alert for this activation
+	if alarm {
+		if err := s.alerts.RaiseAlert(ctx, alertForReading(reading)); err != nil {
+			return err
+		}
+	}
+
+	// Look for slow changes that no single reading reveals
+	s.anomalies.ObserveReading(ctx, reading)
+
+	// Publish the normalised timestamps rather than whatever format the device used
+	data.FechaActivacion = reading.FechaActivacion.Format(time.RFC3339)
+	if reading.FechaDesactivacion != nil {
+		data.FechaDesactivacion = reading.FechaDesactivacion.Format(time.RFC3339)
+	} else {
+		data.FechaDesactivacion = nil
+	}
+	if reading.Sensor == entities.SensorTypeDHT22 {
+		data.Estado = reading.EstadoTexto
+		data.Temperatura = &reading.Temperatura
+		data.Humedad = &reading.Humedad
+		data.IndiceCalor = &reading.IndiceCalor
+		data.PuntoRocio = &reading.PuntoRocio
+	}
+	data.PPM = reading.PPM
+
 	// Then, publish to RabbitMQ
 	if s.rabbitClient != nil {
 		return s.rabbitClient.PublishSensorData(data)
 	}
-	
+
 	return nil
 }
 
-// GetUserAlerts retrieves all alerts for a user based on their ID
-func (s *SensorService) GetUserAlerts(userID int) (map[string]interface{}, error) {
-	return s.repo.GetUserAlerts(userID)
-}
\ No newline at end of file
+// isActivation reports whether a reading is an activation that raises an alert: a flame
+// seen by the KY_026, or gas unless a calibrated sensor stays below its threshold. The
+// DHT_22 reports telemetry, left to the anomaly detector.
+func (s *SensorService) isActivation(ctx context.Context, reading *entities.SensorReading) bool {
+	switch reading.Sensor {
+	case entities.SensorTypeKY026:
+		return reading.Estado == 1
+	case entities.SensorTypeDHT22:
+		return false
+	default:
+		// Gas readings carry their concentration once the sensor has learned its baseline
+		return s.calibration.CalibrateReading(ctx, reading)
+	}
+}
+
+// alertForReading builds the alert raised for a sensor activation
+func alertForReading(reading *entities.SensorReading) *entities.Alert {
+	alert := &entities.Alert{
+		NumeroSerie:   reading.NumeroSerie,
+		Tipo:          reading.Sensor,
+		Severidad:     entities.SeverityForSensor(reading.Sensor),
+		FechaCreacion: reading.FechaActivacion,
+	}
+
+	var valor float64
+	switch reading.Sensor {
+	case entities.SensorTypeKY026:
+		alert.Mensaje = "Flame detected"
+		valor = float64(reading.Estado)
+	case entities.SensorTypeMQ2:
+		alert.Mensaje = fmt.Sprintf("Smoke or combustible gas detected (%s)", gasLevel(reading))
+		valor = gasValue(reading)
+	case entities.SensorTypeMQ135:
+		alert.Mensaje = fmt.Sprintf("Poor air quality detected (%s)", gasLevel(reading))
+		valor = gasValue(reading)
+	}
+	alert.Valor = &valor
+
+	return alert
+}
+
+// gasLevel describes a gas reading by its concentration when the sensor is calibrated
+func gasLevel(reading *entities.SensorReading) string {
+	if reading.PPM != nil {
+		return fmt.Sprintf("%.0f ppm", *reading.PPM)
+	}
+	return fmt.Sprintf("estado %d", reading.Estado)
+}
+
+// gasValue is the alert value of a gas reading, in ppm when the sensor is calibrated
+func gasValue(reading *entities.SensorReading) float64 {
+	if reading.PPM != nil {
+		return *reading.PPM
+	}
+	return float64(reading.Estado)
+}
+
+// deviceLocation returns the timezone configured for a device, falling back to UTC
+func (s *SensorService) deviceLocation(ctx context.Context, numeroSerie string) (*time.Location, error) {
+	tzName, err := s.repo.GetDeviceTimezone(ctx, numeroSerie)
+	if err != nil {
+		return nil, err
+	}
+	if tzName == "" {
+		return time.UTC, nil
+	}
+
+	loc, err := time.LoadLocation(tzName)
+	if err != nil {
+		log.Printf("Unknown timezone %q for device %s, using UTC: %v", tzName, numeroSerie, err)
+		return time.UTC, nil
+	}
+	return loc, nil
+}
+
+// GetUserAlerts retrieves all alerts for a user based on their ID, optionally narrowed
+// to a location and aggregated by the locations of one level
+func (s *SensorService) GetUserAlerts(ctx context.Context, userID int, filter *entities.AlertFilter) (map[string]interface{}, error) {
+	if err := validation.ValidateAlertFilter(filter); err != nil {
+		return nil, err
+	}
+	return s.repo.GetUserAlerts(ctx, userID, filter)
+}
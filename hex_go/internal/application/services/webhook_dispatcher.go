@@ -0,0 +1,201 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strconv"
+	"time"
+
+	"hex_go/internal/domain/entities"
+	"hex_go/internal/domain/ports"
+)
+
+const (
+	// webhookBatch is how many due deliveries are picked up per round
+	webhookBatch = 50
+	// webhookMaxRetryDelay caps the exponential backoff between attempts
+	webhookMaxRetryDelay = time.Hour
+	// webhookLease keeps a claimed delivery from being picked up again while it is sent
+	webhookLease = 5 * time.Minute
+)
+
+// WebhookDeliveryPolicy configures retries and auto-disabling of webhooks
+type WebhookDeliveryPolicy struct {
+	// MaxAttempts is how many times a delivery is tried before it fails
+	MaxAttempts int
+	// RetryBase is the delay before the first retry; it doubles on every attempt
+	RetryBase time.Duration
+	// DisableAfter is how many failed attempts in a row disable a webhook
+	DisableAfter int
+	// PollInterval is how often due retries are looked for
+	PollInterval time.Duration
+}
+
+// WebhookDispatcher sends the pending webhook deliveries. Deliveries are stored before
+// they are sent, so retries survive restarts.
+type WebhookDispatcher struct {
+	repo   ports.WebhookRepositoryPort
+	sender ports.WebhookSenderPort
+	audit  ports.AuditServicePort
+	policy WebhookDeliveryPolicy
+	wake   chan struct{}
+}
+
+func NewWebhookDispatcher(repo ports.WebhookRepositoryPort, sender ports.WebhookSenderPort, audit ports.AuditServicePort, policy WebhookDeliveryPolicy) *WebhookDispatcher {
+	if policy.MaxAttempts <= 0 {
+		policy.MaxAttempts = 1
+	}
+	if policy.DisableAfter <= 0 {
+		policy.DisableAfter = policy.MaxAttempts
+	}
+	return &WebhookDispatcher{
+		repo:   repo,
+		sender: sender,
+		audit:  audit,
+		policy: policy,
+		wake:   make(chan struct{}, 1),
+	}
+}
+
+// Notify asks the dispatcher to look for due deliveries without waiting for the next poll
+func (d *WebhookDispatcher) Notify() {
+	select {
+	case d.wake <- struct{}{}:
+	default:
+	}
+}
+
+// Run delivers due deliveries every poll interval, or when notified, until ctx is cancelled
+func (d *WebhookDispatcher) Run(ctx context.Context) {
+	log.Printf("Webhook dispatcher started: %d attempts, polling every %s", d.policy.MaxAttempts, d.policy.PollInterval)
+
+	ticker := time.NewTicker(d.policy.PollInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			log.Printf("Webhook dispatcher stopped")
+			return
+		case <-ticker.C:
+		case <-d.wake:
+		}
+
+		if err := d.DeliverDue(ctx); err != nil {
+			log.Printf("Error delivering webhooks: %v", err)
+		}
+	}
+}
+
+// DeliverDue sends every delivery whose next attempt is due
+func (d *WebhookDispatcher) DeliverDue(ctx context.Context) error {
+	now := time.Now().UTC()
+	deliveries, err := d.repo.ListDueDeliveries(ctx, now, webhookBatch)
+	if err != nil {
+		return err
+	}
+
+	for _, delivery := range deliveries {
+		claimed, err := d.repo.ClaimDelivery(ctx, delivery, now.Add(webhookLease))
+		if err != nil {
+			return err
+		}
+		if !claimed {
+			// Another dispatcher is sending it
+			continue
+		}
+
+		webhook, err := d.repo.GetWebhook(ctx, delivery.IDWebhook)
+		if err != nil {
+			return err
+		}
+		if !webhook.Activo {
+			delivery.Estado = entities.DeliveryFailed
+			delivery.ProximoIntento = nil
+			delivery.Error = "webhook is disabled"
+			if err := d.repo.UpdateDelivery(ctx, delivery); err != nil {
+				return err
+			}
+			continue
+		}
+
+		if err := d.attempt(ctx, webhook, delivery, true); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// attempt sends a delivery once and stores the outcome. Failed deliveries are retried
+// with exponential backoff when retry is set, until MaxAttempts is reached.
+func (d *WebhookDispatcher) attempt(ctx context.Context, webhook *entities.Webhook, delivery *entities.WebhookDelivery, retry bool) error {
+	now := time.Now().UTC()
+	body := []byte(delivery.Payload)
+	headers := map[string]string{
+		entities.WebhookSignatureHeader: entities.SignWebhookPayload(webhook.Secreto, now, body),
+		entities.WebhookEventHeader:     delivery.Evento,
+		entities.WebhookDeliveryHeader:  strconv.FormatInt(delivery.ID, 10),
+	}
+
+	status, err := d.sender.Send(ctx, webhook.URL, headers, body)
+	delivery.Intentos++
+	delivery.CodigoHTTP = nil
+	if status != 0 {
+		delivery.CodigoHTTP = &status
+	}
+
+	success := err == nil && status >= 200 && status < 300
+	switch {
+	case success:
+		delivery.Estado = entities.DeliveryDelivered
+		delivery.ProximoIntento = nil
+		delivery.FechaEntrega = &now
+		delivery.Error = ""
+	case retry && delivery.Intentos < d.policy.MaxAttempts:
+		next := now.Add(d.retryDelay(delivery.Intentos))
+		delivery.ProximoIntento = &next
+		delivery.Error = deliveryError(status, err)
+	default:
+		delivery.Estado = entities.DeliveryFailed
+		delivery.ProximoIntento = nil
+		delivery.Error = deliveryError(status, err)
+	}
+
+	if err := d.repo.UpdateDelivery(ctx, delivery); err != nil {
+		return err
+	}
+
+	disabled, err := d.repo.RecordWebhookResult(ctx, webhook.ID, success, d.policy.DisableAfter)
+	if err != nil {
+		return err
+	}
+	if disabled {
+		log.Printf("Webhook %d disabled after %d failed deliveries in a row", webhook.ID, d.policy.DisableAfter)
+		d.audit.Record(ctx, entities.AuditWebhookDisabled, entities.ResourceWebhook, strconv.FormatInt(webhook.ID, 10),
+			map[string]bool{"activo": true}, map[string]bool{"activo": false})
+		return d.repo.FailPendingDeliveries(ctx, webhook.ID, "webhook was disabled after repeated failures")
+	}
+
+	return nil
+}
+
+// retryDelay doubles the base delay after every attempt, up to webhookMaxRetryDelay
+func (d *WebhookDispatcher) retryDelay(attempts int) time.Duration {
+	delay := d.policy.RetryBase
+	for i := 1; i < attempts && delay < webhookMaxRetryDelay; i++ {
+		delay *= 2
+	}
+	if delay > webhookMaxRetryDelay {
+		delay = webhookMaxRetryDelay
+	}
+	return delay
+}
+
+func deliveryError(status int, err error) string {
+	if err != nil {
+		return err.Error()
+	}
+	return fmt.Sprintf("unexpected HTTP status %d", status)
+}
@@ -0,0 +1,383 @@
+package services
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"hex_go/internal/domain/entities"
+	"hex_go/internal/domain/ports"
+	"hex_go/pkg/webhook"
+)
+
+// memoryWebhooks keeps the webhooks and deliveries used by the dispatcher in memory,
+// counting failures in a row like the MySQL repository
+type memoryWebhooks struct {
+	ports.WebhookRepositoryPort
+
+	mu         sync.Mutex
+	webhooks   map[int64]*entities.Webhook
+	deliveries map[int64]*entities.WebhookDelivery
+	failures   map[int64]int
+	failed     map[int64]string
+}
+
+func newMemoryWebhooks(webhooks ...*entities.Webhook) *memoryWebhooks {
+	repo := &memoryWebhooks{
+		webhooks:   map[int64]*entities.Webhook{},
+		deliveries: map[int64]*entities.WebhookDelivery{},
+		failures:   map[int64]int{},
+		failed:     map[int64]string{},
+	}
+	for _, w := range webhooks {
+		repo.webhooks[w.ID] = w
+	}
+	return repo
+}
+
+func (r *memoryWebhooks) add(delivery *entities.WebhookDelivery) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.deliveries[delivery.ID] = delivery
+}
+
+func (r *memoryWebhooks) GetWebhook(ctx context.Context, id int64) (*entities.Webhook, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	w, ok := r.webhooks[id]
+	if !ok {
+		return nil, entities.ErrNotFound
+	}
+	copied := *w
+	return &copied, nil
+}
+
+func (r *memoryWebhooks) ListDueDeliveries(ctx context.Context, now time.Time, limit int) ([]*entities.WebhookDelivery, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var due []*entities.WebhookDelivery
+	for _, d := range r.deliveries {
+		if d.Estado == entities.DeliveryPending && d.ProximoIntento != nil && !d.ProximoIntento.After(now) {
+			copied := *d
+			due = append(due, &copied)
+		}
+	}
+	return due, nil
+}
+
+func (r *memoryWebhooks) ClaimDelivery(ctx context.Context, delivery *entities.WebhookDelivery, leaseUntil time.Time) (bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	stored := r.deliveries[delivery.ID]
+	if stored.ProximoIntento == nil || !stored.ProximoIntento.Equal(*delivery.ProximoIntento) {
+		return false, nil
+	}
+	stored.ProximoIntento = &leaseUntil
+	return true, nil
+}
+
+func (r *memoryWebhooks) UpdateDelivery(ctx context.Context, delivery *entities.WebhookDelivery) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	copied := *delivery
+	r.deliveries[delivery.ID] = &copied
+	return nil
+}
+
+func (r *memoryWebhooks) RecordWebhookResult(ctx context.Context, id int64, success bool, disableAfter int) (bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if success {
+		r.failures[id] = 0
+		return false, nil
+	}
+	r.failures[id]++
+	w := r.webhooks[id]
+	if w.Activo && r.failures[id] >= disableAfter {
+		w.Activo = false
+		return true, nil
+	}
+	return false, nil
+}
+
+func (r *memoryWebhooks) FailPendingDeliveries(ctx context.Context, webhookID int64, reason string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.failed[webhookID] = reason
+	for _, d := range r.deliveries {
+		if d.IDWebhook == webhookID && d.Estado == entities.DeliveryPending {
+			d.Estado = entities.DeliveryFailed
+			d.ProximoIntento = nil
+			d.Error = reason
+		}
+	}
+	return nil
+}
+
+func (r *memoryWebhooks) delivery(id int64) *entities.WebhookDelivery {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	copied := *r.deliveries[id]
+	return &copied
+}
+
+// recordingAudit keeps the actions recorded in the audit log
+type recordingAudit struct {
+	ports.AuditServicePort
+	actions []string
+}
+
+func (a *recordingAudit) Record(ctx context.Context, action, resource, resourceID string, before, after interface{}) {
+	a.actions = append(a.actions, action+" "+resource+" "+resourceID)
+}
+
+// receiver is an httptest server answering every delivery with the next status of a
+// list, repeating the last one
+type receiver struct {
+	*httptest.Server
+
+	mu       sync.Mutex
+	statuses []int
+	requests []*http.Request
+	bodies   [][]byte
+}
+
+func newReceiver(t *testing.T, statuses ...int) *receiver {
+	rec := &receiver{statuses: statuses}
+	rec.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		rec.mu.Lock()
+		status := rec.statuses[0]
+		if len(rec.statuses) > 1 {
+			rec.statuses = rec.statuses[1:]
+		}
+		rec.requests = append(rec.requests, r)
+		rec.bodies = append(rec.bodies, body)
+		rec.mu.Unlock()
+		w.WriteHeader(status)
+	}))
+	t.Cleanup(rec.Close)
+	return rec
+}
+
+func (rec *receiver) count() int {
+	rec.mu.Lock()
+	defer rec.mu.Unlock()
+	return len(rec.requests)
+}
+
+// pendingDelivery returns a delivery due now
+func pendingDelivery(id, webhookID int64) *entities.WebhookDelivery {
+	now := time.Now().UTC().Add(-time.Second)
+	return &entities.WebhookDelivery{
+		ID:             id,
+		IDWebhook:      webhookID,
+		Evento:         "alert.created",
+		Payload:        `{"evento":"alert.created","alerta":{"id":` + strconv.FormatInt(id, 10) + `}}`,
+		Estado:         entities.DeliveryPending,
+		ProximoIntento: &now,
+		FechaCreacion:  now,
+	}
+}
+
+// makeDue moves the next attempt of a delivery to now, as if the backoff had elapsed
+func (r *memoryWebhooks) makeDue(id int64) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if d := r.deliveries[id]; d.ProximoIntento != nil {
+		now := time.Now().UTC().Add(-time.Second)
+		d.ProximoIntento = &now
+	}
+}
+
+func newTestDispatcher(repo *memoryWebhooks, audit *recordingAudit, policy WebhookDeliveryPolicy) *WebhookDispatcher {
+	// The receivers listen on the loopback interface
+	return NewWebhookDispatcher(repo, webhook.NewClient(time.Second, true), audit, policy)
+}
+
+func TestWebhookDispatcherSignsDeliveries(t *testing.T) {
+	rec := newReceiver(t, http.StatusNoContent)
+	repo := newMemoryWebhooks(&entities.Webhook{ID: 1, URL: rec.URL, Secreto: "whsec_test", Activo: true})
+	repo.add(pendingDelivery(10, 1))
+	dispatcher := newTestDispatcher(repo, &recordingAudit{}, WebhookDeliveryPolicy{MaxAttempts: 3, RetryBase: time.Minute})
+
+	if err := dispatcher.DeliverDue(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	if rec.count() != 1 {
+		t.Fatalf("receiver got %d requests, want 1", rec.count())
+	}
+	req, body := rec.requests[0], rec.bodies[0]
+	if got := req.Header.Get(entities.WebhookEventHeader); got != "alert.created" {
+		t.Errorf("%s = %q, want alert.created", entities.WebhookEventHeader, got)
+	}
+	if got := req.Header.Get(entities.WebhookDeliveryHeader); got != "10" {
+		t.Errorf("%s = %q, want 10", entities.WebhookDeliveryHeader, got)
+	}
+
+	// A receiver checks the signature with the shared secret over "timestamp.body"
+	signature := req.Header.Get(entities.WebhookSignatureHeader)
+	parts := strings.Split(signature, ",")
+	if len(parts) != 2 || !strings.HasPrefix(parts[0], "t=") || !strings.HasPrefix(parts[1], "v1=") {
+		t.Fatalf("%s = %q, want t=<unix>,v1=<hex>", entities.WebhookSignatureHeader, signature)
+	}
+	timestamp := strings.TrimPrefix(parts[0], "t=")
+	mac := hmac.New(sha256.New, []byte("whsec_test"))
+	mac.Write([]byte(timestamp + "." + string(body)))
+	if want := hex.EncodeToString(mac.Sum(nil)); strings.TrimPrefix(parts[1], "v1=") != want {
+		t.Errorf("signature v1 = %s, want %s", strings.TrimPrefix(parts[1], "v1="), want)
+	}
+	unix, err := strconv.ParseInt(timestamp, 10, 64)
+	if err != nil || time.Since(time.Unix(unix, 0)) > time.Minute {
+		t.Errorf("signature timestamp %q is not the time of sending", timestamp)
+	}
+
+	delivery := repo.delivery(10)
+	if delivery.Estado != entities.DeliveryDelivered || delivery.Intentos != 1 || delivery.FechaEntrega == nil {
+		t.Errorf("delivery = %+v, want delivered on the first attempt", delivery)
+	}
+	if delivery.CodigoHTTP == nil || *delivery.CodigoHTTP != http.StatusNoContent {
+		t.Errorf("codigo_http = %v, want %d", delivery.CodigoHTTP, http.StatusNoContent)
+	}
+}
+
+func TestWebhookDispatcherRetriesWithBackoff(t *testing.T) {
+	rec := newReceiver(t, http.StatusInternalServerError)
+	repo := newMemoryWebhooks(&entities.Webhook{ID: 1, URL: rec.URL, Secreto: "whsec_test", Activo: true})
+	repo.add(pendingDelivery(10, 1))
+	base := 30 * time.Second
+	dispatcher := newTestDispatcher(repo, &recordingAudit{}, WebhookDeliveryPolicy{MaxAttempts: 5, RetryBase: base, DisableAfter: 100})
+
+	// The delay doubles after every failed attempt
+	wantDelays := []time.Duration{base, 2 * base, 4 * base, 8 * base}
+	for attempt, want := range wantDelays {
+		before := time.Now().UTC()
+		if err := dispatcher.DeliverDue(context.Background()); err != nil {
+			t.Fatal(err)
+		}
+
+		delivery := repo.delivery(10)
+		if delivery.Estado != entities.DeliveryPending || delivery.Intentos != attempt+1 {
+			t.Fatalf("after attempt %d delivery = %+v, want pending", attempt+1, delivery)
+		}
+		if delivery.CodigoHTTP == nil || *delivery.CodigoHTTP != http.StatusInternalServerError {
+			t.Errorf("codigo_http = %v, want 500", delivery.CodigoHTTP)
+		}
+		delay := delivery.ProximoIntento.Sub(before)
+		if delay < want-time.Second || delay > want+time.Second {
+			t.Errorf("after attempt %d the next one is in %s, want %s", attempt+1, delay, want)
+		}
+
+		// Not due yet: nothing is sent
+		if err := dispatcher.DeliverDue(context.Background()); err != nil {
+			t.Fatal(err)
+		}
+		if rec.count() != attempt+1 {
+			t.Fatalf("receiver got %d requests before the retry was due, want %d", rec.count(), attempt+1)
+		}
+		repo.makeDue(10)
+	}
+
+	// The last attempt gives up
+	if err := dispatcher.DeliverDue(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	delivery := repo.delivery(10)
+	if delivery.Estado != entities.DeliveryFailed || delivery.Intentos != 5 || delivery.ProximoIntento != nil {
+		t.Errorf("after the last attempt delivery = %+v, want failed", delivery)
+	}
+	if delivery.Error != "unexpected HTTP status 500" {
+		t.Errorf("error = %q", delivery.Error)
+	}
+	if rec.count() != 5 {
+		t.Errorf("receiver got %d requests, want 5", rec.count())
+	}
+}
+
+func TestWebhookDispatcherRetryDelayIsCapped(t *testing.T) {
+	dispatcher := NewWebhookDispatcher(nil, nil, nil, WebhookDeliveryPolicy{RetryBase: 10 * time.Minute})
+
+	tests := []struct {
+		attempts int
+		want     time.Duration
+	}{
+		{1, 10 * time.Minute},
+		{2, 20 * time.Minute},
+		{3, 40 * time.Minute},
+		{4, webhookMaxRetryDelay},
+		{20, webhookMaxRetryDelay},
+	}
+	for _, tt := range tests {
+		if got := dispatcher.retryDelay(tt.attempts); got != tt.want {
+			t.Errorf("retryDelay(%d) = %s, want %s", tt.attempts, got, tt.want)
+		}
+	}
+}
+
+func TestWebhookDispatcherDisablesFailingWebhooks(t *testing.T) {
+	rec := newReceiver(t, http.StatusBadGateway)
+	repo := newMemoryWebhooks(&entities.Webhook{ID: 1, URL: rec.URL, Secreto: "whsec_test", Activo: true})
+	audit := &recordingAudit{}
+	dispatcher := newTestDispatcher(repo, audit, WebhookDeliveryPolicy{MaxAttempts: 1, RetryBase: time.Minute, DisableAfter: 3})
+
+	// A fourth delivery waits for a retry while three fail one after the other
+	waiting := pendingDelivery(4, 1)
+	later := time.Now().UTC().Add(time.Hour)
+	waiting.ProximoIntento = &later
+	repo.add(waiting)
+	for id := int64(1); id <= 3; id++ {
+		repo.add(pendingDelivery(id, 1))
+		if err := dispatcher.DeliverDue(context.Background()); err != nil {
+			t.Fatal(err)
+		}
+		if active := repo.webhooks[1].Activo; active != (id < 3) {
+			t.Fatalf("after %d failures activo = %v", id, active)
+		}
+	}
+
+	if len(audit.actions) != 1 || audit.actions[0] != entities.AuditWebhookDisabled+" "+entities.ResourceWebhook+" 1" {
+		t.Errorf("audit actions = %v, want one %s", audit.actions, entities.AuditWebhookDisabled)
+	}
+	if delivery := repo.delivery(4); delivery.Estado != entities.DeliveryFailed || delivery.Error != repo.failed[1] {
+		t.Errorf("waiting delivery = %+v, want given up", delivery)
+	}
+
+	// A disabled webhook receives nothing more
+	repo.add(pendingDelivery(5, 1))
+	if err := dispatcher.DeliverDue(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if rec.count() != 3 {
+		t.Errorf("receiver got %d requests, want 3", rec.count())
+	}
+	if delivery := repo.delivery(5); delivery.Estado != entities.DeliveryFailed || delivery.Error != "webhook is disabled" {
+		t.Errorf("delivery to the disabled webhook = %+v, want failed", delivery)
+	}
+}
+
+func TestWebhookDispatcherSuccessResetsFailures(t *testing.T) {
+	rec := newReceiver(t, http.StatusInternalServerError, http.StatusInternalServerError, http.StatusOK, http.StatusInternalServerError, http.StatusInternalServerError)
+	repo := newMemoryWebhooks(&entities.Webhook{ID: 1, URL: rec.URL, Secreto: "whsec_test", Activo: true})
+	dispatcher := newTestDispatcher(repo, &recordingAudit{}, WebhookDeliveryPolicy{MaxAttempts: 1, RetryBase: time.Minute, DisableAfter: 3})
+
+	for id := int64(1); id <= 5; id++ {
+		repo.add(pendingDelivery(id, 1))
+		if err := dispatcher.DeliverDue(context.Background()); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if !repo.webhooks[1].Activo {
+		t.Error("webhook was disabled although a delivery succeeded in between")
+	}
+}
@@ -0,0 +1,267 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"strconv"
+	"strings"
+	"time"
+
+	"hex_go/internal/application/validation"
+	"hex_go/internal/domain/entities"
+	"hex_go/internal/domain/ports"
+)
+
+// webhookDeliveryLimit caps the number of deliveries listed at once
+const webhookDeliveryLimit = 100
+
+type WebhookService struct {
+	repo         ports.WebhookRepositoryPort
+	deviceRepo   ports.DeviceRepositoryPort
+	locationRepo ports.LocationRepositoryPort
+	dispatcher   *WebhookDispatcher
+	audit        ports.AuditServicePort
+}
+
+// NewWebhookService creates the webhook service. It is also an alert subscriber that
+// queues a delivery for every webhook watching the device of a new alert.
+func NewWebhookService(repo ports.WebhookRepositoryPort, deviceRepo ports.DeviceRepositoryPort, locationRepo ports.LocationRepositoryPort, dispatcher *WebhookDispatcher, audit ports.AuditServicePort) *WebhookService {
+	return &WebhookService{
+		repo:         repo,
+		deviceRepo:   deviceRepo,
+		locationRepo: locationRepo,
+		dispatcher:   dispatcher,
+		audit:        audit,
+	}
+}
+
+// CreateWebhook registers a webhook for one of the user's devices or locations
+func (s *WebhookService) CreateWebhook(ctx context.Context, userID int, req *entities.CreateWebhookRequest) (*entities.CreatedWebhook, error) {
+	if err := validation.ValidateCreateWebhook(req); err != nil {
+		return nil, err
+	}
+	if err := s.checkTarget(ctx, userID, req.NumeroSerie, req.IDUbicacion); err != nil {
+		return nil, err
+	}
+
+	secreto, err := randomHex(32)
+	if err != nil {
+		return nil, err
+	}
+
+	webhook := &entities.Webhook{
+		IDUser:        userID,
+		URL:           strings.TrimSpace(req.URL),
+		NumeroSerie:   req.NumeroSerie,
+		IDUbicacion:   req.IDUbicacion,
+		Tipos:         uniqueStrings(req.Tipos),
+		Activo:        true,
+		FechaCreacion: time.Now().UTC(),
+		Secreto:       secreto,
+	}
+	if err := s.repo.CreateWebhook(ctx, webhook); err != nil {
+		return nil, err
+	}
+	s.audit.Record(ctx, entities.AuditWebhookCreated, entities.ResourceWebhook, webhookResourceID(webhook.ID), nil, webhook)
+
+	return &entities.CreatedWebhook{Webhook: webhook, Secreto: secreto}, nil
+}
+
+// ListWebhooks returns the webhooks of the user
+func (s *WebhookService) ListWebhooks(ctx context.Context, userID int) ([]*entities.Webhook, error) {
+	return s.repo.ListUserWebhooks(ctx, userID)
+}
+
+// GetWebhook returns a webhook of the user
+func (s *WebhookService) GetWebhook(ctx context.Context, userID int, id int64) (*entities.Webhook, error) {
+	return s.ownedWebhook(ctx, userID, id)
+}
+
+// UpdateWebhook changes the URL or alert types of a webhook, or turns it on and off
+func (s *WebhookService) UpdateWebhook(ctx context.Context, userID int, id int64, req *entities.UpdateWebhookRequest) (*entities.Webhook, error) {
+	if err := validation.ValidateUpdateWebhook(req); err != nil {
+		return nil, err
+	}
+
+	webhook, err := s.ownedWebhook(ctx, userID, id)
+	if err != nil {
+		return nil, err
+	}
+	before := *webhook
+
+	if req.URL != nil {
+		webhook.URL = strings.TrimSpace(*req.URL)
+	}
+	if req.Tipos != nil {
+		webhook.Tipos = uniqueStrings(*req.Tipos)
+	}
+	if req.Activo != nil {
+		webhook.Activo = *req.Activo
+		webhook.FallosConsecutivos = 0
+	}
+
+	if err := s.repo.UpdateWebhook(ctx, webhook); err != nil {
+		return nil, err
+	}
+	s.audit.Record(ctx, entities.AuditWebhookUpdated, entities.ResourceWebhook, webhookResourceID(id), &before, webhook)
+
+	return webhook, nil
+}
+
+// DeleteWebhook removes a webhook together with its delivery log
+func (s *WebhookService) DeleteWebhook(ctx context.Context, userID int, id int64) error {
+	webhook, err := s.ownedWebhook(ctx, userID, id)
+	if err != nil {
+		return err
+	}
+
+	if err := s.repo.DeleteWebhook(ctx, id); err != nil {
+		return err
+	}
+	s.audit.Record(ctx, entities.AuditWebhookDeleted, entities.ResourceWebhook, webhookResourceID(id), webhook, nil)
+	return nil
+}
+
+// SendTestEvent sends a sample alert to a webhook right away and returns the outcome.
+// Test deliveries are attempted once and not retried.
+func (s *WebhookService) SendTestEvent(ctx context.Context, userID int, id int64) (*entities.WebhookDelivery, error) {
+	webhook, err := s.ownedWebhook(ctx, userID, id)
+	if err != nil {
+		return nil, err
+	}
+
+	numeroSerie := "TEST"
+	if webhook.NumeroSerie != nil {
+		numeroSerie = *webhook.NumeroSerie
+	}
+	now := time.Now().UTC()
+	sample := &entities.Alert{
+		NumeroSerie:   numeroSerie,
+		Tipo:          entities.SensorTypeKY026,
+		Severidad:     entities.SeverityForSensor(entities.SensorTypeKY026),
+		Mensaje:       "Test event, no action required",
+		Estado:        entities.AlertStateActive,
+		FechaCreacion: now,
+	}
+
+	delivery, err := s.queueDelivery(ctx, webhook, entities.WebhookEventTest, sample, nil)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.dispatcher.attempt(ctx, webhook, delivery, false); err != nil {
+		return nil, err
+	}
+
+	return delivery, nil
+}
+
+// ListDeliveries returns the latest deliveries of a webhook
+func (s *WebhookService) ListDeliveries(ctx context.Context, userID int, id int64) ([]*entities.WebhookDelivery, error) {
+	if _, err := s.ownedWebhook(ctx, userID, id); err != nil {
+		return nil, err
+	}
+	return s.repo.ListDeliveries(ctx, id, webhookDeliveryLimit)
+}
+
+// AlertRaised queues a delivery of the alert for every matching webhook and wakes the
+// dispatcher. Failures are logged since the alert itself is already stored.
+func (s *WebhookService) AlertRaised(ctx context.Context, alert *entities.Alert) {
+	webhooks, err := s.repo.ListWebhooksForDevice(ctx, alert.NumeroSerie)
+	if err != nil {
+		log.Printf("Error finding webhooks for alert %d: %v", alert.ID, err)
+		return
+	}
+
+	now := time.Now().UTC()
+	queued := false
+	for _, webhook := range webhooks {
+		if !webhook.Accepts(alert.Tipo) {
+			continue
+		}
+		if _, err := s.queueDelivery(ctx, webhook, entities.WebhookEventAlertCreated, alert, &now); err != nil {
+			log.Printf("Error queueing alert %d for webhook %d: %v", alert.ID, webhook.ID, err)
+			continue
+		}
+		queued = true
+	}
+
+	if queued {
+		s.dispatcher.Notify()
+	}
+}
+
+// queueDelivery stores a pending delivery. Deliveries without a next attempt are not
+// picked up by the dispatcher.
+func (s *WebhookService) queueDelivery(ctx context.Context, webhook *entities.Webhook, event string, alert *entities.Alert, next *time.Time) (*entities.WebhookDelivery, error) {
+	now := time.Now().UTC()
+	payload, err := json.Marshal(&entities.WebhookPayload{Evento: event, Fecha: now, Alerta: alert})
+	if err != nil {
+		return nil, fmt.Errorf("error encoding webhook payload: %w", err)
+	}
+
+	delivery := &entities.WebhookDelivery{
+		IDWebhook:      webhook.ID,
+		Evento:         event,
+		Payload:        string(payload),
+		Estado:         entities.DeliveryPending,
+		ProximoIntento: next,
+		FechaCreacion:  now,
+	}
+	if err := s.repo.CreateDelivery(ctx, delivery); err != nil {
+		return nil, err
+	}
+	return delivery, nil
+}
+
+// checkTarget makes sure the watched device or location belongs to the user
+func (s *WebhookService) checkTarget(ctx context.Context, userID int, numeroSerie *string, locationID *int64) error {
+	return checkOwnedTarget(ctx, s.deviceRepo, s.locationRepo, userID, numeroSerie, locationID)
+}
+
+// checkOwnedTarget makes sure a device, or else a location, belongs to the user. Targets
+// of other users are reported as not found.
+func checkOwnedTarget(ctx context.Context, deviceRepo ports.DeviceRepositoryPort, locationRepo ports.LocationRepositoryPort, userID int, numeroSerie *string, locationID *int64) error {
+	if numeroSerie != nil {
+		device, err := deviceRepo.GetDevice(ctx, *numeroSerie)
+		if err != nil && err != entities.ErrNotFound {
+			return err
+		}
+		if err != nil || device.IDUser == nil || *device.IDUser != userID {
+			return &entities.ValidationError{Field: "numero_serie", Message: "device not found"}
+		}
+		return nil
+	}
+
+	location, err := locationRepo.GetLocation(ctx, *locationID)
+	if err != nil && err != entities.ErrNotFound {
+		return err
+	}
+	if err != nil || location.IDUser != userID {
+		return &entities.ValidationError{Field: "id_ubicacion", Message: "location not found"}
+	}
+	return nil
+}
+
+// ownedWebhook loads a webhook and hides it from other users
+func (s *WebhookService) ownedWebhook(ctx context.Context, userID int, id int64) (*entities.Webhook, error) {
+	webhook, err := s.repo.GetWebhook(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if webhook.IDUser != userID {
+		return nil, entities.ErrNotFound
+	}
+	return webhook, nil
+}
+
+func webhookResourceID(id int64) string {
+	return strconv.FormatInt(id, 10)
+}
+
+// Verify interface implementation
+var (
+	_ ports.WebhookServicePort  = (*WebhookService)(nil)
+	_ ports.AlertSubscriberPort = (*WebhookService)(nil)
+)
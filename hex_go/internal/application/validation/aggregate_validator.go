@@ -0,0 +1,56 @@
+package validation
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"hex_go/internal/domain/entities"
+)
+
+const (
+	defaultAggregateRange = 24 * time.Hour
+	maxAggregateBuckets   = 2000
+)
+
+// ValidateAggregateQuery checks an aggregates query, defaults its range to the day before
+// now and widens it to whole buckets
+func ValidateAggregateQuery(query *entities.AggregateQuery, now time.Time) error {
+	var errs entities.ValidationErrors
+
+	if entities.AggregateFields(query.Sensor) == nil {
+		errs.Add("type", fmt.Sprintf("must be one of %s", strings.Join(entities.SensorTypes, ", ")))
+	}
+	size, ok := entities.AggregateBucketSize(query.Bucket)
+	if !ok {
+		errs.Add("bucket", fmt.Sprintf("must be one of %s", strings.Join(entities.AggregateBuckets, ", ")))
+	}
+	if err := errs.Err(); err != nil {
+		return err
+	}
+
+	hasta := now.UTC()
+	if query.Hasta != nil {
+		hasta = query.Hasta.UTC()
+	}
+	desde := hasta.Add(-defaultAggregateRange)
+	if query.Desde != nil {
+		desde = query.Desde.UTC()
+	}
+	if !desde.Before(hasta) {
+		errs.Add("to", "must be after from")
+		return errs.Err()
+	}
+
+	// A bucket that is only partly in range is returned whole
+	desde = desde.Truncate(size)
+	if aligned := hasta.Truncate(size); aligned.Before(hasta) {
+		hasta = aligned.Add(size)
+	}
+	if buckets := hasta.Sub(desde) / size; buckets > maxAggregateBuckets {
+		errs.Add("bucket", fmt.Sprintf("gives %d buckets over the range, at most %d are returned; use a larger bucket", buckets, maxAggregateBuckets))
+	}
+	query.Desde, query.Hasta = &desde, &hasta
+
+	return errs.Err()
+}
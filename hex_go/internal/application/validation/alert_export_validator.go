@@ -0,0 +1,27 @@
+package validation
+
+import (
+	"fmt"
+	"strings"
+
+	"hex_go/internal/domain/entities"
+)
+
+// ValidateAlertExportQuery checks an alert export query and defaults its language to Spanish
+func ValidateAlertExportQuery(query *entities.AlertExportQuery) error {
+	var errs entities.ValidationErrors
+
+	if !entities.IsExportFormat(query.Formato) {
+		errs.Add("format", fmt.Sprintf("must be one of %s", strings.Join(entities.ExportFormats, ", ")))
+	}
+	if query.Idioma == "" {
+		query.Idioma = entities.LanguageSpanish
+	} else if !entities.IsNotificationLanguage(query.Idioma) {
+		errs.Add("lang", fmt.Sprintf("must be one of %s", strings.Join(entities.NotificationLanguages, ", ")))
+	}
+	if query.Desde != nil && query.Hasta != nil && !query.Hasta.After(*query.Desde) {
+		errs.Add("to", "must be after from")
+	}
+
+	return errs.Err()
+}
@@ -0,0 +1,41 @@
+package validation
+
+import (
+	"fmt"
+	"strings"
+	"unicode/utf8"
+
+	"hex_go/internal/domain/entities"
+)
+
+const (
+	maxAPIKeyNameLength = 100
+	// maxAPIKeyLifetimeDays caps how long a key can stay valid
+	maxAPIKeyLifetimeDays = 730
+)
+
+// ValidateCreateAPIKey checks a request to issue an API key
+func ValidateCreateAPIKey(req *entities.CreateAPIKeyRequest) error {
+	var errs entities.ValidationErrors
+
+	if strings.TrimSpace(req.Nombre) == "" {
+		errs.Add("nombre", "is required")
+	} else if utf8.RuneCountInString(req.Nombre) > maxAPIKeyNameLength {
+		errs.Add("nombre", fmt.Sprintf("must be at most %d characters", maxAPIKeyNameLength))
+	}
+
+	if len(req.Scopes) == 0 {
+		errs.Add("scopes", "must grant at least one scope")
+	}
+	for _, scope := range req.Scopes {
+		if !entities.IsAPIKeyScope(scope) {
+			errs.Add("scopes", fmt.Sprintf("unknown scope %q, must be one of %s", scope, strings.Join(entities.APIKeyScopes, ", ")))
+		}
+	}
+
+	if req.ExpiraEnDias < 0 || req.ExpiraEnDias > maxAPIKeyLifetimeDays {
+		errs.Add("expira_en_dias", fmt.Sprintf("must be between 1 and %d days", maxAPIKeyLifetimeDays))
+	}
+
+	return errs.Err()
+}
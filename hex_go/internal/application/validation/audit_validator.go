@@ -0,0 +1,31 @@
+package validation
+
+import (
+	"fmt"
+
+	"hex_go/internal/domain/entities"
+)
+
+const (
+	defaultAuditLimit = 100
+	maxAuditLimit     = 500
+)
+
+// ValidateAuditQuery checks an audit log query and applies the default page size
+func ValidateAuditQuery(query *entities.AuditQuery) error {
+	var errs entities.ValidationErrors
+
+	if query.Limit == 0 {
+		query.Limit = defaultAuditLimit
+	} else if query.Limit < 0 || query.Limit > maxAuditLimit {
+		errs.Add("limit", fmt.Sprintf("must be between 1 and %d", maxAuditLimit))
+	}
+	if query.BeforeID < 0 {
+		errs.Add("before_id", "must be a positive entry ID")
+	}
+	if query.Desde != nil && query.Hasta != nil && query.Hasta.Before(*query.Desde) {
+		errs.Add("to", "must not be before from")
+	}
+
+	return errs.Err()
+}
@@ -0,0 +1,48 @@
+package validation
+
+import (
+	"fmt"
+	"strings"
+
+	"hex_go/internal/domain/entities"
+)
+
+// maxCalibrationGain bounds the gain; units differing more than that are faulty
+const maxCalibrationGain = 10
+
+// ValidateCalibrationSensor checks that a sensor type is calibrated
+func ValidateCalibrationSensor(sensor string) error {
+	if !entities.IsCalibratedSensor(sensor) {
+		return &entities.ValidationError{
+			Field:   "sensor",
+			Message: fmt.Sprintf("must be one of %s", strings.Join(calibratedSensors(), ", ")),
+		}
+	}
+	return nil
+}
+
+// ValidateCalibration checks the offset and gain of a sensor calibration
+func ValidateCalibration(sensor string, req *entities.CalibrationRequest) error {
+	var errs entities.ValidationErrors
+
+	errs.Append("sensor", ValidateCalibrationSensor(sensor))
+	if req.Offset < -entities.CalibrationADCMax || req.Offset > entities.CalibrationADCMax {
+		errs.Add("offset", fmt.Sprintf("must be between %d and %d", -entities.CalibrationADCMax, entities.CalibrationADCMax))
+	}
+	if req.Ganancia <= 0 || req.Ganancia > maxCalibrationGain {
+		errs.Add("ganancia", fmt.Sprintf("must be above 0 and at most %d", maxCalibrationGain))
+	}
+
+	return errs.Err()
+}
+
+// calibratedSensors lists the calibrated sensor types in the order of SensorTypes
+func calibratedSensors() []string {
+	var sensors []string
+	for _, sensor := range entities.SensorTypes {
+		if entities.IsCalibratedSensor(sensor) {
+			sensors = append(sensors, sensor)
+		}
+	}
+	return sensors
+}
@@ -0,0 +1,95 @@
+package validation
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"hex_go/internal/domain/entities"
+)
+
+// firmwareVersionPattern matches versions such as 1.4.2 or v2.0.0-rc1
+var firmwareVersionPattern = regexp.MustCompile(`^v?\d+\.\d+\.\d+([-+][0-9A-Za-z.-]+)?$`)
+
+const (
+	maxDeviceNameLength     = 100
+	maxDeviceLocationLength = 255
+)
+
+// ValidateClaimDevice checks a device registration request
+func ValidateClaimDevice(req *entities.ClaimDeviceRequest) error {
+	var errs entities.ValidationErrors
+
+	errs.Append("numero_serie", ValidateSerialNumber("numero_serie", req.NumeroSerie))
+	if strings.TrimSpace(req.CodigoReclamo) == "" {
+		errs.Add("codigo_reclamo", "is required")
+	}
+	if strings.TrimSpace(req.Nombre) == "" {
+		errs.Add("nombre", "is required")
+	}
+	validateDeviceFields(&errs, &req.Nombre, &req.Ubicacion, &req.SensoresInstalados, &req.VersionFirmware, &req.ZonaHoraria)
+
+	return errs.Err()
+}
+
+// ValidateUpdateDevice checks the fields present in a device update
+func ValidateUpdateDevice(req *entities.UpdateDeviceRequest) error {
+	var errs entities.ValidationErrors
+
+	if req.Nombre != nil && strings.TrimSpace(*req.Nombre) == "" {
+		errs.Add("nombre", "must not be empty")
+	}
+	validateDeviceFields(&errs, req.Nombre, req.Ubicacion, req.SensoresInstalados, req.VersionFirmware, req.ZonaHoraria)
+
+	return errs.Err()
+}
+
+// ValidateTransferDevice checks a device transfer request
+func ValidateTransferDevice(req *entities.TransferDeviceRequest) error {
+	if req.NuevoIDUser <= 0 {
+		return &entities.ValidationError{Field: "nuevo_id_user", Message: "must be a positive user ID"}
+	}
+	return nil
+}
+
+// ValidateHeartbeat checks a heartbeat sent by a board
+func ValidateHeartbeat(numeroSerie string, req *entities.HeartbeatRequest) error {
+	var errs entities.ValidationErrors
+
+	errs.Append("numeroSerie", ValidateSerialNumber("numeroSerie", numeroSerie))
+	if req.VersionFirmware != "" && !firmwareVersionPattern.MatchString(req.VersionFirmware) {
+		errs.Add("version_firmware", "must be a semantic version such as 1.4.2")
+	}
+
+	return errs.Err()
+}
+
+func validateDeviceFields(errs *entities.ValidationErrors, nombre, ubicacion *string, sensores *[]string, firmware, zonaHoraria *string) {
+	if nombre != nil && utf8.RuneCountInString(*nombre) > maxDeviceNameLength {
+		errs.Add("nombre", fmt.Sprintf("must be at most %d characters", maxDeviceNameLength))
+	}
+	if ubicacion != nil && utf8.RuneCountInString(*ubicacion) > maxDeviceLocationLength {
+		errs.Add("ubicacion", fmt.Sprintf("must be at most %d characters", maxDeviceLocationLength))
+	}
+	if sensores != nil {
+		seen := make(map[string]bool)
+		for _, sensor := range *sensores {
+			if !isSupportedSensor(sensor) {
+				errs.Add("sensores_instalados", fmt.Sprintf("unknown sensor %q, must be one of %s", sensor, strings.Join(entities.SensorTypes, ", ")))
+			} else if seen[sensor] {
+				errs.Add("sensores_instalados", fmt.Sprintf("sensor %q is listed twice", sensor))
+			}
+			seen[sensor] = true
+		}
+	}
+	if firmware != nil && *firmware != "" && !firmwareVersionPattern.MatchString(*firmware) {
+		errs.Add("version_firmware", "must be a semantic version such as 1.4.2")
+	}
+	if zonaHoraria != nil && *zonaHoraria != "" {
+		if _, err := time.LoadLocation(*zonaHoraria); err != nil {
+			errs.Add("zona_horaria", fmt.Sprintf("unknown timezone %q", *zonaHoraria))
+		}
+	}
+}
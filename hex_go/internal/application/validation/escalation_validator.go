@@ -0,0 +1,67 @@
+package validation
+
+import (
+	"fmt"
+	"strings"
+
+	"hex_go/internal/domain/entities"
+)
+
+const (
+	maxEscalationNameLength = 100
+	maxEscalationSteps      = 10
+	// maxEscalationWait is one day; alerts left that long need no further escalation
+	maxEscalationWait = 24 * 60
+)
+
+// ValidateEscalationPolicy checks an escalation policy. A policy covers either one device
+// or one location and needs at least one step.
+func ValidateEscalationPolicy(req *entities.EscalationPolicyRequest) error {
+	var errs entities.ValidationErrors
+
+	nombre := strings.TrimSpace(req.Nombre)
+	if nombre == "" {
+		errs.Add("nombre", "is required")
+	} else if len(nombre) > maxEscalationNameLength {
+		errs.Add("nombre", fmt.Sprintf("must be at most %d characters", maxEscalationNameLength))
+	}
+
+	validateTarget(&errs, req.NumeroSerie, req.IDUbicacion)
+
+	for _, severity := range req.Severidades {
+		if !entities.IsSeverity(severity) {
+			errs.Add("severidades", fmt.Sprintf("unknown severity %q, must be one of %s", severity, strings.Join(entities.Severities, ", ")))
+		}
+	}
+
+	if len(req.Pasos) == 0 {
+		errs.Add("pasos", "at least one step is required")
+	} else if len(req.Pasos) > maxEscalationSteps {
+		errs.Add("pasos", fmt.Sprintf("must have at most %d steps", maxEscalationSteps))
+	}
+	for i, step := range req.Pasos {
+		field := fmt.Sprintf("pasos[%d]", i)
+		if step == nil {
+			errs.Add(field, "is required")
+			continue
+		}
+		if strings.TrimSpace(step.Nombre) == "" {
+			errs.Add(field+".nombre", "is required")
+		} else if len(step.Nombre) > maxEscalationNameLength {
+			errs.Add(field+".nombre", fmt.Sprintf("must be at most %d characters", maxEscalationNameLength))
+		}
+		if step.EsperaMinutos < 0 || step.EsperaMinutos > maxEscalationWait {
+			errs.Add(field+".espera_minutos", fmt.Sprintf("must be between 0 and %d", maxEscalationWait))
+		}
+		if !entities.IsNotificationChannel(step.Canal) {
+			errs.Add(field+".canal", fmt.Sprintf("must be one of %s", strings.Join(entities.NotificationChannels, ", ")))
+		} else {
+			validateAddress(&errs, field+".direccion", step.Canal, step.Direccion)
+		}
+		if !entities.IsNotificationLanguage(step.Idioma) {
+			errs.Add(field+".idioma", fmt.Sprintf("must be one of %s", strings.Join(entities.NotificationLanguages, ", ")))
+		}
+	}
+
+	return errs.Err()
+}
@@ -0,0 +1,29 @@
+package validation
+
+import (
+	"fmt"
+	"strings"
+
+	"hex_go/internal/domain/entities"
+)
+
+const (
+	defaultIncidentLimit = 100
+	maxIncidentLimit     = 500
+)
+
+// ValidateIncidentFilter checks an incident filter and applies the default page size
+func ValidateIncidentFilter(filter *entities.IncidentFilter) error {
+	var errs entities.ValidationErrors
+
+	if filter.Estado != "" && !entities.IsIncidentState(filter.Estado) {
+		errs.Add("estado", fmt.Sprintf("must be one of %s", strings.Join(entities.IncidentStates, ", ")))
+	}
+	if filter.Limit == 0 {
+		filter.Limit = defaultIncidentLimit
+	} else if filter.Limit < 0 || filter.Limit > maxIncidentLimit {
+		errs.Add("limit", fmt.Sprintf("must be between 1 and %d", maxIncidentLimit))
+	}
+
+	return errs.Err()
+}
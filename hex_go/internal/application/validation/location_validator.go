@@ -0,0 +1,67 @@
+package validation
+
+import (
+	"fmt"
+	"strings"
+	"unicode/utf8"
+
+	"hex_go/internal/domain/entities"
+)
+
+const (
+	maxLocationNameLength    = 100
+	maxLocationAddressLength = 255
+)
+
+// ValidateCreateLocation checks a new location. The parent level is checked by the
+// service once the parent has been loaded.
+func ValidateCreateLocation(req *entities.CreateLocationRequest) error {
+	var errs entities.ValidationErrors
+
+	if !entities.IsLocationLevel(req.Nivel) {
+		errs.Add("nivel", fmt.Sprintf("must be one of %s", strings.Join(entities.LocationLevels, ", ")))
+	} else if req.Nivel == entities.LocationLevelSite && req.IDPadre != nil {
+		errs.Add("id_padre", "a site cannot have a parent")
+	} else if req.Nivel != entities.LocationLevelSite && req.IDPadre == nil {
+		errs.Add("id_padre", fmt.Sprintf("is required for a %s", req.Nivel))
+	}
+
+	if strings.TrimSpace(req.Nombre) == "" {
+		errs.Add("nombre", "is required")
+	}
+	validateLocationFields(&errs, &req.Nombre, &req.Direccion)
+
+	return errs.Err()
+}
+
+// ValidateUpdateLocation checks the fields present in a location update
+func ValidateUpdateLocation(req *entities.UpdateLocationRequest) error {
+	var errs entities.ValidationErrors
+
+	if req.Nombre != nil && strings.TrimSpace(*req.Nombre) == "" {
+		errs.Add("nombre", "must not be empty")
+	}
+	validateLocationFields(&errs, req.Nombre, req.Direccion)
+
+	return errs.Err()
+}
+
+// ValidateAlertFilter checks the grouping level requested for alerts
+func ValidateAlertFilter(filter *entities.AlertFilter) error {
+	if filter.GroupBy != "" && !entities.IsLocationLevel(filter.GroupBy) {
+		return &entities.ValidationError{
+			Field:   "group_by",
+			Message: fmt.Sprintf("must be one of %s", strings.Join(entities.LocationLevels, ", ")),
+		}
+	}
+	return nil
+}
+
+func validateLocationFields(errs *entities.ValidationErrors, nombre, direccion *string) {
+	if nombre != nil && utf8.RuneCountInString(*nombre) > maxLocationNameLength {
+		errs.Add("nombre", fmt.Sprintf("must be at most %d characters", maxLocationNameLength))
+	}
+	if direccion != nil && utf8.RuneCountInString(*direccion) > maxLocationAddressLength {
+		errs.Add("direccion", fmt.Sprintf("must be at most %d characters", maxLocationAddressLength))
+	}
+}
@@ -0,0 +1,49 @@
+package validation
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"hex_go/internal/domain/entities"
+)
+
+const (
+	maxMaintenanceNameLength = 100
+	// maxMaintenanceDuration keeps a forgotten window from silencing a device for good
+	maxMaintenanceDuration = 7 * 24 * time.Hour
+)
+
+// ValidateMaintenanceWindow checks a maintenance window request. A window covers either
+// one device or one location, ends after now and is given either an end or a duration.
+func ValidateMaintenanceWindow(req *entities.MaintenanceWindowRequest, now time.Time) error {
+	var errs entities.ValidationErrors
+
+	nombre := strings.TrimSpace(req.Nombre)
+	if nombre == "" {
+		errs.Add("nombre", "is required")
+	} else if len(nombre) > maxMaintenanceNameLength {
+		errs.Add("nombre", fmt.Sprintf("must be at most %d characters", maxMaintenanceNameLength))
+	}
+
+	validateTarget(&errs, req.NumeroSerie, req.IDUbicacion)
+
+	switch {
+	case req.Fin != nil && req.DuracionMinutos != 0:
+		errs.Add("fin", "cannot be combined with duracion_minutos")
+	case req.Fin == nil && req.DuracionMinutos <= 0:
+		errs.Add("fin", "either fin or a positive duracion_minutos is required")
+	default:
+		inicio, fin := req.Period(now)
+		switch {
+		case !fin.After(inicio):
+			errs.Add("fin", "must be after inicio")
+		case fin.Sub(inicio) > maxMaintenanceDuration:
+			errs.Add("fin", fmt.Sprintf("the window must last at most %s", maxMaintenanceDuration))
+		case !fin.After(now):
+			errs.Add("fin", "must be in the future")
+		}
+	}
+
+	return errs.Err()
+}
@@ -0,0 +1,127 @@
+package validation
+
+import (
+	"fmt"
+	"net/mail"
+	"regexp"
+	"strings"
+	"time"
+
+	"hex_go/internal/domain/entities"
+)
+
+const maxRecipientAddressLength = 255
+
+var (
+	// phonePattern matches phone numbers in E.164 format, such as +34600123456
+	phonePattern = regexp.MustCompile(`^\+[1-9][0-9]{6,14}$`)
+	// telegramChatPattern matches numeric chat IDs and public channel names
+	telegramChatPattern = regexp.MustCompile(`^(-?[0-9]{1,20}|@[A-Za-z][A-Za-z0-9_]{4,31})$`)
+	// pushTokenPattern matches FCM registration tokens
+	pushTokenPattern = regexp.MustCompile(`^[A-Za-z0-9_:\-]+$`)
+)
+
+// ValidateCreateRecipient checks a notification recipient. The address must suit the channel.
+func ValidateCreateRecipient(req *entities.CreateRecipientRequest) error {
+	var errs entities.ValidationErrors
+
+	if !entities.IsNotificationChannel(req.Canal) {
+		errs.Add("canal", fmt.Sprintf("must be one of %s", strings.Join(entities.NotificationChannels, ", ")))
+	}
+	if !entities.IsNotificationLanguage(req.Idioma) {
+		errs.Add("idioma", fmt.Sprintf("must be one of %s", strings.Join(entities.NotificationLanguages, ", ")))
+	}
+
+	validateAddress(&errs, "direccion", req.Canal, req.Direccion)
+
+	return errs.Err()
+}
+
+// ValidateChannelSettings checks that every severity and channel is known
+func ValidateChannelSettings(settings *entities.NotificationChannelSettings) error {
+	var errs entities.ValidationErrors
+
+	if settings.Canales == nil {
+		errs.Add("canales", "is required")
+	}
+	for severity, channels := range settings.Canales {
+		if !entities.IsSeverity(severity) {
+			errs.Add("canales", fmt.Sprintf("unknown severity %q, must be one of %s", severity, strings.Join(entities.Severities, ", ")))
+			continue
+		}
+		for _, channel := range channels {
+			if !entities.IsNotificationChannel(channel) {
+				errs.Add("canales."+severity, fmt.Sprintf("unknown channel %q, must be one of %s", channel, strings.Join(entities.NotificationChannels, ", ")))
+			}
+		}
+	}
+
+	return errs.Err()
+}
+
+// validateAddress checks that an address suits its channel
+func validateAddress(errs *entities.ValidationErrors, field, channel, address string) {
+	direccion := strings.TrimSpace(address)
+	switch {
+	case direccion == "":
+		errs.Add(field, "is required")
+	case len(direccion) > maxRecipientAddressLength:
+		errs.Add(field, fmt.Sprintf("must be at most %d characters", maxRecipientAddressLength))
+	case channel == entities.ChannelEmail:
+		if addr, err := mail.ParseAddress(direccion); err != nil || addr.Address != direccion {
+			errs.Add(field, "must be a plain email address")
+		}
+	case channel == entities.ChannelSMS:
+		if !phonePattern.MatchString(direccion) {
+			errs.Add(field, "must be a phone number in international format, such as +34600123456")
+		}
+	case channel == entities.ChannelTelegram:
+		if !telegramChatPattern.MatchString(direccion) {
+			errs.Add(field, "must be a Telegram chat ID or @channel name")
+		}
+	case channel == entities.ChannelPush:
+		if !pushTokenPattern.MatchString(direccion) {
+			errs.Add(field, "must be a push registration token")
+		}
+	}
+}
+
+// ValidateNotificationSettings checks the timezone, quiet hours, digest and minimum
+// severities of a user's notification settings
+func ValidateNotificationSettings(settings *entities.NotificationSettings) error {
+	var errs entities.ValidationErrors
+
+	if settings.ZonaHoraria == "" {
+		errs.Add("zona_horaria", "is required")
+	} else if _, err := time.LoadLocation(settings.ZonaHoraria); err != nil {
+		errs.Add("zona_horaria", fmt.Sprintf("unknown timezone %q", settings.ZonaHoraria))
+	}
+
+	if quiet := settings.HorasSilencio; quiet != nil {
+		if _, err := entities.ParseClock(quiet.Inicio); err != nil {
+			errs.Add("horas_silencio.inicio", "must be a time of day such as 22:00")
+		}
+		if _, err := entities.ParseClock(quiet.Fin); err != nil {
+			errs.Add("horas_silencio.fin", "must be a time of day such as 07:00")
+		}
+	}
+
+	if !entities.IsDigestMode(settings.Resumen) {
+		errs.Add("resumen", fmt.Sprintf("must be one of %s", strings.Join(entities.DigestModes, ", ")))
+	}
+	if settings.HoraResumen < 0 || settings.HoraResumen > 23 {
+		errs.Add("hora_resumen", "must be an hour between 0 and 23")
+	}
+
+	for channel, severity := range settings.SeveridadMinima {
+		if !entities.IsNotificationChannel(channel) {
+			errs.Add("severidad_minima", fmt.Sprintf("unknown channel %q, must be one of %s", channel, strings.Join(entities.NotificationChannels, ", ")))
+			continue
+		}
+		if severity != entities.SeverityNone && !entities.IsSeverity(severity) {
+			errs.Add("severidad_minima."+channel, fmt.Sprintf("must be %s or one of %s", entities.SeverityNone, strings.Join(entities.Severities, ", ")))
+		}
+	}
+
+	return errs.Err()
+}
@@ -0,0 +1,56 @@
+package validation
+
+import (
+	"fmt"
+	"strings"
+	"unicode/utf8"
+
+	"hex_go/internal/domain/entities"
+)
+
+const maxOrganizationNameLength = 100
+
+// ValidateCreateOrganization checks a new organisation
+func ValidateCreateOrganization(req *entities.CreateOrganizationRequest) error {
+	var errs entities.ValidationErrors
+
+	if strings.TrimSpace(req.Nombre) == "" {
+		errs.Add("nombre", "is required")
+	} else if utf8.RuneCountInString(req.Nombre) > maxOrganizationNameLength {
+		errs.Add("nombre", fmt.Sprintf("must be at most %d characters", maxOrganizationNameLength))
+	}
+
+	return errs.Err()
+}
+
+// ValidateInviteMember checks an invitation request
+func ValidateInviteMember(req *entities.InviteMemberRequest) error {
+	var errs entities.ValidationErrors
+
+	if req.IDUser <= 0 {
+		errs.Add("id_user", "must be a positive user ID")
+	}
+	errs.Append("rol", validateRole(req.Rol))
+
+	return errs.Err()
+}
+
+// ValidateUpdateMember checks a role change
+func ValidateUpdateMember(req *entities.UpdateMemberRequest) error {
+	return validateRole(req.Rol)
+}
+
+// ValidateShareDevice checks a device sharing request
+func ValidateShareDevice(req *entities.ShareDeviceRequest) error {
+	return ValidateSerialNumber("numero_serie", req.NumeroSerie)
+}
+
+func validateRole(role string) error {
+	if !entities.IsOrganizationRole(role) {
+		return &entities.ValidationError{
+			Field:   "rol",
+			Message: fmt.Sprintf("must be one of %s", strings.Join(entities.OrganizationRoles, ", ")),
+		}
+	}
+	return nil
+}
@@ -0,0 +1,19 @@
+package validation
+
+import (
+	"fmt"
+	"strings"
+
+	"hex_go/internal/domain/entities"
+)
+
+// ValidateReportLanguage checks the language a report is rendered in
+func ValidateReportLanguage(lang string) error {
+	if !entities.IsNotificationLanguage(lang) {
+		return &entities.ValidationError{
+			Field:   "lang",
+			Message: fmt.Sprintf("must be one of %s", strings.Join(entities.NotificationLanguages, ", ")),
+		}
+	}
+	return nil
+}
@@ -0,0 +1,224 @@
+package validation
+
+import (
+	"fmt"
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"hex_go/internal/domain/entities"
+)
+
+// serialNumberPattern matches the serial numbers printed on the ESP32 boards
+var serialNumberPattern = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9_-]{3,63}$`)
+
+// EstadoRange is the inclusive range of raw values a sensor may report
+type EstadoRange struct {
+	Min int
+	Max int
+}
+
+// estadoRanges holds the accepted estado values for the sensors reporting an integer.
+// KY_026 is a digital flame detector; the MQ gas sensors report the 12-bit ADC value.
+var estadoRanges = map[string]EstadoRange{
+	entities.SensorTypeKY026: {Min: 0, Max: 1},
+	entities.SensorTypeMQ2:   {Min: 0, Max: 4095},
+	entities.SensorTypeMQ135: {Min: 0, Max: 4095},
+}
+
+// ValidateSerialNumber checks the format of an ESP32 serial number
+func ValidateSerialNumber(field, numeroSerie string) error {
+	if strings.TrimSpace(numeroSerie) == "" {
+		return &entities.ValidationError{Field: field, Message: "is required"}
+	}
+	if !serialNumberPattern.MatchString(numeroSerie) {
+		return &entities.ValidationError{Field: field, Message: "must be 4-64 letters, digits, '-' or '_'"}
+	}
+	return nil
+}
+
+// ValidateSensorData checks a sensor data request and converts it into a typed reading.
+// Timestamps without an offset are interpreted in loc. Every problem found is returned
+// at once as entities.ValidationErrors.
+func ValidateSensorData(data *entities.SensorDataRequest, loc *time.Location, now time.Time) (*entities.SensorReading, error) {
+	var errs entities.ValidationErrors
+
+	reading := &entities.SensorReading{
+		NumeroSerie: data.NumeroSerie,
+		Sensor:      data.Sensor,
+	}
+
+	errs.Append("numeroSerie", ValidateSerialNumber("numeroSerie", data.NumeroSerie))
+
+	if data.Sensor == "" {
+		errs.Add("sensor", "is required")
+	} else if !isSupportedSensor(data.Sensor) {
+		errs.Add("sensor", fmt.Sprintf("must be one of %s", strings.Join(entities.SensorTypes, ", ")))
+	} else if data.Sensor == entities.SensorTypeDHT22 {
+		errs.Append("estado", validateDHT22(data, reading))
+	} else {
+		errs.Append("estado", validateEstado(data.Sensor, data.Estado, reading))
+	}
+
+	fechaActivacion, err := entities.ParseTimestamp("fecha_activacion", data.FechaActivacion, loc, now)
+	errs.Append("fecha_activacion", err)
+	reading.FechaActivacion = fechaActivacion
+
+	fechaDesactivacion, err := entities.ParseOptionalTimestamp("fecha_desactivacion", data.FechaDesactivacion, loc, now)
+	errs.Append("fecha_desactivacion", err)
+	reading.FechaDesactivacion = fechaDesactivacion
+
+	if fechaDesactivacion != nil && !fechaActivacion.IsZero() && fechaDesactivacion.Before(fechaActivacion) {
+		errs.Add("fecha_desactivacion", "is before fecha_activacion")
+	}
+
+	if err := errs.Err(); err != nil {
+		return nil, err
+	}
+	return reading, nil
+}
+
+// validateEstado converts the raw estado of a sensor and stores it in the reading
+func validateEstado(sensor string, raw interface{}, reading *entities.SensorReading) error {
+	if raw == nil {
+		return &entities.ValidationError{Field: "estado", Message: "is required"}
+	}
+
+	estado, err := toInt(raw)
+	if err != nil {
+		return &entities.ValidationError{Field: "estado", Message: err.Error()}
+	}
+	r := estadoRanges[sensor]
+	if estado < r.Min || estado > r.Max {
+		return &entities.ValidationError{
+			Field:   "estado",
+			Message: fmt.Sprintf("must be between %d and %d for %s", r.Min, r.Max, sensor),
+		}
+	}
+	reading.Estado = estado
+	return nil
+}
+
+// validateDHT22 reads the temperature and humidity of a DHT_22 payload. They may come as
+// top-level temperatura/humedad fields, as an estado object with the same keys or as the
+// legacy "temperatura,humedad" estado string.
+func validateDHT22(data *entities.SensorDataRequest, reading *entities.SensorReading) error {
+	var errs entities.ValidationErrors
+	var temperatura, humedad float64
+
+	switch estado := data.Estado.(type) {
+	case map[string]interface{}:
+		var err error
+		if temperatura, err = toFloat(estado["temperatura"]); err != nil {
+			errs.Add("estado.temperatura", err.Error())
+		}
+		if humedad, err = toFloat(estado["humedad"]); err != nil {
+			errs.Add("estado.humedad", err.Error())
+		}
+	case string:
+		if data.Temperatura != nil || data.Humedad != nil {
+			break
+		}
+		parts := strings.Split(estado, ",")
+		if len(parts) != 2 {
+			return &entities.ValidationError{Field: "estado", Message: `must be "temperatura,humedad"`}
+		}
+		var err error
+		if temperatura, err = toFloat(parts[0]); err != nil {
+			errs.Add("estado", "temperatura "+err.Error())
+		}
+		if humedad, err = toFloat(parts[1]); err != nil {
+			errs.Add("estado", "humedad "+err.Error())
+		}
+	case nil:
+		if data.Temperatura == nil && data.Humedad == nil {
+			return &entities.ValidationError{Field: "estado", Message: "is required"}
+		}
+	default:
+		return &entities.ValidationError{Field: "estado", Message: fmt.Sprintf("unsupported type %T", estado)}
+	}
+
+	if data.Temperatura != nil || data.Humedad != nil {
+		if data.Temperatura == nil {
+			errs.Add("temperatura", "is required")
+		} else {
+			temperatura = *data.Temperatura
+		}
+		if data.Humedad == nil {
+			errs.Add("humedad", "is required")
+		} else {
+			humedad = *data.Humedad
+		}
+	}
+
+	if len(errs) > 0 {
+		return errs
+	}
+
+	if temperatura < entities.DHT22MinTemperatura || temperatura > entities.DHT22MaxTemperatura {
+		errs.Add("temperatura", fmt.Sprintf("must be between %g and %g", entities.DHT22MinTemperatura, entities.DHT22MaxTemperatura))
+	}
+	if humedad < entities.DHT22MinHumedad || humedad > entities.DHT22MaxHumedad {
+		errs.Add("humedad", fmt.Sprintf("must be between %g and %g", entities.DHT22MinHumedad, entities.DHT22MaxHumedad))
+	}
+	if len(errs) > 0 {
+		return errs
+	}
+
+	reading.Temperatura = temperatura
+	reading.Humedad = humedad
+	reading.IndiceCalor = entities.HeatIndex(temperatura, humedad)
+	reading.PuntoRocio = entities.DewPoint(temperatura, humedad)
+	reading.EstadoTexto = strconv.FormatFloat(temperatura, 'f', -1, 64) + "," + strconv.FormatFloat(humedad, 'f', -1, 64)
+	return nil
+}
+
+// toFloat accepts JSON numbers and numeric strings
+func toFloat(raw interface{}) (float64, error) {
+	switch v := raw.(type) {
+	case float64:
+		return v, nil
+	case string:
+		f, err := strconv.ParseFloat(strings.TrimSpace(v), 64)
+		if err != nil {
+			return 0, fmt.Errorf("must be a number, got %q", v)
+		}
+		return f, nil
+	case nil:
+		return 0, fmt.Errorf("is required")
+	default:
+		return 0, fmt.Errorf("must be a number, got %T", v)
+	}
+}
+
+// toInt accepts the integer representations devices send: JSON numbers and numeric strings
+func toInt(raw interface{}) (int, error) {
+	switch v := raw.(type) {
+	case float64:
+		if v != math.Trunc(v) {
+			return 0, fmt.Errorf("must be an integer, got %v", v)
+		}
+		return int(v), nil
+	case int:
+		return v, nil
+	case string:
+		n, err := strconv.Atoi(strings.TrimSpace(v))
+		if err != nil {
+			return 0, fmt.Errorf("must be an integer, got %q", v)
+		}
+		return n, nil
+	default:
+		return 0, fmt.Errorf("must be an integer, got %T", v)
+	}
+}
+
+func isSupportedSensor(sensor string) bool {
+	for _, sensorType := range entities.SensorTypes {
+		if sensor == sensorType {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,20 @@
+package validation
+
+import "hex_go/internal/domain/entities"
+
+// validateTarget checks that exactly one of a device and a location is given, as
+// escalation policies and webhooks watch one or the other
+func validateTarget(errs *entities.ValidationErrors, numeroSerie *string, idUbicacion *int64) {
+	switch {
+	case numeroSerie != nil && idUbicacion != nil:
+		errs.Add("numero_serie", "cannot be combined with id_ubicacion")
+	case numeroSerie != nil:
+		errs.Append("numero_serie", ValidateSerialNumber("numero_serie", *numeroSerie))
+	case idUbicacion != nil:
+		if *idUbicacion <= 0 {
+			errs.Add("id_ubicacion", "must be a positive location ID")
+		}
+	default:
+		errs.Add("numero_serie", "either numero_serie or id_ubicacion is required")
+	}
+}
@@ -0,0 +1,65 @@
+package validation
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+
+	"hex_go/internal/domain/entities"
+)
+
+const maxWebhookURLLength = 500
+
+// ValidateCreateWebhook checks a webhook registration. A webhook watches either one
+// device or one location.
+func ValidateCreateWebhook(req *entities.CreateWebhookRequest) error {
+	var errs entities.ValidationErrors
+
+	errs.Append("url", validateWebhookURL(req.URL))
+
+	validateTarget(&errs, req.NumeroSerie, req.IDUbicacion)
+
+	validateAlertTypes(&errs, req.Tipos)
+
+	return errs.Err()
+}
+
+// ValidateUpdateWebhook checks a webhook change
+func ValidateUpdateWebhook(req *entities.UpdateWebhookRequest) error {
+	var errs entities.ValidationErrors
+
+	if req.URL != nil {
+		errs.Append("url", validateWebhookURL(*req.URL))
+	}
+	if req.Tipos != nil {
+		validateAlertTypes(&errs, *req.Tipos)
+	}
+
+	return errs.Err()
+}
+
+func validateWebhookURL(raw string) error {
+	if strings.TrimSpace(raw) == "" {
+		return &entities.ValidationError{Field: "url", Message: "is required"}
+	}
+	if len(raw) > maxWebhookURLLength {
+		return &entities.ValidationError{Field: "url", Message: fmt.Sprintf("must be at most %d characters", maxWebhookURLLength)}
+	}
+
+	u, err := url.Parse(raw)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return &entities.ValidationError{Field: "url", Message: "must be an absolute http or https URL"}
+	}
+	if u.User != nil {
+		return &entities.ValidationError{Field: "url", Message: "must not contain credentials"}
+	}
+	return nil
+}
+
+func validateAlertTypes(errs *entities.ValidationErrors, types []string) {
+	for _, t := range types {
+		if !entities.IsAlertType(t) {
+			errs.Add("tipos", fmt.Sprintf("unknown alert type %q, must be one of %s", t, strings.Join(entities.AlertTypes, ", ")))
+		}
+	}
+}
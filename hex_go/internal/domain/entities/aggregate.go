@@ -0,0 +1,146 @@
+package entities
+
+import (
+	"math"
+	"time"
+)
+
+// Aggregate sources: computed from the readings or from the hourly rollups
+const (
+	AggregateSourceRaw    = "lecturas"
+	AggregateSourceRollup = "agregados"
+)
+
+// AggregateBuckets lists the bucket sizes readings can be aggregated by, smallest first
+var AggregateBuckets = []string{"1m", "5m", "15m", "1h", "6h", "1d"}
+
+// aggregateBucketSizes holds the duration of every bucket in AggregateBuckets
+var aggregateBucketSizes = map[string]time.Duration{
+	"1m":  time.Minute,
+	"5m":  5 * time.Minute,
+	"15m": 15 * time.Minute,
+	"1h":  time.Hour,
+	"6h":  6 * time.Hour,
+	"1d":  24 * time.Hour,
+}
+
+// AggregateBucketSize returns the duration of a bucket name of AggregateBuckets
+func AggregateBucketSize(bucket string) (time.Duration, bool) {
+	size, ok := aggregateBucketSizes[bucket]
+	return size, ok
+}
+
+// AggregateFields returns the numeric columns aggregated for a sensor type
+func AggregateFields(sensor string) []string {
+	switch sensor {
+	case SensorTypeKY026:
+		return []string{"estado"}
+	case SensorTypeMQ2, SensorTypeMQ135:
+		return []string{"estado", "ppm"}
+	case SensorTypeDHT22:
+		return []string{"temperatura", "humedad", "indice_calor", "punto_rocio"}
+	default:
+		return nil
+	}
+}
+
+// AggregateQuery asks for the readings of one sensor of a device per bucket. Desde and
+// Hasta default to the last day; buckets are aligned to UTC.
+type AggregateQuery struct {
+	NumeroSerie string
+	Sensor      string
+	Bucket      string
+	Desde       *time.Time
+	Hasta       *time.Time
+
+	// OrganizationID is set for API keys, which read the devices shared with their organisation
+	OrganizationID *int64
+}
+
+// AggregateStats summarises the values of one field within a bucket. Minimo, Maximo and
+// Media are null when the bucket holds no value of the field.
+type AggregateStats struct {
+	Cantidad int      `json:"cantidad"`
+	Minimo   *float64 `json:"minimo"`
+	Maximo   *float64 `json:"maximo"`
+	Media    *float64 `json:"media"`
+	Suma     float64  `json:"-"`
+}
+
+// Merge adds the values summarised by other
+func (s *AggregateStats) Merge(other *AggregateStats) {
+	if other == nil || other.Cantidad == 0 {
+		return
+	}
+	if s.Minimo == nil || *other.Minimo < *s.Minimo {
+		s.Minimo = other.Minimo
+	}
+	if s.Maximo == nil || *other.Maximo > *s.Maximo {
+		s.Maximo = other.Maximo
+	}
+	s.Cantidad += other.Cantidad
+	s.Suma += other.Suma
+	media := s.Suma / float64(s.Cantidad)
+	s.Media = &media
+}
+
+// AggregateBucket summarises the readings from Inicio for the bucket size. Lecturas
+// counts the readings, Valores has the statistics of every aggregated field.
+type AggregateBucket struct {
+	Inicio   time.Time                  `json:"inicio"`
+	Lecturas int                        `json:"lecturas"`
+	Valores  map[string]*AggregateStats `json:"valores"`
+}
+
+// Merge adds the readings summarised by other, which starts at the same time
+func (b *AggregateBucket) Merge(other *AggregateBucket) {
+	b.Lecturas += other.Lecturas
+	for field, stats := range other.Valores {
+		if b.Valores[field] == nil {
+			b.Valores[field] = &AggregateStats{}
+		}
+		b.Valores[field].Merge(stats)
+	}
+}
+
+// AggregateSeries is the answer to an AggregateQuery: one bucket for every step from Desde
+// to Hasta, empty ones included, so charts show the gaps
+type AggregateSeries struct {
+	NumeroSerie string             `json:"numero_serie"`
+	Sensor      string             `json:"sensor"`
+	Bucket      string             `json:"bucket"`
+	Desde       time.Time          `json:"desde"`
+	Hasta       time.Time          `json:"hasta"`
+	Campos      []string           `json:"campos"`
+	Fuente      string             `json:"fuente"`
+	Buckets     []*AggregateBucket `json:"buckets"`
+}
+
+// FillBuckets returns the buckets of size from desde up to hasta, taking the summaries of
+// found and an empty bucket for every step without readings
+func FillBuckets(desde, hasta time.Time, size time.Duration, fields []string, found []*AggregateBucket) []*AggregateBucket {
+	byStart := make(map[int64]*AggregateBucket, len(found))
+	for _, bucket := range found {
+		if existing, ok := byStart[bucket.Inicio.Unix()]; ok {
+			existing.Merge(bucket)
+		} else {
+			byStart[bucket.Inicio.Unix()] = bucket
+		}
+	}
+
+	steps := int(math.Ceil(float64(hasta.Sub(desde)) / float64(size)))
+	buckets := make([]*AggregateBucket, 0, steps)
+	for start := desde; start.Before(hasta); start = start.Add(size) {
+		bucket, ok := byStart[start.Unix()]
+		if !ok {
+			bucket = &AggregateBucket{Inicio: start, Valores: map[string]*AggregateStats{}}
+		}
+		for _, field := range fields {
+			if bucket.Valores[field] == nil {
+				bucket.Valores[field] = &AggregateStats{}
+			}
+		}
+		buckets = append(buckets, bucket)
+	}
+	return buckets
+}
@@ -0,0 +1,114 @@
+package entities
+
+import "time"
+
+// Alert types besides the sensor types, which are used as alert types for readings
+const (
+	AlertTypeDeviceOffline = "DEVICE_OFFLINE"
+	AlertTypeAnomaly       = "ANOMALY"
+)
+
+// Alert severities, from most to least urgent
+const (
+	SeverityCritical = "critical"
+	SeverityWarning  = "warning"
+	SeverityInfo     = "info"
+)
+
+// Severities lists every alert severity, from most to least urgent
+var Severities = []string{SeverityCritical, SeverityWarning, SeverityInfo}
+
+// Alert lifecycle states
+const (
+	AlertStateActive       = "activa"
+	AlertStateAcknowledged = "reconocida"
+	AlertStateResolved     = "resuelta"
+)
+
+// Alert is raised for every event a user has to know about: sensor activations and
+// device problems alike. All alerts go through the same pipeline: stored in the
+// alertas table and published to the message queue. Repeated activations are merged
+// into the open alert rather than raising new ones.
+type Alert struct {
+	ID            int64     `json:"id"`
+	NumeroSerie   string    `json:"numero_serie"`
+	Tipo          string    `json:"tipo"`
+	Severidad     string    `json:"severidad"`
+	Mensaje       string    `json:"mensaje"`
+	Valor         *float64  `json:"valor,omitempty"`
+	Estado        string    `json:"estado"`
+	FechaCreacion time.Time `json:"fecha_creacion"`
+
+	// ReconocidaPor is the user who acknowledged the alert, and when
+	ReconocidaPor       *int       `json:"reconocida_por,omitempty"`
+	FechaReconocimiento *time.Time `json:"fecha_reconocimiento,omitempty"`
+
+	// An alert is an episode of activations of the same device and sensor, first seen
+	// at FechaCreacion. See AlertDebounce for how activations are merged.
+	Ocurrencias int       `json:"ocurrencias"`
+	FechaUltima time.Time `json:"fecha_ultima"`
+	Oscilante   bool      `json:"oscilante"`
+
+	// IDIncidente is the incident the alert was correlated into
+	IDIncidente *int64 `json:"id_incidente,omitempty"`
+
+	// Suprimida marks an alert raised during the maintenance window IDMantenimiento. It is
+	// stored as a test and nobody is notified.
+	Suprimida       bool   `json:"suprimida"`
+	IDMantenimiento *int64 `json:"id_mantenimiento,omitempty"`
+
+	// The activations counted since VentanaInicio decide whether the episode is flapping
+	VentanaInicio      time.Time `json:"-"`
+	VentanaOcurrencias int       `json:"-"`
+}
+
+// SeverityForSensor returns the severity of an activation of the given sensor type.
+// Flame and smoke detections are always critical.
+func SeverityForSensor(sensor string) string {
+	switch sensor {
+	case SensorTypeKY026, SensorTypeMQ2:
+		return SeverityCritical
+	case SensorTypeMQ135:
+		return SeverityWarning
+	default:
+		return SeverityInfo
+	}
+}
+
+// AlertTypes lists every type alerts are raised with
+var AlertTypes = append(append([]string{}, SensorTypes...), AlertTypeDeviceOffline, AlertTypeAnomaly)
+
+// IsAlertType reports whether t is a type alerts are raised with
+func IsAlertType(t string) bool {
+	for _, alertType := range AlertTypes {
+		if alertType == t {
+			return true
+		}
+	}
+	return false
+}
+
+// MoreSevere reports whether severity a is more urgent than b
+func MoreSevere(a, b string) bool {
+	return severityRank(a) < severityRank(b)
+}
+
+// severityRank returns the position of a severity in Severities; unknown ones rank last
+func severityRank(severity string) int {
+	for i, s := range Severities {
+		if s == severity {
+			return i
+		}
+	}
+	return len(Severities)
+}
+
+// IsSeverity reports whether severity is a known alert severity
+func IsSeverity(severity string) bool {
+	for _, s := range Severities {
+		if s == severity {
+			return true
+		}
+	}
+	return false
+}
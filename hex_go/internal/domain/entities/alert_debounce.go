@@ -0,0 +1,81 @@
+package entities
+
+import "time"
+
+// AlertDebounce decides when activations of the same device and sensor are merged into
+// one alert episode, and when an episode is flapping.
+//
+// An activation joins the open episode when it comes within Ventana of the last one.
+// The activations are also counted per VentanaOscilacion: an episode starts flapping when
+// a window reaches UmbralEntrada activations and stops when a whole window passes with
+// fewer than UmbralSalida. While flapping, activations join the episode within
+// VentanaOscilacion, so a sensor that toggles in bursts does not open a new episode
+// after every pause. Both thresholds apart keep a sensor near the limit from going in
+// and out of flapping.
+type AlertDebounce struct {
+	Ventana           time.Duration
+	VentanaOscilacion time.Duration
+	UmbralEntrada     int
+	UmbralSalida      int
+}
+
+// Enabled reports whether activations are merged at all
+func (d AlertDebounce) Enabled() bool {
+	return d.Ventana > 0
+}
+
+// MergeWindow returns how long after its last activation an episode still takes new ones
+func (d AlertDebounce) MergeWindow(episode *Alert) time.Duration {
+	if episode.Oscilante && d.VentanaOscilacion > d.Ventana {
+		return d.VentanaOscilacion
+	}
+	return d.Ventana
+}
+
+// Absorbs reports whether an activation at the given time joins the episode
+func (d AlertDebounce) Absorbs(episode *Alert, at time.Time) bool {
+	if !d.Enabled() || episode.Estado == AlertStateResolved {
+		return false
+	}
+	return !at.After(episode.FechaUltima.Add(d.MergeWindow(episode)))
+}
+
+// Merge counts an activation at the given time in the episode and reports whether the
+// episode started or stopped flapping
+func (d AlertDebounce) Merge(episode *Alert, at time.Time) bool {
+	episode.Ocurrencias++
+	if at.After(episode.FechaUltima) {
+		episode.FechaUltima = at
+	}
+	if d.VentanaOscilacion <= 0 || d.UmbralEntrada <= 0 {
+		return false
+	}
+
+	wasFlapping := episode.Oscilante
+	if at.Sub(episode.VentanaInicio) >= d.VentanaOscilacion {
+		// The last window is over; a quiet one ends the flapping. Windows without any
+		// activation at all are quiet as well.
+		quiet := episode.VentanaOcurrencias < d.UmbralSalida ||
+			at.Sub(episode.VentanaInicio) >= 2*d.VentanaOscilacion
+		if episode.Oscilante && quiet {
+			episode.Oscilante = false
+		}
+		episode.VentanaInicio = at
+		episode.VentanaOcurrencias = 0
+	}
+	episode.VentanaOcurrencias++
+	if episode.VentanaOcurrencias >= d.UmbralEntrada {
+		episode.Oscilante = true
+	}
+
+	return episode.Oscilante != wasFlapping
+}
+
+// StartEpisode sets the counters of an alert that opens a new episode
+func (d AlertDebounce) StartEpisode(alert *Alert) {
+	alert.Ocurrencias = 1
+	alert.FechaUltima = alert.FechaCreacion
+	alert.VentanaInicio = alert.FechaCreacion
+	alert.VentanaOcurrencias = 1
+	alert.Oscilante = false
+}
@@ -0,0 +1,129 @@
+package entities
+
+import (
+	"testing"
+	"time"
+)
+
+// testDebounce merges activations within a minute and flaps at 3 activations in five
+// minutes, until a window has fewer than 2
+var testDebounce = AlertDebounce{
+	Ventana:           time.Minute,
+	VentanaOscilacion: 5 * time.Minute,
+	UmbralEntrada:     3,
+	UmbralSalida:      2,
+}
+
+var debounceStart = time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+
+func TestAlertDebounceAbsorbs(t *testing.T) {
+	tests := []struct {
+		name      string
+		debounce  AlertDebounce
+		estado    string
+		oscilante bool
+		after     time.Duration
+		want      bool
+	}{
+		{"within the window", testDebounce, AlertStateActive, false, 30 * time.Second, true},
+		{"at the end of the window", testDebounce, AlertStateActive, false, time.Minute, true},
+		{"after the window", testDebounce, AlertStateActive, false, 2 * time.Minute, false},
+		{"acknowledged episode", testDebounce, AlertStateAcknowledged, false, 30 * time.Second, true},
+		{"resolved episode", testDebounce, AlertStateResolved, false, 30 * time.Second, false},
+		{"flapping episode uses the flapping window", testDebounce, AlertStateActive, true, 4 * time.Minute, true},
+		{"after the flapping window", testDebounce, AlertStateActive, true, 6 * time.Minute, false},
+		{"zero window disables merging", AlertDebounce{}, AlertStateActive, false, 0, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			episode := &Alert{Estado: tt.estado, FechaUltima: debounceStart, Oscilante: tt.oscilante}
+			if got := tt.debounce.Absorbs(episode, debounceStart.Add(tt.after)); got != tt.want {
+				t.Errorf("Absorbs(+%s) = %v, want %v", tt.after, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestAlertDebounceMerge(t *testing.T) {
+	tests := []struct {
+		name string
+		// activations after the one opening the episode
+		activations []time.Duration
+		// whether the episode is flapping after each of them
+		wantFlapping []bool
+	}{
+		{
+			name:         "merged below the entry threshold",
+			activations:  []time.Duration{30 * time.Second},
+			wantFlapping: []bool{false},
+		},
+		{
+			name:         "enters flapping at the entry threshold",
+			activations:  []time.Duration{30 * time.Second, time.Minute},
+			wantFlapping: []bool{false, true},
+		},
+		{
+			name:         "spread over two windows does not enter",
+			activations:  []time.Duration{4 * time.Minute, 5 * time.Minute, 6 * time.Minute},
+			wantFlapping: []bool{false, false, false},
+		},
+		{
+			name: "stays flapping while a window reaches the exit threshold",
+			// 2 activations from 5m on would not enter flapping, but keep it
+			activations:  []time.Duration{30 * time.Second, time.Minute, 5 * time.Minute, 6 * time.Minute, 10 * time.Minute},
+			wantFlapping: []bool{false, true, true, true, true},
+		},
+		{
+			name:         "leaves flapping after a window below the exit threshold",
+			activations:  []time.Duration{30 * time.Second, time.Minute, 5 * time.Minute, 10 * time.Minute},
+			wantFlapping: []bool{false, true, true, false},
+		},
+		{
+			name:         "leaves flapping after an empty window",
+			activations:  []time.Duration{30 * time.Second, time.Minute, 11 * time.Minute},
+			wantFlapping: []bool{false, true, false},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			episode := &Alert{Estado: AlertStateActive, FechaCreacion: debounceStart}
+			testDebounce.StartEpisode(episode)
+
+			for i, after := range tt.activations {
+				at := debounceStart.Add(after)
+				wasFlapping := episode.Oscilante
+				changed := testDebounce.Merge(episode, at)
+
+				if episode.Oscilante != tt.wantFlapping[i] {
+					t.Fatalf("after +%s: flapping = %v, want %v", after, episode.Oscilante, tt.wantFlapping[i])
+				}
+				if changed != (wasFlapping != episode.Oscilante) {
+					t.Errorf("after +%s: Merge() = %v, but flapping went from %v to %v", after, changed, wasFlapping, episode.Oscilante)
+				}
+				if !episode.FechaUltima.Equal(at) {
+					t.Errorf("after +%s: FechaUltima = %s, want %s", after, episode.FechaUltima, at)
+				}
+			}
+			if want := len(tt.activations) + 1; episode.Ocurrencias != want {
+				t.Errorf("Ocurrencias = %d, want %d", episode.Ocurrencias, want)
+			}
+		})
+	}
+}
+
+func TestAlertDebounceMergeWithoutFlapping(t *testing.T) {
+	debounce := AlertDebounce{Ventana: time.Minute}
+	episode := &Alert{Estado: AlertStateActive, FechaCreacion: debounceStart}
+	debounce.StartEpisode(episode)
+
+	for i := 1; i <= 10; i++ {
+		if debounce.Merge(episode, debounceStart.Add(time.Duration(i)*time.Second)) {
+			t.Fatalf("activation %d changed flapping without a flapping window", i)
+		}
+	}
+	if episode.Oscilante {
+		t.Error("episode is flapping without a flapping window")
+	}
+}
@@ -0,0 +1,37 @@
+package entities
+
+import "time"
+
+// Alert history export formats
+const (
+	ExportFormatCSV    = "csv"
+	ExportFormatNDJSON = "ndjson"
+	ExportFormatXLSX   = "xlsx"
+)
+
+// ExportFormats lists every format the alert history can be exported in
+var ExportFormats = []string{ExportFormatCSV, ExportFormatNDJSON, ExportFormatXLSX}
+
+// IsExportFormat reports whether format is a supported export format
+func IsExportFormat(format string) bool {
+	for _, f := range ExportFormats {
+		if f == format {
+			return true
+		}
+	}
+	return false
+}
+
+// AlertExportQuery selects the alerts exported by GET /api/alerts/export: those raised
+// from Desde until Hasta, both optional, on the devices the caller can see, optionally
+// narrowed to a location like the alerts list. Idioma is the language of the headers.
+type AlertExportQuery struct {
+	Formato    string
+	Idioma     string
+	Desde      *time.Time
+	Hasta      *time.Time
+	LocationID *int64
+
+	// OrganizationID is set for API keys, which see the devices shared with their organisation
+	OrganizationID *int64
+}
@@ -0,0 +1,135 @@
+package entities
+
+import (
+	"fmt"
+	"math"
+	"time"
+)
+
+// anomalyMinDeviation is the smallest standard deviation a z-score is computed with per
+// sensor type, so that a sensor that reads the same value for hours does not turn its
+// first small change into an anomaly. MQ sensors are in raw ADC units, DHT_22 in °C.
+var anomalyMinDeviation = map[string]float64{
+	SensorTypeMQ2:   20,
+	SensorTypeMQ135: 20,
+	SensorTypeDHT22: 0.5,
+}
+
+// AnomalyMetric returns the value of a reading the anomaly detector follows: the raw
+// estado of the gas sensors and the temperature of DHT_22. Flame readings are already
+// an alarm on their own and are not followed.
+func AnomalyMetric(reading *SensorReading) (float64, bool) {
+	switch reading.Sensor {
+	case SensorTypeMQ2, SensorTypeMQ135:
+		return float64(reading.Estado), true
+	case SensorTypeDHT22:
+		return reading.Temperatura, true
+	default:
+		return 0, false
+	}
+}
+
+// AnomalyDetector finds readings that stand out from the usual values of the same device
+// and sensor, which fixed thresholds miss when they build up slowly.
+//
+// Every sensor keeps an exponentially weighted mean and variance with smoothing Alfa. A
+// reading more than UmbralZ standard deviations from the mean is an anomaly, once the
+// sensor has Calentamiento readings. Temperatures are also compared against the one at
+// the start of each VentanaSubida: rising TasaSubida °C per minute or faster is an
+// anomaly even when every single reading looks ordinary.
+type AnomalyDetector struct {
+	Alfa          float64
+	UmbralZ       float64
+	Calentamiento int
+	TasaSubida    float64
+	VentanaSubida time.Duration
+}
+
+// Enabled reports whether readings are followed at all
+func (d AnomalyDetector) Enabled() bool {
+	return d.Alfa > 0
+}
+
+// AnomalyState is what the detector knows about one sensor of a device. It lives in
+// memory and is stored now and then so that a restart does not start over.
+type AnomalyState struct {
+	NumeroSerie        string
+	Sensor             string
+	Media              float64
+	Varianza           float64
+	Muestras           int
+	SubidaValor        float64
+	SubidaFecha        time.Time
+	FechaActualizacion time.Time
+}
+
+// Observe adds a reading at the given time to the state of its sensor and returns the
+// explanation of every anomaly it shows, if any
+func (d AnomalyDetector) Observe(state *AnomalyState, value float64, at time.Time) []string {
+	var findings []string
+
+	if state.Muestras >= d.Calentamiento && d.UmbralZ > 0 {
+		deviation := math.Max(math.Sqrt(state.Varianza), anomalyMinDeviation[state.Sensor])
+		z := (value - state.Media) / deviation
+		if math.Abs(z) >= d.UmbralZ {
+			direction := "above"
+			if z < 0 {
+				direction = "below"
+			}
+			findings = append(findings, fmt.Sprintf("%s %s %.1f is %.1f standard deviations %s its usual %.1f",
+				state.Sensor, anomalyMetricName(state.Sensor), value, math.Abs(z), direction, state.Media))
+		}
+	}
+
+	if state.Sensor == SensorTypeDHT22 && d.TasaSubida > 0 && d.VentanaSubida > 0 {
+		if finding := d.rise(state, value, at); finding != "" {
+			findings = append(findings, finding)
+		}
+	}
+
+	// The mean takes anomalous readings too, so that a lasting change becomes the new usual
+	if state.Muestras == 0 {
+		state.Media = value
+		state.Varianza = 0
+	} else {
+		diff := value - state.Media
+		increment := d.Alfa * diff
+		state.Media += increment
+		state.Varianza = (1 - d.Alfa) * (state.Varianza + diff*increment)
+	}
+	state.Muestras++
+	state.FechaActualizacion = at
+
+	return findings
+}
+
+// rise compares a temperature with the one at the start of the current window and starts
+// the next window once this one is over
+func (d AnomalyDetector) rise(state *AnomalyState, value float64, at time.Time) string {
+	if state.SubidaFecha.IsZero() || at.Before(state.SubidaFecha) {
+		state.SubidaValor, state.SubidaFecha = value, at
+		return ""
+	}
+
+	elapsed := at.Sub(state.SubidaFecha)
+	if elapsed < d.VentanaSubida {
+		return ""
+	}
+
+	rate := (value - state.SubidaValor) / elapsed.Minutes()
+	from := state.SubidaValor
+	state.SubidaValor, state.SubidaFecha = value, at
+	if rate < d.TasaSubida {
+		return ""
+	}
+	return fmt.Sprintf("temperature rose from %.1f to %.1f °C in %s (%.2f °C/min, limit %.2f)",
+		from, value, elapsed.Round(time.Second), rate, d.TasaSubida)
+}
+
+// anomalyMetricName names the value AnomalyMetric follows for a sensor type
+func anomalyMetricName(sensor string) string {
+	if sensor == SensorTypeDHT22 {
+		return "temperature"
+	}
+	return "estado"
+}
@@ -0,0 +1,58 @@
+package entities
+
+import "time"
+
+// Permissions an API key can be granted
+var APIKeyScopes = []string{"alerts:read", "alerts:acknowledge", "readings:read"}
+
+// IsAPIKeyScope reports whether scope can be granted to an API key
+func IsAPIKeyScope(scope string) bool {
+	for _, s := range APIKeyScopes {
+		if s == scope {
+			return true
+		}
+	}
+	return false
+}
+
+// Access audit actions for API keys
+const (
+	AuditAPIKeyCreated = "api_key.created"
+	AuditAPIKeyRevoked = "api_key.revoked"
+)
+
+// APIKey lets an integration or service account call the API on behalf of an
+// organisation. Only the SHA-256 of the key is stored; Prefijo is the public part
+// of the key used to find it.
+type APIKey struct {
+	ID              int64      `json:"id"`
+	IDOrganizacion  int64      `json:"id_organizacion"`
+	Nombre          string     `json:"nombre"`
+	Prefijo         string     `json:"prefijo"`
+	Scopes          []string   `json:"scopes"`
+	CreadaPor       int        `json:"creada_por"`
+	FechaCreacion   time.Time  `json:"fecha_creacion"`
+	FechaExpiracion time.Time  `json:"fecha_expiracion"`
+	UltimoUso       *time.Time `json:"ultimo_uso"`
+	Revocada        bool       `json:"revocada"`
+
+	Hash string `json:"-"`
+}
+
+// IsUsable reports whether the key can still authenticate requests at the given time
+func (k *APIKey) IsUsable(now time.Time) bool {
+	return !k.Revocada && now.Before(k.FechaExpiracion)
+}
+
+// CreateAPIKeyRequest represents the request to issue a new API key
+type CreateAPIKeyRequest struct {
+	Nombre       string   `json:"nombre"`
+	Scopes       []string `json:"scopes"`
+	ExpiraEnDias int      `json:"expira_en_dias"`
+}
+
+// IssuedAPIKey is returned once when a key is created; the secret cannot be read again
+type IssuedAPIKey struct {
+	*APIKey
+	Clave string `json:"clave"`
+}
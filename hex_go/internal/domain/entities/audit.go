@@ -0,0 +1,125 @@
+package entities
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"strconv"
+	"time"
+)
+
+// Audited actions
+const (
+	AuditReadingCreated     = "reading.created"
+	AuditDeviceClaimed      = "device.claimed"
+	AuditDeviceUpdated      = "device.updated"
+	AuditDeviceTransferred  = "device.transferred"
+	AuditDeviceReleased     = "device.released"
+	AuditDeviceTokenRotated = "device.token_rotated"
+	AuditDeviceOffline      = "device.offline"
+	AuditDeviceLocated      = "device.location_changed"
+	AuditLocationCreated    = "location.created"
+	AuditLocationUpdated    = "location.updated"
+	AuditLocationDeleted    = "location.deleted"
+)
+
+// Audited resource types
+const (
+	ResourceReading      = "reading"
+	ResourceDevice       = "device"
+	ResourceLocation     = "location"
+	ResourceOrganization = "organization"
+	ResourceAPIKey       = "api_key"
+)
+
+// ActorSystem identifies changes made by background workers
+const ActorSystem = "system"
+
+// AuditEntry records one state-changing operation. Entries are append-only and chained:
+// Hash covers the entry and HashAnterior, the hash of the entry before it, so editing or
+// deleting any stored entry breaks every hash after it.
+type AuditEntry struct {
+	ID          int64           `json:"id"`
+	TipoActor   string          `json:"tipo_actor"`
+	IDActor     string          `json:"id_actor"`
+	Accion      string          `json:"accion"`
+	Recurso     string          `json:"recurso"`
+	IDRecurso   string          `json:"id_recurso"`
+	Antes       json.RawMessage `json:"antes,omitempty"`
+	Despues     json.RawMessage `json:"despues,omitempty"`
+	IDSolicitud string          `json:"id_solicitud,omitempty"`
+	IP          string          `json:"ip,omitempty"`
+	Fecha       time.Time       `json:"fecha"`
+
+	HashAnterior string `json:"hash_anterior"`
+	Hash         string `json:"hash"`
+}
+
+// ComputeHash returns the SHA-256 of the entry chained to HashAnterior. The ID is left
+// out because it is only known once the entry is stored.
+func (e *AuditEntry) ComputeHash() string {
+	fields := []string{
+		e.HashAnterior,
+		e.TipoActor,
+		e.IDActor,
+		e.Accion,
+		e.Recurso,
+		e.IDRecurso,
+		string(e.Antes),
+		string(e.Despues),
+		e.IDSolicitud,
+		e.IP,
+		e.Fecha.UTC().Format(time.RFC3339Nano),
+	}
+
+	h := sha256.New()
+	for _, field := range fields {
+		// Length prefixes keep "ab"+"c" and "a"+"bc" from hashing alike
+		h.Write([]byte(strconv.Itoa(len(field))))
+		h.Write([]byte{':'})
+		h.Write([]byte(field))
+	}
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// AuditQuery narrows the audit entries returned by GET /api/audit
+type AuditQuery struct {
+	TipoActor string
+	IDActor   string
+	Accion    string
+	Recurso   string
+	IDRecurso string
+	Desde     *time.Time
+	Hasta     *time.Time
+	// BeforeID pages backwards: only entries older than this ID are returned
+	BeforeID int64
+	Limit    int
+}
+
+// AuditVerification is the result of checking the hash chain
+type AuditVerification struct {
+	Valida     bool   `json:"valida"`
+	Revisadas  int    `json:"revisadas"`
+	IDRota     *int64 `json:"id_rota,omitempty"`
+	UltimoHash string `json:"ultimo_hash,omitempty"`
+}
+
+// RequestInfo identifies the HTTP request a change was made in
+type RequestInfo struct {
+	ID string
+	IP string
+}
+
+type requestInfoKey struct{}
+
+// WithRequestInfo returns a copy of ctx carrying the request ID and client IP
+func WithRequestInfo(ctx context.Context, info RequestInfo) context.Context {
+	return context.WithValue(ctx, requestInfoKey{}, info)
+}
+
+// RequestInfoFromContext returns the request information stored in ctx, if any
+func RequestInfoFromContext(ctx context.Context) RequestInfo {
+	info, _ := ctx.Value(requestInfoKey{}).(RequestInfo)
+	return info
+}
@@ -0,0 +1,120 @@
+package entities
+
+import (
+	"math"
+	"time"
+)
+
+// CalibrationADCMax is the largest raw value of the 12-bit ADC the MQ sensors are read with
+const CalibrationADCMax = 4095
+
+// Audit log actions and resource type of sensor calibrations
+const (
+	AuditCalibrationUpdated = "calibration.updated"
+	AuditCalibrationReset   = "calibration.reset"
+	ResourceCalibration     = "calibration"
+)
+
+// GasCurve converts the ratio between the sensor resistance and its resistance in clean
+// air into a concentration: ppm = A * ratio^B, fitted to the datasheet curves
+type GasCurve struct {
+	A float64
+	B float64
+}
+
+// GasCurves holds the curves of the calibrated sensors: smoke for MQ_2 and CO2 for MQ_135
+var GasCurves = map[string]GasCurve{
+	SensorTypeMQ2:   {A: 3616.1, B: -2.675},
+	SensorTypeMQ135: {A: 116.6021, B: -2.769},
+}
+
+// IsCalibratedSensor reports whether readings of the sensor type are calibrated
+func IsCalibratedSensor(sensor string) bool {
+	_, ok := GasCurves[sensor]
+	return ok
+}
+
+// SensorCalibration converts the raw estado of one gas sensor of a device into ppm.
+// The raw value is corrected with Offset and Ganancia first. LineaBase is the sensor
+// resistance in clean air, relative to its load resistor, learned as the mean of the
+// readings until FinAprendizaje and then followed slowly as the sensor ages.
+type SensorCalibration struct {
+	NumeroSerie        string    `json:"numero_serie"`
+	Sensor             string    `json:"sensor"`
+	Offset             float64   `json:"offset"`
+	Ganancia           float64   `json:"ganancia"`
+	LineaBase          float64   `json:"linea_base"`
+	Muestras           int       `json:"muestras"`
+	InicioAprendizaje  time.Time `json:"inicio_aprendizaje"`
+	FinAprendizaje     time.Time `json:"fin_aprendizaje"`
+	FechaActualizacion time.Time `json:"fecha_actualizacion"`
+}
+
+// CalibrationRequest sets the offset and gain of a sensor
+type CalibrationRequest struct {
+	Offset   float64 `json:"offset"`
+	Ganancia float64 `json:"ganancia"`
+}
+
+// NewSensorCalibration returns the calibration of a sensor that starts learning its
+// baseline at now, without offset or gain
+func NewSensorCalibration(numeroSerie, sensor string, now time.Time, burnIn time.Duration) *SensorCalibration {
+	c := &SensorCalibration{NumeroSerie: numeroSerie, Sensor: sensor}
+	c.Restart(0, 1, now, burnIn)
+	return c
+}
+
+// Restart forgets the baseline and learns it again with the given offset and gain
+func (c *SensorCalibration) Restart(offset, gain float64, now time.Time, burnIn time.Duration) {
+	c.Offset = offset
+	c.Ganancia = gain
+	c.LineaBase = 0
+	c.Muestras = 0
+	c.InicioAprendizaje = now
+	c.FinAprendizaje = now.Add(burnIn)
+	c.FechaActualizacion = now
+}
+
+// Learning reports whether the baseline is still being learned at the given time
+func (c *SensorCalibration) Learning(at time.Time) bool {
+	return c.Muestras == 0 || at.Before(c.FinAprendizaje)
+}
+
+// Corrected applies the offset and gain to a raw value, kept inside the ADC range
+func (c *SensorCalibration) Corrected(raw int) float64 {
+	value := (float64(raw) - c.Offset) * c.Ganancia
+	return math.Min(math.Max(value, 1), CalibrationADCMax)
+}
+
+// resistance returns the sensor resistance relative to its load resistor. The sensor
+// is the upper half of a voltage divider, so its resistance falls as the value rises.
+func (c *SensorCalibration) resistance(raw int) float64 {
+	value := c.Corrected(raw)
+	return (CalibrationADCMax - value) / value
+}
+
+// Learn adds a raw value to the baseline mean during the burn-in period
+func (c *SensorCalibration) Learn(raw int, at time.Time) {
+	c.Muestras++
+	c.LineaBase += (c.resistance(raw) - c.LineaBase) / float64(c.Muestras)
+	c.FechaActualizacion = at
+}
+
+// Drift moves the baseline a fraction alpha towards a raw value read in clean air, so it
+// follows the slow drift of an ageing sensor
+func (c *SensorCalibration) Drift(raw int, at time.Time, alpha float64) {
+	c.Muestras++
+	c.LineaBase += alpha * (c.resistance(raw) - c.LineaBase)
+	c.FechaActualizacion = at
+}
+
+// PPM converts a raw value into a concentration. It reports false while there is no
+// baseline to compare with.
+func (c *SensorCalibration) PPM(raw int) (float64, bool) {
+	curve, ok := GasCurves[c.Sensor]
+	if !ok || c.Muestras == 0 || c.LineaBase <= 0 {
+		return 0, false
+	}
+	ratio := c.resistance(raw) / c.LineaBase
+	return curve.A * math.Pow(ratio, curve.B), true
+}
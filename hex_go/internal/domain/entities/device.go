@@ -0,0 +1,61 @@
+package entities
+
+import "time"
+
+// Device represents an ESP32 board registered in the ESP32 table
+type Device struct {
+	NumeroSerie        string     `json:"numero_serie"`
+	Nombre             string     `json:"nombre"`
+	Ubicacion          string     `json:"ubicacion"`
+	SensoresInstalados []string   `json:"sensores_instalados"`
+	VersionFirmware    string     `json:"version_firmware"`
+	ZonaHoraria        string     `json:"zona_horaria"`
+	IDUbicacion        *int64     `json:"id_ubicacion"`
+	IDUser             *int       `json:"id_user"`
+	FechaRegistro      *time.Time `json:"fecha_registro"`
+	UltimaConexion     *time.Time `json:"ultima_conexion"`
+	EnLinea            bool       `json:"en_linea"`
+
+	// CodigoReclamoHash is the SHA-256 of the claim code printed on the board
+	CodigoReclamoHash string `json:"-"`
+	// TokenHash is the SHA-256 of the token the board authenticates with, issued when it
+	// is claimed
+	TokenHash string `json:"-"`
+}
+
+// ClaimedDevice is returned once when a board is claimed; the token cannot be read again
+// and is what the board sends in X-Device-Token
+type ClaimedDevice struct {
+	*Device
+	Token string `json:"token"`
+}
+
+// ClaimDeviceRequest represents the request to register a board with its claim code
+type ClaimDeviceRequest struct {
+	NumeroSerie        string   `json:"numero_serie"`
+	CodigoReclamo      string   `json:"codigo_reclamo"`
+	Nombre             string   `json:"nombre"`
+	Ubicacion          string   `json:"ubicacion"`
+	SensoresInstalados []string `json:"sensores_instalados"`
+	VersionFirmware    string   `json:"version_firmware"`
+	ZonaHoraria        string   `json:"zona_horaria"`
+}
+
+// UpdateDeviceRequest represents a partial update of a device. Nil fields are left unchanged.
+type UpdateDeviceRequest struct {
+	Nombre             *string   `json:"nombre"`
+	Ubicacion          *string   `json:"ubicacion"`
+	SensoresInstalados *[]string `json:"sensores_instalados"`
+	VersionFirmware    *string   `json:"version_firmware"`
+	ZonaHoraria        *string   `json:"zona_horaria"`
+}
+
+// TransferDeviceRequest represents the request to hand a device over to another user
+type TransferDeviceRequest struct {
+	NuevoIDUser int `json:"nuevo_id_user"`
+}
+
+// HeartbeatRequest represents the periodic keep-alive sent by a board
+type HeartbeatRequest struct {
+	VersionFirmware string `json:"version_firmware"`
+}
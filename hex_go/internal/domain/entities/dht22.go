@@ -0,0 +1,55 @@
+package entities
+
+import "math"
+
+// DHT22 measurement limits from the sensor datasheet
+const (
+	DHT22MinTemperatura = -40.0
+	DHT22MaxTemperatura = 80.0
+	DHT22MinHumedad     = 0.0
+	DHT22MaxHumedad     = 100.0
+)
+
+// HeatIndex returns the apparent temperature in °C using the NOAA Rothfusz regression.
+// Below 26.7 °C (80 °F) the simple Steadman formula is used, as NOAA recommends.
+func HeatIndex(temperatura, humedad float64) float64 {
+	t := temperatura*9/5 + 32
+
+	simple := 0.5 * (t + 61.0 + (t-68.0)*1.2 + humedad*0.094)
+	if (simple+t)/2 < 80 {
+		return round2((simple - 32) * 5 / 9)
+	}
+
+	hi := -42.379 +
+		2.04901523*t +
+		10.14333127*humedad -
+		0.22475541*t*humedad -
+		0.00683783*t*t -
+		0.05481717*humedad*humedad +
+		0.00122874*t*t*humedad +
+		0.00085282*t*humedad*humedad -
+		0.00000199*t*t*humedad*humedad
+
+	switch {
+	case humedad < 13 && t >= 80 && t <= 112:
+		hi -= ((13 - humedad) / 4) * math.Sqrt((17-math.Abs(t-95))/17)
+	case humedad > 85 && t >= 80 && t <= 87:
+		hi += ((humedad - 85) / 10) * ((87 - t) / 5)
+	}
+
+	return round2((hi - 32) * 5 / 9)
+}
+
+// DewPoint returns the dew point in °C using the Magnus formula
+func DewPoint(temperatura, humedad float64) float64 {
+	const a, b = 17.62, 243.12
+	if humedad <= 0 {
+		humedad = 0.01
+	}
+	gamma := math.Log(humedad/100) + a*temperatura/(b+temperatura)
+	return round2(b * gamma / (a - gamma))
+}
+
+func round2(v float64) float64 {
+	return math.Round(v*100) / 100
+}
@@ -0,0 +1,11 @@
+package entities
+
+import "errors"
+
+// Errors shared by the services so controllers can map them to HTTP statuses
+var (
+	ErrNotFound     = errors.New("resource not found")
+	ErrConflict     = errors.New("resource conflict")
+	ErrForbidden    = errors.New("operation not allowed")
+	ErrUnauthorized = errors.New("authentication required")
+)
@@ -0,0 +1,88 @@
+package entities
+
+import "time"
+
+// Escalation states
+const (
+	EscalationPending   = "pendiente"
+	EscalationCompleted = "completada"
+	EscalationStopped   = "detenida"
+)
+
+// Audit log actions for escalation policies and acknowledgements
+const (
+	AuditEscalationPolicyCreated = "escalation_policy.created"
+	AuditEscalationPolicyUpdated = "escalation_policy.updated"
+	AuditEscalationPolicyDeleted = "escalation_policy.deleted"
+	AuditAlertAcknowledged       = "alert.acknowledged"
+)
+
+// Audit resource types of escalation policies and alerts
+const (
+	ResourceEscalationPolicy = "escalation_policy"
+	ResourceAlert            = "alert"
+)
+
+// EscalationPolicy says who to contact, in order, while an alert of one device, or of
+// every device below a location such as a site, stays unacknowledged. Severidades
+// limits the alerts escalated; empty escalates critical alerts only.
+type EscalationPolicy struct {
+	ID            int64             `json:"id"`
+	IDUser        int               `json:"id_user"`
+	Nombre        string            `json:"nombre"`
+	NumeroSerie   *string           `json:"numero_serie,omitempty"`
+	IDUbicacion   *int64            `json:"id_ubicacion,omitempty"`
+	Severidades   []string          `json:"severidades"`
+	Activa        bool              `json:"activa"`
+	Pasos         []*EscalationStep `json:"pasos"`
+	FechaCreacion time.Time         `json:"fecha_creacion"`
+}
+
+// Escalates reports whether alerts of the given severity are escalated by the policy
+func (p *EscalationPolicy) Escalates(severity string) bool {
+	if len(p.Severidades) == 0 {
+		return severity == SeverityCritical
+	}
+	for _, s := range p.Severidades {
+		if s == severity {
+			return true
+		}
+	}
+	return false
+}
+
+// EscalationStep is a contact notified when the alert is still unacknowledged
+// EsperaMinutos after the previous step, or after the alert for the first step.
+// Contacts need not be users, such as building security or the fire brigade.
+type EscalationStep struct {
+	Nombre        string `json:"nombre"`
+	EsperaMinutos int    `json:"espera_minutos"`
+	Canal         string `json:"canal"`
+	Direccion     string `json:"direccion"`
+	Idioma        string `json:"idioma"`
+}
+
+// EscalationPolicyRequest creates or replaces an escalation policy for either a device
+// or a location
+type EscalationPolicyRequest struct {
+	Nombre      string            `json:"nombre"`
+	NumeroSerie *string           `json:"numero_serie"`
+	IDUbicacion *int64            `json:"id_ubicacion"`
+	Severidades []string          `json:"severidades"`
+	Activa      *bool             `json:"activa"`
+	Pasos       []*EscalationStep `json:"pasos"`
+}
+
+// Escalation tracks one policy running for one alert. SiguientePaso is the index of the
+// next step to notify at ProximoPaso; the scheduler stores both before notifying, so a
+// restart resumes where it stopped.
+type Escalation struct {
+	ID            int64      `json:"id"`
+	IDAlerta      int64      `json:"id_alerta"`
+	IDPolitica    int64      `json:"id_politica"`
+	SiguientePaso int        `json:"siguiente_paso"`
+	ProximoPaso   *time.Time `json:"proximo_paso,omitempty"`
+	Estado        string     `json:"estado"`
+	FechaCreacion time.Time  `json:"fecha_creacion"`
+	FechaFin      *time.Time `json:"fecha_fin,omitempty"`
+}
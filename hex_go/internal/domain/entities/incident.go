@@ -0,0 +1,98 @@
+package entities
+
+import (
+	"strconv"
+	"time"
+)
+
+// Incident lifecycle states
+const (
+	IncidentOpen         = "abierto"
+	IncidentAcknowledged = "reconocido"
+	IncidentResolved     = "resuelto"
+)
+
+// IncidentStates lists every incident state
+var IncidentStates = []string{IncidentOpen, IncidentAcknowledged, IncidentResolved}
+
+// Types of the events in the timeline of an incident
+const (
+	IncidentEventOpened         = "opened"
+	IncidentEventAlertAdded     = "alert_added"
+	IncidentEventSeverityRaised = "severity_raised"
+	IncidentEventAcknowledged   = "acknowledged"
+	IncidentEventResolved       = "resolved"
+)
+
+// Audit log actions and resource type of incidents
+const (
+	AuditIncidentAcknowledged = "incident.acknowledged"
+	AuditIncidentResolved     = "incident.resolved"
+	ResourceIncident          = "incident"
+)
+
+// Incident groups the alerts raised in the same room within a short time of each other,
+// such as every sensor of every board in a kitchen on fire. The room is the location of
+// the devices; alerts of a device without a location form incidents of their own.
+// Incidents, rather than each alert, are what users are notified about.
+type Incident struct {
+	ID           int64     `json:"id"`
+	IDUbicacion  *int64    `json:"id_ubicacion,omitempty"`
+	NumeroSerie  *string   `json:"numero_serie,omitempty"`
+	Severidad    string    `json:"severidad"`
+	Estado       string    `json:"estado"`
+	TotalAlertas int       `json:"total_alertas"`
+	FechaInicio  time.Time `json:"fecha_inicio"`
+	FechaUltima  time.Time `json:"fecha_ultima"`
+
+	ReconocidoPor       *int       `json:"reconocido_por,omitempty"`
+	FechaReconocimiento *time.Time `json:"fecha_reconocimiento,omitempty"`
+	ResueltoPor         *int       `json:"resuelto_por,omitempty"`
+	FechaResolucion     *time.Time `json:"fecha_resolucion,omitempty"`
+
+	// Alertas and Eventos are only filled in when a single incident is read
+	Alertas []*Alert         `json:"alertas,omitempty"`
+	Eventos []*IncidentEvent `json:"eventos,omitempty"`
+}
+
+// IncidentEvent is one entry of the timeline of an incident
+type IncidentEvent struct {
+	ID       int64     `json:"id"`
+	Tipo     string    `json:"tipo"`
+	IDAlerta *int64    `json:"id_alerta,omitempty"`
+	IDUser   *int      `json:"id_user,omitempty"`
+	Detalle  string    `json:"detalle,omitempty"`
+	Fecha    time.Time `json:"fecha"`
+}
+
+// IncidentFilter narrows the incidents of a user by state and location subtree
+type IncidentFilter struct {
+	Estado     string
+	LocationID *int64
+	Limit      int
+}
+
+// IncidentKey returns what alerts of the device are correlated by: its location, or the
+// device itself when it has none
+func IncidentKey(device *Device) string {
+	if device.IDUbicacion != nil {
+		return "ubicacion:" + strconv.FormatInt(*device.IDUbicacion, 10)
+	}
+	return "dispositivo:" + device.NumeroSerie
+}
+
+// Correlates reports whether an alert raised at the given time belongs to the incident,
+// which takes alerts until window has passed since its latest one
+func (i *Incident) Correlates(at time.Time, window time.Duration) bool {
+	return i.Estado != IncidentResolved && !at.After(i.FechaUltima.Add(window))
+}
+
+// IsIncidentState reports whether state is a known incident state
+func IsIncidentState(state string) bool {
+	for _, s := range IncidentStates {
+		if s == state {
+			return true
+		}
+	}
+	return false
+}
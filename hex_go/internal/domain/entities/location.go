@@ -0,0 +1,117 @@
+package entities
+
+// Location levels, from the whole property down to a single room
+const (
+	LocationLevelSite     = "sitio"
+	LocationLevelBuilding = "edificio"
+	LocationLevelFloor    = "piso"
+	LocationLevelRoom     = "habitacion"
+)
+
+// LocationLevels lists the levels from top to bottom
+var LocationLevels = []string{LocationLevelSite, LocationLevelBuilding, LocationLevelFloor, LocationLevelRoom}
+
+// ParentLevel returns the level a location of the given level must hang from.
+// Sites have no parent and return an empty string.
+func ParentLevel(level string) string {
+	for i, l := range LocationLevels {
+		if l == level && i > 0 {
+			return LocationLevels[i-1]
+		}
+	}
+	return ""
+}
+
+// IsLocationLevel reports whether level is one of the known location levels
+func IsLocationLevel(level string) bool {
+	for _, l := range LocationLevels {
+		if l == level {
+			return true
+		}
+	}
+	return false
+}
+
+// Location is a node of the site → building → floor → room hierarchy.
+// Ruta is the materialised path of IDs from the site down to this node, such as
+// "/3/12/40/", and is used to select whole subtrees.
+type Location struct {
+	ID        int64  `json:"id"`
+	IDPadre   *int64 `json:"id_padre"`
+	Nivel     string `json:"nivel"`
+	Nombre    string `json:"nombre"`
+	Direccion string `json:"direccion,omitempty"`
+	IDUser    int    `json:"id_user"`
+	Ruta      string `json:"-"`
+}
+
+// CreateLocationRequest represents the request to add a location to the hierarchy
+type CreateLocationRequest struct {
+	IDPadre   *int64 `json:"id_padre"`
+	Nivel     string `json:"nivel"`
+	Nombre    string `json:"nombre"`
+	Direccion string `json:"direccion"`
+}
+
+// UpdateLocationRequest represents a rename of a location
+type UpdateLocationRequest struct {
+	Nombre    *string `json:"nombre"`
+	Direccion *string `json:"direccion"`
+}
+
+// AssignLocationRequest represents the request to place a device in a location.
+// A nil IDUbicacion removes the device from the hierarchy.
+type AssignLocationRequest struct {
+	IDUbicacion *int64 `json:"id_ubicacion"`
+}
+
+// AlertFilter narrows the alerts of a user to a part of the hierarchy and optionally
+// aggregates them by the locations of one level. With OrganizationID the alerts of the
+// devices shared with that organisation are read instead of the user's.
+type AlertFilter struct {
+	LocationID     *int64
+	GroupBy        string
+	OrganizationID *int64
+}
+
+// AlertGroup holds the alert counts of the devices below one location
+type AlertGroup struct {
+	Ubicacion *Location      `json:"ubicacion"`
+	Total     int            `json:"total"`
+	Activas   int            `json:"activas"`
+	PorTipo   map[string]int `json:"por_tipo"`
+}
+
+// DeviceStatus is the current state of one device inside a location
+type DeviceStatus struct {
+	NumeroSerie    string         `json:"numero_serie"`
+	Nombre         string         `json:"nombre"`
+	IDUbicacion    *int64         `json:"id_ubicacion"`
+	EnLinea        bool           `json:"en_linea"`
+	AlertasActivas map[string]int `json:"alertas_activas"`
+	PeorSeveridad  string         `json:"peor_severidad,omitempty"`
+}
+
+// LocationStatus summarises the active alerts of every device below a location
+type LocationStatus struct {
+	Ubicacion                *Location       `json:"ubicacion"`
+	TotalDispositivos        int             `json:"total_dispositivos"`
+	DispositivosFueraDeLinea int             `json:"dispositivos_fuera_de_linea"`
+	AlertasActivas           map[string]int  `json:"alertas_activas"`
+	PeorSeveridad            string          `json:"peor_severidad,omitempty"`
+	Dispositivos             []*DeviceStatus `json:"dispositivos"`
+}
+
+// SeverityRank orders severities so the worst one can be picked; unknown values rank lowest
+func SeverityRank(severity string) int {
+	switch severity {
+	case SeverityCritical:
+		return 3
+	case SeverityWarning:
+		return 2
+	case SeverityInfo:
+		return 1
+	default:
+		return 0
+	}
+}
@@ -0,0 +1,61 @@
+package entities
+
+import "time"
+
+// Audit log actions and resource type of maintenance windows
+const (
+	AuditMaintenanceCreated = "maintenance.created"
+	AuditMaintenanceUpdated = "maintenance.updated"
+	AuditMaintenanceEnded   = "maintenance.ended"
+	AuditMaintenanceDeleted = "maintenance.deleted"
+	ResourceMaintenance     = "maintenance_window"
+)
+
+// MaintenanceWindow suppresses the alerts of one device, or of every device below a
+// location such as a room or a site, from Inicio until Fin, while technicians test the
+// detectors. Readings are still stored; the alerts raised meanwhile are stored as
+// suppressed and nobody is notified.
+type MaintenanceWindow struct {
+	ID            int64     `json:"id"`
+	IDUser        int       `json:"id_user"`
+	Nombre        string    `json:"nombre"`
+	NumeroSerie   *string   `json:"numero_serie,omitempty"`
+	IDUbicacion   *int64    `json:"id_ubicacion,omitempty"`
+	Inicio        time.Time `json:"inicio"`
+	Fin           time.Time `json:"fin"`
+	FechaCreacion time.Time `json:"fecha_creacion"`
+}
+
+// Active reports whether the window suppresses alerts at the given time
+func (m *MaintenanceWindow) Active(at time.Time) bool {
+	return !at.Before(m.Inicio) && at.Before(m.Fin)
+}
+
+// MaintenanceWindowRequest schedules a maintenance window or replaces one. A window
+// without Inicio starts right away; it lasts until Fin or for DuracionMinutos.
+type MaintenanceWindowRequest struct {
+	Nombre          string     `json:"nombre"`
+	NumeroSerie     *string    `json:"numero_serie"`
+	IDUbicacion     *int64     `json:"id_ubicacion"`
+	Inicio          *time.Time `json:"inicio"`
+	Fin             *time.Time `json:"fin"`
+	DuracionMinutos int        `json:"duracion_minutos"`
+}
+
+// Period returns the start and end of the requested window, starting at now when it has
+// no start
+func (r *MaintenanceWindowRequest) Period(now time.Time) (time.Time, time.Time) {
+	inicio := now
+	if r.Inicio != nil {
+		inicio = *r.Inicio
+	}
+	if r.Fin != nil {
+		return inicio.UTC(), r.Fin.UTC()
+	}
+	return inicio.UTC(), inicio.Add(time.Duration(r.DuracionMinutos) * time.Minute).UTC()
+}
+
+// MaintenanceFilter narrows the maintenance windows of a user to those not over yet
+type MaintenanceFilter struct {
+	Current bool
+}
@@ -0,0 +1,165 @@
+package entities
+
+import "time"
+
+// Notification channels
+const (
+	ChannelEmail    = "email"
+	ChannelSMS      = "sms"
+	ChannelTelegram = "telegram"
+	ChannelPush     = "push"
+)
+
+// NotificationChannels lists the channels recipients can be registered for
+var NotificationChannels = []string{ChannelEmail, ChannelSMS, ChannelTelegram, ChannelPush}
+
+// Notification languages
+const (
+	LanguageSpanish = "es"
+	LanguageEnglish = "en"
+)
+
+// NotificationLanguages lists the languages notifications are written in
+var NotificationLanguages = []string{LanguageSpanish, LanguageEnglish}
+
+// Audit log actions for notification recipients
+const (
+	AuditRecipientCreated = "recipient.created"
+	AuditRecipientDeleted = "recipient.deleted"
+
+	AuditNotificationChannelsUpdated = "notification_channels.updated"
+)
+
+// Audit resource types of notification settings
+const (
+	ResourceRecipient = "recipient"
+	ResourceUser      = "user"
+)
+
+// NotificationRecipient is an address a user wants alerts sent to, such as their own
+// email and a neighbour's
+type NotificationRecipient struct {
+	ID            int64     `json:"id"`
+	IDUser        int       `json:"id_user"`
+	Canal         string    `json:"canal"`
+	Direccion     string    `json:"direccion"`
+	Idioma        string    `json:"idioma"`
+	FechaCreacion time.Time `json:"fecha_creacion"`
+}
+
+// CreateRecipientRequest represents the request to add a notification recipient
+type CreateRecipientRequest struct {
+	Canal     string `json:"canal"`
+	Direccion string `json:"direccion"`
+	Idioma    string `json:"idioma"`
+}
+
+// Notification is an alert addressed to one recipient. Each channel adapter renders it
+// in the recipient's language.
+type Notification struct {
+	Destinatario *NotificationRecipient
+	Alerta       *Alert
+	// Dispositivo is the device name, or its serial number when it has none
+	Dispositivo string
+	// Ubicacion is the location of the device, from the site down to the room
+	Ubicacion string
+	// Fecha is the time of the alert in the timezone of the device
+	Fecha time.Time
+}
+
+// NotificationChannelSettings maps each alert severity to the channels a user wants to be
+// notified by. A severity without channels is not notified.
+type NotificationChannelSettings struct {
+	Canales map[string][]string `json:"canales"`
+}
+
+// DefaultNotificationChannelSettings returns the settings of users who never chose:
+// critical alerts go to every channel and the rest are not notified
+func DefaultNotificationChannelSettings() *NotificationChannelSettings {
+	return &NotificationChannelSettings{
+		Canales: map[string][]string{
+			SeverityCritical: append([]string{}, NotificationChannels...),
+			SeverityWarning:  {},
+			SeverityInfo:     {},
+		},
+	}
+}
+
+// Allows reports whether alerts of the given severity are sent through channel
+func (s *NotificationChannelSettings) Allows(severity, channel string) bool {
+	for _, c := range s.Canales[severity] {
+		if c == channel {
+			return true
+		}
+	}
+	return false
+}
+
+// MinimumSeverities returns, for every channel, the least severe alert sent through it,
+// or SeverityNone when the channel is not used
+func (s *NotificationChannelSettings) MinimumSeverities() map[string]string {
+	minimum := make(map[string]string, len(NotificationChannels))
+	for _, channel := range NotificationChannels {
+		minimum[channel] = SeverityNone
+		for _, severity := range Severities {
+			if s.Allows(severity, channel) {
+				minimum[channel] = severity
+			}
+		}
+	}
+	return minimum
+}
+
+// SetMinimumSeverity sends alerts of severity at least minimum through channel, and no
+// others. SeverityNone turns the channel off.
+func (s *NotificationChannelSettings) SetMinimumSeverity(channel, minimum string) {
+	for _, severity := range Severities {
+		channels := make([]string, 0, len(s.Canales[severity])+1)
+		for _, c := range s.Canales[severity] {
+			if c != channel {
+				channels = append(channels, c)
+			}
+		}
+		if minimum != SeverityNone && SeverityRank(severity) >= SeverityRank(minimum) {
+			channels = append(channels, channel)
+		}
+		s.Canales[severity] = channels
+	}
+}
+
+// NotificationDelivery records one notification sent, or that failed to send, to a
+// recipient. Every channel shares the same log. Estado is DeliveryDelivered or
+// DeliveryFailed. IDDestinatario is nil once the recipient has been removed, and for
+// escalation contacts, which are identified by IDEscalacion instead.
+type NotificationDelivery struct {
+	ID             int64     `json:"id"`
+	IDAlerta       int64     `json:"id_alerta"`
+	IDDestinatario *int64    `json:"id_destinatario,omitempty"`
+	IDEscalacion   *int64    `json:"id_escalacion,omitempty"`
+	IDUser         int       `json:"id_user"`
+	Canal          string    `json:"canal"`
+	Direccion      string    `json:"direccion"`
+	Estado         string    `json:"estado"`
+	Error          string    `json:"error,omitempty"`
+	FechaCreacion  time.Time `json:"fecha_creacion"`
+}
+
+// IsNotificationChannel reports whether channel is a supported notification channel
+func IsNotificationChannel(channel string) bool {
+	for _, c := range NotificationChannels {
+		if c == channel {
+			return true
+		}
+	}
+	return false
+}
+
+// IsNotificationLanguage reports whether lang is a supported notification language
+func IsNotificationLanguage(lang string) bool {
+	for _, l := range NotificationLanguages {
+		if l == lang {
+			return true
+		}
+	}
+	return false
+}
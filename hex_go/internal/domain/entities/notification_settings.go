@@ -0,0 +1,145 @@
+package entities
+
+import (
+	"fmt"
+	"time"
+)
+
+// Digest modes batch non-critical notifications
+const (
+	DigestNone   = "none"
+	DigestHourly = "hourly"
+	DigestDaily  = "daily"
+)
+
+// DigestModes lists every digest mode
+var DigestModes = []string{DigestNone, DigestHourly, DigestDaily}
+
+// SeverityNone turns a channel off in NotificationSettings.SeveridadMinima
+const SeverityNone = "none"
+
+// AuditNotificationSettingsUpdated is logged when a user changes their notification settings
+const AuditNotificationSettingsUpdated = "notification_settings.updated"
+
+// QuietHours is a daily period, in the user's timezone, during which non-critical
+// notifications are held back. Times are "HH:MM"; a period may cross midnight.
+type QuietHours struct {
+	Inicio string `json:"inicio"`
+	Fin    string `json:"fin"`
+}
+
+// NotificationPreferences are when a user wants to be notified. Critical alerts are
+// always sent right away; the rest wait for the end of quiet hours or for the next
+// digest. HoraResumen is the local hour daily digests are sent at.
+type NotificationPreferences struct {
+	ZonaHoraria   string      `json:"zona_horaria"`
+	HorasSilencio *QuietHours `json:"horas_silencio"`
+	Resumen       string      `json:"resumen"`
+	HoraResumen   int         `json:"hora_resumen"`
+}
+
+// DefaultNotificationPreferences returns the preferences of users who never set them:
+// every notification is sent right away
+func DefaultNotificationPreferences() *NotificationPreferences {
+	return &NotificationPreferences{
+		ZonaHoraria: "UTC",
+		Resumen:     DigestNone,
+		HoraResumen: 8,
+	}
+}
+
+// NotificationSettings is the view of a user's notification preferences together with
+// the least severe alert each channel is used for
+type NotificationSettings struct {
+	NotificationPreferences
+	SeveridadMinima map[string]string `json:"severidad_minima"`
+}
+
+// DeferUntil returns when a non-critical notification raised at now should be sent:
+// at the next digest, pushed past quiet hours. It reports false when it should be
+// sent right away.
+func (p *NotificationPreferences) DeferUntil(now time.Time) (time.Time, bool) {
+	loc, err := time.LoadLocation(p.ZonaHoraria)
+	if err != nil {
+		loc = time.UTC
+	}
+	local := now.In(loc)
+
+	var at time.Time
+	switch p.Resumen {
+	case DigestHourly:
+		at = time.Date(local.Year(), local.Month(), local.Day(), local.Hour()+1, 0, 0, 0, loc)
+	case DigestDaily:
+		at = time.Date(local.Year(), local.Month(), local.Day(), p.HoraResumen, 0, 0, 0, loc)
+		if !at.After(local) {
+			at = at.AddDate(0, 0, 1)
+		}
+	default:
+		if end, ok := p.quietEnd(local); ok {
+			return end, true
+		}
+		return time.Time{}, false
+	}
+
+	if end, ok := p.quietEnd(at); ok {
+		at = end
+	}
+	return at, true
+}
+
+// quietEnd returns the end of the quiet period t falls in, if any
+func (p *NotificationPreferences) quietEnd(t time.Time) (time.Time, bool) {
+	if p.HorasSilencio == nil {
+		return time.Time{}, false
+	}
+	start, err := ParseClock(p.HorasSilencio.Inicio)
+	if err != nil {
+		return time.Time{}, false
+	}
+	end, err := ParseClock(p.HorasSilencio.Fin)
+	if err != nil || start == end {
+		return time.Time{}, false
+	}
+
+	minute := t.Hour()*60 + t.Minute()
+	quiet := minute >= start && minute < end
+	if start > end {
+		quiet = minute >= start || minute < end
+	}
+	if !quiet {
+		return time.Time{}, false
+	}
+
+	at := time.Date(t.Year(), t.Month(), t.Day(), end/60, end%60, 0, 0, t.Location())
+	if !at.After(t) {
+		at = at.AddDate(0, 0, 1)
+	}
+	return at, true
+}
+
+// ParseClock parses a "HH:MM" time of day into minutes since midnight
+func ParseClock(value string) (int, error) {
+	t, err := time.Parse("15:04", value)
+	if err != nil {
+		return 0, fmt.Errorf("invalid time of day %q", value)
+	}
+	return t.Hour()*60 + t.Minute(), nil
+}
+
+// IsDigestMode reports whether mode is a known digest mode
+func IsDigestMode(mode string) bool {
+	for _, m := range DigestModes {
+		if m == mode {
+			return true
+		}
+	}
+	return false
+}
+
+// NotificationDigest batches the non-critical notifications held back for one
+// recipient. Notificaciones holds the latest ones; Total counts them all.
+type NotificationDigest struct {
+	Destinatario   *NotificationRecipient
+	Notificaciones []*Notification
+	Total          int
+}
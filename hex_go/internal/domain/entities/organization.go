@@ -0,0 +1,127 @@
+package entities
+
+import "time"
+
+// Organisation member roles
+const (
+	RoleOwner     = "owner"
+	RoleAdmin     = "admin"
+	RoleViewer    = "viewer"
+	RoleResponder = "responder"
+)
+
+// OrganizationRoles lists every member role
+var OrganizationRoles = []string{RoleOwner, RoleAdmin, RoleViewer, RoleResponder}
+
+// IsOrganizationRole reports whether role is a known member role
+func IsOrganizationRole(role string) bool {
+	for _, r := range OrganizationRoles {
+		if r == role {
+			return true
+		}
+	}
+	return false
+}
+
+// CanManageOrganization reports whether a role may change members, invitations and shared devices
+func CanManageOrganization(role string) bool {
+	return role == RoleOwner || role == RoleAdmin
+}
+
+// CanRespondToAlerts reports whether a role may acknowledge alerts and acknowledge or
+// resolve incidents of the devices it sees. Viewers only read them.
+func CanRespondToAlerts(role string) bool {
+	return role == RoleOwner || role == RoleAdmin || role == RoleResponder
+}
+
+// Invitation states
+const (
+	InvitationPending  = "pendiente"
+	InvitationAccepted = "aceptada"
+	InvitationDeclined = "rechazada"
+	InvitationRevoked  = "revocada"
+)
+
+// Access audit actions
+const (
+	AuditOrganizationCreated = "organization.created"
+	AuditMemberAdded         = "member.added"
+	AuditMemberRoleChanged   = "member.role_changed"
+	AuditMemberRemoved       = "member.removed"
+	AuditInvitationCreated   = "invitation.created"
+	AuditInvitationAccepted  = "invitation.accepted"
+	AuditInvitationDeclined  = "invitation.declined"
+	AuditInvitationRevoked   = "invitation.revoked"
+	AuditDeviceShared        = "device.shared"
+	AuditDeviceUnshared      = "device.unshared"
+)
+
+// Organization groups users, such as a household or a facility team, that share devices
+type Organization struct {
+	ID            int64     `json:"id"`
+	Nombre        string    `json:"nombre"`
+	FechaCreacion time.Time `json:"fecha_creacion"`
+
+	// Rol is the role of the calling user, filled in when listing their organisations
+	Rol string `json:"rol,omitempty"`
+}
+
+// Membership is the role of a user inside an organisation
+type Membership struct {
+	IDOrganizacion int64     `json:"id_organizacion"`
+	IDUser         int       `json:"id_user"`
+	Rol            string    `json:"rol"`
+	FechaAlta      time.Time `json:"fecha_alta"`
+}
+
+// Invitation asks a user to join an organisation with a given role
+type Invitation struct {
+	ID              int64     `json:"id"`
+	IDOrganizacion  int64     `json:"id_organizacion"`
+	IDUserInvitado  int       `json:"id_user_invitado"`
+	Rol             string    `json:"rol"`
+	Estado          string    `json:"estado"`
+	InvitadoPor     int       `json:"invitado_por"`
+	FechaCreacion   time.Time `json:"fecha_creacion"`
+	FechaExpiracion time.Time `json:"fecha_expiracion"`
+}
+
+// SharedDevice is a device made visible to every member of an organisation
+type SharedDevice struct {
+	IDOrganizacion int64     `json:"id_organizacion"`
+	NumeroSerie    string    `json:"numero_serie"`
+	CompartidoPor  int       `json:"compartido_por"`
+	FechaAlta      time.Time `json:"fecha_alta"`
+}
+
+// AccessAuditEntry records a change to who can access an organisation or its devices
+type AccessAuditEntry struct {
+	ID             int64     `json:"id"`
+	IDOrganizacion int64     `json:"id_organizacion"`
+	Actor          int       `json:"actor"`
+	Accion         string    `json:"accion"`
+	Objetivo       string    `json:"objetivo"`
+	Detalle        string    `json:"detalle,omitempty"`
+	Fecha          time.Time `json:"fecha"`
+}
+
+// CreateOrganizationRequest represents the request to create an organisation
+type CreateOrganizationRequest struct {
+	Nombre string `json:"nombre"`
+}
+
+// InviteMemberRequest represents the request to invite a user into an organisation
+type InviteMemberRequest struct {
+	IDUser int    `json:"id_user"`
+	Rol    string `json:"rol"`
+}
+
+// UpdateMemberRequest represents a change of a member's role
+type UpdateMemberRequest struct {
+	Rol string `json:"rol"`
+}
+
+// ShareDeviceRequest represents the request to share a device with an organisation
+type ShareDeviceRequest struct {
+	NumeroSerie string `json:"numero_serie"`
+}
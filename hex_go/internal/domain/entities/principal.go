@@ -0,0 +1,71 @@
+package entities
+
+import (
+	"context"
+	"strconv"
+)
+
+// Principal kinds
+const (
+	PrincipalAnonymous = "anonymous"
+	PrincipalUser      = "user"
+	PrincipalDevice    = "device"
+	PrincipalAPIKey    = "api_key"
+)
+
+// Principal is the authenticated caller of a request
+type Principal struct {
+	Kind        string   `json:"kind"`
+	UserID      int      `json:"user_id,omitempty"`
+	NumeroSerie string   `json:"numero_serie,omitempty"`
+	Roles       []string `json:"roles"`
+
+	// API key callers act for an organisation with the permissions granted to the key
+	IDOrganizacion int64    `json:"id_organizacion,omitempty"`
+	IDAPIKey       int64    `json:"id_api_key,omitempty"`
+	Scopes         []string `json:"scopes,omitempty"`
+}
+
+// IsUser reports whether the caller is an authenticated user
+func (p *Principal) IsUser() bool {
+	return p != nil && p.Kind == PrincipalUser
+}
+
+// IsDevice reports whether the caller is a device firmware
+func (p *Principal) IsDevice() bool {
+	return p != nil && p.Kind == PrincipalDevice
+}
+
+// IsAPIKey reports whether the caller is an integration using an API key
+func (p *Principal) IsAPIKey() bool {
+	return p != nil && p.Kind == PrincipalAPIKey
+}
+
+// Actor returns the kind and identifier of a caller as stored in the audit log
+func (p *Principal) Actor() (string, string) {
+	switch {
+	case p.IsUser():
+		return PrincipalUser, strconv.Itoa(p.UserID)
+	case p.IsDevice():
+		return PrincipalDevice, p.NumeroSerie
+	case p.IsAPIKey():
+		return PrincipalAPIKey, strconv.FormatInt(p.IDAPIKey, 10)
+	case p == nil:
+		return ActorSystem, ""
+	default:
+		return PrincipalAnonymous, ""
+	}
+}
+
+type principalKey struct{}
+
+// WithPrincipal returns a copy of ctx carrying the caller of the request
+func WithPrincipal(ctx context.Context, principal *Principal) context.Context {
+	return context.WithValue(ctx, principalKey{}, principal)
+}
+
+// PrincipalFromContext returns the caller stored in ctx, or nil
+func PrincipalFromContext(ctx context.Context) *Principal {
+	principal, _ := ctx.Value(principalKey{}).(*Principal)
+	return principal
+}
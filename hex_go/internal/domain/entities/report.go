@@ -0,0 +1,119 @@
+package entities
+
+import (
+	"sort"
+	"time"
+)
+
+// ReportMonthLayout is the format of the month of a report, such as "2026-09"
+const ReportMonthLayout = "2006-01"
+
+// ReportNoisyDevices is how many of the devices with most activations a report lists
+const ReportNoisyDevices = 5
+
+// SiteReport is the monthly safety report of a site: how many alarms went off, how fast
+// they were acknowledged, how long the devices were connected and which devices raised
+// the most activations. Months are calendar months in UTC; a report of the current month
+// covers it up to Hasta.
+type SiteReport struct {
+	Sitio        *Location             `json:"sitio"`
+	Mes          string                `json:"mes"`
+	Desde        time.Time             `json:"desde"`
+	Hasta        time.Time             `json:"hasta"`
+	GeneradoEn   time.Time             `json:"generado_en"`
+	Alarmas      []*ReportAlarmCount   `json:"alarmas"`
+	Respuesta    ReportResponseTimes   `json:"respuesta"`
+	Dispositivos []*ReportDeviceUptime `json:"dispositivos"`
+	Ruidosos     []*ReportNoisyDevice  `json:"ruidosos"`
+}
+
+// ReportAlarmCount counts the alerts of one type raised in the month, leaving out those
+// suppressed by maintenance windows
+type ReportAlarmCount struct {
+	Tipo         string `json:"tipo"`
+	Alertas      int    `json:"alertas"`
+	Activaciones int    `json:"activaciones"`
+	Criticas     int    `json:"criticas"`
+}
+
+// ReportResponseTimes summarises the time from raising an alert to acknowledging it
+type ReportResponseTimes struct {
+	Reconocidas  int           `json:"reconocidas"`
+	SinReconocer int           `json:"sin_reconocer"`
+	Media        time.Duration `json:"media"`
+	Mediana      time.Duration `json:"mediana"`
+	P90          time.Duration `json:"p90"`
+}
+
+// NewReportResponseTimes summarises the response times of the acknowledged alerts
+func NewReportResponseTimes(acknowledged []time.Duration, pending int) ReportResponseTimes {
+	times := ReportResponseTimes{Reconocidas: len(acknowledged), SinReconocer: pending}
+	if len(acknowledged) == 0 {
+		return times
+	}
+
+	sorted := append([]time.Duration{}, acknowledged...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i] < sorted[j] })
+	var total time.Duration
+	for _, d := range sorted {
+		total += d
+	}
+	middle := len(sorted) / 2
+	times.Media = total / time.Duration(len(sorted))
+	times.Mediana = sorted[middle]
+	if len(sorted)%2 == 0 {
+		times.Mediana = (sorted[middle-1] + sorted[middle]) / 2
+	}
+	times.P90 = sorted[len(sorted)*9/10]
+	return times
+}
+
+// ReportDeviceUptime is the share of the month a device was connected. Observado is the
+// part of the month since the device was registered; SinConexion the time offline in it.
+type ReportDeviceUptime struct {
+	NumeroSerie    string        `json:"numero_serie"`
+	Nombre         string        `json:"nombre"`
+	Ubicacion      string        `json:"ubicacion"`
+	Observado      time.Duration `json:"observado"`
+	SinConexion    time.Duration `json:"sin_conexion"`
+	Disponibilidad float64       `json:"disponibilidad"`
+}
+
+// ComputeAvailability sets Disponibilidad from the observed and offline time; a device
+// not observed at all counts as fully available
+func (u *ReportDeviceUptime) ComputeAvailability() {
+	u.Disponibilidad = 1
+	if u.Observado > 0 {
+		offline := u.SinConexion
+		if offline > u.Observado {
+			offline = u.Observado
+		}
+		u.Disponibilidad = 1 - float64(offline)/float64(u.Observado)
+	}
+}
+
+// ReportNoisyDevice is one of the devices with most activations in the month
+type ReportNoisyDevice struct {
+	NumeroSerie  string `json:"numero_serie"`
+	Nombre       string `json:"nombre"`
+	Alertas      int    `json:"alertas"`
+	Activaciones int    `json:"activaciones"`
+	Oscilantes   int    `json:"oscilantes"`
+}
+
+// ReportPeriod returns the start of a report month and its end, or now for the current month
+func ReportPeriod(month string, now time.Time) (time.Time, time.Time, error) {
+	desde, err := time.Parse(ReportMonthLayout, month)
+	if err != nil {
+		return time.Time{}, time.Time{}, &ValidationError{Field: "month", Message: "must be a month such as 2026-09"}
+	}
+	if !desde.Before(now) {
+		return time.Time{}, time.Time{}, &ValidationError{Field: "month", Message: "must not be in the future"}
+	}
+
+	hasta := desde.AddDate(0, 1, 0)
+	if hasta.After(now) {
+		hasta = now.UTC()
+	}
+	return desde, hasta, nil
+}
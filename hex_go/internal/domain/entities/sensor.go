@@ -1,46 +1,95 @@
 package entities
 
+import "time"
+
 // SensorKY026 represents the KY_026 sensor entity
 type SensorKY026 struct {
-	ID                 int    `json:"id"`
-	FechaActivacion    string `json:"fecha_activacion"`
-	FechaDesactivacion string `json:"fecha_desactivacion"`
-	Estado             int    `json:"estado"`
-	NumeroSerie        string `json:"numero_serie"`
+	ID                 int        `json:"id"`
+	FechaActivacion    time.Time  `json:"fecha_activacion"`
+	FechaDesactivacion *time.Time `json:"fecha_desactivacion"`
+	Estado             int        `json:"estado"`
+	NumeroSerie        string     `json:"numero_serie"`
 }
 
 // SensorMQ2 represents the MQ_2 sensor entity
 type SensorMQ2 struct {
-	ID                 int    `json:"id"`
-	FechaActivacion    string `json:"fecha_activacion"`
-	FechaDesactivacion string `json:"fecha_desactivacion"`
-	Estado             int    `json:"estado"`
-	NumeroSerie        string `json:"numero_serie"`
+	ID                 int        `json:"id"`
+	FechaActivacion    time.Time  `json:"fecha_activacion"`
+	FechaDesactivacion *time.Time `json:"fecha_desactivacion"`
+	Estado             int        `json:"estado"`
+	NumeroSerie        string     `json:"numero_serie"`
+	PPM                *float64   `json:"ppm"`
 }
 
 // SensorMQ135 represents the MQ_135 sensor entity
 type SensorMQ135 struct {
-	ID                 int    `json:"id"`
-	FechaActivacion    string `json:"fecha_activacion"`
-	FechaDesactivacion string `json:"fecha_desactivacion"`
-	Estado             int    `json:"estado"`
-	NumeroSerie        string `json:"numero_serie"`
+	ID                 int        `json:"id"`
+	FechaActivacion    time.Time  `json:"fecha_activacion"`
+	FechaDesactivacion *time.Time `json:"fecha_desactivacion"`
+	Estado             int        `json:"estado"`
+	NumeroSerie        string     `json:"numero_serie"`
+	PPM                *float64   `json:"ppm"`
 }
 
 // SensorDHT22 represents the DHT_22 sensor entity
 type SensorDHT22 struct {
-	ID                 int    `json:"id"`
-	FechaActivacion    string `json:"fecha_activacion"`
-	FechaDesactivacion string `json:"fecha_desactivacion"`
-	Estado             string `json:"estado"` // Changed from int to string
-	NumeroSerie        string `json:"numero_serie"`
+	ID                 int        `json:"id"`
+	FechaActivacion    time.Time  `json:"fecha_activacion"`
+	FechaDesactivacion *time.Time `json:"fecha_desactivacion"`
+	Estado             string     `json:"estado"` // Legacy "temperatura,humedad" text
+	NumeroSerie        string     `json:"numero_serie"`
+	Temperatura        float64    `json:"temperatura"`
+	Humedad            float64    `json:"humedad"`
+	IndiceCalor        float64    `json:"indice_calor"`
+	PuntoRocio         float64    `json:"punto_rocio"`
 }
 
 // SensorDataRequest represents the incoming request for sensor data
 type SensorDataRequest struct {
 	NumeroSerie        string      `json:"numeroSerie"`
 	Sensor             string      `json:"sensor"`
-	FechaActivacion    string      `json:"fecha_activacion"`
-	FechaDesactivacion string      `json:"fecha_desactivacion"`
-	Estado             interface{} `json:"estado"` 
-}
\ No newline at end of file
+	FechaActivacion    interface{} `json:"fecha_activacion"`
+	FechaDesactivacion interface{} `json:"fecha_desactivacion"`
+	Estado             interface{} `json:"estado"`
+
+	// DHT_22 readings may send the values as separate fields instead of inside estado.
+	// IndiceCalor and PuntoRocio are filled in at ingest before the data is published.
+	Temperatura *float64 `json:"temperatura,omitempty"`
+	Humedad     *float64 `json:"humedad,omitempty"`
+	IndiceCalor *float64 `json:"indice_calor,omitempty"`
+	PuntoRocio  *float64 `json:"punto_rocio,omitempty"`
+
+	// PPM is filled in at ingest for calibrated MQ_2 and MQ_135 readings
+	PPM *float64 `json:"ppm,omitempty"`
+}
+
+// Sensor type names as sent by the devices and used for table names and routing keys
+const (
+	SensorTypeKY026 = "KY_026"
+	SensorTypeMQ2   = "MQ_2"
+	SensorTypeMQ135 = "MQ_135"
+	SensorTypeDHT22 = "DHT_22"
+)
+
+// SensorTypes lists every supported sensor type
+var SensorTypes = []string{SensorTypeKY026, SensorTypeMQ2, SensorTypeMQ135, SensorTypeDHT22}
+
+// SensorReading is a validated sensor data request with its values converted to typed form
+type SensorReading struct {
+	NumeroSerie        string
+	Sensor             string
+	FechaActivacion    time.Time
+	FechaDesactivacion *time.Time
+	Estado             int    // KY_026, MQ_2 and MQ_135
+	EstadoTexto        string // DHT_22, legacy "temperatura,humedad" text
+
+	// DHT_22 values, with the derived heat index and dew point in °C
+	Temperatura float64
+	Humedad     float64
+	IndiceCalor float64
+	PuntoRocio  float64
+
+	// PPM is the calibrated concentration of MQ_2 and MQ_135 readings, nil while the
+	// sensor learns its baseline
+	PPM *float64
+}
@@ -0,0 +1,103 @@
+package entities
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// localTimestampLayout is the layout devices use when they send local wall-clock time
+const localTimestampLayout = "2006-01-02 15:04:05"
+
+// MaxClockSkew is how far in the future a device clock may be before a reading is rejected
+const MaxClockSkew = 5 * time.Minute
+
+// MinValidTimestamp is the earliest timestamp accepted from a device. Anything older
+// usually comes from a board whose RTC was never set.
+var MinValidTimestamp = time.Date(2020, time.January, 1, 0, 0, 0, 0, time.UTC)
+
+// millisThreshold separates epoch seconds from epoch milliseconds
+const millisThreshold = 1e11
+
+// ParseTimestamp parses a device timestamp and normalises it to UTC. It accepts RFC3339,
+// "YYYY-MM-DD HH:MM:SS" (interpreted in loc) and epoch seconds or milliseconds, either as
+// a JSON number or as a string of digits. Timestamps later than now plus MaxClockSkew or
+// earlier than MinValidTimestamp are rejected.
+func ParseTimestamp(field string, raw interface{}, loc *time.Location, now time.Time) (time.Time, error) {
+	if loc == nil {
+		loc = time.UTC
+	}
+
+	var t time.Time
+	switch v := raw.(type) {
+	case float64:
+		t = fromEpoch(v)
+	case int:
+		t = fromEpoch(float64(v))
+	case int64:
+		t = fromEpoch(float64(v))
+	case string:
+		s := strings.TrimSpace(v)
+		if s == "" {
+			return time.Time{}, &ValidationError{Field: field, Message: "is required"}
+		}
+		parsed, err := parseTimestampString(s, loc)
+		if err != nil {
+			return time.Time{}, &ValidationError{Field: field, Message: err.Error()}
+		}
+		t = parsed
+	case nil:
+		return time.Time{}, &ValidationError{Field: field, Message: "is required"}
+	default:
+		return time.Time{}, &ValidationError{Field: field, Message: fmt.Sprintf("unsupported type %T", v)}
+	}
+
+	t = t.UTC()
+	if t.After(now.Add(MaxClockSkew)) {
+		return time.Time{}, &ValidationError{Field: field, Message: fmt.Sprintf("%s is in the future", t.Format(time.RFC3339))}
+	}
+	if t.Before(MinValidTimestamp) {
+		return time.Time{}, &ValidationError{Field: field, Message: fmt.Sprintf("%s is before %s", t.Format(time.RFC3339), MinValidTimestamp.Format(time.RFC3339))}
+	}
+
+	return t, nil
+}
+
+// ParseOptionalTimestamp behaves like ParseTimestamp but returns nil for an empty value
+func ParseOptionalTimestamp(field string, raw interface{}, loc *time.Location, now time.Time) (*time.Time, error) {
+	if raw == nil {
+		return nil, nil
+	}
+	if s, ok := raw.(string); ok && strings.TrimSpace(s) == "" {
+		return nil, nil
+	}
+
+	t, err := ParseTimestamp(field, raw, loc, now)
+	if err != nil {
+		return nil, err
+	}
+	return &t, nil
+}
+
+func parseTimestampString(s string, loc *time.Location) (time.Time, error) {
+	if n, err := strconv.ParseInt(s, 10, 64); err == nil {
+		return fromEpoch(float64(n)), nil
+	}
+	if t, err := time.Parse(time.RFC3339, s); err == nil {
+		return t, nil
+	}
+	if t, err := time.ParseInLocation(localTimestampLayout, s, loc); err == nil {
+		return t, nil
+	}
+	return time.Time{}, fmt.Errorf("%q is not RFC3339, YYYY-MM-DD HH:MM:SS or epoch seconds/milliseconds", s)
+}
+
+func fromEpoch(v float64) time.Time {
+	if math.Abs(v) >= millisThreshold {
+		return time.UnixMilli(int64(v))
+	}
+	sec, frac := math.Modf(v)
+	return time.Unix(int64(sec), int64(frac*1e9))
+}
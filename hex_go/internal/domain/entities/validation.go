@@ -0,0 +1,54 @@
+package entities
+
+import (
+	"fmt"
+	"strings"
+)
+
+// ValidationError reports a request field whose value cannot be accepted
+type ValidationError struct {
+	Field   string `json:"field"`
+	Message string `json:"message"`
+}
+
+func (e *ValidationError) Error() string {
+	return fmt.Sprintf("invalid %s: %s", e.Field, e.Message)
+}
+
+// ValidationErrors collects every field error found while validating a request
+type ValidationErrors []*ValidationError
+
+func (e ValidationErrors) Error() string {
+	messages := make([]string, len(e))
+	for i, fieldErr := range e {
+		messages[i] = fieldErr.Error()
+	}
+	return strings.Join(messages, "; ")
+}
+
+// Add records a new field error
+func (e *ValidationErrors) Add(field, message string) {
+	*e = append(*e, &ValidationError{Field: field, Message: message})
+}
+
+// Append records an error returned by another check. ValidationError values are kept as
+// field errors; any other error is recorded against the given field.
+func (e *ValidationErrors) Append(field string, err error) {
+	switch v := err.(type) {
+	case nil:
+	case *ValidationError:
+		*e = append(*e, v)
+	case ValidationErrors:
+		*e = append(*e, v...)
+	default:
+		e.Add(field, err.Error())
+	}
+}
+
+// Err returns nil when no field error was recorded
+func (e ValidationErrors) Err() error {
+	if len(e) == 0 {
+		return nil
+	}
+	return e
+}
@@ -0,0 +1,127 @@
+package entities
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"strconv"
+	"time"
+)
+
+// Webhook events
+const (
+	WebhookEventAlertCreated = "alert.created"
+	WebhookEventTest         = "webhook.test"
+)
+
+// Webhook delivery states
+const (
+	DeliveryPending   = "pendiente"
+	DeliveryDelivered = "entregada"
+	DeliveryFailed    = "fallida"
+)
+
+// Headers sent with every webhook delivery
+const (
+	WebhookSignatureHeader = "X-StopFire-Signature"
+	WebhookEventHeader     = "X-StopFire-Event"
+	WebhookDeliveryHeader  = "X-StopFire-Delivery"
+)
+
+// Access audit and audit log actions for webhooks
+const (
+	AuditWebhookCreated  = "webhook.created"
+	AuditWebhookUpdated  = "webhook.updated"
+	AuditWebhookDeleted  = "webhook.deleted"
+	AuditWebhookDisabled = "webhook.disabled"
+)
+
+// ResourceWebhook is the audit resource type of webhooks
+const ResourceWebhook = "webhook"
+
+// Webhook pushes the alerts of one device, or of every device below a location such as
+// a site, to a URL of the user. Tipos limits the alert types sent; empty sends all.
+type Webhook struct {
+	ID                 int64     `json:"id"`
+	IDUser             int       `json:"id_user"`
+	URL                string    `json:"url"`
+	NumeroSerie        *string   `json:"numero_serie,omitempty"`
+	IDUbicacion        *int64    `json:"id_ubicacion,omitempty"`
+	Tipos              []string  `json:"tipos"`
+	Activo             bool      `json:"activo"`
+	FallosConsecutivos int       `json:"fallos_consecutivos"`
+	FechaCreacion      time.Time `json:"fecha_creacion"`
+
+	// Secreto signs the deliveries; it is only shown when the webhook is created
+	Secreto string `json:"-"`
+}
+
+// Accepts reports whether an alert of the given type is sent to the webhook
+func (w *Webhook) Accepts(alertType string) bool {
+	if len(w.Tipos) == 0 {
+		return true
+	}
+	for _, t := range w.Tipos {
+		if t == alertType {
+			return true
+		}
+	}
+	return false
+}
+
+// CreatedWebhook is returned once when a webhook is registered, with its signing secret
+type CreatedWebhook struct {
+	*Webhook
+	Secreto string `json:"secreto"`
+}
+
+// CreateWebhookRequest registers a webhook for either a device or a location
+type CreateWebhookRequest struct {
+	URL         string   `json:"url"`
+	NumeroSerie *string  `json:"numero_serie"`
+	IDUbicacion *int64   `json:"id_ubicacion"`
+	Tipos       []string `json:"tipos"`
+}
+
+// UpdateWebhookRequest changes a webhook. Setting Activo re-enables a disabled webhook
+// and clears its failure count.
+type UpdateWebhookRequest struct {
+	URL    *string   `json:"url"`
+	Tipos  *[]string `json:"tipos"`
+	Activo *bool     `json:"activo"`
+}
+
+// WebhookDelivery is one event sent, or to be sent, to a webhook. The payload is kept
+// so every retry sends the same bytes.
+type WebhookDelivery struct {
+	ID             int64      `json:"id"`
+	IDWebhook      int64      `json:"id_webhook"`
+	Evento         string     `json:"evento"`
+	Payload        string     `json:"payload"`
+	Estado         string     `json:"estado"`
+	Intentos       int        `json:"intentos"`
+	ProximoIntento *time.Time `json:"proximo_intento,omitempty"`
+	CodigoHTTP     *int       `json:"codigo_http,omitempty"`
+	Error          string     `json:"error,omitempty"`
+	FechaCreacion  time.Time  `json:"fecha_creacion"`
+	FechaEntrega   *time.Time `json:"fecha_entrega,omitempty"`
+}
+
+// WebhookPayload is the JSON body of a delivery
+type WebhookPayload struct {
+	Evento string    `json:"evento"`
+	Fecha  time.Time `json:"fecha"`
+	Alerta *Alert    `json:"alerta"`
+}
+
+// SignWebhookPayload returns the signature header value of a delivery body: the
+// timestamp and the hex HMAC-SHA256 of "<timestamp>.<body>" keyed with the secret.
+// Receivers should recompute it and reject old timestamps to stop replays.
+func SignWebhookPayload(secret string, timestamp time.Time, body []byte) string {
+	ts := strconv.FormatInt(timestamp.Unix(), 10)
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(ts))
+	mac.Write([]byte{'.'})
+	mac.Write(body)
+	return "t=" + ts + ",v1=" + hex.EncodeToString(mac.Sum(nil))
+}
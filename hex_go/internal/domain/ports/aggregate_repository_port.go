@@ -0,0 +1,28 @@
+package ports
+
+import (
+	"context"
+	"time"
+
+	"hex_go/internal/domain/entities"
+)
+
+// AggregateRepositoryPort summarises readings per time bucket, from the sensor tables or
+// from the hourly rollups kept for long ranges
+type AggregateRepositoryPort interface {
+	// DeviceVisible reports whether a user, or the organisation of an API key when
+	// organizationID is set, can read the readings of a device
+	DeviceVisible(ctx context.Context, numeroSerie string, userID int, organizationID *int64) (bool, error)
+	// AggregateReadings summarises the readings from desde until hasta in buckets of size
+	// counted from origin; buckets without readings are left out
+	AggregateReadings(ctx context.Context, numeroSerie, sensor string, origin, desde, hasta time.Time, size time.Duration) ([]*entities.AggregateBucket, error)
+	// AggregateRollups does the same from the hourly rollups; desde and hasta are whole hours
+	AggregateRollups(ctx context.Context, numeroSerie, sensor string, origin, desde, hasta time.Time, size time.Duration) ([]*entities.AggregateBucket, error)
+	// RollupProgress returns the end of the hours rolled up for a sensor type, zero if none
+	RollupProgress(ctx context.Context, sensor string) (time.Time, error)
+	// FirstReading returns the time of the oldest reading of a sensor type, zero if none
+	FirstReading(ctx context.Context, sensor string) (time.Time, error)
+	// RollupHours computes again the rollups of the whole hours from desde until hasta and
+	// moves the progress of the sensor type to hasta, in one transaction
+	RollupHours(ctx context.Context, sensor string, desde, hasta time.Time) error
+}
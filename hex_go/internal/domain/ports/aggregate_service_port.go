@@ -0,0 +1,13 @@
+package ports
+
+import (
+	"context"
+
+	"hex_go/internal/domain/entities"
+)
+
+type AggregateServicePort interface {
+	// AggregateReadings returns the readings of one sensor of a device summarised per
+	// bucket, with an empty bucket for every step without readings
+	AggregateReadings(ctx context.Context, userID int, query *entities.AggregateQuery) (*entities.AggregateSeries, error)
+}
@@ -0,0 +1,31 @@
+package ports
+
+import (
+	"context"
+	"time"
+
+	"hex_go/internal/domain/entities"
+)
+
+type AlertRepositoryPort interface {
+	CreateAlert(ctx context.Context, alert *entities.Alert) error
+	GetAlert(ctx context.Context, id int64) (*entities.Alert, error)
+	// FindOpenEpisode returns the latest unresolved alert of the device and type, or
+	// entities.ErrNotFound
+	FindOpenEpisode(ctx context.Context, numeroSerie, tipo string) (*entities.Alert, error)
+	// UpdateEpisode stores the value and counters of an alert episode. It returns
+	// entities.ErrConflict when the alert no longer has seen occurrences, because
+	// another activation was merged meanwhile, or was resolved.
+	UpdateEpisode(ctx context.Context, alert *entities.Alert, seen int) error
+	// GetUserAlert returns an alert of a device the user owns or sees through an
+	// organisation, with the role they act in on the device: entities.RoleOwner for their
+	// own devices, otherwise their strongest member role. It returns entities.ErrNotFound
+	// when the user cannot see the alert.
+	GetUserAlert(ctx context.Context, userID int, id int64) (*entities.Alert, string, error)
+	// AcknowledgeAlert marks an active alert as acknowledged by the user. It returns
+	// entities.ErrConflict when the alert is no longer active.
+	AcknowledgeAlert(ctx context.Context, id int64, userID int, at time.Time) error
+	// StreamUserAlerts calls fn with every alert the export query selects among the devices
+	// the user sees, oldest first, reading them one at a time. It stops at the first error.
+	StreamUserAlerts(ctx context.Context, userID int, query *entities.AlertExportQuery, fn func(*entities.Alert) error) error
+}
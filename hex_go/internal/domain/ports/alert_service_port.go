@@ -0,0 +1,15 @@
+package ports
+
+import (
+	"context"
+
+	"hex_go/internal/domain/entities"
+)
+
+type AlertServicePort interface {
+	RaiseAlert(ctx context.Context, alert *entities.Alert) error
+	AcknowledgeAlert(ctx context.Context, userID int, id int64) (*entities.Alert, error)
+	// ExportAlerts validates an export query and calls fn with every alert it selects,
+	// oldest first, without holding them all in memory
+	ExportAlerts(ctx context.Context, userID int, query *entities.AlertExportQuery, fn func(*entities.Alert) error) error
+}
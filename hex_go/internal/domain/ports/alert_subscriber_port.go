@@ -0,0 +1,13 @@
+package ports
+
+import (
+	"context"
+
+	"hex_go/internal/domain/entities"
+)
+
+// AlertSubscriberPort is told about every alert once it is stored. Subscribers handle
+// their own failures; they cannot undo the alert.
+type AlertSubscriberPort interface {
+	AlertRaised(ctx context.Context, alert *entities.Alert)
+}
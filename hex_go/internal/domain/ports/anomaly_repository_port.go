@@ -0,0 +1,15 @@
+package ports
+
+import (
+	"context"
+
+	"hex_go/internal/domain/entities"
+)
+
+// AnomalyRepositoryPort stores snapshots of the anomaly detector state
+type AnomalyRepositoryPort interface {
+	// GetAnomalyState returns the stored state of a sensor of a device or entities.ErrNotFound
+	GetAnomalyState(ctx context.Context, numeroSerie, sensor string) (*entities.AnomalyState, error)
+	// SaveAnomalyStates inserts or replaces the given states in one transaction
+	SaveAnomalyStates(ctx context.Context, states []*entities.AnomalyState) error
+}
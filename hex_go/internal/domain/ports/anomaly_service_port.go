@@ -0,0 +1,14 @@
+package ports
+
+import (
+	"context"
+
+	"hex_go/internal/domain/entities"
+)
+
+type AnomalyServicePort interface {
+	// ObserveReading follows a stored reading and raises an anomaly alert when it stands
+	// out from the usual values of its sensor. Failures are logged, never returned, so
+	// that detection cannot lose readings.
+	ObserveReading(ctx context.Context, reading *entities.SensorReading)
+}
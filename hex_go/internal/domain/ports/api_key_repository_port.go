@@ -0,0 +1,19 @@
+package ports
+
+import (
+	"context"
+	"time"
+
+	"hex_go/internal/domain/entities"
+)
+
+// APIKeyRepositoryPort stores the hashed API keys of organisations. Creating and
+// revoking a key also stores the given audit entry in the same transaction.
+type APIKeyRepositoryPort interface {
+	CreateAPIKey(ctx context.Context, key *entities.APIKey, audit *entities.AccessAuditEntry) error
+	GetAPIKeyByPrefix(ctx context.Context, prefijo string) (*entities.APIKey, error)
+	ListAPIKeys(ctx context.Context, orgID int64) ([]*entities.APIKey, error)
+	RevokeAPIKey(ctx context.Context, orgID int64, id int64, audit *entities.AccessAuditEntry) error
+	// TouchAPIKey sets the last use of a key unless it was already set after usedBefore
+	TouchAPIKey(ctx context.Context, id int64, at time.Time, usedBefore time.Time) error
+}
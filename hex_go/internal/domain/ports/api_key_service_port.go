@@ -0,0 +1,16 @@
+package ports
+
+import (
+	"context"
+
+	"hex_go/internal/domain/entities"
+)
+
+type APIKeyServicePort interface {
+	CreateAPIKey(ctx context.Context, userID int, orgID int64, req *entities.CreateAPIKeyRequest) (*entities.IssuedAPIKey, error)
+	ListAPIKeys(ctx context.Context, userID int, orgID int64) ([]*entities.APIKey, error)
+	RevokeAPIKey(ctx context.Context, userID int, orgID int64, keyID int64) error
+
+	// Authenticate returns the key matching a raw X-API-Key value, or entities.ErrUnauthorized
+	Authenticate(ctx context.Context, rawKey string) (*entities.APIKey, error)
+}
@@ -0,0 +1,19 @@
+package ports
+
+import (
+	"context"
+
+	"hex_go/internal/domain/entities"
+)
+
+// AuditRepositoryPort stores the append-only audit log. Entries are never updated or
+// deleted; AppendAuditEntry links each entry to the previous one.
+type AuditRepositoryPort interface {
+	// AppendAuditEntry sets the chain hashes and the ID of the entry and stores it
+	AppendAuditEntry(ctx context.Context, entry *entities.AuditEntry) error
+	ListAuditEntries(ctx context.Context, query *entities.AuditQuery) ([]*entities.AuditEntry, error)
+	// ListAuditChain returns up to limit entries after afterID in chain order
+	ListAuditChain(ctx context.Context, afterID int64, limit int) ([]*entities.AuditEntry, error)
+	// GetAuditChainHead returns the ID and hash of the last appended entry
+	GetAuditChainHead(ctx context.Context) (int64, string, error)
+}
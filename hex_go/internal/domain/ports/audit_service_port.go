@@ -0,0 +1,16 @@
+package ports
+
+import (
+	"context"
+
+	"hex_go/internal/domain/entities"
+)
+
+type AuditServicePort interface {
+	// Record appends a change made by the caller in ctx. before and after are stored as
+	// JSON and may be nil. Failures are logged and never undo the change.
+	Record(ctx context.Context, action, resource, resourceID string, before, after interface{})
+
+	ListEntries(ctx context.Context, query *entities.AuditQuery) ([]*entities.AuditEntry, error)
+	VerifyChain(ctx context.Context) (*entities.AuditVerification, error)
+}
@@ -0,0 +1,22 @@
+package ports
+
+import (
+	"context"
+
+	"hex_go/internal/domain/entities"
+)
+
+// CalibrationRepositoryPort stores the calibration of the gas sensors of each device
+type CalibrationRepositoryPort interface {
+	// GetCalibration returns the calibration of a sensor of a device or entities.ErrNotFound
+	GetCalibration(ctx context.Context, numeroSerie, sensor string) (*entities.SensorCalibration, error)
+	ListDeviceCalibrations(ctx context.Context, numeroSerie string) ([]*entities.SensorCalibration, error)
+	// CreateCalibration inserts a calibration; it returns entities.ErrConflict when the
+	// sensor already has one
+	CreateCalibration(ctx context.Context, calibration *entities.SensorCalibration) error
+	// UpdateCalibration stores a calibration provided it still has the given number of
+	// samples; otherwise another reading updated it first and it returns entities.ErrConflict
+	UpdateCalibration(ctx context.Context, calibration *entities.SensorCalibration, seen int) error
+	// SaveCalibration inserts or replaces a calibration
+	SaveCalibration(ctx context.Context, calibration *entities.SensorCalibration) error
+}
@@ -0,0 +1,16 @@
+package ports
+
+import (
+	"context"
+
+	"hex_go/internal/domain/entities"
+)
+
+type CalibrationServicePort interface {
+	// CalibrateReading sets the concentration of a gas reading once its sensor has a
+	// baseline, and reports whether the reading is an alarm
+	CalibrateReading(ctx context.Context, reading *entities.SensorReading) bool
+	ListCalibrations(ctx context.Context, userID int, numeroSerie string) ([]*entities.SensorCalibration, error)
+	UpdateCalibration(ctx context.Context, userID int, numeroSerie, sensor string, req *entities.CalibrationRequest) (*entities.SensorCalibration, error)
+	ResetCalibration(ctx context.Context, userID int, numeroSerie, sensor string) (*entities.SensorCalibration, error)
+}
@@ -0,0 +1,23 @@
+package ports
+
+import (
+	"context"
+	"time"
+
+	"hex_go/internal/domain/entities"
+)
+
+type DeviceRepositoryPort interface {
+	GetDevice(ctx context.Context, numeroSerie string) (*entities.Device, error)
+	ListUserDevices(ctx context.Context, userID int) ([]*entities.Device, error)
+	ClaimDevice(ctx context.Context, device *entities.Device) error
+	UpdateDevice(ctx context.Context, device *entities.Device) error
+	TransferDevice(ctx context.Context, numeroSerie string, fromUserID, toUserID int) error
+	ReleaseDevice(ctx context.Context, numeroSerie string, userID int) error
+	// SetDeviceToken replaces the token hash of a device the user owns. It returns
+	// entities.ErrConflict if the user no longer owns the device.
+	SetDeviceToken(ctx context.Context, numeroSerie string, userID int, tokenHash string) error
+	RecordHeartbeat(ctx context.Context, numeroSerie string, at time.Time, versionFirmware string) error
+	ListOfflineCandidates(ctx context.Context, lastSeenBefore time.Time) ([]*entities.Device, error)
+	MarkDeviceOffline(ctx context.Context, numeroSerie string, lastSeenBefore time.Time) (bool, error)
+}
@@ -0,0 +1,21 @@
+package ports
+
+import (
+	"context"
+
+	"hex_go/internal/domain/entities"
+)
+
+type DeviceServicePort interface {
+	ClaimDevice(ctx context.Context, userID int, req *entities.ClaimDeviceRequest) (*entities.ClaimedDevice, error)
+	GetDevice(ctx context.Context, userID int, numeroSerie string) (*entities.Device, error)
+	ListDevices(ctx context.Context, userID int) ([]*entities.Device, error)
+	UpdateDevice(ctx context.Context, userID int, numeroSerie string, req *entities.UpdateDeviceRequest) (*entities.Device, error)
+	TransferDevice(ctx context.Context, userID int, numeroSerie string, req *entities.TransferDeviceRequest) error
+	DeleteDevice(ctx context.Context, userID int, numeroSerie string) error
+	RecordHeartbeat(ctx context.Context, numeroSerie string, req *entities.HeartbeatRequest) error
+	RotateDeviceToken(ctx context.Context, userID int, numeroSerie string) (*entities.ClaimedDevice, error)
+	// AuthenticateDevice returns entities.ErrUnauthorized unless token is the current token
+	// of the board
+	AuthenticateDevice(ctx context.Context, numeroSerie, token string) error
+}
@@ -0,0 +1,33 @@
+package ports
+
+import (
+	"context"
+	"time"
+
+	"hex_go/internal/domain/entities"
+)
+
+// EscalationRepositoryPort stores escalation policies and the escalations running for alerts
+type EscalationRepositoryPort interface {
+	// CreatePolicy inserts a policy with its steps and sets its ID
+	CreatePolicy(ctx context.Context, policy *entities.EscalationPolicy) error
+	GetPolicy(ctx context.Context, id int64) (*entities.EscalationPolicy, error)
+	ListUserPolicies(ctx context.Context, userID int) ([]*entities.EscalationPolicy, error)
+	// UpdatePolicy stores a policy and replaces its steps
+	UpdatePolicy(ctx context.Context, policy *entities.EscalationPolicy) error
+	DeletePolicy(ctx context.Context, id int64) error
+	// ListPoliciesForDevice returns the active policies of the device owner registered
+	// for the device itself or for a location containing it
+	ListPoliciesForDevice(ctx context.Context, numeroSerie string) ([]*entities.EscalationPolicy, error)
+
+	// CreateEscalation starts an escalation; an alert runs each policy at most once
+	CreateEscalation(ctx context.Context, escalation *entities.Escalation) error
+	ListAlertEscalations(ctx context.Context, alertID int64) ([]*entities.Escalation, error)
+	// ListDueEscalations returns the pending escalations whose next step is due
+	ListDueEscalations(ctx context.Context, now time.Time, limit int) ([]*entities.Escalation, error)
+	// AdvanceEscalation moves a due escalation from its current step to the next one, or
+	// finishes it when next is nil. It reports false if another scheduler moved it first.
+	AdvanceEscalation(ctx context.Context, escalation *entities.Escalation, nextStep int, next *time.Time) (bool, error)
+	// StopEscalations stops the pending escalations of an alert
+	StopEscalations(ctx context.Context, alertID int64, at time.Time) error
+}
@@ -0,0 +1,16 @@
+package ports
+
+import (
+	"context"
+
+	"hex_go/internal/domain/entities"
+)
+
+type EscalationServicePort interface {
+	CreatePolicy(ctx context.Context, userID int, req *entities.EscalationPolicyRequest) (*entities.EscalationPolicy, error)
+	ListPolicies(ctx context.Context, userID int) ([]*entities.EscalationPolicy, error)
+	GetPolicy(ctx context.Context, userID int, id int64) (*entities.EscalationPolicy, error)
+	UpdatePolicy(ctx context.Context, userID int, id int64, req *entities.EscalationPolicyRequest) (*entities.EscalationPolicy, error)
+	DeletePolicy(ctx context.Context, userID int, id int64) error
+	ListAlertEscalations(ctx context.Context, userID int, alertID int64) ([]*entities.Escalation, error)
+}
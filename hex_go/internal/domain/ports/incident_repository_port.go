@@ -0,0 +1,37 @@
+package ports
+
+import (
+	"context"
+	"time"
+
+	"hex_go/internal/domain/entities"
+)
+
+// IncidentRepositoryPort stores incidents, their timeline and the alerts they group
+type IncidentRepositoryPort interface {
+	// FindCorrelating returns the incident that takes the alerts of the correlation key,
+	// or entities.ErrNotFound
+	FindCorrelating(ctx context.Context, key string) (*entities.Incident, error)
+	// CreateIncident opens an incident for the correlation key with its first alert and
+	// sets its ID. It returns entities.ErrConflict when an incident already takes the
+	// alerts of the key.
+	CreateIncident(ctx context.Context, incident *entities.Incident, key string, alert *entities.Alert) error
+	// AddAlert adds an alert to the incident, which must still take the alerts of the key,
+	// and stores its new severity and latest time; otherwise it returns entities.ErrConflict
+	AddAlert(ctx context.Context, incident *entities.Incident, key string, alert *entities.Alert, severityRaised bool) error
+	// ReleaseKey stops the incident from taking more alerts of the key
+	ReleaseKey(ctx context.Context, id int64, key string) error
+
+	// GetUserIncident returns an incident with one of the user's devices, with its alerts
+	// and timeline and the strongest role the user holds on the devices of its alerts, or
+	// entities.ErrNotFound
+	GetUserIncident(ctx context.Context, userID int, id int64) (*entities.Incident, string, error)
+	// ListUserIncidents returns the latest incidents with one of the user's devices
+	ListUserIncidents(ctx context.Context, userID int, filter *entities.IncidentFilter) ([]*entities.Incident, error)
+	// AcknowledgeIncident marks an open incident and its active alerts as acknowledged by
+	// the user. It returns entities.ErrConflict when the incident is no longer open.
+	AcknowledgeIncident(ctx context.Context, id int64, userID int, at time.Time) error
+	// ResolveIncident closes an incident and resolves its alerts. It returns
+	// entities.ErrConflict when the incident is already resolved.
+	ResolveIncident(ctx context.Context, id int64, userID int, at time.Time) error
+}
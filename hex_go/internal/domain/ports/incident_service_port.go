@@ -0,0 +1,14 @@
+package ports
+
+import (
+	"context"
+
+	"hex_go/internal/domain/entities"
+)
+
+type IncidentServicePort interface {
+	ListIncidents(ctx context.Context, userID int, filter *entities.IncidentFilter) ([]*entities.Incident, error)
+	GetIncident(ctx context.Context, userID int, id int64) (*entities.Incident, error)
+	AcknowledgeIncident(ctx context.Context, userID int, id int64) (*entities.Incident, error)
+	ResolveIncident(ctx context.Context, userID int, id int64) (*entities.Incident, error)
+}
@@ -0,0 +1,14 @@
+package ports
+
+import (
+	"context"
+
+	"hex_go/internal/domain/entities"
+)
+
+// IncidentSubscriberPort is told when an incident opens and when an alert more severe
+// than the rest joins it, with that alert. Other alerts joining an incident are only
+// added to its timeline.
+type IncidentSubscriberPort interface {
+	IncidentRaised(ctx context.Context, incident *entities.Incident, alert *entities.Alert)
+}
@@ -0,0 +1,20 @@
+package ports
+
+import (
+	"context"
+
+	"hex_go/internal/domain/entities"
+)
+
+type LocationRepositoryPort interface {
+	CreateLocation(ctx context.Context, location *entities.Location) error
+	GetLocation(ctx context.Context, id int64) (*entities.Location, error)
+	// ListLocationPath returns a location and its ancestors, from the site down
+	ListLocationPath(ctx context.Context, id int64) ([]*entities.Location, error)
+	ListUserLocations(ctx context.Context, userID int) ([]*entities.Location, error)
+	UpdateLocation(ctx context.Context, location *entities.Location) error
+	DeleteLocation(ctx context.Context, id int64) error
+	CountLocationContents(ctx context.Context, id int64) (children int, devices int, err error)
+	AssignDevice(ctx context.Context, numeroSerie string, locationID *int64) error
+	ListDeviceStatuses(ctx context.Context, location *entities.Location) ([]*entities.DeviceStatus, error)
+}
@@ -0,0 +1,17 @@
+package ports
+
+import (
+	"context"
+
+	"hex_go/internal/domain/entities"
+)
+
+type LocationServicePort interface {
+	CreateLocation(ctx context.Context, userID int, req *entities.CreateLocationRequest) (*entities.Location, error)
+	GetLocation(ctx context.Context, userID int, id int64) (*entities.Location, error)
+	ListLocations(ctx context.Context, userID int) ([]*entities.Location, error)
+	UpdateLocation(ctx context.Context, userID int, id int64, req *entities.UpdateLocationRequest) (*entities.Location, error)
+	DeleteLocation(ctx context.Context, userID int, id int64) error
+	AssignDevice(ctx context.Context, userID int, numeroSerie string, req *entities.AssignLocationRequest) error
+	GetLocationStatus(ctx context.Context, userID int, id int64) (*entities.LocationStatus, error)
+}
@@ -0,0 +1,23 @@
+package ports
+
+import (
+	"context"
+	"time"
+
+	"hex_go/internal/domain/entities"
+)
+
+// MaintenanceRepositoryPort stores maintenance windows
+type MaintenanceRepositoryPort interface {
+	// CreateWindow inserts a window and sets its ID
+	CreateWindow(ctx context.Context, window *entities.MaintenanceWindow) error
+	GetWindow(ctx context.Context, id int64) (*entities.MaintenanceWindow, error)
+	// ListUserWindows returns the windows of a user, only those ending after now when
+	// filter.Current is set
+	ListUserWindows(ctx context.Context, userID int, filter *entities.MaintenanceFilter, now time.Time) ([]*entities.MaintenanceWindow, error)
+	UpdateWindow(ctx context.Context, window *entities.MaintenanceWindow) error
+	DeleteWindow(ctx context.Context, id int64) error
+	// FindActiveWindow returns a window of the device owner covering the device, or a
+	// location containing it, at the given time, or entities.ErrNotFound
+	FindActiveWindow(ctx context.Context, numeroSerie string, at time.Time) (*entities.MaintenanceWindow, error)
+}
@@ -0,0 +1,16 @@
+package ports
+
+import (
+	"context"
+
+	"hex_go/internal/domain/entities"
+)
+
+type MaintenanceServicePort interface {
+	CreateWindow(ctx context.Context, userID int, req *entities.MaintenanceWindowRequest) (*entities.MaintenanceWindow, error)
+	ListWindows(ctx context.Context, userID int, filter *entities.MaintenanceFilter) ([]*entities.MaintenanceWindow, error)
+	GetWindow(ctx context.Context, userID int, id int64) (*entities.MaintenanceWindow, error)
+	UpdateWindow(ctx context.Context, userID int, id int64, req *entities.MaintenanceWindowRequest) (*entities.MaintenanceWindow, error)
+	EndWindow(ctx context.Context, userID int, id int64) (*entities.MaintenanceWindow, error)
+	DeleteWindow(ctx context.Context, userID int, id int64) error
+}
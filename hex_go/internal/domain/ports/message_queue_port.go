@@ -4,5 +4,6 @@ import "hex_go/internal/domain/entities"
 
 type MessageQueuePort interface {
     PublishSensorData(data *entities.SensorDataRequest) error
+    PublishAlert(alert *entities.Alert) error
     Close() error
 }
\ No newline at end of file
@@ -0,0 +1,37 @@
+package ports
+
+import (
+	"context"
+	"time"
+
+	"hex_go/internal/domain/entities"
+)
+
+type NotificationRepositoryPort interface {
+	CreateRecipient(ctx context.Context, recipient *entities.NotificationRecipient) error
+	ListUserRecipients(ctx context.Context, userID int) ([]*entities.NotificationRecipient, error)
+	GetRecipient(ctx context.Context, id int64) (*entities.NotificationRecipient, error)
+	DeleteRecipient(ctx context.Context, id int64) error
+	// ListDeviceRecipients returns the recipients of every user who can see the device:
+	// its owner and the members of organisations it is shared with
+	ListDeviceRecipients(ctx context.Context, numeroSerie string) ([]*entities.NotificationRecipient, error)
+	// GetChannelSettings returns the channels chosen by a user, or nil when they never chose
+	GetChannelSettings(ctx context.Context, userID int) (*entities.NotificationChannelSettings, error)
+	SetChannelSettings(ctx context.Context, userID int, settings *entities.NotificationChannelSettings) error
+	RecordDelivery(ctx context.Context, delivery *entities.NotificationDelivery) error
+	ListUserDeliveries(ctx context.Context, userID int, limit int) ([]*entities.NotificationDelivery, error)
+
+	// GetPreferences returns the preferences of a user, or nil when they never set them
+	GetPreferences(ctx context.Context, userID int) (*entities.NotificationPreferences, error)
+	SetPreferences(ctx context.Context, userID int, prefs *entities.NotificationPreferences) error
+
+	// QueueDigestItem holds back the notification of an alert to a recipient until sendAfter
+	QueueDigestItem(ctx context.Context, recipientID, alertID int64, sendAfter time.Time) error
+	// ListDueDigestRecipients returns the recipients with held back notifications due
+	ListDueDigestRecipients(ctx context.Context, now time.Time, limit int) ([]int64, error)
+	// ClaimDigest takes the due notifications of a recipient under batch until leaseUntil,
+	// so no other worker sends them, and returns their alerts, oldest first
+	ClaimDigest(ctx context.Context, recipientID int64, now time.Time, batch string, leaseUntil time.Time) ([]*entities.Alert, error)
+	// DeleteDigest removes the notifications of a batch once sent
+	DeleteDigest(ctx context.Context, batch string) error
+}
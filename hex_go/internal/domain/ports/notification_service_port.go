@@ -0,0 +1,18 @@
+package ports
+
+import (
+	"context"
+
+	"hex_go/internal/domain/entities"
+)
+
+type NotificationServicePort interface {
+	CreateRecipient(ctx context.Context, userID int, req *entities.CreateRecipientRequest) (*entities.NotificationRecipient, error)
+	ListRecipients(ctx context.Context, userID int) ([]*entities.NotificationRecipient, error)
+	DeleteRecipient(ctx context.Context, userID int, id int64) error
+	GetChannelSettings(ctx context.Context, userID int) (*entities.NotificationChannelSettings, error)
+	UpdateChannelSettings(ctx context.Context, userID int, settings *entities.NotificationChannelSettings) (*entities.NotificationChannelSettings, error)
+	ListDeliveries(ctx context.Context, userID int) ([]*entities.NotificationDelivery, error)
+	GetSettings(ctx context.Context, userID int) (*entities.NotificationSettings, error)
+	UpdateSettings(ctx context.Context, userID int, settings *entities.NotificationSettings) (*entities.NotificationSettings, error)
+}
@@ -0,0 +1,16 @@
+package ports
+
+import (
+	"context"
+
+	"hex_go/internal/domain/entities"
+)
+
+// NotifierPort sends notifications over one channel, such as email
+type NotifierPort interface {
+	// Channel returns the channel recipients are registered for
+	Channel() string
+	Notify(ctx context.Context, notification *entities.Notification) error
+	// NotifyDigest sends the notifications held back for one recipient as one message
+	NotifyDigest(ctx context.Context, digest *entities.NotificationDigest) error
+}
@@ -0,0 +1,35 @@
+package ports
+
+import (
+	"context"
+
+	"hex_go/internal/domain/entities"
+)
+
+// OrganizationRepositoryPort stores organisations and their members, invitations and
+// shared devices. Every method that changes who can access what also stores the given
+// audit entry in the same transaction.
+type OrganizationRepositoryPort interface {
+	CreateOrganization(ctx context.Context, org *entities.Organization, owner *entities.Membership, audit *entities.AccessAuditEntry) error
+	GetOrganization(ctx context.Context, id int64) (*entities.Organization, error)
+	ListUserOrganizations(ctx context.Context, userID int) ([]*entities.Organization, error)
+
+	GetMembership(ctx context.Context, orgID int64, userID int) (*entities.Membership, error)
+	ListMembers(ctx context.Context, orgID int64) ([]*entities.Membership, error)
+	CountMembersWithRole(ctx context.Context, orgID int64, role string) (int, error)
+	UpdateMemberRole(ctx context.Context, orgID int64, userID int, role string, audit *entities.AccessAuditEntry) error
+	RemoveMember(ctx context.Context, orgID int64, userID int, audit *entities.AccessAuditEntry) error
+
+	CreateInvitation(ctx context.Context, invitation *entities.Invitation, audit *entities.AccessAuditEntry) error
+	GetInvitation(ctx context.Context, id int64) (*entities.Invitation, error)
+	ListPendingInvitations(ctx context.Context, userID int) ([]*entities.Invitation, error)
+	ListOrganizationInvitations(ctx context.Context, orgID int64) ([]*entities.Invitation, error)
+	AcceptInvitation(ctx context.Context, invitation *entities.Invitation, member *entities.Membership, audit *entities.AccessAuditEntry) error
+	CloseInvitation(ctx context.Context, id int64, state string, audit *entities.AccessAuditEntry) error
+
+	ShareDevice(ctx context.Context, shared *entities.SharedDevice, audit *entities.AccessAuditEntry) error
+	UnshareDevice(ctx context.Context, orgID int64, numeroSerie string, audit *entities.AccessAuditEntry) error
+	ListSharedDevices(ctx context.Context, orgID int64) ([]*entities.SharedDevice, error)
+
+	ListAccessAudit(ctx context.Context, orgID int64, limit int) ([]*entities.AccessAuditEntry, error)
+}
@@ -0,0 +1,29 @@
+package ports
+
+import (
+	"context"
+
+	"hex_go/internal/domain/entities"
+)
+
+type OrganizationServicePort interface {
+	CreateOrganization(ctx context.Context, userID int, req *entities.CreateOrganizationRequest) (*entities.Organization, error)
+	ListOrganizations(ctx context.Context, userID int) ([]*entities.Organization, error)
+
+	ListMembers(ctx context.Context, userID int, orgID int64) ([]*entities.Membership, error)
+	UpdateMember(ctx context.Context, userID int, orgID int64, memberID int, req *entities.UpdateMemberRequest) error
+	RemoveMember(ctx context.Context, userID int, orgID int64, memberID int) error
+
+	InviteMember(ctx context.Context, userID int, orgID int64, req *entities.InviteMemberRequest) (*entities.Invitation, error)
+	ListOrganizationInvitations(ctx context.Context, userID int, orgID int64) ([]*entities.Invitation, error)
+	RevokeInvitation(ctx context.Context, userID int, orgID int64, invitationID int64) error
+	ListMyInvitations(ctx context.Context, userID int) ([]*entities.Invitation, error)
+	AcceptInvitation(ctx context.Context, userID int, invitationID int64) error
+	DeclineInvitation(ctx context.Context, userID int, invitationID int64) error
+
+	ShareDevice(ctx context.Context, userID int, orgID int64, req *entities.ShareDeviceRequest) error
+	UnshareDevice(ctx context.Context, userID int, orgID int64, numeroSerie string) error
+	ListSharedDevices(ctx context.Context, userID int, orgID int64) ([]*entities.SharedDevice, error)
+
+	ListAccessAudit(ctx context.Context, userID int, orgID int64) ([]*entities.AccessAuditEntry, error)
+}
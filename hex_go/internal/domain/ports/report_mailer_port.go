@@ -0,0 +1,13 @@
+package ports
+
+import (
+	"context"
+
+	"hex_go/internal/domain/entities"
+)
+
+// ReportMailerPort emails the monthly reports
+type ReportMailerPort interface {
+	// SendReport emails a report to a recipient, in their language, with the PDF attached
+	SendReport(ctx context.Context, recipient *entities.NotificationRecipient, report *entities.SiteReport, pdf []byte) error
+}
@@ -0,0 +1,9 @@
+package ports
+
+import "hex_go/internal/domain/entities"
+
+// ReportRendererPort turns a site report into a document
+type ReportRendererPort interface {
+	// RenderReport returns the report as a PDF with its text in lang
+	RenderReport(report *entities.SiteReport, lang string) ([]byte, error)
+}
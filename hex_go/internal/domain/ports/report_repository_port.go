@@ -0,0 +1,26 @@
+package ports
+
+import (
+	"context"
+	"time"
+
+	"hex_go/internal/domain/entities"
+)
+
+// ReportRepositoryPort reads what the monthly site reports are built from. Every query
+// covers the devices placed anywhere below the site whose path is given.
+type ReportRepositoryPort interface {
+	ListSites(ctx context.Context) ([]*entities.Location, error)
+	CountAlarms(ctx context.Context, sitePath string, desde, hasta time.Time) ([]*entities.ReportAlarmCount, error)
+	// ListResponseTimes returns how long each acknowledged alert took to be acknowledged
+	// and how many alerts were not acknowledged
+	ListResponseTimes(ctx context.Context, sitePath string, desde, hasta time.Time) ([]time.Duration, int, error)
+	ListDeviceUptime(ctx context.Context, sitePath string, desde, hasta time.Time) ([]*entities.ReportDeviceUptime, error)
+	ListNoisyDevices(ctx context.Context, sitePath string, desde, hasta time.Time, limit int) ([]*entities.ReportNoisyDevice, error)
+
+	// ClaimReport records that the report of a site and month is being delivered; it
+	// reports false when it already was, by this or another instance
+	ClaimReport(ctx context.Context, siteID int64, month string, at time.Time) (bool, error)
+	// ReleaseReport forgets a claim so that delivery is tried again
+	ReleaseReport(ctx context.Context, siteID int64, month string) error
+}
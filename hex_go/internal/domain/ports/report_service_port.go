@@ -0,0 +1,10 @@
+package ports
+
+import (
+	"context"
+)
+
+type ReportServicePort interface {
+	// GetReportPDF renders the report of a month of a site of the user in lang
+	GetReportPDF(ctx context.Context, userID int, siteID int64, month, lang string) ([]byte, error)
+}
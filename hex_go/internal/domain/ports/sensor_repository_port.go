@@ -1,6 +1,7 @@
 package ports
 
 import (
+    "context"
     "database/sql"
     "hex_go/internal/domain/entities"
 )
@@ -10,6 +11,7 @@ type SensorRepositoryPort interface {
     CreateMQ2(sensor *entities.SensorMQ2) error
     CreateMQ135(sensor *entities.SensorMQ135) error
     CreateDHT22(sensor *entities.SensorDHT22) error
-    GetUserAlerts(userID int) (map[string]interface{}, error)
+    GetDeviceTimezone(ctx context.Context, numeroSerie string) (string, error)
+    GetUserAlerts(ctx context.Context, userID int, filter *entities.AlertFilter) (map[string]interface{}, error)
     DB() *sql.DB
 }
\ No newline at end of file
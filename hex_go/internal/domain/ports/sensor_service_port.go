@@ -1,8 +1,11 @@
 package ports
 
-import "hex_go/internal/domain/entities"
+import (
+    "context"
+    "hex_go/internal/domain/entities"
+)
 
 type SensorServicePort interface {
-    ProcessSensorData(data *entities.SensorDataRequest) error
-    GetUserAlerts(userID int) (map[string]interface{}, error)
+    ProcessSensorData(ctx context.Context, data *entities.SensorDataRequest) error
+    GetUserAlerts(ctx context.Context, userID int, filter *entities.AlertFilter) (map[string]interface{}, error)
 }
\ No newline at end of file
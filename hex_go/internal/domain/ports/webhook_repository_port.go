@@ -0,0 +1,34 @@
+package ports
+
+import (
+	"context"
+	"time"
+
+	"hex_go/internal/domain/entities"
+)
+
+// WebhookRepositoryPort stores webhooks and the log of their deliveries
+type WebhookRepositoryPort interface {
+	CreateWebhook(ctx context.Context, webhook *entities.Webhook) error
+	GetWebhook(ctx context.Context, id int64) (*entities.Webhook, error)
+	ListUserWebhooks(ctx context.Context, userID int) ([]*entities.Webhook, error)
+	UpdateWebhook(ctx context.Context, webhook *entities.Webhook) error
+	DeleteWebhook(ctx context.Context, id int64) error
+	// ListWebhooksForDevice returns the active webhooks of the device owner registered
+	// for the device itself or for a location containing it
+	ListWebhooksForDevice(ctx context.Context, numeroSerie string) ([]*entities.Webhook, error)
+	// RecordWebhookResult resets the failure count after a success, or counts a failure
+	// and disables the webhook once it reaches disableAfter. It reports whether this
+	// call disabled the webhook.
+	RecordWebhookResult(ctx context.Context, id int64, success bool, disableAfter int) (bool, error)
+
+	CreateDelivery(ctx context.Context, delivery *entities.WebhookDelivery) error
+	ListDeliveries(ctx context.Context, webhookID int64, limit int) ([]*entities.WebhookDelivery, error)
+	ListDueDeliveries(ctx context.Context, now time.Time, limit int) ([]*entities.WebhookDelivery, error)
+	// ClaimDelivery moves the next attempt of a due delivery to leaseUntil so no other
+	// dispatcher picks it up. It reports false if the delivery was claimed meanwhile.
+	ClaimDelivery(ctx context.Context, delivery *entities.WebhookDelivery, leaseUntil time.Time) (bool, error)
+	UpdateDelivery(ctx context.Context, delivery *entities.WebhookDelivery) error
+	// FailPendingDeliveries gives up the pending deliveries of a webhook
+	FailPendingDeliveries(ctx context.Context, webhookID int64, reason string) error
+}
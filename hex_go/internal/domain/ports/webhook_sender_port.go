@@ -0,0 +1,8 @@
+package ports
+
+import "context"
+
+// WebhookSenderPort posts a delivery body to a webhook URL and returns the HTTP status
+type WebhookSenderPort interface {
+	Send(ctx context.Context, url string, headers map[string]string, body []byte) (int, error)
+}
@@ -0,0 +1,17 @@
+package ports
+
+import (
+	"context"
+
+	"hex_go/internal/domain/entities"
+)
+
+type WebhookServicePort interface {
+	CreateWebhook(ctx context.Context, userID int, req *entities.CreateWebhookRequest) (*entities.CreatedWebhook, error)
+	ListWebhooks(ctx context.Context, userID int) ([]*entities.Webhook, error)
+	GetWebhook(ctx context.Context, userID int, id int64) (*entities.Webhook, error)
+	UpdateWebhook(ctx context.Context, userID int, id int64, req *entities.UpdateWebhookRequest) (*entities.Webhook, error)
+	DeleteWebhook(ctx context.Context, userID int, id int64) error
+	SendTestEvent(ctx context.Context, userID int, id int64) (*entities.WebhookDelivery, error)
+	ListDeliveries(ctx context.Context, userID int, id int64) ([]*entities.WebhookDelivery, error)
+}
@@ -1,6 +1,7 @@
 package repositories
 
 import (
+	"context"
 	"database/sql"
 	"hex_go/internal/domain/entities"
 )
@@ -12,8 +13,11 @@ type SensorRepository interface {
 	CreateMQ135(sensor *entities.SensorMQ135) error
 	CreateDHT22(sensor *entities.SensorDHT22) error
 
-	// GetUserAlerts retrieves all alerts for a user based on their ID
-	GetUserAlerts(userID int) (map[string]interface{}, error)
+	// GetDeviceTimezone returns the IANA timezone configured for a device, or an empty string
+	GetDeviceTimezone(ctx context.Context, numeroSerie string) (string, error)
+
+	// GetUserAlerts retrieves the alerts for a user, optionally narrowed to a location
+	GetUserAlerts(ctx context.Context, userID int, filter *entities.AlertFilter) (map[string]interface{}, error)
 
 	DB() *sql.DB
 }
\ No newline at end of file
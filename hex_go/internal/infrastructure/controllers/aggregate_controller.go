@@ -0,0 +1,63 @@
+package controllers
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/gorilla/mux"
+
+	"hex_go/internal/domain/entities"
+	"hex_go/internal/domain/ports"
+)
+
+type AggregateController struct {
+	aggregateService ports.AggregateServicePort
+}
+
+func NewAggregateController(aggregateService ports.AggregateServicePort) *AggregateController {
+	return &AggregateController{
+		aggregateService: aggregateService,
+	}
+}
+
+// GetAggregates handles summarising the readings of one sensor of a device per bucket.
+// from and to take the same formats as reading timestamps; API keys read the devices
+// shared with their organisation.
+func (c *AggregateController) GetAggregates(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	params := r.URL.Query()
+	query := &entities.AggregateQuery{
+		NumeroSerie: vars["numeroSerie"],
+		Sensor:      vars["type"],
+		Bucket:      params.Get("bucket"),
+	}
+
+	var userID int
+	if principal := entities.PrincipalFromContext(r.Context()); principal.IsAPIKey() {
+		query.OrganizationID = &principal.IDOrganizacion
+	} else {
+		var ok bool
+		if userID, ok = requestUserID(w, r); !ok {
+			return
+		}
+	}
+
+	now := time.Now()
+	var err error
+	if query.Desde, err = entities.ParseOptionalTimestamp("from", params.Get("from"), time.UTC, now); err != nil {
+		writeError(w, r, err)
+		return
+	}
+	if query.Hasta, err = entities.ParseOptionalTimestamp("to", params.Get("to"), time.UTC, now); err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	series, err := c.aggregateService.AggregateReadings(r.Context(), userID, query)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, series)
+}
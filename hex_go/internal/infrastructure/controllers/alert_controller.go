@@ -0,0 +1,138 @@
+package controllers
+
+import (
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"hex_go/internal/domain/entities"
+	"hex_go/internal/domain/ports"
+	"hex_go/pkg/export"
+)
+
+type AlertController struct {
+	alertService ports.AlertServicePort
+}
+
+func NewAlertController(alertService ports.AlertServicePort) *AlertController {
+	return &AlertController{
+		alertService: alertService,
+	}
+}
+
+// AcknowledgeAlert handles a user taking charge of an active alert, which stops its escalation
+func (c *AlertController) AcknowledgeAlert(w http.ResponseWriter, r *http.Request) {
+	userID, ok := requestUserID(w, r)
+	if !ok {
+		return
+	}
+	id, ok := pathID(w, r, "id")
+	if !ok {
+		return
+	}
+
+	alert, err := c.alertService.AcknowledgeAlert(r.Context(), userID, id)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, alert)
+}
+
+// ExportAlerts handles downloading the alert history as csv, ndjson or xlsx. from and to
+// take the same formats as reading timestamps and location_id narrows it like the alerts
+// list. Headers are in lang, or else the language of the Accept-Language header.
+func (c *AlertController) ExportAlerts(w http.ResponseWriter, r *http.Request) {
+	params := r.URL.Query()
+	query := &entities.AlertExportQuery{
+		Formato: params.Get("format"),
+		Idioma:  params.Get("lang"),
+	}
+	if query.Idioma == "" {
+		query.Idioma = acceptedLanguage(r)
+	}
+
+	var userID int
+	if principal := entities.PrincipalFromContext(r.Context()); principal.IsAPIKey() {
+		query.OrganizationID = &principal.IDOrganizacion
+	} else {
+		var ok bool
+		if userID, ok = requestUserID(w, r); !ok {
+			return
+		}
+	}
+
+	now := time.Now()
+	var err error
+	if query.Desde, err = entities.ParseOptionalTimestamp("from", params.Get("from"), time.UTC, now); err != nil {
+		writeError(w, r, err)
+		return
+	}
+	if query.Hasta, err = entities.ParseOptionalTimestamp("to", params.Get("to"), time.UTC, now); err != nil {
+		writeError(w, r, err)
+		return
+	}
+	if locationID := params.Get("location_id"); locationID != "" {
+		id, err := strconv.ParseInt(locationID, 10, 64)
+		if err != nil {
+			writeBadRequest(w, r, "Invalid location_id parameter", "location_id")
+			return
+		}
+		query.LocationID = &id
+	}
+
+	// The response starts with the first alert, so errors before it are still reported as usual
+	var writer export.AlertWriter
+	start := func() error {
+		aw, err := export.NewAlertWriter(query.Formato, query.Idioma, w)
+		if err != nil {
+			return err
+		}
+		writer = aw
+		w.Header().Set("Content-Type", export.ContentType(query.Formato))
+		w.Header().Set("Content-Disposition", `attachment; filename="`+export.FileName(query.Formato, query.Idioma, now)+`"`)
+		w.WriteHeader(http.StatusOK)
+		return writer.WriteHeader()
+	}
+
+	err = c.alertService.ExportAlerts(r.Context(), userID, query, func(alert *entities.Alert) error {
+		if writer == nil {
+			if err := start(); err != nil {
+				return err
+			}
+		}
+		return writer.WriteAlert(alert)
+	})
+	if err == nil && writer == nil {
+		err = start()
+	}
+	if err != nil {
+		if writer == nil {
+			writeError(w, r, err)
+			return
+		}
+		// Too late for an error response; the client gets a truncated file
+		log.Printf("Error exporting alerts: %v", err)
+		return
+	}
+
+	if err := writer.Close(); err != nil {
+		log.Printf("Error finishing alert export: %v", err)
+	}
+}
+
+// acceptedLanguage returns the first supported language of the Accept-Language header,
+// or "" when there is none
+func acceptedLanguage(r *http.Request) string {
+	for _, tag := range strings.Split(r.Header.Get("Accept-Language"), ",") {
+		tag = strings.TrimSpace(strings.SplitN(tag, ";", 2)[0])
+		lang := strings.ToLower(strings.SplitN(tag, "-", 2)[0])
+		if entities.IsNotificationLanguage(lang) {
+			return lang
+		}
+	}
+	return ""
+}
@@ -0,0 +1,76 @@
+package controllers
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gorilla/mux"
+	"hex_go/internal/application/services"
+	"hex_go/internal/domain/entities"
+	"hex_go/internal/domain/ports"
+)
+
+// discardAudit drops every audit entry
+type discardAudit struct {
+	ports.AuditServicePort
+}
+
+func (discardAudit) Record(ctx context.Context, action, resource, resourceID string, before, after interface{}) {
+}
+
+// roleAlerts holds one active alert that the user sees with a fixed role
+type roleAlerts struct {
+	ports.AlertRepositoryPort
+	role         string
+	acknowledged bool
+}
+
+func (r *roleAlerts) GetUserAlert(ctx context.Context, userID int, id int64) (*entities.Alert, string, error) {
+	return &entities.Alert{ID: id, NumeroSerie: "ESP32-0001", Estado: entities.AlertStateActive}, r.role, nil
+}
+
+func (r *roleAlerts) AcknowledgeAlert(ctx context.Context, id int64, userID int, at time.Time) error {
+	r.acknowledged = true
+	return nil
+}
+
+// asUser serves a request as the given user
+func asUser(router http.Handler, method, target string, userID int) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(method, target, nil)
+	req = req.WithContext(entities.WithPrincipal(req.Context(), &entities.Principal{Kind: entities.PrincipalUser, UserID: userID}))
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, req)
+	return rec
+}
+
+func TestAcknowledgeAlertRequiresRespondingRole(t *testing.T) {
+	tests := []struct {
+		role       string
+		wantStatus int
+	}{
+		{entities.RoleOwner, http.StatusOK},
+		{entities.RoleAdmin, http.StatusOK},
+		{entities.RoleResponder, http.StatusOK},
+		{entities.RoleViewer, http.StatusForbidden},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.role, func(t *testing.T) {
+			repo := &roleAlerts{role: tt.role}
+			service := services.NewAlertService(repo, nil, nil, discardAudit{}, entities.AlertDebounce{})
+			router := mux.NewRouter()
+			router.HandleFunc("/api/alerts/{id}/acknowledge", NewAlertController(service).AcknowledgeAlert).Methods("POST")
+
+			rec := asUser(router, "POST", "/api/alerts/3/acknowledge", 5)
+			if rec.Code != tt.wantStatus {
+				t.Fatalf("status = %d, want %d: %s", rec.Code, tt.wantStatus, rec.Body)
+			}
+			if repo.acknowledged != (tt.wantStatus == http.StatusOK) {
+				t.Errorf("acknowledged = %v", repo.acknowledged)
+			}
+		})
+	}
+}
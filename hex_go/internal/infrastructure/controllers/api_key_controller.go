@@ -0,0 +1,74 @@
+package controllers
+
+import (
+	"net/http"
+
+	"hex_go/internal/domain/entities"
+	"hex_go/internal/domain/ports"
+)
+
+type APIKeyController struct {
+	apiKeyService ports.APIKeyServicePort
+}
+
+func NewAPIKeyController(apiKeyService ports.APIKeyServicePort) *APIKeyController {
+	return &APIKeyController{
+		apiKeyService: apiKeyService,
+	}
+}
+
+// CreateAPIKey handles issuing an API key. The key is only shown in this response.
+func (c *APIKeyController) CreateAPIKey(w http.ResponseWriter, r *http.Request) {
+	userID, orgID, ok := organizationRequest(w, r)
+	if !ok {
+		return
+	}
+
+	var req entities.CreateAPIKeyRequest
+	if !decodeJSON(w, r, &req) {
+		return
+	}
+
+	key, err := c.apiKeyService.CreateAPIKey(r.Context(), userID, orgID, &req)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	writeJSON(w, http.StatusCreated, key)
+}
+
+// ListAPIKeys handles listing the API keys of an organisation
+func (c *APIKeyController) ListAPIKeys(w http.ResponseWriter, r *http.Request) {
+	userID, orgID, ok := organizationRequest(w, r)
+	if !ok {
+		return
+	}
+
+	keys, err := c.apiKeyService.ListAPIKeys(r.Context(), userID, orgID)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, keys)
+}
+
+// RevokeAPIKey handles revoking an API key
+func (c *APIKeyController) RevokeAPIKey(w http.ResponseWriter, r *http.Request) {
+	userID, orgID, ok := organizationRequest(w, r)
+	if !ok {
+		return
+	}
+	keyID, ok := pathID(w, r, "keyId")
+	if !ok {
+		return
+	}
+
+	if err := c.apiKeyService.RevokeAPIKey(r.Context(), userID, orgID, keyID); err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
@@ -0,0 +1,85 @@
+package controllers
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"hex_go/internal/domain/entities"
+	"hex_go/internal/domain/ports"
+)
+
+type AuditController struct {
+	auditService ports.AuditServicePort
+}
+
+func NewAuditController(auditService ports.AuditServicePort) *AuditController {
+	return &AuditController{
+		auditService: auditService,
+	}
+}
+
+// ListEntries handles querying the audit log. Every filter is optional; from and to take
+// the same formats as reading timestamps and before_id pages to older entries.
+func (c *AuditController) ListEntries(w http.ResponseWriter, r *http.Request) {
+	params := r.URL.Query()
+	query := &entities.AuditQuery{
+		TipoActor: params.Get("actor_type"),
+		IDActor:   params.Get("actor_id"),
+		Accion:    params.Get("action"),
+		Recurso:   params.Get("resource"),
+		IDRecurso: params.Get("resource_id"),
+	}
+
+	now := time.Now()
+	var err error
+	if query.Desde, err = entities.ParseOptionalTimestamp("from", params.Get("from"), time.UTC, now); err != nil {
+		writeError(w, r, err)
+		return
+	}
+	if query.Hasta, err = entities.ParseOptionalTimestamp("to", params.Get("to"), time.UTC, now); err != nil {
+		writeError(w, r, err)
+		return
+	}
+	if query.Limit, err = intParam(params.Get("limit")); err != nil {
+		writeBadRequest(w, r, "Invalid limit parameter", "limit")
+		return
+	}
+	if query.BeforeID, err = int64Param(params.Get("before_id")); err != nil {
+		writeBadRequest(w, r, "Invalid before_id parameter", "before_id")
+		return
+	}
+
+	entries, err := c.auditService.ListEntries(r.Context(), query)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, entries)
+}
+
+// VerifyChain handles checking that no audit entry was changed or removed
+func (c *AuditController) VerifyChain(w http.ResponseWriter, r *http.Request) {
+	result, err := c.auditService.VerifyChain(r.Context())
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, result)
+}
+
+func intParam(value string) (int, error) {
+	if value == "" {
+		return 0, nil
+	}
+	return strconv.Atoi(value)
+}
+
+func int64Param(value string) (int64, error) {
+	if value == "" {
+		return 0, nil
+	}
+	return strconv.ParseInt(value, 10, 64)
+}
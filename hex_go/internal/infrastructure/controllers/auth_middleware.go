@@ -0,0 +1,120 @@
+package controllers
+
+import (
+	"log"
+	"net/http"
+	"strconv"
+
+	"github.com/gorilla/mux"
+	"hex_go/internal/application/authorization"
+	"hex_go/internal/application/validation"
+	"hex_go/internal/domain/entities"
+	"hex_go/internal/domain/ports"
+)
+
+// Headers identifying the caller
+const (
+	userIDHeader       = "X-User-ID"
+	deviceSerialHeader = "X-Device-Serial"
+	deviceTokenHeader  = "X-Device-Token"
+	apiKeyHeader       = "X-API-Key"
+)
+
+// AuthMiddleware identifies the caller of every request and checks the permission the
+// policy requires for the matched mux route. Routes missing from the policy are denied.
+type AuthMiddleware struct {
+	policy  *authorization.Policy
+	apiKeys ports.APIKeyServicePort
+	devices ports.DeviceServicePort
+	// legacyUserQuery accepts the user_id query parameter when X-User-ID is missing
+	legacyUserQuery bool
+}
+
+func NewAuthMiddleware(policy *authorization.Policy, apiKeys ports.APIKeyServicePort, devices ports.DeviceServicePort, legacyUserQuery bool) *AuthMiddleware {
+	return &AuthMiddleware{
+		policy:          policy,
+		apiKeys:         apiKeys,
+		devices:         devices,
+		legacyUserQuery: legacyUserQuery,
+	}
+}
+
+// Middleware is meant to be installed with router.Use so the current route is known
+func (m *AuthMiddleware) Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		principal, ok := m.authenticate(w, r)
+		if !ok {
+			return
+		}
+
+		routeName := ""
+		if route := mux.CurrentRoute(r); route != nil {
+			routeName = route.GetName()
+		}
+
+		permission, known := m.policy.RoutePermission(routeName)
+		if !known {
+			log.Printf("Denying %s %s: route %q has no permission in the policy", r.Method, r.URL.Path, routeName)
+			writeProblem(w, r, http.StatusForbidden, entities.ErrForbidden.Error(), nil)
+			return
+		}
+		if !m.policy.Allows(principal, permission) {
+			if principal.Kind == entities.PrincipalAnonymous {
+				writeProblem(w, r, http.StatusUnauthorized, entities.ErrUnauthorized.Error(), nil)
+				return
+			}
+			writeProblem(w, r, http.StatusForbidden, "Missing permission "+permission, nil)
+			return
+		}
+
+		next.ServeHTTP(w, r.WithContext(entities.WithPrincipal(r.Context(), principal)))
+	})
+}
+
+// authenticate builds the principal of a request from the API key, device or user headers.
+// Devices prove their serial number with the token issued when they were claimed. The
+// user_id query parameter is only accepted when legacyUserQuery is set, for clients
+// written before the header existed.
+func (m *AuthMiddleware) authenticate(w http.ResponseWriter, r *http.Request) (*entities.Principal, bool) {
+	principal := &entities.Principal{Kind: entities.PrincipalAnonymous}
+
+	if rawKey := r.Header.Get(apiKeyHeader); rawKey != "" {
+		key, err := m.apiKeys.Authenticate(r.Context(), rawKey)
+		if err != nil {
+			writeError(w, r, err)
+			return nil, false
+		}
+		principal = &entities.Principal{
+			Kind:           entities.PrincipalAPIKey,
+			IDOrganizacion: key.IDOrganizacion,
+			IDAPIKey:       key.ID,
+			Scopes:         key.Scopes,
+		}
+	} else if serial := r.Header.Get(deviceSerialHeader); serial != "" {
+		if err := validation.ValidateSerialNumber(deviceSerialHeader, serial); err != nil {
+			writeError(w, r, err)
+			return nil, false
+		}
+		if err := m.devices.AuthenticateDevice(r.Context(), serial, r.Header.Get(deviceTokenHeader)); err != nil {
+			writeError(w, r, err)
+			return nil, false
+		}
+		principal = &entities.Principal{Kind: entities.PrincipalDevice, NumeroSerie: serial}
+	} else {
+		userIDStr := r.Header.Get(userIDHeader)
+		if userIDStr == "" && m.legacyUserQuery {
+			userIDStr = r.URL.Query().Get("user_id")
+		}
+		if userIDStr != "" {
+			userID, err := strconv.Atoi(userIDStr)
+			if err != nil || userID <= 0 {
+				writeBadRequest(w, r, "Invalid user ID", "user_id")
+				return nil, false
+			}
+			principal = &entities.Principal{Kind: entities.PrincipalUser, UserID: userID}
+		}
+	}
+
+	principal.Roles = m.policy.RolesFor(principal)
+	return principal, true
+}
@@ -0,0 +1,164 @@
+package controllers
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gorilla/mux"
+	"hex_go/internal/application/authorization"
+	"hex_go/internal/domain/entities"
+	"hex_go/internal/domain/ports"
+)
+
+// fakeAPIKeys authenticates the raw keys it holds
+type fakeAPIKeys struct {
+	ports.APIKeyServicePort
+	keys map[string]*entities.APIKey
+}
+
+func (f *fakeAPIKeys) Authenticate(ctx context.Context, rawKey string) (*entities.APIKey, error) {
+	key, ok := f.keys[rawKey]
+	if !ok {
+		return nil, entities.ErrUnauthorized
+	}
+	return key, nil
+}
+
+// fakeDevices authenticates the boards whose tokens it holds
+type fakeDevices struct {
+	ports.DeviceServicePort
+	tokens map[string]string
+}
+
+func (f *fakeDevices) AuthenticateDevice(ctx context.Context, numeroSerie, token string) error {
+	if want, ok := f.tokens[numeroSerie]; !ok || token != want {
+		return entities.ErrUnauthorized
+	}
+	return nil
+}
+
+// newAuthRouter returns a router behind the auth middleware whose handlers reply with the
+// kind of the caller
+func newAuthRouter(t *testing.T, legacyUserQuery bool) *mux.Router {
+	t.Helper()
+
+	policy := &authorization.Policy{
+		DefaultUserRoles: []string{"resident"},
+		UserRoles:        map[string][]string{"7": {"admin"}},
+		Roles: map[string][]string{
+			"anonymous": {"docs:read"},
+			"device":    {"readings:create"},
+			"resident":  {"alerts:read"},
+			"admin":     {authorization.AnyPermission},
+		},
+		Routes: map[string]string{
+			"docs.spec":      "docs:read",
+			"sensors.create": "readings:create",
+			"alerts.list":    "alerts:read",
+			"audit.list":     "audit:read",
+		},
+	}
+	apiKeys := &fakeAPIKeys{keys: map[string]*entities.APIKey{
+		"sf_alerts": {ID: 1, IDOrganizacion: 2, Scopes: []string{"alerts:read"}},
+	}}
+
+	devices := &fakeDevices{tokens: map[string]string{"ESP32-0001": "sfd_secret"}}
+
+	reply := func(w http.ResponseWriter, r *http.Request) {
+		principal := entities.PrincipalFromContext(r.Context())
+		w.Write([]byte(principal.Kind))
+	}
+
+	router := mux.NewRouter()
+	router.Use(NewAuthMiddleware(policy, apiKeys, devices, legacyUserQuery).Middleware)
+	router.HandleFunc("/api/openapi.json", reply).Methods("GET").Name("docs.spec")
+	router.HandleFunc("/api/sensors", reply).Methods("POST").Name("sensors.create")
+	router.HandleFunc("/api/alerts", reply).Methods("GET").Name("alerts.list")
+	router.HandleFunc("/api/audit", reply).Methods("GET").Name("audit.list")
+	router.HandleFunc("/api/unlisted", reply).Methods("GET").Name("unlisted")
+	router.HandleFunc("/api/unnamed", reply).Methods("GET")
+	return router
+}
+
+func TestAuthMiddleware(t *testing.T) {
+	router := newAuthRouter(t, false)
+
+	tests := []struct {
+		name       string
+		method     string
+		target     string
+		headers    map[string]string
+		wantStatus int
+		wantKind   string
+	}{
+		{"unknown route", "GET", "/api/unlisted", map[string]string{"X-User-ID": "7"}, http.StatusForbidden, ""},
+		{"unnamed route", "GET", "/api/unnamed", map[string]string{"X-User-ID": "7"}, http.StatusForbidden, ""},
+
+		{"anonymous reads docs", "GET", "/api/openapi.json", nil, http.StatusOK, entities.PrincipalAnonymous},
+		{"anonymous reads alerts", "GET", "/api/alerts", nil, http.StatusUnauthorized, ""},
+		{"anonymous posts readings", "POST", "/api/sensors", nil, http.StatusUnauthorized, ""},
+
+		{"device posts readings", "POST", "/api/sensors", map[string]string{"X-Device-Serial": "ESP32-0001", "X-Device-Token": "sfd_secret"}, http.StatusOK, entities.PrincipalDevice},
+		{"device reads alerts", "GET", "/api/alerts", map[string]string{"X-Device-Serial": "ESP32-0001", "X-Device-Token": "sfd_secret"}, http.StatusForbidden, ""},
+		{"device without token", "POST", "/api/sensors", map[string]string{"X-Device-Serial": "ESP32-0001"}, http.StatusUnauthorized, ""},
+		{"device with wrong token", "POST", "/api/sensors", map[string]string{"X-Device-Serial": "ESP32-0001", "X-Device-Token": "sfd_other"}, http.StatusUnauthorized, ""},
+		{"token of another device", "POST", "/api/sensors", map[string]string{"X-Device-Serial": "ESP32-0002", "X-Device-Token": "sfd_secret"}, http.StatusUnauthorized, ""},
+		{"invalid device serial", "POST", "/api/sensors", map[string]string{"X-Device-Serial": "a b"}, http.StatusUnprocessableEntity, ""},
+
+		{"user reads alerts", "GET", "/api/alerts", map[string]string{"X-User-ID": "3"}, http.StatusOK, entities.PrincipalUser},
+		{"user from query", "GET", "/api/alerts?user_id=3", nil, http.StatusUnauthorized, ""},
+		{"user reads audit", "GET", "/api/audit", map[string]string{"X-User-ID": "3"}, http.StatusForbidden, ""},
+		{"admin reads audit", "GET", "/api/audit", map[string]string{"X-User-ID": "7"}, http.StatusOK, entities.PrincipalUser},
+		{"invalid user ID", "GET", "/api/alerts", map[string]string{"X-User-ID": "-1"}, http.StatusBadRequest, ""},
+
+		{"api key with the scope", "GET", "/api/alerts", map[string]string{"X-API-Key": "sf_alerts"}, http.StatusOK, entities.PrincipalAPIKey},
+		{"api key without the scope", "GET", "/api/audit", map[string]string{"X-API-Key": "sf_alerts"}, http.StatusForbidden, ""},
+		{"unknown api key", "GET", "/api/alerts", map[string]string{"X-API-Key": "sf_unknown"}, http.StatusUnauthorized, ""},
+		{"api key before user", "GET", "/api/alerts", map[string]string{"X-API-Key": "sf_alerts", "X-User-ID": "7"}, http.StatusOK, entities.PrincipalAPIKey},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(tt.method, tt.target, nil)
+			for name, value := range tt.headers {
+				req.Header.Set(name, value)
+			}
+			rec := httptest.NewRecorder()
+
+			router.ServeHTTP(rec, req)
+
+			if rec.Code != tt.wantStatus {
+				t.Fatalf("status = %d, want %d: %s", rec.Code, tt.wantStatus, rec.Body.String())
+			}
+			if tt.wantKind != "" && rec.Body.String() != tt.wantKind {
+				t.Errorf("caller = %q, want %q", rec.Body.String(), tt.wantKind)
+			}
+		})
+	}
+}
+
+func TestAuthMiddlewareLegacyUserQuery(t *testing.T) {
+	tests := []struct {
+		name            string
+		legacyUserQuery bool
+		wantStatus      int
+	}{
+		{"off by default", false, http.StatusUnauthorized},
+		{"on", true, http.StatusOK},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			router := newAuthRouter(t, tt.legacyUserQuery)
+			rec := httptest.NewRecorder()
+
+			router.ServeHTTP(rec, httptest.NewRequest("GET", "/api/alerts?user_id=3", nil))
+
+			if rec.Code != tt.wantStatus {
+				t.Fatalf("status = %d, want %d: %s", rec.Code, tt.wantStatus, rec.Body.String())
+			}
+		})
+	}
+}
@@ -0,0 +1,75 @@
+package controllers
+
+import (
+	"net/http"
+
+	"github.com/gorilla/mux"
+
+	"hex_go/internal/domain/entities"
+	"hex_go/internal/domain/ports"
+)
+
+type CalibrationController struct {
+	calibrationService ports.CalibrationServicePort
+}
+
+func NewCalibrationController(calibrationService ports.CalibrationServicePort) *CalibrationController {
+	return &CalibrationController{
+		calibrationService: calibrationService,
+	}
+}
+
+// ListCalibrations handles listing the gas sensor calibrations of a device
+func (c *CalibrationController) ListCalibrations(w http.ResponseWriter, r *http.Request) {
+	userID, ok := requestUserID(w, r)
+	if !ok {
+		return
+	}
+
+	calibrations, err := c.calibrationService.ListCalibrations(r.Context(), userID, mux.Vars(r)["numeroSerie"])
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, calibrations)
+}
+
+// UpdateCalibration handles setting the offset and gain of a gas sensor
+func (c *CalibrationController) UpdateCalibration(w http.ResponseWriter, r *http.Request) {
+	userID, ok := requestUserID(w, r)
+	if !ok {
+		return
+	}
+
+	var req entities.CalibrationRequest
+	if !decodeJSON(w, r, &req) {
+		return
+	}
+
+	vars := mux.Vars(r)
+	calibration, err := c.calibrationService.UpdateCalibration(r.Context(), userID, vars["numeroSerie"], vars["sensor"], &req)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, calibration)
+}
+
+// ResetCalibration handles learning the baseline of a gas sensor again
+func (c *CalibrationController) ResetCalibration(w http.ResponseWriter, r *http.Request) {
+	userID, ok := requestUserID(w, r)
+	if !ok {
+		return
+	}
+
+	vars := mux.Vars(r)
+	calibration, err := c.calibrationService.ResetCalibration(r.Context(), userID, vars["numeroSerie"], vars["sensor"])
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, calibration)
+}
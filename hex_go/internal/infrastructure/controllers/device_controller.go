@@ -0,0 +1,163 @@
+package controllers
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+
+	"github.com/gorilla/mux"
+	"hex_go/internal/domain/entities"
+	"hex_go/internal/domain/ports"
+)
+
+type DeviceController struct {
+	deviceService ports.DeviceServicePort
+}
+
+func NewDeviceController(deviceService ports.DeviceServicePort) *DeviceController {
+	return &DeviceController{
+		deviceService: deviceService,
+	}
+}
+
+// ListDevices handles listing the devices of a user
+func (c *DeviceController) ListDevices(w http.ResponseWriter, r *http.Request) {
+	userID, ok := requestUserID(w, r)
+	if !ok {
+		return
+	}
+
+	devices, err := c.deviceService.ListDevices(r.Context(), userID)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, devices)
+}
+
+// ClaimDevice handles registering a board with its claim code
+func (c *DeviceController) ClaimDevice(w http.ResponseWriter, r *http.Request) {
+	userID, ok := requestUserID(w, r)
+	if !ok {
+		return
+	}
+
+	var req entities.ClaimDeviceRequest
+	if !decodeJSON(w, r, &req) {
+		return
+	}
+
+	device, err := c.deviceService.ClaimDevice(r.Context(), userID, &req)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	writeJSON(w, http.StatusCreated, device)
+}
+
+// GetDevice handles retrieving a single device
+func (c *DeviceController) GetDevice(w http.ResponseWriter, r *http.Request) {
+	userID, ok := requestUserID(w, r)
+	if !ok {
+		return
+	}
+
+	device, err := c.deviceService.GetDevice(r.Context(), userID, mux.Vars(r)["numeroSerie"])
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, device)
+}
+
+// UpdateDevice handles renaming a device or changing its details
+func (c *DeviceController) UpdateDevice(w http.ResponseWriter, r *http.Request) {
+	userID, ok := requestUserID(w, r)
+	if !ok {
+		return
+	}
+
+	var req entities.UpdateDeviceRequest
+	if !decodeJSON(w, r, &req) {
+		return
+	}
+
+	device, err := c.deviceService.UpdateDevice(r.Context(), userID, mux.Vars(r)["numeroSerie"], &req)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, device)
+}
+
+// TransferDevice handles handing a device over to another user
+func (c *DeviceController) TransferDevice(w http.ResponseWriter, r *http.Request) {
+	userID, ok := requestUserID(w, r)
+	if !ok {
+		return
+	}
+
+	var req entities.TransferDeviceRequest
+	if !decodeJSON(w, r, &req) {
+		return
+	}
+
+	if err := c.deviceService.TransferDevice(r.Context(), userID, mux.Vars(r)["numeroSerie"], &req); err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// DeleteDevice handles unregistering a device
+func (c *DeviceController) DeleteDevice(w http.ResponseWriter, r *http.Request) {
+	userID, ok := requestUserID(w, r)
+	if !ok {
+		return
+	}
+
+	if err := c.deviceService.DeleteDevice(r.Context(), userID, mux.Vars(r)["numeroSerie"]); err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// RotateDeviceToken handles issuing a new token for a board, such as one claimed before
+// boards had tokens or whose token leaked
+func (c *DeviceController) RotateDeviceToken(w http.ResponseWriter, r *http.Request) {
+	userID, ok := requestUserID(w, r)
+	if !ok {
+		return
+	}
+
+	device, err := c.deviceService.RotateDeviceToken(r.Context(), userID, mux.Vars(r)["numeroSerie"])
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, device)
+}
+
+// RecordHeartbeat handles the keep-alive sent by a board. The body is optional.
+func (c *DeviceController) RecordHeartbeat(w http.ResponseWriter, r *http.Request) {
+	var req entities.HeartbeatRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil && err != io.EOF {
+		writeBadRequest(w, r, "Invalid request body", "")
+		return
+	}
+
+	if err := c.deviceService.RecordHeartbeat(r.Context(), mux.Vars(r)["numeroSerie"], &req); err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
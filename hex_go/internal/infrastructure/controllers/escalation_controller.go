@@ -0,0 +1,136 @@
+package controllers
+
+import (
+	"net/http"
+
+	"hex_go/internal/domain/entities"
+	"hex_go/internal/domain/ports"
+)
+
+type EscalationController struct {
+	escalationService ports.EscalationServicePort
+}
+
+func NewEscalationController(escalationService ports.EscalationServicePort) *EscalationController {
+	return &EscalationController{
+		escalationService: escalationService,
+	}
+}
+
+// ListPolicies handles listing the escalation policies of a user
+func (c *EscalationController) ListPolicies(w http.ResponseWriter, r *http.Request) {
+	userID, ok := requestUserID(w, r)
+	if !ok {
+		return
+	}
+
+	policies, err := c.escalationService.ListPolicies(r.Context(), userID)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, policies)
+}
+
+// CreatePolicy handles registering an escalation policy
+func (c *EscalationController) CreatePolicy(w http.ResponseWriter, r *http.Request) {
+	userID, ok := requestUserID(w, r)
+	if !ok {
+		return
+	}
+
+	var req entities.EscalationPolicyRequest
+	if !decodeJSON(w, r, &req) {
+		return
+	}
+
+	policy, err := c.escalationService.CreatePolicy(r.Context(), userID, &req)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	writeJSON(w, http.StatusCreated, policy)
+}
+
+// GetPolicy handles retrieving an escalation policy
+func (c *EscalationController) GetPolicy(w http.ResponseWriter, r *http.Request) {
+	userID, id, ok := idRequest(w, r)
+	if !ok {
+		return
+	}
+
+	policy, err := c.escalationService.GetPolicy(r.Context(), userID, id)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, policy)
+}
+
+// UpdatePolicy handles replacing an escalation policy
+func (c *EscalationController) UpdatePolicy(w http.ResponseWriter, r *http.Request) {
+	userID, id, ok := idRequest(w, r)
+	if !ok {
+		return
+	}
+
+	var req entities.EscalationPolicyRequest
+	if !decodeJSON(w, r, &req) {
+		return
+	}
+
+	policy, err := c.escalationService.UpdatePolicy(r.Context(), userID, id, &req)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, policy)
+}
+
+// DeletePolicy handles removing an escalation policy
+func (c *EscalationController) DeletePolicy(w http.ResponseWriter, r *http.Request) {
+	userID, id, ok := idRequest(w, r)
+	if !ok {
+		return
+	}
+
+	if err := c.escalationService.DeletePolicy(r.Context(), userID, id); err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// ListAlertEscalations handles listing the escalations running or run for an alert
+func (c *EscalationController) ListAlertEscalations(w http.ResponseWriter, r *http.Request) {
+	userID, id, ok := idRequest(w, r)
+	if !ok {
+		return
+	}
+
+	escalations, err := c.escalationService.ListAlertEscalations(r.Context(), userID, id)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, escalations)
+}
+
+// idRequest reads the calling user and the ID of the route
+func idRequest(w http.ResponseWriter, r *http.Request) (int, int64, bool) {
+	userID, ok := requestUserID(w, r)
+	if !ok {
+		return 0, 0, false
+	}
+	id, ok := pathID(w, r, "id")
+	if !ok {
+		return 0, 0, false
+	}
+	return userID, id, true
+}
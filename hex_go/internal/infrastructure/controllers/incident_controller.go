@@ -0,0 +1,87 @@
+package controllers
+
+import (
+	"context"
+	"net/http"
+
+	"hex_go/internal/domain/entities"
+	"hex_go/internal/domain/ports"
+)
+
+type IncidentController struct {
+	incidentService ports.IncidentServicePort
+}
+
+func NewIncidentController(incidentService ports.IncidentServicePort) *IncidentController {
+	return &IncidentController{
+		incidentService: incidentService,
+	}
+}
+
+// ListIncidents handles listing the latest incidents of a user's devices, optionally
+// narrowed by state and location
+func (c *IncidentController) ListIncidents(w http.ResponseWriter, r *http.Request) {
+	userID, ok := requestUserID(w, r)
+	if !ok {
+		return
+	}
+
+	params := r.URL.Query()
+	filter := &entities.IncidentFilter{Estado: params.Get("estado")}
+	if value := params.Get("location_id"); value != "" {
+		locationID, err := int64Param(value)
+		if err != nil {
+			writeBadRequest(w, r, "Invalid location_id parameter", "location_id")
+			return
+		}
+		filter.LocationID = &locationID
+	}
+	var err error
+	if filter.Limit, err = intParam(params.Get("limit")); err != nil {
+		writeBadRequest(w, r, "Invalid limit parameter", "limit")
+		return
+	}
+
+	incidents, err := c.incidentService.ListIncidents(r.Context(), userID, filter)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, incidents)
+}
+
+// GetIncident handles retrieving an incident with its alerts and timeline
+func (c *IncidentController) GetIncident(w http.ResponseWriter, r *http.Request) {
+	c.handle(w, r, c.incidentService.GetIncident)
+}
+
+// AcknowledgeIncident handles a user taking charge of an open incident
+func (c *IncidentController) AcknowledgeIncident(w http.ResponseWriter, r *http.Request) {
+	c.handle(w, r, c.incidentService.AcknowledgeIncident)
+}
+
+// ResolveIncident handles a user closing an incident
+func (c *IncidentController) ResolveIncident(w http.ResponseWriter, r *http.Request) {
+	c.handle(w, r, c.incidentService.ResolveIncident)
+}
+
+// handle runs an operation on the incident of the request path and writes it back
+func (c *IncidentController) handle(w http.ResponseWriter, r *http.Request, op func(ctx context.Context, userID int, id int64) (*entities.Incident, error)) {
+	userID, ok := requestUserID(w, r)
+	if !ok {
+		return
+	}
+	id, ok := pathID(w, r, "id")
+	if !ok {
+		return
+	}
+
+	incident, err := op(r.Context(), userID, id)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, incident)
+}
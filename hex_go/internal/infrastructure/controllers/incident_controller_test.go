@@ -0,0 +1,67 @@
+package controllers
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/gorilla/mux"
+	"hex_go/internal/application/services"
+	"hex_go/internal/domain/entities"
+	"hex_go/internal/domain/ports"
+)
+
+// roleIncidents holds one open incident that the user sees with a fixed role
+type roleIncidents struct {
+	ports.IncidentRepositoryPort
+	role    string
+	changed bool
+}
+
+func (r *roleIncidents) GetUserIncident(ctx context.Context, userID int, id int64) (*entities.Incident, string, error) {
+	return &entities.Incident{ID: id, Estado: entities.IncidentOpen}, r.role, nil
+}
+
+func (r *roleIncidents) AcknowledgeIncident(ctx context.Context, id int64, userID int, at time.Time) error {
+	r.changed = true
+	return nil
+}
+
+func (r *roleIncidents) ResolveIncident(ctx context.Context, id int64, userID int, at time.Time) error {
+	r.changed = true
+	return nil
+}
+
+func TestIncidentChangesRequireRespondingRole(t *testing.T) {
+	tests := []struct {
+		name       string
+		action     string
+		role       string
+		wantStatus int
+	}{
+		{"responder acknowledges", "acknowledge", entities.RoleResponder, http.StatusOK},
+		{"viewer acknowledges", "acknowledge", entities.RoleViewer, http.StatusForbidden},
+		{"owner resolves", "resolve", entities.RoleOwner, http.StatusOK},
+		{"admin resolves", "resolve", entities.RoleAdmin, http.StatusOK},
+		{"viewer resolves", "resolve", entities.RoleViewer, http.StatusForbidden},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := &roleIncidents{role: tt.role}
+			controller := NewIncidentController(services.NewIncidentService(repo, nil, discardAudit{}, time.Hour))
+			router := mux.NewRouter()
+			router.HandleFunc("/api/incidents/{id}/acknowledge", controller.AcknowledgeIncident).Methods("POST")
+			router.HandleFunc("/api/incidents/{id}/resolve", controller.ResolveIncident).Methods("POST")
+
+			rec := asUser(router, "POST", "/api/incidents/4/"+tt.action, 5)
+			if rec.Code != tt.wantStatus {
+				t.Fatalf("status = %d, want %d: %s", rec.Code, tt.wantStatus, rec.Body)
+			}
+			if repo.changed != (tt.wantStatus == http.StatusOK) {
+				t.Errorf("changed = %v", repo.changed)
+			}
+		})
+	}
+}
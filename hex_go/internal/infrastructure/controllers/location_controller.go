@@ -0,0 +1,160 @@
+package controllers
+
+import (
+	"net/http"
+
+	"github.com/gorilla/mux"
+	"hex_go/internal/domain/entities"
+	"hex_go/internal/domain/ports"
+)
+
+type LocationController struct {
+	locationService ports.LocationServicePort
+}
+
+func NewLocationController(locationService ports.LocationServicePort) *LocationController {
+	return &LocationController{
+		locationService: locationService,
+	}
+}
+
+// ListLocations handles listing the location hierarchy of a user
+func (c *LocationController) ListLocations(w http.ResponseWriter, r *http.Request) {
+	userID, ok := requestUserID(w, r)
+	if !ok {
+		return
+	}
+
+	locations, err := c.locationService.ListLocations(r.Context(), userID)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, locations)
+}
+
+// CreateLocation handles adding a site, building, floor or room
+func (c *LocationController) CreateLocation(w http.ResponseWriter, r *http.Request) {
+	userID, ok := requestUserID(w, r)
+	if !ok {
+		return
+	}
+
+	var req entities.CreateLocationRequest
+	if !decodeJSON(w, r, &req) {
+		return
+	}
+
+	location, err := c.locationService.CreateLocation(r.Context(), userID, &req)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	writeJSON(w, http.StatusCreated, location)
+}
+
+// GetLocation handles retrieving a single location
+func (c *LocationController) GetLocation(w http.ResponseWriter, r *http.Request) {
+	userID, ok := requestUserID(w, r)
+	if !ok {
+		return
+	}
+	id, ok := pathID(w, r, "id")
+	if !ok {
+		return
+	}
+
+	location, err := c.locationService.GetLocation(r.Context(), userID, id)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, location)
+}
+
+// UpdateLocation handles renaming a location
+func (c *LocationController) UpdateLocation(w http.ResponseWriter, r *http.Request) {
+	userID, ok := requestUserID(w, r)
+	if !ok {
+		return
+	}
+	id, ok := pathID(w, r, "id")
+	if !ok {
+		return
+	}
+
+	var req entities.UpdateLocationRequest
+	if !decodeJSON(w, r, &req) {
+		return
+	}
+
+	location, err := c.locationService.UpdateLocation(r.Context(), userID, id, &req)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, location)
+}
+
+// DeleteLocation handles removing an empty location
+func (c *LocationController) DeleteLocation(w http.ResponseWriter, r *http.Request) {
+	userID, ok := requestUserID(w, r)
+	if !ok {
+		return
+	}
+	id, ok := pathID(w, r, "id")
+	if !ok {
+		return
+	}
+
+	if err := c.locationService.DeleteLocation(r.Context(), userID, id); err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// GetLocationStatus handles retrieving the current alert status of a location, such as a whole site
+func (c *LocationController) GetLocationStatus(w http.ResponseWriter, r *http.Request) {
+	userID, ok := requestUserID(w, r)
+	if !ok {
+		return
+	}
+	id, ok := pathID(w, r, "id")
+	if !ok {
+		return
+	}
+
+	status, err := c.locationService.GetLocationStatus(r.Context(), userID, id)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, status)
+}
+
+// AssignDevice handles placing a device in a location
+func (c *LocationController) AssignDevice(w http.ResponseWriter, r *http.Request) {
+	userID, ok := requestUserID(w, r)
+	if !ok {
+		return
+	}
+
+	var req entities.AssignLocationRequest
+	if !decodeJSON(w, r, &req) {
+		return
+	}
+
+	if err := c.locationService.AssignDevice(r.Context(), userID, mux.Vars(r)["numeroSerie"], &req); err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
@@ -0,0 +1,125 @@
+package controllers
+
+import (
+	"net/http"
+
+	"hex_go/internal/domain/entities"
+	"hex_go/internal/domain/ports"
+)
+
+type MaintenanceController struct {
+	maintenanceService ports.MaintenanceServicePort
+}
+
+func NewMaintenanceController(maintenanceService ports.MaintenanceServicePort) *MaintenanceController {
+	return &MaintenanceController{
+		maintenanceService: maintenanceService,
+	}
+}
+
+// ListWindows handles listing the maintenance windows of a user; current=true leaves out
+// those already over
+func (c *MaintenanceController) ListWindows(w http.ResponseWriter, r *http.Request) {
+	userID, ok := requestUserID(w, r)
+	if !ok {
+		return
+	}
+
+	filter := &entities.MaintenanceFilter{Current: r.URL.Query().Get("current") == "true"}
+	windows, err := c.maintenanceService.ListWindows(r.Context(), userID, filter)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, windows)
+}
+
+// CreateWindow handles scheduling a maintenance window or starting one right away
+func (c *MaintenanceController) CreateWindow(w http.ResponseWriter, r *http.Request) {
+	userID, ok := requestUserID(w, r)
+	if !ok {
+		return
+	}
+
+	var req entities.MaintenanceWindowRequest
+	if !decodeJSON(w, r, &req) {
+		return
+	}
+
+	window, err := c.maintenanceService.CreateWindow(r.Context(), userID, &req)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	writeJSON(w, http.StatusCreated, window)
+}
+
+// GetWindow handles retrieving a maintenance window
+func (c *MaintenanceController) GetWindow(w http.ResponseWriter, r *http.Request) {
+	userID, id, ok := idRequest(w, r)
+	if !ok {
+		return
+	}
+
+	window, err := c.maintenanceService.GetWindow(r.Context(), userID, id)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, window)
+}
+
+// UpdateWindow handles replacing a maintenance window that is not over yet
+func (c *MaintenanceController) UpdateWindow(w http.ResponseWriter, r *http.Request) {
+	userID, id, ok := idRequest(w, r)
+	if !ok {
+		return
+	}
+
+	var req entities.MaintenanceWindowRequest
+	if !decodeJSON(w, r, &req) {
+		return
+	}
+
+	window, err := c.maintenanceService.UpdateWindow(r.Context(), userID, id, &req)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, window)
+}
+
+// EndWindow handles ending an active maintenance window early
+func (c *MaintenanceController) EndWindow(w http.ResponseWriter, r *http.Request) {
+	userID, id, ok := idRequest(w, r)
+	if !ok {
+		return
+	}
+
+	window, err := c.maintenanceService.EndWindow(r.Context(), userID, id)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, window)
+}
+
+// DeleteWindow handles removing a maintenance window
+func (c *MaintenanceController) DeleteWindow(w http.ResponseWriter, r *http.Request) {
+	userID, id, ok := idRequest(w, r)
+	if !ok {
+		return
+	}
+
+	if err := c.maintenanceService.DeleteWindow(r.Context(), userID, id); err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
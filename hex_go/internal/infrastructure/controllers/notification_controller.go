@@ -0,0 +1,164 @@
+package controllers
+
+import (
+	"net/http"
+
+	"hex_go/internal/domain/entities"
+	"hex_go/internal/domain/ports"
+)
+
+type NotificationController struct {
+	notificationService ports.NotificationServicePort
+}
+
+func NewNotificationController(notificationService ports.NotificationServicePort) *NotificationController {
+	return &NotificationController{
+		notificationService: notificationService,
+	}
+}
+
+// ListRecipients handles listing the addresses a user's alerts are sent to
+func (c *NotificationController) ListRecipients(w http.ResponseWriter, r *http.Request) {
+	userID, ok := requestUserID(w, r)
+	if !ok {
+		return
+	}
+
+	recipients, err := c.notificationService.ListRecipients(r.Context(), userID)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, recipients)
+}
+
+// CreateRecipient handles adding an address to send a user's alerts to
+func (c *NotificationController) CreateRecipient(w http.ResponseWriter, r *http.Request) {
+	userID, ok := requestUserID(w, r)
+	if !ok {
+		return
+	}
+
+	var req entities.CreateRecipientRequest
+	if !decodeJSON(w, r, &req) {
+		return
+	}
+
+	recipient, err := c.notificationService.CreateRecipient(r.Context(), userID, &req)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	writeJSON(w, http.StatusCreated, recipient)
+}
+
+// DeleteRecipient handles removing a notification recipient
+func (c *NotificationController) DeleteRecipient(w http.ResponseWriter, r *http.Request) {
+	userID, ok := requestUserID(w, r)
+	if !ok {
+		return
+	}
+	id, ok := pathID(w, r, "id")
+	if !ok {
+		return
+	}
+
+	if err := c.notificationService.DeleteRecipient(r.Context(), userID, id); err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// GetChannelSettings handles retrieving the channels a user is notified by per severity
+func (c *NotificationController) GetChannelSettings(w http.ResponseWriter, r *http.Request) {
+	userID, ok := requestUserID(w, r)
+	if !ok {
+		return
+	}
+
+	settings, err := c.notificationService.GetChannelSettings(r.Context(), userID)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, settings)
+}
+
+// UpdateChannelSettings handles choosing the channels a user is notified by per severity
+func (c *NotificationController) UpdateChannelSettings(w http.ResponseWriter, r *http.Request) {
+	userID, ok := requestUserID(w, r)
+	if !ok {
+		return
+	}
+
+	var req entities.NotificationChannelSettings
+	if !decodeJSON(w, r, &req) {
+		return
+	}
+
+	settings, err := c.notificationService.UpdateChannelSettings(r.Context(), userID, &req)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, settings)
+}
+
+// ListDeliveries handles listing the latest notifications sent to a user's recipients
+func (c *NotificationController) ListDeliveries(w http.ResponseWriter, r *http.Request) {
+	userID, ok := requestUserID(w, r)
+	if !ok {
+		return
+	}
+
+	deliveries, err := c.notificationService.ListDeliveries(r.Context(), userID)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, deliveries)
+}
+
+// GetSettings handles retrieving a user's quiet hours, digest and minimum severity per channel
+func (c *NotificationController) GetSettings(w http.ResponseWriter, r *http.Request) {
+	userID, ok := requestUserID(w, r)
+	if !ok {
+		return
+	}
+
+	settings, err := c.notificationService.GetSettings(r.Context(), userID)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, settings)
+}
+
+// UpdateSettings handles changing a user's quiet hours, digest and minimum severity per channel
+func (c *NotificationController) UpdateSettings(w http.ResponseWriter, r *http.Request) {
+	userID, ok := requestUserID(w, r)
+	if !ok {
+		return
+	}
+
+	var req entities.NotificationSettings
+	if !decodeJSON(w, r, &req) {
+		return
+	}
+
+	settings, err := c.notificationService.UpdateSettings(r.Context(), userID, &req)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, settings)
+}
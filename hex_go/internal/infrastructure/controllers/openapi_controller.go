@@ -0,0 +1,50 @@
+package controllers
+
+import (
+	"net/http"
+)
+
+// swaggerUIVersion is the Swagger UI release the docs page loads from the CDN
+const swaggerUIVersion = "5.17.14"
+
+// docsPage shows the specification served next to it with Swagger UI
+const docsPage = `<!DOCTYPE html>
+<html lang="en">
+<head>
+  <meta charset="utf-8">
+  <title>StopFire API</title>
+  <link rel="stylesheet" href="https://unpkg.com/swagger-ui-dist@` + swaggerUIVersion + `/swagger-ui.css">
+</head>
+<body>
+  <div id="swagger-ui"></div>
+  <script src="https://unpkg.com/swagger-ui-dist@` + swaggerUIVersion + `/swagger-ui-bundle.js" crossorigin></script>
+  <script>
+    window.ui = SwaggerUIBundle({ url: "openapi.json", dom_id: "#swagger-ui" });
+  </script>
+</body>
+</html>
+`
+
+type OpenAPIController struct {
+	spec []byte
+}
+
+func NewOpenAPIController(spec []byte) *OpenAPIController {
+	return &OpenAPIController{
+		spec: spec,
+	}
+}
+
+// GetSpec handles serving the OpenAPI specification of the API
+func (c *OpenAPIController) GetSpec(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	w.Write(c.spec)
+}
+
+// GetDocs handles serving Swagger UI for the specification
+func (c *OpenAPIController) GetDocs(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	w.WriteHeader(http.StatusOK)
+	w.Write([]byte(docsPage))
+}
@@ -0,0 +1,305 @@
+package controllers
+
+import (
+	"net/http"
+
+	"github.com/gorilla/mux"
+	"hex_go/internal/domain/entities"
+	"hex_go/internal/domain/ports"
+)
+
+type OrganizationController struct {
+	organizationService ports.OrganizationServicePort
+}
+
+func NewOrganizationController(organizationService ports.OrganizationServicePort) *OrganizationController {
+	return &OrganizationController{
+		organizationService: organizationService,
+	}
+}
+
+// ListOrganizations handles listing the organisations of a user
+func (c *OrganizationController) ListOrganizations(w http.ResponseWriter, r *http.Request) {
+	userID, ok := requestUserID(w, r)
+	if !ok {
+		return
+	}
+
+	orgs, err := c.organizationService.ListOrganizations(r.Context(), userID)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, orgs)
+}
+
+// CreateOrganization handles creating an organisation
+func (c *OrganizationController) CreateOrganization(w http.ResponseWriter, r *http.Request) {
+	userID, ok := requestUserID(w, r)
+	if !ok {
+		return
+	}
+
+	var req entities.CreateOrganizationRequest
+	if !decodeJSON(w, r, &req) {
+		return
+	}
+
+	org, err := c.organizationService.CreateOrganization(r.Context(), userID, &req)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	writeJSON(w, http.StatusCreated, org)
+}
+
+// ListMembers handles listing the members of an organisation
+func (c *OrganizationController) ListMembers(w http.ResponseWriter, r *http.Request) {
+	userID, orgID, ok := organizationRequest(w, r)
+	if !ok {
+		return
+	}
+
+	members, err := c.organizationService.ListMembers(r.Context(), userID, orgID)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, members)
+}
+
+// UpdateMember handles changing the role of a member
+func (c *OrganizationController) UpdateMember(w http.ResponseWriter, r *http.Request) {
+	userID, orgID, ok := organizationRequest(w, r)
+	if !ok {
+		return
+	}
+	memberID, ok := pathID(w, r, "memberId")
+	if !ok {
+		return
+	}
+
+	var req entities.UpdateMemberRequest
+	if !decodeJSON(w, r, &req) {
+		return
+	}
+
+	if err := c.organizationService.UpdateMember(r.Context(), userID, orgID, int(memberID), &req); err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// RemoveMember handles removing a member, or leaving the organisation
+func (c *OrganizationController) RemoveMember(w http.ResponseWriter, r *http.Request) {
+	userID, orgID, ok := organizationRequest(w, r)
+	if !ok {
+		return
+	}
+	memberID, ok := pathID(w, r, "memberId")
+	if !ok {
+		return
+	}
+
+	if err := c.organizationService.RemoveMember(r.Context(), userID, orgID, int(memberID)); err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// InviteMember handles inviting a user into an organisation
+func (c *OrganizationController) InviteMember(w http.ResponseWriter, r *http.Request) {
+	userID, orgID, ok := organizationRequest(w, r)
+	if !ok {
+		return
+	}
+
+	var req entities.InviteMemberRequest
+	if !decodeJSON(w, r, &req) {
+		return
+	}
+
+	invitation, err := c.organizationService.InviteMember(r.Context(), userID, orgID, &req)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	writeJSON(w, http.StatusCreated, invitation)
+}
+
+// ListOrganizationInvitations handles listing the invitations sent by an organisation
+func (c *OrganizationController) ListOrganizationInvitations(w http.ResponseWriter, r *http.Request) {
+	userID, orgID, ok := organizationRequest(w, r)
+	if !ok {
+		return
+	}
+
+	invitations, err := c.organizationService.ListOrganizationInvitations(r.Context(), userID, orgID)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, invitations)
+}
+
+// RevokeInvitation handles cancelling a pending invitation
+func (c *OrganizationController) RevokeInvitation(w http.ResponseWriter, r *http.Request) {
+	userID, orgID, ok := organizationRequest(w, r)
+	if !ok {
+		return
+	}
+	invitationID, ok := pathID(w, r, "invitationId")
+	if !ok {
+		return
+	}
+
+	if err := c.organizationService.RevokeInvitation(r.Context(), userID, orgID, invitationID); err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// ListMyInvitations handles listing the pending invitations of a user
+func (c *OrganizationController) ListMyInvitations(w http.ResponseWriter, r *http.Request) {
+	userID, ok := requestUserID(w, r)
+	if !ok {
+		return
+	}
+
+	invitations, err := c.organizationService.ListMyInvitations(r.Context(), userID)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, invitations)
+}
+
+// AcceptInvitation handles accepting an invitation
+func (c *OrganizationController) AcceptInvitation(w http.ResponseWriter, r *http.Request) {
+	userID, ok := requestUserID(w, r)
+	if !ok {
+		return
+	}
+	invitationID, ok := pathID(w, r, "invitationId")
+	if !ok {
+		return
+	}
+
+	if err := c.organizationService.AcceptInvitation(r.Context(), userID, invitationID); err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// DeclineInvitation handles rejecting an invitation
+func (c *OrganizationController) DeclineInvitation(w http.ResponseWriter, r *http.Request) {
+	userID, ok := requestUserID(w, r)
+	if !ok {
+		return
+	}
+	invitationID, ok := pathID(w, r, "invitationId")
+	if !ok {
+		return
+	}
+
+	if err := c.organizationService.DeclineInvitation(r.Context(), userID, invitationID); err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// ShareDevice handles sharing a device with an organisation
+func (c *OrganizationController) ShareDevice(w http.ResponseWriter, r *http.Request) {
+	userID, orgID, ok := organizationRequest(w, r)
+	if !ok {
+		return
+	}
+
+	var req entities.ShareDeviceRequest
+	if !decodeJSON(w, r, &req) {
+		return
+	}
+
+	if err := c.organizationService.ShareDevice(r.Context(), userID, orgID, &req); err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// UnshareDevice handles stopping sharing a device with an organisation
+func (c *OrganizationController) UnshareDevice(w http.ResponseWriter, r *http.Request) {
+	userID, orgID, ok := organizationRequest(w, r)
+	if !ok {
+		return
+	}
+
+	if err := c.organizationService.UnshareDevice(r.Context(), userID, orgID, mux.Vars(r)["numeroSerie"]); err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// ListSharedDevices handles listing the devices shared with an organisation
+func (c *OrganizationController) ListSharedDevices(w http.ResponseWriter, r *http.Request) {
+	userID, orgID, ok := organizationRequest(w, r)
+	if !ok {
+		return
+	}
+
+	devices, err := c.organizationService.ListSharedDevices(r.Context(), userID, orgID)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, devices)
+}
+
+// ListAccessAudit handles listing the access changes of an organisation
+func (c *OrganizationController) ListAccessAudit(w http.ResponseWriter, r *http.Request) {
+	userID, orgID, ok := organizationRequest(w, r)
+	if !ok {
+		return
+	}
+
+	entries, err := c.organizationService.ListAccessAudit(r.Context(), userID, orgID)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, entries)
+}
+
+// organizationRequest reads the calling user and the organisation ID of the route
+func organizationRequest(w http.ResponseWriter, r *http.Request) (int, int64, bool) {
+	userID, ok := requestUserID(w, r)
+	if !ok {
+		return 0, 0, false
+	}
+	orgID, ok := pathID(w, r, "id")
+	if !ok {
+		return 0, 0, false
+	}
+	return userID, orgID, true
+}
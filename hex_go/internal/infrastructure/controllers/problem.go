@@ -0,0 +1,85 @@
+package controllers
+
+import (
+	"encoding/json"
+	"errors"
+	"log"
+	"net/http"
+
+	"hex_go/internal/domain/entities"
+)
+
+// problemContentType is the media type defined by RFC 7807
+const problemContentType = "application/problem+json"
+
+// Problem is an RFC 7807 problem details body shared by every controller
+type Problem struct {
+	Type     string                      `json:"type"`
+	Title    string                      `json:"title"`
+	Status   int                         `json:"status"`
+	Detail   string                      `json:"detail,omitempty"`
+	Instance string                      `json:"instance,omitempty"`
+	Errors   []*entities.ValidationError `json:"errors,omitempty"`
+}
+
+// writeProblem writes a problem details response
+func writeProblem(w http.ResponseWriter, r *http.Request, status int, detail string, fieldErrors []*entities.ValidationError) {
+	problem := Problem{
+		Type:     "about:blank",
+		Title:    http.StatusText(status),
+		Status:   status,
+		Detail:   detail,
+		Instance: r.URL.Path,
+		Errors:   fieldErrors,
+	}
+
+	w.Header().Set("Content-Type", problemContentType)
+	w.WriteHeader(status)
+	if err := json.NewEncoder(w).Encode(problem); err != nil {
+		log.Printf("Error encoding problem response: %v", err)
+	}
+}
+
+// writeError maps an error returned by a service to a problem details response.
+// Validation errors become 422 responses listing each field, the shared domain errors
+// their matching 4xx status and anything else a 500.
+func writeError(w http.ResponseWriter, r *http.Request, err error) {
+	var fieldErrors entities.ValidationErrors
+	if errors.As(err, &fieldErrors) {
+		writeProblem(w, r, http.StatusUnprocessableEntity, "The request contains invalid fields", fieldErrors)
+		return
+	}
+
+	var fieldErr *entities.ValidationError
+	if errors.As(err, &fieldErr) {
+		writeProblem(w, r, http.StatusUnprocessableEntity, "The request contains invalid fields", []*entities.ValidationError{fieldErr})
+		return
+	}
+
+	switch {
+	case errors.Is(err, entities.ErrNotFound):
+		writeProblem(w, r, http.StatusNotFound, err.Error(), nil)
+		return
+	case errors.Is(err, entities.ErrConflict):
+		writeProblem(w, r, http.StatusConflict, err.Error(), nil)
+		return
+	case errors.Is(err, entities.ErrUnauthorized):
+		writeProblem(w, r, http.StatusUnauthorized, err.Error(), nil)
+		return
+	case errors.Is(err, entities.ErrForbidden):
+		writeProblem(w, r, http.StatusForbidden, err.Error(), nil)
+		return
+	}
+
+	log.Printf("Internal error on %s %s: %v", r.Method, r.URL.Path, err)
+	writeProblem(w, r, http.StatusInternalServerError, "An unexpected error occurred", nil)
+}
+
+// writeBadRequest answers with a 400 problem, optionally naming the offending field
+func writeBadRequest(w http.ResponseWriter, r *http.Request, detail string, field string) {
+	var fieldErrors []*entities.ValidationError
+	if field != "" {
+		fieldErrors = []*entities.ValidationError{{Field: field, Message: detail}}
+	}
+	writeProblem(w, r, http.StatusBadRequest, detail, fieldErrors)
+}
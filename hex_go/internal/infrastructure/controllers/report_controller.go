@@ -0,0 +1,57 @@
+package controllers
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/gorilla/mux"
+
+	"hex_go/internal/domain/entities"
+	"hex_go/internal/domain/ports"
+)
+
+type ReportController struct {
+	reportService ports.ReportServicePort
+}
+
+func NewReportController(reportService ports.ReportServicePort) *ReportController {
+	return &ReportController{
+		reportService: reportService,
+	}
+}
+
+// GetReport handles downloading the monthly safety report of one of the user's sites
+// as a PDF. The language comes from lang or the Accept-Language header, else Spanish.
+func (c *ReportController) GetReport(w http.ResponseWriter, r *http.Request) {
+	userID, ok := requestUserID(w, r)
+	if !ok {
+		return
+	}
+	vars := mux.Vars(r)
+	siteID, err := strconv.ParseInt(vars["site"], 10, 64)
+	if err != nil {
+		writeBadRequest(w, r, "Invalid site ID", "site")
+		return
+	}
+
+	lang := r.URL.Query().Get("lang")
+	if lang == "" {
+		lang = acceptedLanguage(r)
+	}
+	if lang == "" {
+		lang = entities.LanguageSpanish
+	}
+
+	pdf, err := c.reportService.GetReportPDF(r.Context(), userID, siteID, vars["month"], lang)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/pdf")
+	w.Header().Set("Content-Disposition", fmt.Sprintf(`attachment; filename="informe-%d-%s.pdf"`, siteID, vars["month"]))
+	w.Header().Set("Content-Length", strconv.Itoa(len(pdf)))
+	w.WriteHeader(http.StatusOK)
+	w.Write(pdf)
+}
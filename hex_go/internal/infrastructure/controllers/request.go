@@ -0,0 +1,48 @@
+package controllers
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+
+	"github.com/gorilla/mux"
+	"hex_go/internal/domain/entities"
+)
+
+// requestUserID returns the authenticated user of the request. It writes a 401 problem
+// and returns false when the caller is not a user.
+func requestUserID(w http.ResponseWriter, r *http.Request) (int, bool) {
+	principal := entities.PrincipalFromContext(r.Context())
+	if !principal.IsUser() {
+		writeProblem(w, r, http.StatusUnauthorized, entities.ErrUnauthorized.Error(), nil)
+		return 0, false
+	}
+
+	return principal.UserID, true
+}
+
+// pathID reads a numeric ID from the route variables, writing a 400 problem when it is invalid
+func pathID(w http.ResponseWriter, r *http.Request, name string) (int64, bool) {
+	id, err := strconv.ParseInt(mux.Vars(r)[name], 10, 64)
+	if err != nil || id <= 0 {
+		writeBadRequest(w, r, "Invalid "+name+" parameter", name)
+		return 0, false
+	}
+	return id, true
+}
+
+// decodeJSON decodes the request body into dest, writing a 400 problem on failure
+func decodeJSON(w http.ResponseWriter, r *http.Request, dest interface{}) bool {
+	if err := json.NewDecoder(r.Body).Decode(dest); err != nil {
+		writeBadRequest(w, r, "Invalid request body", "")
+		return false
+	}
+	return true
+}
+
+// writeJSON writes a JSON response with the given status
+func writeJSON(w http.ResponseWriter, status int, body interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(body)
+}
@@ -0,0 +1,67 @@
+package controllers
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"net"
+	"net/http"
+	"strings"
+
+	"hex_go/internal/domain/entities"
+)
+
+const (
+	requestIDHeader    = "X-Request-ID"
+	forwardedForHeader = "X-Forwarded-For"
+	maxRequestIDLength = 64
+)
+
+// RequestInfoMiddleware gives every request an ID, reusing the X-Request-ID sent by the
+// client or a proxy, and stores it with the client IP for the audit log
+type RequestInfoMiddleware struct {
+	trustProxyHeaders bool
+}
+
+func NewRequestInfoMiddleware(trustProxyHeaders bool) *RequestInfoMiddleware {
+	return &RequestInfoMiddleware{
+		trustProxyHeaders: trustProxyHeaders,
+	}
+}
+
+// Middleware echoes the request ID in the response so clients can quote it
+func (m *RequestInfoMiddleware) Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requestID := r.Header.Get(requestIDHeader)
+		if requestID == "" || len(requestID) > maxRequestIDLength {
+			requestID = newRequestID()
+		}
+		w.Header().Set(requestIDHeader, requestID)
+
+		info := entities.RequestInfo{ID: requestID, IP: m.clientIP(r)}
+		next.ServeHTTP(w, r.WithContext(entities.WithRequestInfo(r.Context(), info)))
+	})
+}
+
+// clientIP returns the address of the caller. X-Forwarded-For is only trusted when
+// configured, since clients can set it to anything.
+func (m *RequestInfoMiddleware) clientIP(r *http.Request) string {
+	if m.trustProxyHeaders {
+		if forwarded := r.Header.Get(forwardedForHeader); forwarded != "" {
+			return strings.TrimSpace(strings.Split(forwarded, ",")[0])
+		}
+	}
+
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return r.RemoteAddr
+	}
+	return host
+}
+
+func newRequestID() string {
+	buf := make([]byte, 16)
+	if _, err := rand.Read(buf); err != nil {
+		return ""
+	}
+	return hex.EncodeToString(buf)
+}
@@ -27,7 +27,7 @@ func (c *SensorController) CreateSensorData(w http.ResponseWriter, r *http.Reque
 	// Read the request body
 	body, err := ioutil.ReadAll(r.Body)
 	if err != nil {
-		http.Error(w, "Error reading request body", http.StatusBadRequest)
+		writeBadRequest(w, r, "Error reading request body", "")
 		return
 	}
 	
@@ -38,7 +38,7 @@ func (c *SensorController) CreateSensorData(w http.ResponseWriter, r *http.Reque
 	err = json.Unmarshal(body, &sensorData)
 	if err != nil {
 		log.Printf("Error decoding JSON: %v", err)
-		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		writeBadRequest(w, r, "Invalid request body", "")
 		return
 	}
 
@@ -46,10 +46,10 @@ func (c *SensorController) CreateSensorData(w http.ResponseWriter, r *http.Reque
 	log.Printf("Parsed sensor data: %+v", sensorData)
 
 	// Process the sensor data
-	err = c.sensorService.ProcessSensorData(&sensorData)
+	err = c.sensorService.ProcessSensorData(r.Context(), &sensorData)
 	if err != nil {
 		log.Printf("Error processing sensor data: %v", err)
-		http.Error(w, err.Error(), http.StatusInternalServerError)
+		writeError(w, r, err)
 		return
 	}
 
@@ -61,27 +61,36 @@ func (c *SensorController) CreateSensorData(w http.ResponseWriter, r *http.Reque
 	})
 }
 
-// GetUserAlerts handles retrieving all alerts for a user
+// GetUserAlerts handles retrieving all alerts for a user, or for an API key the alerts
+// of the devices shared with its organisation
 func (c *SensorController) GetUserAlerts(w http.ResponseWriter, r *http.Request) {
-	// Get user ID from URL parameter
-	userIDStr := r.URL.Query().Get("user_id")
-	if userIDStr == "" {
-		http.Error(w, "Missing user_id parameter", http.StatusBadRequest)
-		return
+	// Optional location filter and aggregation level
+	filter := &entities.AlertFilter{GroupBy: r.URL.Query().Get("group_by")}
+	
+	var userID int
+	if principal := entities.PrincipalFromContext(r.Context()); principal.IsAPIKey() {
+		filter.OrganizationID = &principal.IDOrganizacion
+	} else {
+		var ok bool
+		if userID, ok = requestUserID(w, r); !ok {
+			return
+		}
 	}
 	
-	// Convert user ID to integer
-	userID, err := strconv.Atoi(userIDStr)
-	if err != nil {
-		http.Error(w, "Invalid user_id parameter", http.StatusBadRequest)
-		return
+	if locationIDStr := r.URL.Query().Get("location_id"); locationIDStr != "" {
+		locationID, err := strconv.ParseInt(locationIDStr, 10, 64)
+		if err != nil {
+			writeBadRequest(w, r, "Invalid location_id parameter", "location_id")
+			return
+		}
+		filter.LocationID = &locationID
 	}
 	
 	// Get alerts for this user
-	alerts, err := c.sensorService.GetUserAlerts(userID)
+	alerts, err := c.sensorService.GetUserAlerts(r.Context(), userID, filter)
 	if err != nil {
 		log.Printf("Error getting user alerts: %v", err)
-		http.Error(w, err.Error(), http.StatusInternalServerError)
+		writeError(w, r, err)
 		return
 	}
 	
@@ -0,0 +1,152 @@
+package controllers
+
+import (
+	"net/http"
+
+	"hex_go/internal/domain/entities"
+	"hex_go/internal/domain/ports"
+)
+
+type WebhookController struct {
+	webhookService ports.WebhookServicePort
+}
+
+func NewWebhookController(webhookService ports.WebhookServicePort) *WebhookController {
+	return &WebhookController{
+		webhookService: webhookService,
+	}
+}
+
+// ListWebhooks handles listing the webhooks of a user
+func (c *WebhookController) ListWebhooks(w http.ResponseWriter, r *http.Request) {
+	userID, ok := requestUserID(w, r)
+	if !ok {
+		return
+	}
+
+	webhooks, err := c.webhookService.ListWebhooks(r.Context(), userID)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, webhooks)
+}
+
+// CreateWebhook handles registering a webhook. The signing secret is only shown in this response.
+func (c *WebhookController) CreateWebhook(w http.ResponseWriter, r *http.Request) {
+	userID, ok := requestUserID(w, r)
+	if !ok {
+		return
+	}
+
+	var req entities.CreateWebhookRequest
+	if !decodeJSON(w, r, &req) {
+		return
+	}
+
+	webhook, err := c.webhookService.CreateWebhook(r.Context(), userID, &req)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	writeJSON(w, http.StatusCreated, webhook)
+}
+
+// GetWebhook handles retrieving a webhook
+func (c *WebhookController) GetWebhook(w http.ResponseWriter, r *http.Request) {
+	userID, id, ok := webhookRequest(w, r)
+	if !ok {
+		return
+	}
+
+	webhook, err := c.webhookService.GetWebhook(r.Context(), userID, id)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, webhook)
+}
+
+// UpdateWebhook handles changing a webhook
+func (c *WebhookController) UpdateWebhook(w http.ResponseWriter, r *http.Request) {
+	userID, id, ok := webhookRequest(w, r)
+	if !ok {
+		return
+	}
+
+	var req entities.UpdateWebhookRequest
+	if !decodeJSON(w, r, &req) {
+		return
+	}
+
+	webhook, err := c.webhookService.UpdateWebhook(r.Context(), userID, id, &req)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, webhook)
+}
+
+// DeleteWebhook handles removing a webhook
+func (c *WebhookController) DeleteWebhook(w http.ResponseWriter, r *http.Request) {
+	userID, id, ok := webhookRequest(w, r)
+	if !ok {
+		return
+	}
+
+	if err := c.webhookService.DeleteWebhook(r.Context(), userID, id); err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// SendTestEvent handles sending a sample alert to a webhook and returns the delivery
+func (c *WebhookController) SendTestEvent(w http.ResponseWriter, r *http.Request) {
+	userID, id, ok := webhookRequest(w, r)
+	if !ok {
+		return
+	}
+
+	delivery, err := c.webhookService.SendTestEvent(r.Context(), userID, id)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, delivery)
+}
+
+// ListDeliveries handles listing the delivery log of a webhook
+func (c *WebhookController) ListDeliveries(w http.ResponseWriter, r *http.Request) {
+	userID, id, ok := webhookRequest(w, r)
+	if !ok {
+		return
+	}
+
+	deliveries, err := c.webhookService.ListDeliveries(r.Context(), userID, id)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, deliveries)
+}
+
+// webhookRequest reads the calling user and the webhook ID of the route
+func webhookRequest(w http.ResponseWriter, r *http.Request) (int, int64, bool) {
+	userID, ok := requestUserID(w, r)
+	if !ok {
+		return 0, 0, false
+	}
+	id, ok := pathID(w, r, "id")
+	if !ok {
+		return 0, 0, false
+	}
+	return userID, id, true
+}
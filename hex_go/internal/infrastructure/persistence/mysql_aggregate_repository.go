@@ -0,0 +1,238 @@
+package persistence
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"hex_go/internal/domain/entities"
+	"hex_go/internal/domain/ports"
+)
+
+// rollupHour truncates a reading time to its hour without involving time zones
+const rollupHour = `DATE_FORMAT(fecha_activacion, '%Y-%m-%d %H:00:00')`
+
+// MySQLAggregateRepository implements the AggregateRepositoryPort
+type MySQLAggregateRepository struct {
+	db *sql.DB
+}
+
+// NewMySQLAggregateRepository creates a new MySQL aggregate repository
+func NewMySQLAggregateRepository(db *sql.DB) *MySQLAggregateRepository {
+	return &MySQLAggregateRepository{
+		db: db,
+	}
+}
+
+// DeviceVisible reports whether the user or the organisation can read a device
+func (r *MySQLAggregateRepository) DeviceVisible(ctx context.Context, numeroSerie string, userID int, organizationID *int64) (bool, error) {
+	devices, args := accessibleDevicesQuery, []interface{}{userID, userID}
+	if organizationID != nil {
+		devices, args = organizationDevicesQuery, []interface{}{*organizationID}
+	}
+
+	var visible bool
+	err := r.db.QueryRowContext(ctx, `SELECT ? IN (`+devices+`)`, append([]interface{}{numeroSerie}, args...)...).Scan(&visible)
+	if err != nil {
+		return false, fmt.Errorf("error checking access to device %s: %w", numeroSerie, err)
+	}
+	return visible, nil
+}
+
+// AggregateReadings summarises the readings of one sensor of a device in SQL
+func (r *MySQLAggregateRepository) AggregateReadings(ctx context.Context, numeroSerie, sensor string, origin, desde, hasta time.Time, size time.Duration) ([]*entities.AggregateBucket, error) {
+	fields, err := aggregateFields(sensor)
+	if err != nil {
+		return nil, err
+	}
+
+	query := `SELECT TIMESTAMPDIFF(SECOND, ?, fecha_activacion) DIV ?, COUNT(*)`
+	for _, field := range fields {
+		query += fmt.Sprintf(", COUNT(%[1]s), SUM(%[1]s), MIN(%[1]s), MAX(%[1]s)", field)
+	}
+	query += ` FROM ` + sensor + ` WHERE numero_serie = ? AND fecha_activacion >= ? AND fecha_activacion < ? GROUP BY 1`
+
+	rows, err := r.db.QueryContext(ctx, query, origin, int64(size/time.Second), numeroSerie, desde, hasta)
+	if err != nil {
+		return nil, fmt.Errorf("error aggregating %s readings of device %s: %w", sensor, numeroSerie, err)
+	}
+	defer rows.Close()
+
+	buckets := []*entities.AggregateBucket{}
+	for rows.Next() {
+		var index int64
+		bucket := &entities.AggregateBucket{Valores: map[string]*entities.AggregateStats{}}
+		stats := make([]aggregateRow, len(fields))
+		dest := []interface{}{&index, &bucket.Lecturas}
+		for i := range stats {
+			dest = append(dest, stats[i].dest()...)
+		}
+		if err := rows.Scan(dest...); err != nil {
+			return nil, fmt.Errorf("error scanning aggregate: %w", err)
+		}
+
+		bucket.Inicio = origin.Add(time.Duration(index) * size)
+		for i, field := range fields {
+			bucket.Valores[field] = stats[i].stats()
+		}
+		buckets = append(buckets, bucket)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating aggregates: %w", err)
+	}
+
+	return buckets, nil
+}
+
+// AggregateRollups summarises the hourly rollups of one sensor of a device in SQL
+func (r *MySQLAggregateRepository) AggregateRollups(ctx context.Context, numeroSerie, sensor string, origin, desde, hasta time.Time, size time.Duration) ([]*entities.AggregateBucket, error) {
+	rows, err := r.db.QueryContext(ctx,
+		`SELECT TIMESTAMPDIFF(SECOND, ?, inicio) DIV ? AS idx, campo, SUM(lecturas), SUM(cantidad), SUM(suma),
+			MIN(minimo), MAX(maximo)
+		FROM agregados_hora
+		WHERE numero_serie = ? AND sensor = ? AND inicio >= ? AND inicio < ?
+		GROUP BY idx, campo`,
+		origin, int64(size/time.Second), numeroSerie, sensor, desde, hasta)
+	if err != nil {
+		return nil, fmt.Errorf("error aggregating %s rollups of device %s: %w", sensor, numeroSerie, err)
+	}
+	defer rows.Close()
+
+	byIndex := map[int64]*entities.AggregateBucket{}
+	buckets := []*entities.AggregateBucket{}
+	for rows.Next() {
+		var index int64
+		var field string
+		var lecturas int
+		var row aggregateRow
+		if err := rows.Scan(append([]interface{}{&index, &field, &lecturas}, row.dest()...)...); err != nil {
+			return nil, fmt.Errorf("error scanning rollup: %w", err)
+		}
+
+		bucket, ok := byIndex[index]
+		if !ok {
+			// Every field of an hour counts the same readings
+			bucket = &entities.AggregateBucket{
+				Inicio:   origin.Add(time.Duration(index) * size),
+				Lecturas: lecturas,
+				Valores:  map[string]*entities.AggregateStats{},
+			}
+			byIndex[index] = bucket
+			buckets = append(buckets, bucket)
+		}
+		bucket.Valores[field] = row.stats()
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating rollups: %w", err)
+	}
+
+	return buckets, nil
+}
+
+// RollupProgress returns the end of the hours rolled up for a sensor type
+func (r *MySQLAggregateRepository) RollupProgress(ctx context.Context, sensor string) (time.Time, error) {
+	var hasta time.Time
+	err := r.db.QueryRowContext(ctx, `SELECT hasta FROM progreso_agregados WHERE sensor = ?`, sensor).Scan(&hasta)
+	if err == sql.ErrNoRows {
+		return time.Time{}, nil
+	}
+	if err != nil {
+		return time.Time{}, fmt.Errorf("error fetching %s rollup progress: %w", sensor, err)
+	}
+	return hasta, nil
+}
+
+// FirstReading returns the time of the oldest reading of a sensor type
+func (r *MySQLAggregateRepository) FirstReading(ctx context.Context, sensor string) (time.Time, error) {
+	if _, err := aggregateFields(sensor); err != nil {
+		return time.Time{}, err
+	}
+
+	var first sql.NullTime
+	if err := r.db.QueryRowContext(ctx, `SELECT MIN(fecha_activacion) FROM `+sensor).Scan(&first); err != nil {
+		return time.Time{}, fmt.Errorf("error fetching first %s reading: %w", sensor, err)
+	}
+	return first.Time, nil
+}
+
+// RollupHours replaces the rollups of the hours from desde until hasta and records the progress
+func (r *MySQLAggregateRepository) RollupHours(ctx context.Context, sensor string, desde, hasta time.Time) error {
+	fields, err := aggregateFields(sensor)
+	if err != nil {
+		return err
+	}
+
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("error starting transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	_, err = tx.ExecContext(ctx, `DELETE FROM agregados_hora WHERE sensor = ? AND inicio >= ? AND inicio < ?`,
+		sensor, desde, hasta)
+	if err != nil {
+		return fmt.Errorf("error clearing %s rollups: %w", sensor, err)
+	}
+	for _, field := range fields {
+		_, err = tx.ExecContext(ctx,
+			`INSERT INTO agregados_hora (numero_serie, sensor, inicio, campo, lecturas, cantidad, suma, minimo, maximo)
+			SELECT numero_serie, ?, `+rollupHour+`, ?, COUNT(*), COUNT(`+field+`), COALESCE(SUM(`+field+`), 0),
+				MIN(`+field+`), MAX(`+field+`)
+			FROM `+sensor+`
+			WHERE fecha_activacion >= ? AND fecha_activacion < ?
+			GROUP BY numero_serie, `+rollupHour,
+			sensor, field, desde, hasta)
+		if err != nil {
+			return fmt.Errorf("error rolling up %s %s: %w", sensor, field, err)
+		}
+	}
+	_, err = tx.ExecContext(ctx,
+		`INSERT INTO progreso_agregados (sensor, hasta) VALUES (?, ?)
+		ON DUPLICATE KEY UPDATE hasta = GREATEST(hasta, VALUES(hasta))`, sensor, hasta)
+	if err != nil {
+		return fmt.Errorf("error recording %s rollup progress: %w", sensor, err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("error committing %s rollups: %w", sensor, err)
+	}
+	return nil
+}
+
+// aggregateFields returns the aggregated columns of a sensor type. The type names its
+// table, so nothing else may be put into a query.
+func aggregateFields(sensor string) ([]string, error) {
+	fields := entities.AggregateFields(sensor)
+	if fields == nil {
+		return nil, fmt.Errorf("sensor type %q not supported", sensor)
+	}
+	return fields, nil
+}
+
+// aggregateRow scans the count, sum, minimum and maximum of one field
+type aggregateRow struct {
+	cantidad int
+	suma     sql.NullFloat64
+	minimo   sql.NullFloat64
+	maximo   sql.NullFloat64
+}
+
+func (a *aggregateRow) dest() []interface{} {
+	return []interface{}{&a.cantidad, &a.suma, &a.minimo, &a.maximo}
+}
+
+// stats converts the scanned values; a field without values has null statistics
+func (a *aggregateRow) stats() *entities.AggregateStats {
+	stats := &entities.AggregateStats{Cantidad: a.cantidad}
+	if a.cantidad == 0 {
+		return stats
+	}
+	minimo, maximo := a.minimo.Float64, a.maximo.Float64
+	media := a.suma.Float64 / float64(a.cantidad)
+	stats.Minimo, stats.Maximo, stats.Media, stats.Suma = &minimo, &maximo, &media, a.suma.Float64
+	return stats
+}
+
+// Verify interface implementation
+var _ ports.AggregateRepositoryPort = (*MySQLAggregateRepository)(nil)
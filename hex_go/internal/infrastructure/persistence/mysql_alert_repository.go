@@ -0,0 +1,219 @@
+package persistence
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"hex_go/internal/domain/entities"
+	"hex_go/internal/domain/ports"
+)
+
+// alertColumns are the alertas columns read into an Alert, in scan order
+const alertColumns = `a.idAlerta, a.numero_serie, a.tipo, a.severidad, a.mensaje, a.valor, a.estado,
+	a.fecha_creacion, a.reconocida_por, a.fecha_reconocimiento, a.ocurrencias, a.fecha_ultima, a.oscilante,
+	a.ventana_inicio, a.ventana_ocurrencias, a.idIncidente, a.suprimida, a.idMantenimiento`
+
+// MySQLAlertRepository implements the AlertRepositoryPort over the alertas table
+type MySQLAlertRepository struct {
+	db *sql.DB
+}
+
+// NewMySQLAlertRepository creates a new MySQL alert repository
+func NewMySQLAlertRepository(db *sql.DB) *MySQLAlertRepository {
+	return &MySQLAlertRepository{
+		db: db,
+	}
+}
+
+// CreateAlert inserts a new alert and sets its ID
+func (r *MySQLAlertRepository) CreateAlert(ctx context.Context, alert *entities.Alert) error {
+	query := `INSERT INTO alertas (numero_serie, tipo, severidad, mensaje, valor, estado, fecha_creacion,
+			ocurrencias, fecha_ultima, oscilante, ventana_inicio, ventana_ocurrencias, suprimida, idMantenimiento)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`
+
+	result, err := r.db.ExecContext(ctx, query,
+		alert.NumeroSerie, alert.Tipo, alert.Severidad, alert.Mensaje, alert.Valor, alert.Estado, alert.FechaCreacion,
+		alert.Ocurrencias, alert.FechaUltima, alert.Oscilante, alert.VentanaInicio, alert.VentanaOcurrencias,
+		alert.Suprimida, alert.IDMantenimiento)
+	if err != nil {
+		return fmt.Errorf("error creating %s alert: %w", alert.Tipo, err)
+	}
+
+	id, err := result.LastInsertId()
+	if err != nil {
+		return fmt.Errorf("error reading alert ID: %w", err)
+	}
+	alert.ID = id
+
+	return nil
+}
+
+// GetAlert returns an alert by ID or entities.ErrNotFound
+func (r *MySQLAlertRepository) GetAlert(ctx context.Context, id int64) (*entities.Alert, error) {
+	query := `SELECT ` + alertColumns + ` FROM alertas a WHERE a.idAlerta = ?`
+
+	return r.getAlert(ctx, id, query, id)
+}
+
+// GetUserAlert returns an alert of a device the user can see with the strongest role they
+// hold on the device, or entities.ErrNotFound
+func (r *MySQLAlertRepository) GetUserAlert(ctx context.Context, userID int, id int64) (*entities.Alert, string, error) {
+	query := `SELECT ` + alertColumns + `, r.rol FROM alertas a
+		JOIN (` + deviceRolesQuery + `) r ON r.numero_serie = a.numero_serie
+		WHERE a.idAlerta = ?
+		ORDER BY ` + strongestRoleFirst + ` LIMIT 1`
+
+	var role string
+	alert, err := scanAlert(roleScanner{r.db.QueryRowContext(ctx, query, userID, userID, id), &role})
+	if err == sql.ErrNoRows {
+		return nil, "", entities.ErrNotFound
+	}
+	if err != nil {
+		return nil, "", fmt.Errorf("error fetching alert %d: %w", id, err)
+	}
+
+	return alert, role, nil
+}
+
+// FindOpenEpisode returns the latest alert of the device and type that is not resolved,
+// or entities.ErrNotFound
+func (r *MySQLAlertRepository) FindOpenEpisode(ctx context.Context, numeroSerie, tipo string) (*entities.Alert, error) {
+	query := `SELECT ` + alertColumns + ` FROM alertas a
+		WHERE a.numero_serie = ? AND a.tipo = ? AND a.estado <> ?
+		ORDER BY a.idAlerta DESC LIMIT 1`
+
+	alert, err := scanAlert(r.db.QueryRowContext(ctx, query, numeroSerie, tipo, entities.AlertStateResolved))
+	if err == sql.ErrNoRows {
+		return nil, entities.ErrNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error fetching open %s alert of device %s: %w", tipo, numeroSerie, err)
+	}
+
+	return alert, nil
+}
+
+// UpdateEpisode stores the counters of an alert episode, provided it still has the given
+// number of occurrences
+func (r *MySQLAlertRepository) UpdateEpisode(ctx context.Context, alert *entities.Alert, seen int) error {
+	result, err := r.db.ExecContext(ctx,
+		`UPDATE alertas SET valor = ?, ocurrencias = ?, fecha_ultima = ?, oscilante = ?,
+			ventana_inicio = ?, ventana_ocurrencias = ?
+		WHERE idAlerta = ? AND ocurrencias = ? AND estado <> ?`,
+		alert.Valor, alert.Ocurrencias, alert.FechaUltima, alert.Oscilante, alert.VentanaInicio,
+		alert.VentanaOcurrencias, alert.ID, seen, entities.AlertStateResolved)
+	if err != nil {
+		return fmt.Errorf("error updating alert %d: %w", alert.ID, err)
+	}
+
+	return expectOneRow(result, entities.ErrConflict)
+}
+
+// AcknowledgeAlert marks an active alert as acknowledged
+func (r *MySQLAlertRepository) AcknowledgeAlert(ctx context.Context, id int64, userID int, at time.Time) error {
+	result, err := r.db.ExecContext(ctx,
+		`UPDATE alertas SET estado = ?, reconocida_por = ?, fecha_reconocimiento = ?
+		WHERE idAlerta = ? AND estado = ?`,
+		entities.AlertStateAcknowledged, userID, at, id, entities.AlertStateActive)
+	if err != nil {
+		return fmt.Errorf("error acknowledging alert %d: %w", id, err)
+	}
+
+	return expectOneRow(result, entities.ErrConflict)
+}
+
+// StreamUserAlerts scans the selected alerts one row at a time, so exports of any size
+// take constant memory. The connection stays busy until fn has seen every alert.
+func (r *MySQLAlertRepository) StreamUserAlerts(ctx context.Context, userID int, query *entities.AlertExportQuery, fn func(*entities.Alert) error) error {
+	devicesQuery, args := visibleDevices(userID, &entities.AlertFilter{OrganizationID: query.OrganizationID})
+	sqlQuery := `SELECT ` + alertColumns + ` FROM alertas a WHERE a.numero_serie IN (` + devicesQuery + `)`
+	if query.LocationID != nil {
+		sqlQuery += ` AND a.numero_serie IN (SELECT e.numero_serie FROM ESP32 e
+			JOIN ubicaciones u ON u.idUbicacion = e.idUbicacion
+			JOIN ubicaciones f ON f.idUbicacion = ?
+			WHERE u.ruta LIKE CONCAT(f.ruta, '%'))`
+		args = append(args, *query.LocationID)
+	}
+	if query.Desde != nil {
+		sqlQuery += ` AND a.fecha_creacion >= ?`
+		args = append(args, *query.Desde)
+	}
+	if query.Hasta != nil {
+		sqlQuery += ` AND a.fecha_creacion < ?`
+		args = append(args, *query.Hasta)
+	}
+	sqlQuery += ` ORDER BY a.fecha_creacion, a.idAlerta`
+
+	rows, err := r.db.QueryContext(ctx, sqlQuery, args...)
+	if err != nil {
+		return fmt.Errorf("error exporting alerts: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		alert, err := scanAlert(rows)
+		if err != nil {
+			return fmt.Errorf("error scanning alert: %w", err)
+		}
+		if err := fn(alert); err != nil {
+			return err
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("error iterating alerts: %w", err)
+	}
+
+	return nil
+}
+
+func (r *MySQLAlertRepository) getAlert(ctx context.Context, id int64, query string, args ...interface{}) (*entities.Alert, error) {
+	alert, err := scanAlert(r.db.QueryRowContext(ctx, query, args...))
+	if err == sql.ErrNoRows {
+		return nil, entities.ErrNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error fetching alert %d: %w", id, err)
+	}
+
+	return alert, nil
+}
+
+// scanAlert reads the alertColumns of a row
+func scanAlert(row rowScanner) (*entities.Alert, error) {
+	var alert entities.Alert
+	var valor sql.NullFloat64
+	var reconocidaPor sql.NullInt64
+	var fechaReconocimiento sql.NullTime
+	var idIncidente, idMantenimiento sql.NullInt64
+	err := row.Scan(&alert.ID, &alert.NumeroSerie, &alert.Tipo, &alert.Severidad, &alert.Mensaje, &valor,
+		&alert.Estado, &alert.FechaCreacion, &reconocidaPor, &fechaReconocimiento, &alert.Ocurrencias,
+		&alert.FechaUltima, &alert.Oscilante, &alert.VentanaInicio, &alert.VentanaOcurrencias, &idIncidente,
+		&alert.Suprimida, &idMantenimiento)
+	if err != nil {
+		return nil, err
+	}
+
+	if valor.Valid {
+		alert.Valor = &valor.Float64
+	}
+	if reconocidaPor.Valid {
+		userID := int(reconocidaPor.Int64)
+		alert.ReconocidaPor = &userID
+	}
+	if fechaReconocimiento.Valid {
+		alert.FechaReconocimiento = &fechaReconocimiento.Time
+	}
+	if idIncidente.Valid {
+		alert.IDIncidente = &idIncidente.Int64
+	}
+	if idMantenimiento.Valid {
+		alert.IDMantenimiento = &idMantenimiento.Int64
+	}
+
+	return &alert, nil
+}
+
+// Verify interface implementation
+var _ ports.AlertRepositoryPort = (*MySQLAlertRepository)(nil)
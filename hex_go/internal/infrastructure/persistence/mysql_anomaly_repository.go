@@ -0,0 +1,86 @@
+package persistence
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"hex_go/internal/domain/entities"
+	"hex_go/internal/domain/ports"
+)
+
+// MySQLAnomalyRepository implements the AnomalyRepositoryPort
+type MySQLAnomalyRepository struct {
+	db *sql.DB
+}
+
+// NewMySQLAnomalyRepository creates a new MySQL anomaly repository
+func NewMySQLAnomalyRepository(db *sql.DB) *MySQLAnomalyRepository {
+	return &MySQLAnomalyRepository{
+		db: db,
+	}
+}
+
+// GetAnomalyState returns the stored state of a sensor of a device or entities.ErrNotFound
+func (r *MySQLAnomalyRepository) GetAnomalyState(ctx context.Context, numeroSerie, sensor string) (*entities.AnomalyState, error) {
+	state := entities.AnomalyState{NumeroSerie: numeroSerie, Sensor: sensor}
+	var subidaFecha sql.NullTime
+	err := r.db.QueryRowContext(ctx,
+		`SELECT media, varianza, muestras, subida_valor, subida_fecha, fecha_actualizacion
+		FROM estados_anomalia WHERE numero_serie = ? AND sensor = ?`, numeroSerie, sensor).
+		Scan(&state.Media, &state.Varianza, &state.Muestras, &state.SubidaValor, &subidaFecha, &state.FechaActualizacion)
+	if err == sql.ErrNoRows {
+		return nil, entities.ErrNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error fetching %s anomaly state of device %s: %w", sensor, numeroSerie, err)
+	}
+	if subidaFecha.Valid {
+		state.SubidaFecha = subidaFecha.Time
+	}
+
+	return &state, nil
+}
+
+// SaveAnomalyStates inserts or replaces the given states in one transaction
+func (r *MySQLAnomalyRepository) SaveAnomalyStates(ctx context.Context, states []*entities.AnomalyState) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("error starting transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	stmt, err := tx.PrepareContext(ctx,
+		`INSERT INTO estados_anomalia (numero_serie, sensor, media, varianza, muestras, subida_valor, subida_fecha,
+			fecha_actualizacion)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?)
+		ON DUPLICATE KEY UPDATE media = VALUES(media), varianza = VALUES(varianza), muestras = VALUES(muestras),
+			subida_valor = VALUES(subida_valor), subida_fecha = VALUES(subida_fecha),
+			fecha_actualizacion = VALUES(fecha_actualizacion)`)
+	if err != nil {
+		return fmt.Errorf("error preparing anomaly state statement: %w", err)
+	}
+	defer stmt.Close()
+
+	for _, s := range states {
+		_, err := stmt.ExecContext(ctx, s.NumeroSerie, s.Sensor, s.Media, s.Varianza, s.Muestras, s.SubidaValor,
+			nullTime(s.SubidaFecha), s.FechaActualizacion)
+		if err != nil {
+			return fmt.Errorf("error saving %s anomaly state of device %s: %w", s.Sensor, s.NumeroSerie, err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("error committing anomaly states: %w", err)
+	}
+	return nil
+}
+
+// nullTime stores the zero time as NULL
+func nullTime(t time.Time) sql.NullTime {
+	return sql.NullTime{Time: t, Valid: !t.IsZero()}
+}
+
+// Verify interface implementation
+var _ ports.AnomalyRepositoryPort = (*MySQLAnomalyRepository)(nil)
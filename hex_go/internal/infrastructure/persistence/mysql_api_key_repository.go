@@ -0,0 +1,134 @@
+package persistence
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+
+	"hex_go/internal/domain/entities"
+	"hex_go/internal/domain/ports"
+)
+
+// apiKeyColumns are the claves_api columns read into an APIKey, in scan order
+const apiKeyColumns = `idClaveApi, idOrganizacion, nombre, prefijo, hash, scopes, creada_por,
+	fecha_creacion, fecha_expiracion, ultimo_uso, revocada`
+
+// MySQLAPIKeyRepository implements the APIKeyRepositoryPort
+type MySQLAPIKeyRepository struct {
+	db *sql.DB
+}
+
+// NewMySQLAPIKeyRepository creates a new MySQL API key repository
+func NewMySQLAPIKeyRepository(db *sql.DB) *MySQLAPIKeyRepository {
+	return &MySQLAPIKeyRepository{
+		db: db,
+	}
+}
+
+// CreateAPIKey inserts a key and sets its ID
+func (r *MySQLAPIKeyRepository) CreateAPIKey(ctx context.Context, key *entities.APIKey, audit *entities.AccessAuditEntry) error {
+	return withAccessAudit(ctx, r.db, audit, func(tx *sql.Tx) error {
+		result, err := tx.ExecContext(ctx,
+			`INSERT INTO claves_api (idOrganizacion, nombre, prefijo, hash, scopes, creada_por, fecha_creacion, fecha_expiracion, revocada)
+			VALUES (?, ?, ?, ?, ?, ?, ?, ?, FALSE)`,
+			key.IDOrganizacion, key.Nombre, key.Prefijo, key.Hash, strings.Join(key.Scopes, ","),
+			key.CreadaPor, key.FechaCreacion, key.FechaExpiracion)
+		if err != nil {
+			return fmt.Errorf("error creating API key: %w", err)
+		}
+		if key.ID, err = result.LastInsertId(); err != nil {
+			return fmt.Errorf("error reading API key ID: %w", err)
+		}
+		audit.Objetivo = fmt.Sprintf("api_key:%d", key.ID)
+		return nil
+	})
+}
+
+// GetAPIKeyByPrefix returns the key with a prefix or entities.ErrNotFound
+func (r *MySQLAPIKeyRepository) GetAPIKeyByPrefix(ctx context.Context, prefijo string) (*entities.APIKey, error) {
+	query := `SELECT ` + apiKeyColumns + ` FROM claves_api WHERE prefijo = ?`
+
+	key, err := scanAPIKey(r.db.QueryRowContext(ctx, query, prefijo))
+	if err == sql.ErrNoRows {
+		return nil, entities.ErrNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error fetching API key %s: %w", prefijo, err)
+	}
+
+	return key, nil
+}
+
+// ListAPIKeys returns every key of an organisation, newest first
+func (r *MySQLAPIKeyRepository) ListAPIKeys(ctx context.Context, orgID int64) ([]*entities.APIKey, error) {
+	rows, err := r.db.QueryContext(ctx,
+		`SELECT `+apiKeyColumns+` FROM claves_api WHERE idOrganizacion = ? ORDER BY fecha_creacion DESC`, orgID)
+	if err != nil {
+		return nil, fmt.Errorf("error fetching API keys of organization %d: %w", orgID, err)
+	}
+	defer rows.Close()
+
+	keys := []*entities.APIKey{}
+	for rows.Next() {
+		key, err := scanAPIKey(rows)
+		if err != nil {
+			return nil, fmt.Errorf("error scanning API key: %w", err)
+		}
+		keys = append(keys, key)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating API keys: %w", err)
+	}
+
+	return keys, nil
+}
+
+// RevokeAPIKey revokes a key of an organisation. It returns entities.ErrNotFound if the
+// organisation has no such key that is still active.
+func (r *MySQLAPIKeyRepository) RevokeAPIKey(ctx context.Context, orgID int64, id int64, audit *entities.AccessAuditEntry) error {
+	return withAccessAudit(ctx, r.db, audit, func(tx *sql.Tx) error {
+		result, err := tx.ExecContext(ctx,
+			`UPDATE claves_api SET revocada = TRUE WHERE idClaveApi = ? AND idOrganizacion = ? AND revocada = FALSE`, id, orgID)
+		if err != nil {
+			return fmt.Errorf("error revoking API key %d: %w", id, err)
+		}
+		return expectOneRow(result, entities.ErrNotFound)
+	})
+}
+
+// TouchAPIKey records the last use of a key. The condition keeps busy integrations from
+// writing the row on every request.
+func (r *MySQLAPIKeyRepository) TouchAPIKey(ctx context.Context, id int64, at time.Time, usedBefore time.Time) error {
+	_, err := r.db.ExecContext(ctx,
+		`UPDATE claves_api SET ultimo_uso = ? WHERE idClaveApi = ? AND (ultimo_uso IS NULL OR ultimo_uso < ?)`,
+		at, id, usedBefore)
+	if err != nil {
+		return fmt.Errorf("error updating last use of API key %d: %w", id, err)
+	}
+	return nil
+}
+
+func scanAPIKey(row rowScanner) (*entities.APIKey, error) {
+	var k entities.APIKey
+	var scopes string
+	var ultimoUso sql.NullTime
+	err := row.Scan(&k.ID, &k.IDOrganizacion, &k.Nombre, &k.Prefijo, &k.Hash, &scopes, &k.CreadaPor,
+		&k.FechaCreacion, &k.FechaExpiracion, &ultimoUso, &k.Revocada)
+	if err != nil {
+		return nil, err
+	}
+	k.Scopes = []string{}
+	if scopes != "" {
+		k.Scopes = strings.Split(scopes, ",")
+	}
+	if ultimoUso.Valid {
+		k.UltimoUso = &ultimoUso.Time
+	}
+	return &k, nil
+}
+
+// Verify interface implementation
+var _ ports.APIKeyRepositoryPort = (*MySQLAPIKeyRepository)(nil)
@@ -0,0 +1,163 @@
+package persistence
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"hex_go/internal/domain/entities"
+	"hex_go/internal/domain/ports"
+)
+
+// auditColumns are the registro_auditoria columns read into an AuditEntry, in scan order
+const auditColumns = `idRegistro, tipo_actor, id_actor, accion, recurso, id_recurso, antes, despues,
+	id_solicitud, ip, fecha, hash_anterior, hash`
+
+// MySQLAuditRepository implements the AuditRepositoryPort
+type MySQLAuditRepository struct {
+	db *sql.DB
+}
+
+// NewMySQLAuditRepository creates a new MySQL audit repository
+func NewMySQLAuditRepository(db *sql.DB) *MySQLAuditRepository {
+	return &MySQLAuditRepository{
+		db: db,
+	}
+}
+
+// AppendAuditEntry links the entry to the current head of the chain, stores it and moves
+// the head, all while holding the lock on the head row
+func (r *MySQLAuditRepository) AppendAuditEntry(ctx context.Context, entry *entities.AuditEntry) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("error starting transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	var headID int64
+	err = tx.QueryRowContext(ctx, `SELECT idRegistro, hash FROM cadena_auditoria WHERE id = 1 FOR UPDATE`).
+		Scan(&headID, &entry.HashAnterior)
+	if err != nil {
+		return fmt.Errorf("error locking audit chain: %w", err)
+	}
+	entry.Hash = entry.ComputeHash()
+
+	result, err := tx.ExecContext(ctx,
+		`INSERT INTO registro_auditoria (tipo_actor, id_actor, accion, recurso, id_recurso, antes, despues,
+			id_solicitud, ip, fecha, hash_anterior, hash)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+		entry.TipoActor, entry.IDActor, entry.Accion, entry.Recurso, entry.IDRecurso,
+		nullString(string(entry.Antes)), nullString(string(entry.Despues)),
+		nullString(entry.IDSolicitud), nullString(entry.IP), entry.Fecha, entry.HashAnterior, entry.Hash)
+	if err != nil {
+		return fmt.Errorf("error storing audit entry: %w", err)
+	}
+	if entry.ID, err = result.LastInsertId(); err != nil {
+		return fmt.Errorf("error reading audit entry ID: %w", err)
+	}
+
+	_, err = tx.ExecContext(ctx, `UPDATE cadena_auditoria SET idRegistro = ?, hash = ? WHERE id = 1`, entry.ID, entry.Hash)
+	if err != nil {
+		return fmt.Errorf("error moving audit chain head: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("error committing transaction: %w", err)
+	}
+	return nil
+}
+
+// ListAuditEntries returns the entries matching a query, newest first
+func (r *MySQLAuditRepository) ListAuditEntries(ctx context.Context, query *entities.AuditQuery) ([]*entities.AuditEntry, error) {
+	sqlQuery := `SELECT ` + auditColumns + ` FROM registro_auditoria WHERE 1 = 1`
+	args := []interface{}{}
+
+	filters := []struct {
+		column string
+		value  string
+	}{
+		{"tipo_actor", query.TipoActor},
+		{"id_actor", query.IDActor},
+		{"accion", query.Accion},
+		{"recurso", query.Recurso},
+		{"id_recurso", query.IDRecurso},
+	}
+	for _, f := range filters {
+		if f.value != "" {
+			sqlQuery += ` AND ` + f.column + ` = ?`
+			args = append(args, f.value)
+		}
+	}
+	if query.Desde != nil {
+		sqlQuery += ` AND fecha >= ?`
+		args = append(args, *query.Desde)
+	}
+	if query.Hasta != nil {
+		sqlQuery += ` AND fecha <= ?`
+		args = append(args, *query.Hasta)
+	}
+	if query.BeforeID > 0 {
+		sqlQuery += ` AND idRegistro < ?`
+		args = append(args, query.BeforeID)
+	}
+	sqlQuery += ` ORDER BY idRegistro DESC LIMIT ?`
+	args = append(args, query.Limit)
+
+	return r.queryAuditEntries(ctx, sqlQuery, args...)
+}
+
+// ListAuditChain returns entries in the order they were appended
+func (r *MySQLAuditRepository) ListAuditChain(ctx context.Context, afterID int64, limit int) ([]*entities.AuditEntry, error) {
+	query := `SELECT ` + auditColumns + ` FROM registro_auditoria WHERE idRegistro > ? ORDER BY idRegistro LIMIT ?`
+
+	return r.queryAuditEntries(ctx, query, afterID, limit)
+}
+
+// GetAuditChainHead returns the ID and hash of the last appended entry
+func (r *MySQLAuditRepository) GetAuditChainHead(ctx context.Context) (int64, string, error) {
+	var id int64
+	var hash string
+	err := r.db.QueryRowContext(ctx, `SELECT idRegistro, hash FROM cadena_auditoria WHERE id = 1`).Scan(&id, &hash)
+	if err != nil {
+		return 0, "", fmt.Errorf("error fetching audit chain head: %w", err)
+	}
+
+	return id, hash, nil
+}
+
+func (r *MySQLAuditRepository) queryAuditEntries(ctx context.Context, query string, args ...interface{}) ([]*entities.AuditEntry, error) {
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("error fetching audit entries: %w", err)
+	}
+	defer rows.Close()
+
+	entries := []*entities.AuditEntry{}
+	for rows.Next() {
+		var e entities.AuditEntry
+		var antes, despues, idSolicitud, ip sql.NullString
+		err := rows.Scan(&e.ID, &e.TipoActor, &e.IDActor, &e.Accion, &e.Recurso, &e.IDRecurso, &antes, &despues,
+			&idSolicitud, &ip, &e.Fecha, &e.HashAnterior, &e.Hash)
+		if err != nil {
+			return nil, fmt.Errorf("error scanning audit entry: %w", err)
+		}
+		if antes.Valid {
+			e.Antes = []byte(antes.String)
+		}
+		if despues.Valid {
+			e.Despues = []byte(despues.String)
+		}
+		e.IDSolicitud = idSolicitud.String
+		e.IP = ip.String
+		entries = append(entries, &e)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating audit entries: %w", err)
+	}
+
+	return entries, nil
+}
+
+// Verify interface implementation
+var _ ports.AuditRepositoryPort = (*MySQLAuditRepository)(nil)
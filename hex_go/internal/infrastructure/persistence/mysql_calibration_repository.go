@@ -0,0 +1,122 @@
+package persistence
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"hex_go/internal/domain/entities"
+	"hex_go/internal/domain/ports"
+)
+
+// calibrationColumns are the calibraciones columns read into a SensorCalibration, in scan order
+const calibrationColumns = `numero_serie, sensor, offset_valor, ganancia, linea_base, muestras,
+	inicio_aprendizaje, fin_aprendizaje, fecha_actualizacion`
+
+// MySQLCalibrationRepository implements the CalibrationRepositoryPort
+type MySQLCalibrationRepository struct {
+	db *sql.DB
+}
+
+// NewMySQLCalibrationRepository creates a new MySQL calibration repository
+func NewMySQLCalibrationRepository(db *sql.DB) *MySQLCalibrationRepository {
+	return &MySQLCalibrationRepository{
+		db: db,
+	}
+}
+
+// GetCalibration returns the calibration of a sensor of a device or entities.ErrNotFound
+func (r *MySQLCalibrationRepository) GetCalibration(ctx context.Context, numeroSerie, sensor string) (*entities.SensorCalibration, error) {
+	query := `SELECT ` + calibrationColumns + ` FROM calibraciones WHERE numero_serie = ? AND sensor = ?`
+
+	calibration, err := scanCalibration(r.db.QueryRowContext(ctx, query, numeroSerie, sensor))
+	if err == sql.ErrNoRows {
+		return nil, entities.ErrNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error fetching %s calibration of device %s: %w", sensor, numeroSerie, err)
+	}
+
+	return calibration, nil
+}
+
+// ListDeviceCalibrations returns the calibration of every sensor of a device seen so far
+func (r *MySQLCalibrationRepository) ListDeviceCalibrations(ctx context.Context, numeroSerie string) ([]*entities.SensorCalibration, error) {
+	rows, err := r.db.QueryContext(ctx,
+		`SELECT `+calibrationColumns+` FROM calibraciones WHERE numero_serie = ? ORDER BY sensor`, numeroSerie)
+	if err != nil {
+		return nil, fmt.Errorf("error fetching calibrations of device %s: %w", numeroSerie, err)
+	}
+	defer rows.Close()
+
+	calibrations := []*entities.SensorCalibration{}
+	for rows.Next() {
+		calibration, err := scanCalibration(rows)
+		if err != nil {
+			return nil, fmt.Errorf("error scanning calibration: %w", err)
+		}
+		calibrations = append(calibrations, calibration)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating calibrations: %w", err)
+	}
+
+	return calibrations, nil
+}
+
+// CreateCalibration inserts a calibration unless the sensor already has one
+func (r *MySQLCalibrationRepository) CreateCalibration(ctx context.Context, c *entities.SensorCalibration) error {
+	result, err := r.db.ExecContext(ctx,
+		`INSERT IGNORE INTO calibraciones (`+calibrationColumns+`) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+		c.NumeroSerie, c.Sensor, c.Offset, c.Ganancia, c.LineaBase, c.Muestras, c.InicioAprendizaje,
+		c.FinAprendizaje, c.FechaActualizacion)
+	if err != nil {
+		return fmt.Errorf("error creating %s calibration of device %s: %w", c.Sensor, c.NumeroSerie, err)
+	}
+
+	return expectOneRow(result, entities.ErrConflict)
+}
+
+// UpdateCalibration stores the baseline of a calibration that still has seen samples
+func (r *MySQLCalibrationRepository) UpdateCalibration(ctx context.Context, c *entities.SensorCalibration, seen int) error {
+	result, err := r.db.ExecContext(ctx,
+		`UPDATE calibraciones SET linea_base = ?, muestras = ?, fecha_actualizacion = ?
+		WHERE numero_serie = ? AND sensor = ? AND muestras = ?`,
+		c.LineaBase, c.Muestras, c.FechaActualizacion, c.NumeroSerie, c.Sensor, seen)
+	if err != nil {
+		return fmt.Errorf("error updating %s calibration of device %s: %w", c.Sensor, c.NumeroSerie, err)
+	}
+
+	return expectOneRow(result, entities.ErrConflict)
+}
+
+// SaveCalibration inserts a calibration or replaces the existing one
+func (r *MySQLCalibrationRepository) SaveCalibration(ctx context.Context, c *entities.SensorCalibration) error {
+	_, err := r.db.ExecContext(ctx,
+		`INSERT INTO calibraciones (`+calibrationColumns+`) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)
+		ON DUPLICATE KEY UPDATE offset_valor = VALUES(offset_valor), ganancia = VALUES(ganancia),
+			linea_base = VALUES(linea_base), muestras = VALUES(muestras),
+			inicio_aprendizaje = VALUES(inicio_aprendizaje), fin_aprendizaje = VALUES(fin_aprendizaje),
+			fecha_actualizacion = VALUES(fecha_actualizacion)`,
+		c.NumeroSerie, c.Sensor, c.Offset, c.Ganancia, c.LineaBase, c.Muestras, c.InicioAprendizaje,
+		c.FinAprendizaje, c.FechaActualizacion)
+	if err != nil {
+		return fmt.Errorf("error saving %s calibration of device %s: %w", c.Sensor, c.NumeroSerie, err)
+	}
+
+	return nil
+}
+
+// scanCalibration reads the calibrationColumns of a row
+func scanCalibration(row rowScanner) (*entities.SensorCalibration, error) {
+	var c entities.SensorCalibration
+	err := row.Scan(&c.NumeroSerie, &c.Sensor, &c.Offset, &c.Ganancia, &c.LineaBase, &c.Muestras,
+		&c.InicioAprendizaje, &c.FinAprendizaje, &c.FechaActualizacion)
+	if err != nil {
+		return nil, err
+	}
+	return &c, nil
+}
+
+// Verify interface implementation
+var _ ports.CalibrationRepositoryPort = (*MySQLCalibrationRepository)(nil)
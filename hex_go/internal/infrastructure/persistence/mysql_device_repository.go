@@ -0,0 +1,331 @@
+package persistence
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+
+	"hex_go/internal/domain/entities"
+	"hex_go/internal/domain/ports"
+)
+
+// deviceColumns are the ESP32 columns read into a Device, in scan order
+const deviceColumns = `numero_serie, idUser, nombre, ubicacion, sensores_instalados,
+	version_firmware, zona_horaria, fecha_registro, codigo_reclamo_hash, token_hash, ultima_conexion, en_linea, idUbicacion`
+
+// MySQLDeviceRepository implements the DeviceRepositoryPort over the ESP32 table
+type MySQLDeviceRepository struct {
+	db *sql.DB
+}
+
+// NewMySQLDeviceRepository creates a new MySQL device repository
+func NewMySQLDeviceRepository(db *sql.DB) *MySQLDeviceRepository {
+	return &MySQLDeviceRepository{
+		db: db,
+	}
+}
+
+// GetDevice returns a device by serial number or entities.ErrNotFound
+func (r *MySQLDeviceRepository) GetDevice(ctx context.Context, numeroSerie string) (*entities.Device, error) {
+	query := `SELECT ` + deviceColumns + ` FROM ESP32 WHERE numero_serie = ?`
+
+	device, err := scanDevice(r.db.QueryRowContext(ctx, query, numeroSerie))
+	if err == sql.ErrNoRows {
+		return nil, entities.ErrNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error fetching device %s: %w", numeroSerie, err)
+	}
+
+	return device, nil
+}
+
+// ListUserDevices returns the devices owned by a user
+func (r *MySQLDeviceRepository) ListUserDevices(ctx context.Context, userID int) ([]*entities.Device, error) {
+	query := `SELECT ` + deviceColumns + ` FROM ESP32 WHERE idUser = ? ORDER BY nombre, numero_serie`
+
+	rows, err := r.db.QueryContext(ctx, query, userID)
+	if err != nil {
+		return nil, fmt.Errorf("error fetching devices for user %d: %w", userID, err)
+	}
+	defer rows.Close()
+
+	devices := []*entities.Device{}
+	for rows.Next() {
+		device, err := scanDevice(rows)
+		if err != nil {
+			return nil, fmt.Errorf("error scanning device: %w", err)
+		}
+		devices = append(devices, device)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating devices: %w", err)
+	}
+
+	return devices, nil
+}
+
+// ClaimDevice assigns an unclaimed board to device.IDUser and stores its details and
+// token hash. It returns entities.ErrConflict if the board was claimed in the meantime.
+func (r *MySQLDeviceRepository) ClaimDevice(ctx context.Context, device *entities.Device) error {
+	query := `UPDATE ESP32
+		SET idUser = ?, nombre = ?, ubicacion = ?, sensores_instalados = ?,
+			version_firmware = ?, zona_horaria = ?, fecha_registro = ?, token_hash = ?
+		WHERE numero_serie = ? AND idUser IS NULL`
+
+	result, err := r.db.ExecContext(ctx, query,
+		device.IDUser, device.Nombre, device.Ubicacion, joinSensors(device.SensoresInstalados),
+		device.VersionFirmware, nullString(device.ZonaHoraria), device.FechaRegistro, device.TokenHash,
+		device.NumeroSerie)
+	if err != nil {
+		return fmt.Errorf("error claiming device %s: %w", device.NumeroSerie, err)
+	}
+
+	return expectOneRow(result, entities.ErrConflict)
+}
+
+// UpdateDevice stores the editable details of a device
+func (r *MySQLDeviceRepository) UpdateDevice(ctx context.Context, device *entities.Device) error {
+	query := `UPDATE ESP32
+		SET nombre = ?, ubicacion = ?, sensores_instalados = ?, version_firmware = ?, zona_horaria = ?
+		WHERE numero_serie = ?`
+
+	_, err := r.db.ExecContext(ctx, query,
+		device.Nombre, device.Ubicacion, joinSensors(device.SensoresInstalados),
+		device.VersionFirmware, nullString(device.ZonaHoraria), device.NumeroSerie)
+	if err != nil {
+		return fmt.Errorf("error updating device %s: %w", device.NumeroSerie, err)
+	}
+
+	return nil
+}
+
+// TransferDevice moves a device from one owner to another, takes it out of the previous
+// owner's locations and stops sharing it with their organisations. It returns
+// entities.ErrConflict if fromUserID no longer owns the device.
+func (r *MySQLDeviceRepository) TransferDevice(ctx context.Context, numeroSerie string, fromUserID, toUserID int) error {
+	query := `UPDATE ESP32 SET idUser = ?, idUbicacion = NULL WHERE numero_serie = ? AND idUser = ?`
+
+	return r.changeOwnership(ctx, numeroSerie, fromUserID, "device transferred", query, toUserID, numeroSerie, fromUserID)
+}
+
+// ReleaseDevice removes the registration of a device so it can be claimed again, revokes
+// its token and stops sharing it. The readings it sent are kept.
+func (r *MySQLDeviceRepository) ReleaseDevice(ctx context.Context, numeroSerie string, userID int) error {
+	query := `UPDATE ESP32
+		SET idUser = NULL, nombre = NULL, ubicacion = NULL, sensores_instalados = NULL, fecha_registro = NULL,
+			idUbicacion = NULL, token_hash = NULL
+		WHERE numero_serie = ? AND idUser = ?`
+
+	return r.changeOwnership(ctx, numeroSerie, userID, "device released", query, numeroSerie, userID)
+}
+
+// SetDeviceToken replaces the token hash of a device the user owns
+func (r *MySQLDeviceRepository) SetDeviceToken(ctx context.Context, numeroSerie string, userID int, tokenHash string) error {
+	result, err := r.db.ExecContext(ctx,
+		`UPDATE ESP32 SET token_hash = ? WHERE numero_serie = ? AND idUser = ?`, tokenHash, numeroSerie, userID)
+	if err != nil {
+		return fmt.Errorf("error setting token of device %s: %w", numeroSerie, err)
+	}
+
+	return expectOneRow(result, entities.ErrConflict)
+}
+
+// changeOwnership runs an ownership update and revokes the device's organisation shares in
+// the same transaction, recording each revoked share in the access audit
+func (r *MySQLDeviceRepository) changeOwnership(ctx context.Context, numeroSerie string, actor int, reason string, query string, args ...interface{}) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("error starting transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	result, err := tx.ExecContext(ctx, query, args...)
+	if err != nil {
+		return fmt.Errorf("error changing owner of device %s: %w", numeroSerie, err)
+	}
+	if err := expectOneRow(result, entities.ErrConflict); err != nil {
+		return err
+	}
+
+	_, err = tx.ExecContext(ctx,
+		`INSERT INTO auditoria_accesos (idOrganizacion, actor, accion, objetivo, detalle, fecha)
+		SELECT idOrganizacion, ?, ?, CONCAT('device:', numero_serie), ?, ?
+		FROM dispositivos_compartidos WHERE numero_serie = ?`,
+		actor, entities.AuditDeviceUnshared, reason, time.Now().UTC(), numeroSerie)
+	if err != nil {
+		return fmt.Errorf("error recording revoked shares of device %s: %w", numeroSerie, err)
+	}
+	if _, err := tx.ExecContext(ctx, `DELETE FROM dispositivos_compartidos WHERE numero_serie = ?`, numeroSerie); err != nil {
+		return fmt.Errorf("error revoking shares of device %s: %w", numeroSerie, err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("error committing transaction: %w", err)
+	}
+	return nil
+}
+
+// RecordHeartbeat marks a device as online and moves its last-seen time forward, ending
+// the period offline it was in, if any. An empty versionFirmware keeps the stored version.
+func (r *MySQLDeviceRepository) RecordHeartbeat(ctx context.Context, numeroSerie string, at time.Time, versionFirmware string) error {
+	query := `UPDATE ESP32
+		SET ultima_conexion = GREATEST(COALESCE(ultima_conexion, ?), ?),
+			en_linea = 1,
+			version_firmware = COALESCE(NULLIF(?, ''), version_firmware)
+		WHERE numero_serie = ?`
+
+	_, err := r.db.ExecContext(ctx, query, at, at, versionFirmware, numeroSerie)
+	if err != nil {
+		return fmt.Errorf("error recording heartbeat for device %s: %w", numeroSerie, err)
+	}
+
+	_, err = r.db.ExecContext(ctx, `UPDATE desconexiones SET fin = ? WHERE numero_serie = ? AND fin IS NULL`,
+		at, numeroSerie)
+	if err != nil {
+		return fmt.Errorf("error ending offline period of device %s: %w", numeroSerie, err)
+	}
+
+	return nil
+}
+
+// ListOfflineCandidates returns the online devices that have not been seen since lastSeenBefore
+func (r *MySQLDeviceRepository) ListOfflineCandidates(ctx context.Context, lastSeenBefore time.Time) ([]*entities.Device, error) {
+	query := `SELECT ` + deviceColumns + ` FROM ESP32 WHERE en_linea = 1 AND ultima_conexion < ?`
+
+	rows, err := r.db.QueryContext(ctx, query, lastSeenBefore)
+	if err != nil {
+		return nil, fmt.Errorf("error fetching stale devices: %w", err)
+	}
+	defer rows.Close()
+
+	var devices []*entities.Device
+	for rows.Next() {
+		device, err := scanDevice(rows)
+		if err != nil {
+			return nil, fmt.Errorf("error scanning device: %w", err)
+		}
+		devices = append(devices, device)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating devices: %w", err)
+	}
+
+	return devices, nil
+}
+
+// MarkDeviceOffline flags a device as offline if it is still online and has not been seen
+// since lastSeenBefore, and opens its period offline from the last contact. It reports
+// whether this call changed the device, so concurrent sweepers raise a single alert.
+func (r *MySQLDeviceRepository) MarkDeviceOffline(ctx context.Context, numeroSerie string, lastSeenBefore time.Time) (bool, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return false, fmt.Errorf("error starting transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	query := `UPDATE ESP32 SET en_linea = 0
+		WHERE numero_serie = ? AND en_linea = 1 AND ultima_conexion < ?`
+
+	result, err := tx.ExecContext(ctx, query, numeroSerie, lastSeenBefore)
+	if err != nil {
+		return false, fmt.Errorf("error marking device %s offline: %w", numeroSerie, err)
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("error reading affected rows: %w", err)
+	}
+	if affected == 0 {
+		return false, nil
+	}
+
+	_, err = tx.ExecContext(ctx,
+		`INSERT INTO desconexiones (numero_serie, inicio) SELECT numero_serie, ultima_conexion FROM ESP32 WHERE numero_serie = ?`,
+		numeroSerie)
+	if err != nil {
+		return false, fmt.Errorf("error opening offline period of device %s: %w", numeroSerie, err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return false, fmt.Errorf("error committing transaction: %w", err)
+	}
+	return true, nil
+}
+
+// rowScanner is implemented by both *sql.Row and *sql.Rows
+type rowScanner interface {
+	Scan(dest ...interface{}) error
+}
+
+func scanDevice(row rowScanner) (*entities.Device, error) {
+	var device entities.Device
+	var idUser, idUbicacion sql.NullInt64
+	var nombre, ubicacion, sensores, firmware, zonaHoraria, codigoHash, tokenHash sql.NullString
+	var fechaRegistro, ultimaConexion sql.NullTime
+
+	err := row.Scan(&device.NumeroSerie, &idUser, &nombre, &ubicacion, &sensores,
+		&firmware, &zonaHoraria, &fechaRegistro, &codigoHash, &tokenHash, &ultimaConexion, &device.EnLinea, &idUbicacion)
+	if err != nil {
+		return nil, err
+	}
+
+	if idUser.Valid {
+		id := int(idUser.Int64)
+		device.IDUser = &id
+	}
+	if fechaRegistro.Valid {
+		device.FechaRegistro = &fechaRegistro.Time
+	}
+	if idUbicacion.Valid {
+		device.IDUbicacion = &idUbicacion.Int64
+	}
+	if ultimaConexion.Valid {
+		device.UltimaConexion = &ultimaConexion.Time
+	}
+	device.Nombre = nombre.String
+	device.Ubicacion = ubicacion.String
+	device.SensoresInstalados = splitSensors(sensores.String)
+	device.VersionFirmware = firmware.String
+	device.ZonaHoraria = zonaHoraria.String
+	device.CodigoReclamoHash = codigoHash.String
+	device.TokenHash = tokenHash.String
+
+	return &device, nil
+}
+
+// joinSensors stores the installed sensors as a comma separated list
+func joinSensors(sensors []string) string {
+	return strings.Join(sensors, ",")
+}
+
+func splitSensors(value string) []string {
+	if value == "" {
+		return []string{}
+	}
+	return strings.Split(value, ",")
+}
+
+// nullString stores empty strings as NULL
+func nullString(value string) sql.NullString {
+	return sql.NullString{String: value, Valid: value != ""}
+}
+
+// expectOneRow returns errNone when a statement did not change any row
+func expectOneRow(result sql.Result, errNone error) error {
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("error reading affected rows: %w", err)
+	}
+	if affected == 0 {
+		return errNone
+	}
+	return nil
+}
+
+// Verify interface implementation
+var _ ports.DeviceRepositoryPort = (*MySQLDeviceRepository)(nil)
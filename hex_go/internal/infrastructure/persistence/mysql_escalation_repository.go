@@ -0,0 +1,326 @@
+package persistence
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+
+	"hex_go/internal/domain/entities"
+	"hex_go/internal/domain/ports"
+)
+
+// policyColumns are the politicas_escalacion columns read into an EscalationPolicy, in scan order
+const policyColumns = `p.idPolitica, p.idUser, p.nombre, p.numero_serie, p.idUbicacion, p.severidades,
+	p.activa, p.fecha_creacion`
+
+// escalationColumns are the escalaciones columns read into an Escalation, in scan order
+const escalationColumns = `idEscalacion, idAlerta, idPolitica, siguiente_paso, proximo_paso, estado,
+	fecha_creacion, fecha_fin`
+
+// MySQLEscalationRepository implements the EscalationRepositoryPort
+type MySQLEscalationRepository struct {
+	db *sql.DB
+}
+
+// NewMySQLEscalationRepository creates a new MySQL escalation repository
+func NewMySQLEscalationRepository(db *sql.DB) *MySQLEscalationRepository {
+	return &MySQLEscalationRepository{
+		db: db,
+	}
+}
+
+// CreatePolicy inserts a policy and its steps in one transaction and sets its ID
+func (r *MySQLEscalationRepository) CreatePolicy(ctx context.Context, policy *entities.EscalationPolicy) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("error starting transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	result, err := tx.ExecContext(ctx,
+		`INSERT INTO politicas_escalacion (idUser, nombre, numero_serie, idUbicacion, severidades, activa, fecha_creacion)
+		VALUES (?, ?, ?, ?, ?, ?, ?)`,
+		policy.IDUser, policy.Nombre, policy.NumeroSerie, policy.IDUbicacion,
+		strings.Join(policy.Severidades, ","), policy.Activa, policy.FechaCreacion)
+	if err != nil {
+		return fmt.Errorf("error creating escalation policy: %w", err)
+	}
+	if policy.ID, err = result.LastInsertId(); err != nil {
+		return fmt.Errorf("error reading escalation policy ID: %w", err)
+	}
+	if err := insertSteps(ctx, tx, policy); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("error committing escalation policy: %w", err)
+	}
+	return nil
+}
+
+// GetPolicy returns a policy with its steps by ID or entities.ErrNotFound
+func (r *MySQLEscalationRepository) GetPolicy(ctx context.Context, id int64) (*entities.EscalationPolicy, error) {
+	query := `SELECT ` + policyColumns + ` FROM politicas_escalacion p WHERE p.idPolitica = ?`
+
+	policies, err := r.queryPolicies(ctx, query, id)
+	if err != nil {
+		return nil, err
+	}
+	if len(policies) == 0 {
+		return nil, entities.ErrNotFound
+	}
+
+	return policies[0], nil
+}
+
+// ListUserPolicies returns every policy of a user
+func (r *MySQLEscalationRepository) ListUserPolicies(ctx context.Context, userID int) ([]*entities.EscalationPolicy, error) {
+	query := `SELECT ` + policyColumns + ` FROM politicas_escalacion p WHERE p.idUser = ? ORDER BY p.fecha_creacion`
+
+	return r.queryPolicies(ctx, query, userID)
+}
+
+// UpdatePolicy stores a policy and replaces its steps in one transaction
+func (r *MySQLEscalationRepository) UpdatePolicy(ctx context.Context, policy *entities.EscalationPolicy) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("error starting transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	_, err = tx.ExecContext(ctx,
+		`UPDATE politicas_escalacion SET nombre = ?, numero_serie = ?, idUbicacion = ?, severidades = ?, activa = ?
+		WHERE idPolitica = ?`,
+		policy.Nombre, policy.NumeroSerie, policy.IDUbicacion, strings.Join(policy.Severidades, ","),
+		policy.Activa, policy.ID)
+	if err != nil {
+		return fmt.Errorf("error updating escalation policy %d: %w", policy.ID, err)
+	}
+	if _, err := tx.ExecContext(ctx, `DELETE FROM pasos_escalacion WHERE idPolitica = ?`, policy.ID); err != nil {
+		return fmt.Errorf("error clearing steps of escalation policy %d: %w", policy.ID, err)
+	}
+	if err := insertSteps(ctx, tx, policy); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("error committing escalation policy: %w", err)
+	}
+	return nil
+}
+
+// DeletePolicy removes a policy together with its steps and escalations
+func (r *MySQLEscalationRepository) DeletePolicy(ctx context.Context, id int64) error {
+	result, err := r.db.ExecContext(ctx, `DELETE FROM politicas_escalacion WHERE idPolitica = ?`, id)
+	if err != nil {
+		return fmt.Errorf("error deleting escalation policy %d: %w", id, err)
+	}
+
+	return expectOneRow(result, entities.ErrNotFound)
+}
+
+// ListPoliciesForDevice returns the active policies of the device owner that cover the
+// device or a location whose subtree contains it
+func (r *MySQLEscalationRepository) ListPoliciesForDevice(ctx context.Context, numeroSerie string) ([]*entities.EscalationPolicy, error) {
+	query := `SELECT ` + policyColumns + ` FROM politicas_escalacion p
+		JOIN ESP32 e ON e.numero_serie = ? AND e.idUser = p.idUser
+		LEFT JOIN ubicaciones u ON u.idUbicacion = e.idUbicacion
+		LEFT JOIN ubicaciones pu ON pu.idUbicacion = p.idUbicacion
+		WHERE p.activa = TRUE
+			AND (p.numero_serie = e.numero_serie OR (u.ruta IS NOT NULL AND u.ruta LIKE CONCAT(pu.ruta, '%')))`
+
+	return r.queryPolicies(ctx, query, numeroSerie)
+}
+
+// CreateEscalation inserts an escalation and sets its ID. It returns entities.ErrConflict
+// when the policy already runs for the alert.
+func (r *MySQLEscalationRepository) CreateEscalation(ctx context.Context, escalation *entities.Escalation) error {
+	result, err := r.db.ExecContext(ctx,
+		`INSERT IGNORE INTO escalaciones (idAlerta, idPolitica, siguiente_paso, proximo_paso, estado, fecha_creacion)
+		VALUES (?, ?, ?, ?, ?, ?)`,
+		escalation.IDAlerta, escalation.IDPolitica, escalation.SiguientePaso, escalation.ProximoPaso,
+		escalation.Estado, escalation.FechaCreacion)
+	if err != nil {
+		return fmt.Errorf("error creating escalation: %w", err)
+	}
+	if err := expectOneRow(result, entities.ErrConflict); err != nil {
+		return err
+	}
+	if escalation.ID, err = result.LastInsertId(); err != nil {
+		return fmt.Errorf("error reading escalation ID: %w", err)
+	}
+
+	return nil
+}
+
+// ListAlertEscalations returns the escalations of an alert
+func (r *MySQLEscalationRepository) ListAlertEscalations(ctx context.Context, alertID int64) ([]*entities.Escalation, error) {
+	query := `SELECT ` + escalationColumns + ` FROM escalaciones WHERE idAlerta = ? ORDER BY idEscalacion`
+
+	return r.queryEscalations(ctx, query, alertID)
+}
+
+// ListDueEscalations returns the pending escalations whose next step is due, oldest first
+func (r *MySQLEscalationRepository) ListDueEscalations(ctx context.Context, now time.Time, limit int) ([]*entities.Escalation, error) {
+	query := `SELECT ` + escalationColumns + ` FROM escalaciones
+		WHERE estado = ? AND proximo_paso <= ? ORDER BY proximo_paso LIMIT ?`
+
+	return r.queryEscalations(ctx, query, entities.EscalationPending, now, limit)
+}
+
+// AdvanceEscalation moves an escalation to its next step if no one else did it first
+func (r *MySQLEscalationRepository) AdvanceEscalation(ctx context.Context, escalation *entities.Escalation, nextStep int, next *time.Time) (bool, error) {
+	estado := entities.EscalationPending
+	var fechaFin *time.Time
+	if next == nil {
+		now := time.Now().UTC()
+		estado = entities.EscalationCompleted
+		fechaFin = &now
+	}
+
+	result, err := r.db.ExecContext(ctx,
+		`UPDATE escalaciones SET siguiente_paso = ?, proximo_paso = ?, estado = ?, fecha_fin = ?
+		WHERE idEscalacion = ? AND estado = ? AND siguiente_paso = ?`,
+		nextStep, next, estado, fechaFin, escalation.ID, entities.EscalationPending, escalation.SiguientePaso)
+	if err != nil {
+		return false, fmt.Errorf("error advancing escalation %d: %w", escalation.ID, err)
+	}
+	if err := expectOneRow(result, entities.ErrConflict); err != nil {
+		if err == entities.ErrConflict {
+			return false, nil
+		}
+		return false, err
+	}
+
+	escalation.SiguientePaso = nextStep
+	escalation.ProximoPaso = next
+	escalation.Estado = estado
+	escalation.FechaFin = fechaFin
+	return true, nil
+}
+
+// StopEscalations stops the pending escalations of an alert
+func (r *MySQLEscalationRepository) StopEscalations(ctx context.Context, alertID int64, at time.Time) error {
+	_, err := r.db.ExecContext(ctx,
+		`UPDATE escalaciones SET estado = ?, proximo_paso = NULL, fecha_fin = ? WHERE idAlerta = ? AND estado = ?`,
+		entities.EscalationStopped, at, alertID, entities.EscalationPending)
+	if err != nil {
+		return fmt.Errorf("error stopping escalations of alert %d: %w", alertID, err)
+	}
+	return nil
+}
+
+// insertSteps stores the steps of a policy in their order
+func insertSteps(ctx context.Context, tx *sql.Tx, policy *entities.EscalationPolicy) error {
+	for i, step := range policy.Pasos {
+		_, err := tx.ExecContext(ctx,
+			`INSERT INTO pasos_escalacion (idPolitica, orden, nombre, espera_minutos, canal, direccion, idioma)
+			VALUES (?, ?, ?, ?, ?, ?, ?)`,
+			policy.ID, i, step.Nombre, step.EsperaMinutos, step.Canal, step.Direccion, step.Idioma)
+		if err != nil {
+			return fmt.Errorf("error storing step %d of escalation policy %d: %w", i, policy.ID, err)
+		}
+	}
+	return nil
+}
+
+// queryPolicies runs a policy query and loads the steps of every policy found
+func (r *MySQLEscalationRepository) queryPolicies(ctx context.Context, query string, args ...interface{}) ([]*entities.EscalationPolicy, error) {
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("error fetching escalation policies: %w", err)
+	}
+	defer rows.Close()
+
+	policies := []*entities.EscalationPolicy{}
+	byID := make(map[int64]*entities.EscalationPolicy)
+	for rows.Next() {
+		var p entities.EscalationPolicy
+		var numeroSerie, severidades sql.NullString
+		var idUbicacion sql.NullInt64
+		if err := rows.Scan(&p.ID, &p.IDUser, &p.Nombre, &numeroSerie, &idUbicacion, &severidades,
+			&p.Activa, &p.FechaCreacion); err != nil {
+			return nil, fmt.Errorf("error scanning escalation policy: %w", err)
+		}
+		if numeroSerie.Valid {
+			p.NumeroSerie = &numeroSerie.String
+		}
+		if idUbicacion.Valid {
+			p.IDUbicacion = &idUbicacion.Int64
+		}
+		p.Severidades = splitSensors(severidades.String)
+		p.Pasos = []*entities.EscalationStep{}
+		policies = append(policies, &p)
+		byID[p.ID] = &p
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating escalation policies: %w", err)
+	}
+	if len(policies) == 0 {
+		return policies, nil
+	}
+
+	placeholders := make([]string, len(policies))
+	ids := make([]interface{}, len(policies))
+	for i, p := range policies {
+		placeholders[i] = "?"
+		ids[i] = p.ID
+	}
+	stepRows, err := r.db.QueryContext(ctx,
+		`SELECT idPolitica, nombre, espera_minutos, canal, direccion, idioma FROM pasos_escalacion
+		WHERE idPolitica IN (`+strings.Join(placeholders, ",")+`) ORDER BY idPolitica, orden`, ids...)
+	if err != nil {
+		return nil, fmt.Errorf("error fetching escalation steps: %w", err)
+	}
+	defer stepRows.Close()
+
+	for stepRows.Next() {
+		var policyID int64
+		var step entities.EscalationStep
+		if err := stepRows.Scan(&policyID, &step.Nombre, &step.EsperaMinutos, &step.Canal, &step.Direccion, &step.Idioma); err != nil {
+			return nil, fmt.Errorf("error scanning escalation step: %w", err)
+		}
+		byID[policyID].Pasos = append(byID[policyID].Pasos, &step)
+	}
+	if err := stepRows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating escalation steps: %w", err)
+	}
+
+	return policies, nil
+}
+
+func (r *MySQLEscalationRepository) queryEscalations(ctx context.Context, query string, args ...interface{}) ([]*entities.Escalation, error) {
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("error fetching escalations: %w", err)
+	}
+	defer rows.Close()
+
+	escalations := []*entities.Escalation{}
+	for rows.Next() {
+		var e entities.Escalation
+		var proximoPaso, fechaFin sql.NullTime
+		if err := rows.Scan(&e.ID, &e.IDAlerta, &e.IDPolitica, &e.SiguientePaso, &proximoPaso, &e.Estado,
+			&e.FechaCreacion, &fechaFin); err != nil {
+			return nil, fmt.Errorf("error scanning escalation: %w", err)
+		}
+		if proximoPaso.Valid {
+			e.ProximoPaso = &proximoPaso.Time
+		}
+		if fechaFin.Valid {
+			e.FechaFin = &fechaFin.Time
+		}
+		escalations = append(escalations, &e)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating escalations: %w", err)
+	}
+
+	return escalations, nil
+}
+
+// Verify interface implementation
+var _ ports.EscalationRepositoryPort = (*MySQLEscalationRepository)(nil)
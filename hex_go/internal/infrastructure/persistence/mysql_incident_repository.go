@@ -0,0 +1,377 @@
+package persistence
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"hex_go/internal/domain/entities"
+	"hex_go/internal/domain/ports"
+)
+
+// incidentColumns are the incidentes columns read into an Incident, in scan order
+const incidentColumns = `i.idIncidente, i.idUbicacion, i.numero_serie, i.severidad, i.estado, i.total_alertas,
+	i.fecha_inicio, i.fecha_ultima, i.reconocido_por, i.fecha_reconocimiento, i.resuelto_por, i.fecha_resolucion`
+
+// visibleIncidentCondition keeps the incidents with an alert of a device the user can see
+const visibleIncidentCondition = `EXISTS (SELECT 1 FROM alertas a WHERE a.idIncidente = i.idIncidente
+		AND a.numero_serie IN (` + accessibleDevicesQuery + `))`
+
+// MySQLIncidentRepository implements the IncidentRepositoryPort
+type MySQLIncidentRepository struct {
+	db *sql.DB
+}
+
+// NewMySQLIncidentRepository creates a new MySQL incident repository
+func NewMySQLIncidentRepository(db *sql.DB) *MySQLIncidentRepository {
+	return &MySQLIncidentRepository{
+		db: db,
+	}
+}
+
+// FindCorrelating returns the incident holding the correlation key or entities.ErrNotFound
+func (r *MySQLIncidentRepository) FindCorrelating(ctx context.Context, key string) (*entities.Incident, error) {
+	query := `SELECT ` + incidentColumns + ` FROM incidentes i WHERE i.clave = ?`
+
+	incident, err := scanIncident(r.db.QueryRowContext(ctx, query, key))
+	if err == sql.ErrNoRows {
+		return nil, entities.ErrNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error fetching incident of %s: %w", key, err)
+	}
+
+	return incident, nil
+}
+
+// CreateIncident inserts an incident holding the key, links its first alert and opens
+// its timeline in one transaction
+func (r *MySQLIncidentRepository) CreateIncident(ctx context.Context, incident *entities.Incident, key string, alert *entities.Alert) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("error starting transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	result, err := tx.ExecContext(ctx,
+		`INSERT IGNORE INTO incidentes (clave, idUbicacion, numero_serie, severidad, estado, total_alertas,
+			fecha_inicio, fecha_ultima)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?)`,
+		key, incident.IDUbicacion, incident.NumeroSerie, incident.Severidad, incident.Estado,
+		incident.TotalAlertas, incident.FechaInicio, incident.FechaUltima)
+	if err != nil {
+		return fmt.Errorf("error creating incident: %w", err)
+	}
+	if err := expectOneRow(result, entities.ErrConflict); err != nil {
+		return err
+	}
+	if incident.ID, err = result.LastInsertId(); err != nil {
+		return fmt.Errorf("error reading incident ID: %w", err)
+	}
+
+	if err := linkAlert(ctx, tx, incident.ID, alert, entities.IncidentEventOpened); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("error committing incident: %w", err)
+	}
+	return nil
+}
+
+// AddAlert links an alert to the incident holding the key and adds it to the timeline
+func (r *MySQLIncidentRepository) AddAlert(ctx context.Context, incident *entities.Incident, key string, alert *entities.Alert, severityRaised bool) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("error starting transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	result, err := tx.ExecContext(ctx,
+		`UPDATE incidentes SET severidad = ?, total_alertas = total_alertas + 1,
+			fecha_ultima = GREATEST(fecha_ultima, ?)
+		WHERE idIncidente = ? AND clave = ? AND estado <> ?`,
+		incident.Severidad, alert.FechaCreacion, incident.ID, key, entities.IncidentResolved)
+	if err != nil {
+		return fmt.Errorf("error updating incident %d: %w", incident.ID, err)
+	}
+	if err := expectOneRow(result, entities.ErrConflict); err != nil {
+		return err
+	}
+
+	event := entities.IncidentEventAlertAdded
+	if severityRaised {
+		event = entities.IncidentEventSeverityRaised
+	}
+	if err := linkAlert(ctx, tx, incident.ID, alert, event); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("error committing incident %d: %w", incident.ID, err)
+	}
+	return nil
+}
+
+// ReleaseKey clears the correlation key of an incident if it still holds it
+func (r *MySQLIncidentRepository) ReleaseKey(ctx context.Context, id int64, key string) error {
+	_, err := r.db.ExecContext(ctx, `UPDATE incidentes SET clave = NULL WHERE idIncidente = ? AND clave = ?`, id, key)
+	if err != nil {
+		return fmt.Errorf("error releasing incident %d: %w", id, err)
+	}
+	return nil
+}
+
+// GetUserIncident returns a visible incident with its alerts and timeline, and the strongest
+// role the user holds on the devices of its alerts, or entities.ErrNotFound
+func (r *MySQLIncidentRepository) GetUserIncident(ctx context.Context, userID int, id int64) (*entities.Incident, string, error) {
+	query := `SELECT ` + incidentColumns + `, r.rol FROM incidentes i
+		JOIN alertas a ON a.idIncidente = i.idIncidente
+		JOIN (` + deviceRolesQuery + `) r ON r.numero_serie = a.numero_serie
+		WHERE i.idIncidente = ?
+		ORDER BY ` + strongestRoleFirst + ` LIMIT 1`
+
+	var role string
+	incident, err := scanIncident(roleScanner{r.db.QueryRowContext(ctx, query, userID, userID, id), &role})
+	if err == sql.ErrNoRows {
+		return nil, "", entities.ErrNotFound
+	}
+	if err != nil {
+		return nil, "", fmt.Errorf("error fetching incident %d: %w", id, err)
+	}
+
+	if incident.Alertas, err = r.incidentAlerts(ctx, id); err != nil {
+		return nil, "", err
+	}
+	if incident.Eventos, err = r.incidentEvents(ctx, id); err != nil {
+		return nil, "", err
+	}
+
+	return incident, role, nil
+}
+
+// ListUserIncidents returns the latest visible incidents, newest first
+func (r *MySQLIncidentRepository) ListUserIncidents(ctx context.Context, userID int, filter *entities.IncidentFilter) ([]*entities.Incident, error) {
+	query := `SELECT ` + incidentColumns + ` FROM incidentes i WHERE ` + visibleIncidentCondition
+	args := []interface{}{userID, userID}
+	if filter.Estado != "" {
+		query += ` AND i.estado = ?`
+		args = append(args, filter.Estado)
+	}
+	if filter.LocationID != nil {
+		query += ` AND i.idUbicacion IN (SELECT idUbicacion FROM ubicaciones
+			WHERE ruta LIKE CONCAT((SELECT ruta FROM ubicaciones WHERE idUbicacion = ?), '%'))`
+		args = append(args, *filter.LocationID)
+	}
+	query += ` ORDER BY i.idIncidente DESC LIMIT ?`
+	args = append(args, filter.Limit)
+
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("error fetching incidents of user %d: %w", userID, err)
+	}
+	defer rows.Close()
+
+	incidents := []*entities.Incident{}
+	for rows.Next() {
+		incident, err := scanIncident(rows)
+		if err != nil {
+			return nil, fmt.Errorf("error scanning incident: %w", err)
+		}
+		incidents = append(incidents, incident)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating incidents: %w", err)
+	}
+
+	return incidents, nil
+}
+
+// AcknowledgeIncident acknowledges an open incident and its active alerts in one transaction
+func (r *MySQLIncidentRepository) AcknowledgeIncident(ctx context.Context, id int64, userID int, at time.Time) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("error starting transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	result, err := tx.ExecContext(ctx,
+		`UPDATE incidentes SET estado = ?, reconocido_por = ?, fecha_reconocimiento = ?
+		WHERE idIncidente = ? AND estado = ?`,
+		entities.IncidentAcknowledged, userID, at, id, entities.IncidentOpen)
+	if err != nil {
+		return fmt.Errorf("error acknowledging incident %d: %w", id, err)
+	}
+	if err := expectOneRow(result, entities.ErrConflict); err != nil {
+		return err
+	}
+
+	_, err = tx.ExecContext(ctx,
+		`UPDATE alertas SET estado = ?, reconocida_por = ?, fecha_reconocimiento = ?
+		WHERE idIncidente = ? AND estado = ?`,
+		entities.AlertStateAcknowledged, userID, at, id, entities.AlertStateActive)
+	if err != nil {
+		return fmt.Errorf("error acknowledging alerts of incident %d: %w", id, err)
+	}
+	if err := insertIncidentEvent(ctx, tx, id, entities.IncidentEventAcknowledged, nil, &userID, "", at); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("error committing incident %d: %w", id, err)
+	}
+	return nil
+}
+
+// ResolveIncident resolves an incident and its alerts and releases its key in one transaction
+func (r *MySQLIncidentRepository) ResolveIncident(ctx context.Context, id int64, userID int, at time.Time) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("error starting transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	result, err := tx.ExecContext(ctx,
+		`UPDATE incidentes SET estado = ?, clave = NULL, resuelto_por = ?, fecha_resolucion = ?
+		WHERE idIncidente = ? AND estado <> ?`,
+		entities.IncidentResolved, userID, at, id, entities.IncidentResolved)
+	if err != nil {
+		return fmt.Errorf("error resolving incident %d: %w", id, err)
+	}
+	if err := expectOneRow(result, entities.ErrConflict); err != nil {
+		return err
+	}
+
+	_, err = tx.ExecContext(ctx,
+		`UPDATE alertas SET estado = ? WHERE idIncidente = ? AND estado <> ?`,
+		entities.AlertStateResolved, id, entities.AlertStateResolved)
+	if err != nil {
+		return fmt.Errorf("error resolving alerts of incident %d: %w", id, err)
+	}
+	if err := insertIncidentEvent(ctx, tx, id, entities.IncidentEventResolved, nil, &userID, "", at); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("error committing incident %d: %w", id, err)
+	}
+	return nil
+}
+
+// incidentAlerts returns the alerts of an incident, oldest first
+func (r *MySQLIncidentRepository) incidentAlerts(ctx context.Context, id int64) ([]*entities.Alert, error) {
+	rows, err := r.db.QueryContext(ctx,
+		`SELECT `+alertColumns+` FROM alertas a WHERE a.idIncidente = ? ORDER BY a.fecha_creacion, a.idAlerta`, id)
+	if err != nil {
+		return nil, fmt.Errorf("error fetching alerts of incident %d: %w", id, err)
+	}
+	defer rows.Close()
+
+	alerts := []*entities.Alert{}
+	for rows.Next() {
+		alert, err := scanAlert(rows)
+		if err != nil {
+			return nil, fmt.Errorf("error scanning alert: %w", err)
+		}
+		alerts = append(alerts, alert)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating alerts: %w", err)
+	}
+
+	return alerts, nil
+}
+
+// incidentEvents returns the timeline of an incident in order
+func (r *MySQLIncidentRepository) incidentEvents(ctx context.Context, id int64) ([]*entities.IncidentEvent, error) {
+	rows, err := r.db.QueryContext(ctx,
+		`SELECT idEvento, tipo, idAlerta, idUser, detalle, fecha FROM eventos_incidente
+		WHERE idIncidente = ? ORDER BY idEvento`, id)
+	if err != nil {
+		return nil, fmt.Errorf("error fetching timeline of incident %d: %w", id, err)
+	}
+	defer rows.Close()
+
+	events := []*entities.IncidentEvent{}
+	for rows.Next() {
+		var event entities.IncidentEvent
+		var idAlerta, idUser sql.NullInt64
+		if err := rows.Scan(&event.ID, &event.Tipo, &idAlerta, &idUser, &event.Detalle, &event.Fecha); err != nil {
+			return nil, fmt.Errorf("error scanning incident event: %w", err)
+		}
+		if idAlerta.Valid {
+			event.IDAlerta = &idAlerta.Int64
+		}
+		if idUser.Valid {
+			userID := int(idUser.Int64)
+			event.IDUser = &userID
+		}
+		events = append(events, &event)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating incident events: %w", err)
+	}
+
+	return events, nil
+}
+
+// linkAlert assigns an alert to an incident and records it in the timeline
+func linkAlert(ctx context.Context, tx *sql.Tx, incidentID int64, alert *entities.Alert, event string) error {
+	if _, err := tx.ExecContext(ctx, `UPDATE alertas SET idIncidente = ? WHERE idAlerta = ?`, incidentID, alert.ID); err != nil {
+		return fmt.Errorf("error linking alert %d to incident %d: %w", alert.ID, incidentID, err)
+	}
+	detail := truncate(alert.NumeroSerie+": "+alert.Mensaje, 255)
+	return insertIncidentEvent(ctx, tx, incidentID, event, &alert.ID, nil, detail, alert.FechaCreacion)
+}
+
+// insertIncidentEvent adds an entry to the timeline of an incident
+func insertIncidentEvent(ctx context.Context, tx *sql.Tx, incidentID int64, tipo string, alertID *int64, userID *int, detail string, at time.Time) error {
+	_, err := tx.ExecContext(ctx,
+		`INSERT INTO eventos_incidente (idIncidente, tipo, idAlerta, idUser, detalle, fecha) VALUES (?, ?, ?, ?, ?, ?)`,
+		incidentID, tipo, alertID, userID, detail, at)
+	if err != nil {
+		return fmt.Errorf("error recording %s event of incident %d: %w", tipo, incidentID, err)
+	}
+	return nil
+}
+
+// scanIncident reads the incidentColumns of a row
+func scanIncident(row rowScanner) (*entities.Incident, error) {
+	var incident entities.Incident
+	var idUbicacion, reconocidoPor, resueltoPor sql.NullInt64
+	var numeroSerie sql.NullString
+	var fechaReconocimiento, fechaResolucion sql.NullTime
+	err := row.Scan(&incident.ID, &idUbicacion, &numeroSerie, &incident.Severidad, &incident.Estado,
+		&incident.TotalAlertas, &incident.FechaInicio, &incident.FechaUltima, &reconocidoPor,
+		&fechaReconocimiento, &resueltoPor, &fechaResolucion)
+	if err != nil {
+		return nil, err
+	}
+
+	if idUbicacion.Valid {
+		incident.IDUbicacion = &idUbicacion.Int64
+	}
+	if numeroSerie.Valid {
+		incident.NumeroSerie = &numeroSerie.String
+	}
+	if reconocidoPor.Valid {
+		userID := int(reconocidoPor.Int64)
+		incident.ReconocidoPor = &userID
+	}
+	if fechaReconocimiento.Valid {
+		incident.FechaReconocimiento = &fechaReconocimiento.Time
+	}
+	if resueltoPor.Valid {
+		userID := int(resueltoPor.Int64)
+		incident.ResueltoPor = &userID
+	}
+	if fechaResolucion.Valid {
+		incident.FechaResolucion = &fechaResolucion.Time
+	}
+
+	return &incident, nil
+}
+
+// Verify interface implementation
+var _ ports.IncidentRepositoryPort = (*MySQLIncidentRepository)(nil)
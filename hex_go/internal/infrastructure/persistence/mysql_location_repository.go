@@ -0,0 +1,250 @@
+package persistence
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"hex_go/internal/domain/entities"
+	"hex_go/internal/domain/ports"
+)
+
+// locationColumns are the ubicaciones columns read into a Location, in scan order
+const locationColumns = `idUbicacion, idPadre, nivel, nombre, direccion, idUser, ruta`
+
+// MySQLLocationRepository implements the LocationRepositoryPort over the ubicaciones table
+type MySQLLocationRepository struct {
+	db *sql.DB
+}
+
+// NewMySQLLocationRepository creates a new MySQL location repository
+func NewMySQLLocationRepository(db *sql.DB) *MySQLLocationRepository {
+	return &MySQLLocationRepository{
+		db: db,
+	}
+}
+
+// CreateLocation inserts a location below its parent and sets its ID and path
+func (r *MySQLLocationRepository) CreateLocation(ctx context.Context, location *entities.Location) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("error starting transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	parentPath := "/"
+	if location.IDPadre != nil {
+		err := tx.QueryRowContext(ctx, `SELECT ruta FROM ubicaciones WHERE idUbicacion = ?`, *location.IDPadre).Scan(&parentPath)
+		if err == sql.ErrNoRows {
+			return entities.ErrNotFound
+		}
+		if err != nil {
+			return fmt.Errorf("error fetching parent location: %w", err)
+		}
+	}
+
+	result, err := tx.ExecContext(ctx,
+		`INSERT INTO ubicaciones (idPadre, nivel, nombre, direccion, idUser, ruta) VALUES (?, ?, ?, ?, ?, '')`,
+		location.IDPadre, location.Nivel, location.Nombre, nullString(location.Direccion), location.IDUser)
+	if err != nil {
+		return fmt.Errorf("error creating location: %w", err)
+	}
+
+	id, err := result.LastInsertId()
+	if err != nil {
+		return fmt.Errorf("error reading location ID: %w", err)
+	}
+
+	path := fmt.Sprintf("%s%d/", parentPath, id)
+	if _, err := tx.ExecContext(ctx, `UPDATE ubicaciones SET ruta = ? WHERE idUbicacion = ?`, path, id); err != nil {
+		return fmt.Errorf("error storing location path: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("error committing location: %w", err)
+	}
+
+	location.ID = id
+	location.Ruta = path
+	return nil
+}
+
+// GetLocation returns a location by ID or entities.ErrNotFound
+func (r *MySQLLocationRepository) GetLocation(ctx context.Context, id int64) (*entities.Location, error) {
+	query := `SELECT ` + locationColumns + ` FROM ubicaciones WHERE idUbicacion = ?`
+
+	location, err := scanLocation(r.db.QueryRowContext(ctx, query, id))
+	if err == sql.ErrNoRows {
+		return nil, entities.ErrNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error fetching location %d: %w", id, err)
+	}
+
+	return location, nil
+}
+
+// ListUserLocations returns every location of a user ordered so parents come first
+func (r *MySQLLocationRepository) ListUserLocations(ctx context.Context, userID int) ([]*entities.Location, error) {
+	query := `SELECT ` + locationColumns + ` FROM ubicaciones WHERE idUser = ? ORDER BY ruta`
+
+	return r.queryLocations(ctx, query, userID)
+}
+
+// ListLocationPath returns a location and every location above it, the site first
+func (r *MySQLLocationRepository) ListLocationPath(ctx context.Context, id int64) ([]*entities.Location, error) {
+	query := `SELECT a.idUbicacion, a.idPadre, a.nivel, a.nombre, a.direccion, a.idUser, a.ruta
+		FROM ubicaciones a
+		JOIN ubicaciones l ON l.idUbicacion = ? AND l.ruta LIKE CONCAT(a.ruta, '%')
+		ORDER BY LENGTH(a.ruta)`
+
+	return r.queryLocations(ctx, query, id)
+}
+
+func (r *MySQLLocationRepository) queryLocations(ctx context.Context, query string, args ...interface{}) ([]*entities.Location, error) {
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("error fetching locations: %w", err)
+	}
+	defer rows.Close()
+
+	locations := []*entities.Location{}
+	for rows.Next() {
+		location, err := scanLocation(rows)
+		if err != nil {
+			return nil, fmt.Errorf("error scanning location: %w", err)
+		}
+		locations = append(locations, location)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating locations: %w", err)
+	}
+
+	return locations, nil
+}
+
+// UpdateLocation stores the name and address of a location
+func (r *MySQLLocationRepository) UpdateLocation(ctx context.Context, location *entities.Location) error {
+	query := `UPDATE ubicaciones SET nombre = ?, direccion = ? WHERE idUbicacion = ?`
+
+	_, err := r.db.ExecContext(ctx, query, location.Nombre, nullString(location.Direccion), location.ID)
+	if err != nil {
+		return fmt.Errorf("error updating location %d: %w", location.ID, err)
+	}
+
+	return nil
+}
+
+// DeleteLocation removes a location
+func (r *MySQLLocationRepository) DeleteLocation(ctx context.Context, id int64) error {
+	result, err := r.db.ExecContext(ctx, `DELETE FROM ubicaciones WHERE idUbicacion = ?`, id)
+	if err != nil {
+		return fmt.Errorf("error deleting location %d: %w", id, err)
+	}
+
+	return expectOneRow(result, entities.ErrNotFound)
+}
+
+// CountLocationContents returns how many child locations and devices hang directly from a location
+func (r *MySQLLocationRepository) CountLocationContents(ctx context.Context, id int64) (int, int, error) {
+	query := `SELECT
+		(SELECT COUNT(*) FROM ubicaciones WHERE idPadre = ?),
+		(SELECT COUNT(*) FROM ESP32 WHERE idUbicacion = ?)`
+
+	var children, devices int
+	if err := r.db.QueryRowContext(ctx, query, id, id).Scan(&children, &devices); err != nil {
+		return 0, 0, fmt.Errorf("error counting contents of location %d: %w", id, err)
+	}
+
+	return children, devices, nil
+}
+
+// AssignDevice places a device in a location, or removes it from the hierarchy when locationID is nil
+func (r *MySQLLocationRepository) AssignDevice(ctx context.Context, numeroSerie string, locationID *int64) error {
+	_, err := r.db.ExecContext(ctx, `UPDATE ESP32 SET idUbicacion = ? WHERE numero_serie = ?`, locationID, numeroSerie)
+	if err != nil {
+		return fmt.Errorf("error assigning device %s to location: %w", numeroSerie, err)
+	}
+
+	return nil
+}
+
+// ListDeviceStatuses returns every device below a location with its active alert counts
+func (r *MySQLLocationRepository) ListDeviceStatuses(ctx context.Context, location *entities.Location) ([]*entities.DeviceStatus, error) {
+	query := `SELECT e.numero_serie, e.nombre, e.idUbicacion, e.en_linea, a.severidad, COUNT(a.idAlerta)
+		FROM ESP32 e
+		JOIN ubicaciones u ON u.idUbicacion = e.idUbicacion
+		LEFT JOIN alertas a ON a.numero_serie = e.numero_serie AND a.estado = ?
+		WHERE u.ruta LIKE ?
+		GROUP BY e.numero_serie, e.nombre, e.idUbicacion, e.en_linea, a.severidad
+		ORDER BY e.numero_serie`
+
+	rows, err := r.db.QueryContext(ctx, query, entities.AlertStateActive, location.Ruta+"%")
+	if err != nil {
+		return nil, fmt.Errorf("error fetching device status for location %d: %w", location.ID, err)
+	}
+	defer rows.Close()
+
+	var statuses []*entities.DeviceStatus
+	byDevice := make(map[string]*entities.DeviceStatus)
+	for rows.Next() {
+		var numeroSerie string
+		var nombre, severidad sql.NullString
+		var idUbicacion sql.NullInt64
+		var enLinea bool
+		var count int
+		if err := rows.Scan(&numeroSerie, &nombre, &idUbicacion, &enLinea, &severidad, &count); err != nil {
+			return nil, fmt.Errorf("error scanning device status: %w", err)
+		}
+
+		status, ok := byDevice[numeroSerie]
+		if !ok {
+			status = &entities.DeviceStatus{
+				NumeroSerie:    numeroSerie,
+				Nombre:         nombre.String,
+				EnLinea:        enLinea,
+				AlertasActivas: map[string]int{},
+			}
+			if idUbicacion.Valid {
+				status.IDUbicacion = &idUbicacion.Int64
+			}
+			byDevice[numeroSerie] = status
+			statuses = append(statuses, status)
+		}
+
+		if severidad.Valid && count > 0 {
+			status.AlertasActivas[severidad.String] = count
+			if entities.SeverityRank(severidad.String) > entities.SeverityRank(status.PeorSeveridad) {
+				status.PeorSeveridad = severidad.String
+			}
+		}
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating device status: %w", err)
+	}
+
+	return statuses, nil
+}
+
+func scanLocation(row rowScanner) (*entities.Location, error) {
+	var location entities.Location
+	var idPadre sql.NullInt64
+	var direccion sql.NullString
+
+	err := row.Scan(&location.ID, &idPadre, &location.Nivel, &location.Nombre, &direccion, &location.IDUser, &location.Ruta)
+	if err != nil {
+		return nil, err
+	}
+
+	if idPadre.Valid {
+		location.IDPadre = &idPadre.Int64
+	}
+	location.Direccion = direccion.String
+
+	return &location, nil
+}
+
+// Verify interface implementation
+var _ ports.LocationRepositoryPort = (*MySQLLocationRepository)(nil)
@@ -0,0 +1,164 @@
+package persistence
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"hex_go/internal/domain/entities"
+	"hex_go/internal/domain/ports"
+)
+
+// maintenanceColumns are the ventanas_mantenimiento columns read into a MaintenanceWindow, in scan order
+const maintenanceColumns = `v.idMantenimiento, v.idUser, v.nombre, v.numero_serie, v.idUbicacion, v.inicio, v.fin,
+	v.fecha_creacion`
+
+// MySQLMaintenanceRepository implements the MaintenanceRepositoryPort
+type MySQLMaintenanceRepository struct {
+	db *sql.DB
+}
+
+// NewMySQLMaintenanceRepository creates a new MySQL maintenance window repository
+func NewMySQLMaintenanceRepository(db *sql.DB) *MySQLMaintenanceRepository {
+	return &MySQLMaintenanceRepository{
+		db: db,
+	}
+}
+
+// CreateWindow inserts a maintenance window and sets its ID
+func (r *MySQLMaintenanceRepository) CreateWindow(ctx context.Context, window *entities.MaintenanceWindow) error {
+	result, err := r.db.ExecContext(ctx,
+		`INSERT INTO ventanas_mantenimiento (idUser, nombre, numero_serie, idUbicacion, inicio, fin, fecha_creacion)
+		VALUES (?, ?, ?, ?, ?, ?, ?)`,
+		window.IDUser, window.Nombre, window.NumeroSerie, window.IDUbicacion, window.Inicio, window.Fin,
+		window.FechaCreacion)
+	if err != nil {
+		return fmt.Errorf("error creating maintenance window: %w", err)
+	}
+	if window.ID, err = result.LastInsertId(); err != nil {
+		return fmt.Errorf("error reading maintenance window ID: %w", err)
+	}
+
+	return nil
+}
+
+// GetWindow returns a maintenance window by ID or entities.ErrNotFound
+func (r *MySQLMaintenanceRepository) GetWindow(ctx context.Context, id int64) (*entities.MaintenanceWindow, error) {
+	query := `SELECT ` + maintenanceColumns + ` FROM ventanas_mantenimiento v WHERE v.idMantenimiento = ?`
+
+	window, err := scanMaintenanceWindow(r.db.QueryRowContext(ctx, query, id))
+	if err == sql.ErrNoRows {
+		return nil, entities.ErrNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error fetching maintenance window %d: %w", id, err)
+	}
+
+	return window, nil
+}
+
+// ListUserWindows returns the maintenance windows of a user, latest start first
+func (r *MySQLMaintenanceRepository) ListUserWindows(ctx context.Context, userID int, filter *entities.MaintenanceFilter, now time.Time) ([]*entities.MaintenanceWindow, error) {
+	query := `SELECT ` + maintenanceColumns + ` FROM ventanas_mantenimiento v WHERE v.idUser = ?`
+	args := []interface{}{userID}
+	if filter.Current {
+		query += ` AND v.fin > ?`
+		args = append(args, now)
+	}
+	query += ` ORDER BY v.inicio DESC`
+
+	return r.queryWindows(ctx, query, args...)
+}
+
+// UpdateWindow stores the name, target and period of a maintenance window
+func (r *MySQLMaintenanceRepository) UpdateWindow(ctx context.Context, window *entities.MaintenanceWindow) error {
+	result, err := r.db.ExecContext(ctx,
+		`UPDATE ventanas_mantenimiento SET nombre = ?, numero_serie = ?, idUbicacion = ?, inicio = ?, fin = ?
+		WHERE idMantenimiento = ?`,
+		window.Nombre, window.NumeroSerie, window.IDUbicacion, window.Inicio, window.Fin, window.ID)
+	if err != nil {
+		return fmt.Errorf("error updating maintenance window %d: %w", window.ID, err)
+	}
+
+	return expectOneRow(result, entities.ErrNotFound)
+}
+
+// DeleteWindow removes a maintenance window
+func (r *MySQLMaintenanceRepository) DeleteWindow(ctx context.Context, id int64) error {
+	result, err := r.db.ExecContext(ctx, `DELETE FROM ventanas_mantenimiento WHERE idMantenimiento = ?`, id)
+	if err != nil {
+		return fmt.Errorf("error deleting maintenance window %d: %w", id, err)
+	}
+
+	return expectOneRow(result, entities.ErrNotFound)
+}
+
+// FindActiveWindow returns the window of the device owner covering the device or a
+// location whose subtree contains it at the given time, the one ending last first
+func (r *MySQLMaintenanceRepository) FindActiveWindow(ctx context.Context, numeroSerie string, at time.Time) (*entities.MaintenanceWindow, error) {
+	query := `SELECT ` + maintenanceColumns + ` FROM ventanas_mantenimiento v
+		JOIN ESP32 e ON e.numero_serie = ? AND e.idUser = v.idUser
+		LEFT JOIN ubicaciones u ON u.idUbicacion = e.idUbicacion
+		LEFT JOIN ubicaciones vu ON vu.idUbicacion = v.idUbicacion
+		WHERE v.inicio <= ? AND v.fin > ?
+			AND (v.numero_serie = e.numero_serie OR (u.ruta IS NOT NULL AND u.ruta LIKE CONCAT(vu.ruta, '%')))
+		ORDER BY v.fin DESC
+		LIMIT 1`
+
+	window, err := scanMaintenanceWindow(r.db.QueryRowContext(ctx, query, numeroSerie, at, at))
+	if err == sql.ErrNoRows {
+		return nil, entities.ErrNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error fetching maintenance window of device %s: %w", numeroSerie, err)
+	}
+
+	return window, nil
+}
+
+func (r *MySQLMaintenanceRepository) queryWindows(ctx context.Context, query string, args ...interface{}) ([]*entities.MaintenanceWindow, error) {
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("error fetching maintenance windows: %w", err)
+	}
+	defer rows.Close()
+
+	windows := []*entities.MaintenanceWindow{}
+	for rows.Next() {
+		window, err := scanMaintenanceWindow(rows)
+		if err != nil {
+			return nil, fmt.Errorf("error scanning maintenance window: %w", err)
+		}
+		windows = append(windows, window)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating maintenance windows: %w", err)
+	}
+
+	return windows, nil
+}
+
+// scanMaintenanceWindow reads the maintenanceColumns of a row
+func scanMaintenanceWindow(row rowScanner) (*entities.MaintenanceWindow, error) {
+	var window entities.MaintenanceWindow
+	var numeroSerie sql.NullString
+	var idUbicacion sql.NullInt64
+	err := row.Scan(&window.ID, &window.IDUser, &window.Nombre, &numeroSerie, &idUbicacion, &window.Inicio,
+		&window.Fin, &window.FechaCreacion)
+	if err != nil {
+		return nil, err
+	}
+
+	if numeroSerie.Valid {
+		window.NumeroSerie = &numeroSerie.String
+	}
+	if idUbicacion.Valid {
+		window.IDUbicacion = &idUbicacion.Int64
+	}
+
+	return &window, nil
+}
+
+// Verify interface implementation
+var _ ports.MaintenanceRepositoryPort = (*MySQLMaintenanceRepository)(nil)
@@ -0,0 +1,360 @@
+package persistence
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+
+	"hex_go/internal/domain/entities"
+	"hex_go/internal/domain/ports"
+)
+
+// recipientColumns are the destinatarios_notificacion columns read into a
+// NotificationRecipient, in scan order
+const recipientColumns = `d.idDestinatario, d.idUser, d.canal, d.direccion, d.idioma, d.fecha_creacion`
+
+// notificationDeliveryColumns are the entregas_notificacion columns read into a
+// NotificationDelivery, in scan order
+const notificationDeliveryColumns = `idEntrega, idAlerta, idDestinatario, idEscalacion, idUser, canal, direccion, estado, error, fecha_creacion`
+
+// MySQLNotificationRepository implements the NotificationRepositoryPort
+type MySQLNotificationRepository struct {
+	db *sql.DB
+}
+
+// NewMySQLNotificationRepository creates a new MySQL notification repository
+func NewMySQLNotificationRepository(db *sql.DB) *MySQLNotificationRepository {
+	return &MySQLNotificationRepository{
+		db: db,
+	}
+}
+
+// CreateRecipient inserts a recipient and sets its ID. It returns entities.ErrConflict
+// when the user already registered the address for that channel.
+func (r *MySQLNotificationRepository) CreateRecipient(ctx context.Context, recipient *entities.NotificationRecipient) error {
+	result, err := r.db.ExecContext(ctx,
+		`INSERT IGNORE INTO destinatarios_notificacion (idUser, canal, direccion, idioma, fecha_creacion)
+		VALUES (?, ?, ?, ?, ?)`,
+		recipient.IDUser, recipient.Canal, recipient.Direccion, recipient.Idioma, recipient.FechaCreacion)
+	if err != nil {
+		return fmt.Errorf("error creating notification recipient: %w", err)
+	}
+	if err := expectOneRow(result, entities.ErrConflict); err != nil {
+		return err
+	}
+	if recipient.ID, err = result.LastInsertId(); err != nil {
+		return fmt.Errorf("error reading notification recipient ID: %w", err)
+	}
+
+	return nil
+}
+
+// ListUserRecipients returns the recipients registered by a user
+func (r *MySQLNotificationRepository) ListUserRecipients(ctx context.Context, userID int) ([]*entities.NotificationRecipient, error) {
+	query := `SELECT ` + recipientColumns + ` FROM destinatarios_notificacion d
+		WHERE d.idUser = ? ORDER BY d.canal, d.direccion`
+
+	return r.queryRecipients(ctx, query, userID)
+}
+
+// GetRecipient returns a recipient by ID or entities.ErrNotFound
+func (r *MySQLNotificationRepository) GetRecipient(ctx context.Context, id int64) (*entities.NotificationRecipient, error) {
+	query := `SELECT ` + recipientColumns + ` FROM destinatarios_notificacion d WHERE d.idDestinatario = ?`
+
+	recipients, err := r.queryRecipients(ctx, query, id)
+	if err != nil {
+		return nil, err
+	}
+	if len(recipients) == 0 {
+		return nil, entities.ErrNotFound
+	}
+
+	return recipients[0], nil
+}
+
+// DeleteRecipient removes a recipient
+func (r *MySQLNotificationRepository) DeleteRecipient(ctx context.Context, id int64) error {
+	result, err := r.db.ExecContext(ctx, `DELETE FROM destinatarios_notificacion WHERE idDestinatario = ?`, id)
+	if err != nil {
+		return fmt.Errorf("error deleting notification recipient %d: %w", id, err)
+	}
+
+	return expectOneRow(result, entities.ErrNotFound)
+}
+
+// ListDeviceRecipients returns the recipients of the device owner and of the members of
+// every organisation the device is shared with
+func (r *MySQLNotificationRepository) ListDeviceRecipients(ctx context.Context, numeroSerie string) ([]*entities.NotificationRecipient, error) {
+	query := `SELECT ` + recipientColumns + ` FROM destinatarios_notificacion d
+		WHERE d.idUser IN (
+			SELECT idUser FROM ESP32 WHERE numero_serie = ? AND idUser IS NOT NULL
+			UNION
+			SELECT m.idUser FROM miembros_organizacion m
+			JOIN dispositivos_compartidos dc ON dc.idOrganizacion = m.idOrganizacion
+			WHERE dc.numero_serie = ?
+		)
+		ORDER BY d.idUser, d.canal`
+
+	return r.queryRecipients(ctx, query, numeroSerie, numeroSerie)
+}
+
+// GetChannelSettings returns the channels a user chose per severity, or nil when the
+// user never chose
+func (r *MySQLNotificationRepository) GetChannelSettings(ctx context.Context, userID int) (*entities.NotificationChannelSettings, error) {
+	rows, err := r.db.QueryContext(ctx,
+		`SELECT severidad, canales FROM canales_notificacion WHERE idUser = ?`, userID)
+	if err != nil {
+		return nil, fmt.Errorf("error fetching notification channels of user %d: %w", userID, err)
+	}
+	defer rows.Close()
+
+	var settings *entities.NotificationChannelSettings
+	for rows.Next() {
+		var severity, channels string
+		if err := rows.Scan(&severity, &channels); err != nil {
+			return nil, fmt.Errorf("error scanning notification channels: %w", err)
+		}
+		if settings == nil {
+			settings = &entities.NotificationChannelSettings{Canales: make(map[string][]string)}
+		}
+		settings.Canales[severity] = splitSensors(channels)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating notification channels: %w", err)
+	}
+
+	return settings, nil
+}
+
+// SetChannelSettings replaces the channels a user chose. Every severity gets a row, even
+// without channels, so the user no longer falls back to the defaults.
+func (r *MySQLNotificationRepository) SetChannelSettings(ctx context.Context, userID int, settings *entities.NotificationChannelSettings) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("error starting transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.ExecContext(ctx, `DELETE FROM canales_notificacion WHERE idUser = ?`, userID); err != nil {
+		return fmt.Errorf("error clearing notification channels of user %d: %w", userID, err)
+	}
+	for _, severity := range entities.Severities {
+		_, err := tx.ExecContext(ctx,
+			`INSERT INTO canales_notificacion (idUser, severidad, canales) VALUES (?, ?, ?)`,
+			userID, severity, strings.Join(settings.Canales[severity], ","))
+		if err != nil {
+			return fmt.Errorf("error storing notification channels of user %d: %w", userID, err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("error committing notification channels: %w", err)
+	}
+	return nil
+}
+
+// RecordDelivery inserts a notification delivery and sets its ID
+func (r *MySQLNotificationRepository) RecordDelivery(ctx context.Context, delivery *entities.NotificationDelivery) error {
+	result, err := r.db.ExecContext(ctx,
+		`INSERT INTO entregas_notificacion (idAlerta, idDestinatario, idEscalacion, idUser, canal, direccion, estado, error, fecha_creacion)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+		delivery.IDAlerta, delivery.IDDestinatario, delivery.IDEscalacion, delivery.IDUser, delivery.Canal, delivery.Direccion,
+		delivery.Estado, nullString(truncate(delivery.Error, maxDeliveryErrorLength)), delivery.FechaCreacion)
+	if err != nil {
+		return fmt.Errorf("error recording notification delivery: %w", err)
+	}
+	if delivery.ID, err = result.LastInsertId(); err != nil {
+		return fmt.Errorf("error reading notification delivery ID: %w", err)
+	}
+
+	return nil
+}
+
+// ListUserDeliveries returns the latest notifications sent to the recipients of a user
+func (r *MySQLNotificationRepository) ListUserDeliveries(ctx context.Context, userID int, limit int) ([]*entities.NotificationDelivery, error) {
+	rows, err := r.db.QueryContext(ctx,
+		`SELECT `+notificationDeliveryColumns+` FROM entregas_notificacion
+		WHERE idUser = ? ORDER BY idEntrega DESC LIMIT ?`, userID, limit)
+	if err != nil {
+		return nil, fmt.Errorf("error fetching notification deliveries: %w", err)
+	}
+	defer rows.Close()
+
+	deliveries := []*entities.NotificationDelivery{}
+	for rows.Next() {
+		var d entities.NotificationDelivery
+		var recipientID, escalationID sql.NullInt64
+		var deliveryErr sql.NullString
+		if err := rows.Scan(&d.ID, &d.IDAlerta, &recipientID, &escalationID, &d.IDUser, &d.Canal, &d.Direccion,
+			&d.Estado, &deliveryErr, &d.FechaCreacion); err != nil {
+			return nil, fmt.Errorf("error scanning notification delivery: %w", err)
+		}
+		if recipientID.Valid {
+			d.IDDestinatario = &recipientID.Int64
+		}
+		if escalationID.Valid {
+			d.IDEscalacion = &escalationID.Int64
+		}
+		d.Error = deliveryErr.String
+		deliveries = append(deliveries, &d)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating notification deliveries: %w", err)
+	}
+
+	return deliveries, nil
+}
+
+// GetPreferences returns the notification preferences of a user, or nil when they never
+// set them
+func (r *MySQLNotificationRepository) GetPreferences(ctx context.Context, userID int) (*entities.NotificationPreferences, error) {
+	var prefs entities.NotificationPreferences
+	var inicio, fin sql.NullString
+	err := r.db.QueryRowContext(ctx,
+		`SELECT zona_horaria, silencio_inicio, silencio_fin, resumen, hora_resumen
+		FROM preferencias_notificacion WHERE idUser = ?`, userID).
+		Scan(&prefs.ZonaHoraria, &inicio, &fin, &prefs.Resumen, &prefs.HoraResumen)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error fetching notification preferences of user %d: %w", userID, err)
+	}
+
+	if inicio.Valid && fin.Valid {
+		prefs.HorasSilencio = &entities.QuietHours{Inicio: inicio.String, Fin: fin.String}
+	}
+	return &prefs, nil
+}
+
+// SetPreferences stores the notification preferences of a user
+func (r *MySQLNotificationRepository) SetPreferences(ctx context.Context, userID int, prefs *entities.NotificationPreferences) error {
+	var inicio, fin sql.NullString
+	if prefs.HorasSilencio != nil {
+		inicio = nullString(prefs.HorasSilencio.Inicio)
+		fin = nullString(prefs.HorasSilencio.Fin)
+	}
+
+	_, err := r.db.ExecContext(ctx,
+		`INSERT INTO preferencias_notificacion (idUser, zona_horaria, silencio_inicio, silencio_fin, resumen, hora_resumen)
+		VALUES (?, ?, ?, ?, ?, ?)
+		ON DUPLICATE KEY UPDATE zona_horaria = VALUES(zona_horaria), silencio_inicio = VALUES(silencio_inicio),
+			silencio_fin = VALUES(silencio_fin), resumen = VALUES(resumen), hora_resumen = VALUES(hora_resumen)`,
+		userID, prefs.ZonaHoraria, inicio, fin, prefs.Resumen, prefs.HoraResumen)
+	if err != nil {
+		return fmt.Errorf("error storing notification preferences of user %d: %w", userID, err)
+	}
+	return nil
+}
+
+// QueueDigestItem holds back a notification until sendAfter
+func (r *MySQLNotificationRepository) QueueDigestItem(ctx context.Context, recipientID, alertID int64, sendAfter time.Time) error {
+	_, err := r.db.ExecContext(ctx,
+		`INSERT INTO resumen_pendiente (idDestinatario, idAlerta, enviar_despues, fecha_creacion) VALUES (?, ?, ?, ?)`,
+		recipientID, alertID, sendAfter, time.Now().UTC())
+	if err != nil {
+		return fmt.Errorf("error holding back notification of alert %d: %w", alertID, err)
+	}
+	return nil
+}
+
+// ListDueDigestRecipients returns the recipients with due notifications that are not
+// being sent by another worker
+func (r *MySQLNotificationRepository) ListDueDigestRecipients(ctx context.Context, now time.Time, limit int) ([]int64, error) {
+	rows, err := r.db.QueryContext(ctx,
+		`SELECT DISTINCT idDestinatario FROM resumen_pendiente
+		WHERE enviar_despues <= ? AND (lote IS NULL OR reclamado_hasta < ?)
+		LIMIT ?`, now, now, limit)
+	if err != nil {
+		return nil, fmt.Errorf("error fetching due digests: %w", err)
+	}
+	defer rows.Close()
+
+	ids := []int64{}
+	for rows.Next() {
+		var id int64
+		if err := rows.Scan(&id); err != nil {
+			return nil, fmt.Errorf("error scanning due digest: %w", err)
+		}
+		ids = append(ids, id)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating due digests: %w", err)
+	}
+
+	return ids, nil
+}
+
+// ClaimDigest marks the due notifications of a recipient with batch and returns their alerts
+func (r *MySQLNotificationRepository) ClaimDigest(ctx context.Context, recipientID int64, now time.Time, batch string, leaseUntil time.Time) ([]*entities.Alert, error) {
+	_, err := r.db.ExecContext(ctx,
+		`UPDATE resumen_pendiente SET lote = ?, reclamado_hasta = ?
+		WHERE idDestinatario = ? AND enviar_despues <= ? AND (lote IS NULL OR reclamado_hasta < ?)`,
+		batch, leaseUntil, recipientID, now, now)
+	if err != nil {
+		return nil, fmt.Errorf("error claiming digest of recipient %d: %w", recipientID, err)
+	}
+
+	rows, err := r.db.QueryContext(ctx,
+		`SELECT `+alertColumns+` FROM resumen_pendiente p
+		JOIN alertas a ON a.idAlerta = p.idAlerta
+		WHERE p.lote = ? ORDER BY a.fecha_creacion, a.idAlerta`, batch)
+	if err != nil {
+		return nil, fmt.Errorf("error fetching digest of recipient %d: %w", recipientID, err)
+	}
+	defer rows.Close()
+
+	alerts := []*entities.Alert{}
+	for rows.Next() {
+		alert, err := scanAlert(rows)
+		if err != nil {
+			return nil, fmt.Errorf("error scanning digest alert: %w", err)
+		}
+		alerts = append(alerts, alert)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating digest alerts: %w", err)
+	}
+
+	return alerts, nil
+}
+
+// DeleteDigest removes the notifications of a sent batch
+func (r *MySQLNotificationRepository) DeleteDigest(ctx context.Context, batch string) error {
+	if _, err := r.db.ExecContext(ctx, `DELETE FROM resumen_pendiente WHERE lote = ?`, batch); err != nil {
+		return fmt.Errorf("error deleting digest %s: %w", batch, err)
+	}
+	return nil
+}
+
+func (r *MySQLNotificationRepository) queryRecipients(ctx context.Context, query string, args ...interface{}) ([]*entities.NotificationRecipient, error) {
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("error fetching notification recipients: %w", err)
+	}
+	defer rows.Close()
+
+	recipients := []*entities.NotificationRecipient{}
+	for rows.Next() {
+		var d entities.NotificationRecipient
+		if err := rows.Scan(&d.ID, &d.IDUser, &d.Canal, &d.Direccion, &d.Idioma, &d.FechaCreacion); err != nil {
+			return nil, fmt.Errorf("error scanning notification recipient: %w", err)
+		}
+		recipients = append(recipients, &d)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating notification recipients: %w", err)
+	}
+
+	return recipients, nil
+}
+
+// Verify interface implementation
+var _ ports.NotificationRepositoryPort = (*MySQLNotificationRepository)(nil)
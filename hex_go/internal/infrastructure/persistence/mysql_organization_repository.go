@@ -0,0 +1,410 @@
+package persistence
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"hex_go/internal/domain/entities"
+	"hex_go/internal/domain/ports"
+)
+
+// invitationColumns are the invitaciones columns read into an Invitation, in scan order
+const invitationColumns = `idInvitacion, idOrganizacion, idUserInvitado, rol, estado, invitado_por,
+	fecha_creacion, fecha_expiracion`
+
+// MySQLOrganizationRepository implements the OrganizationRepositoryPort
+type MySQLOrganizationRepository struct {
+	db *sql.DB
+}
+
+// NewMySQLOrganizationRepository creates a new MySQL organisation repository
+func NewMySQLOrganizationRepository(db *sql.DB) *MySQLOrganizationRepository {
+	return &MySQLOrganizationRepository{
+		db: db,
+	}
+}
+
+// CreateOrganization inserts an organisation together with its first owner
+func (r *MySQLOrganizationRepository) CreateOrganization(ctx context.Context, org *entities.Organization, owner *entities.Membership, audit *entities.AccessAuditEntry) error {
+	return withAccessAudit(ctx, r.db, audit, func(tx *sql.Tx) error {
+		result, err := tx.ExecContext(ctx,
+			`INSERT INTO organizaciones (nombre, fecha_creacion) VALUES (?, ?)`, org.Nombre, org.FechaCreacion)
+		if err != nil {
+			return fmt.Errorf("error creating organization: %w", err)
+		}
+		if org.ID, err = result.LastInsertId(); err != nil {
+			return fmt.Errorf("error reading organization ID: %w", err)
+		}
+
+		owner.IDOrganizacion = org.ID
+		audit.IDOrganizacion = org.ID
+		return insertMember(ctx, tx, owner)
+	})
+}
+
+// GetOrganization returns an organisation by ID or entities.ErrNotFound
+func (r *MySQLOrganizationRepository) GetOrganization(ctx context.Context, id int64) (*entities.Organization, error) {
+	var org entities.Organization
+	err := r.db.QueryRowContext(ctx,
+		`SELECT idOrganizacion, nombre, fecha_creacion FROM organizaciones WHERE idOrganizacion = ?`, id).
+		Scan(&org.ID, &org.Nombre, &org.FechaCreacion)
+	if err == sql.ErrNoRows {
+		return nil, entities.ErrNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error fetching organization %d: %w", id, err)
+	}
+
+	return &org, nil
+}
+
+// ListUserOrganizations returns the organisations a user belongs to with the user's role
+func (r *MySQLOrganizationRepository) ListUserOrganizations(ctx context.Context, userID int) ([]*entities.Organization, error) {
+	query := `SELECT o.idOrganizacion, o.nombre, o.fecha_creacion, m.rol
+		FROM organizaciones o
+		JOIN miembros_organizacion m ON m.idOrganizacion = o.idOrganizacion
+		WHERE m.idUser = ?
+		ORDER BY o.nombre`
+
+	rows, err := r.db.QueryContext(ctx, query, userID)
+	if err != nil {
+		return nil, fmt.Errorf("error fetching organizations for user %d: %w", userID, err)
+	}
+	defer rows.Close()
+
+	orgs := []*entities.Organization{}
+	for rows.Next() {
+		var org entities.Organization
+		if err := rows.Scan(&org.ID, &org.Nombre, &org.FechaCreacion, &org.Rol); err != nil {
+			return nil, fmt.Errorf("error scanning organization: %w", err)
+		}
+		orgs = append(orgs, &org)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating organizations: %w", err)
+	}
+
+	return orgs, nil
+}
+
+// GetMembership returns the membership of a user or entities.ErrNotFound
+func (r *MySQLOrganizationRepository) GetMembership(ctx context.Context, orgID int64, userID int) (*entities.Membership, error) {
+	var m entities.Membership
+	err := r.db.QueryRowContext(ctx,
+		`SELECT idOrganizacion, idUser, rol, fecha_alta FROM miembros_organizacion WHERE idOrganizacion = ? AND idUser = ?`,
+		orgID, userID).Scan(&m.IDOrganizacion, &m.IDUser, &m.Rol, &m.FechaAlta)
+	if err == sql.ErrNoRows {
+		return nil, entities.ErrNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error fetching membership of user %d: %w", userID, err)
+	}
+
+	return &m, nil
+}
+
+// ListMembers returns every member of an organisation
+func (r *MySQLOrganizationRepository) ListMembers(ctx context.Context, orgID int64) ([]*entities.Membership, error) {
+	rows, err := r.db.QueryContext(ctx,
+		`SELECT idOrganizacion, idUser, rol, fecha_alta FROM miembros_organizacion WHERE idOrganizacion = ? ORDER BY fecha_alta`,
+		orgID)
+	if err != nil {
+		return nil, fmt.Errorf("error fetching members of organization %d: %w", orgID, err)
+	}
+	defer rows.Close()
+
+	members := []*entities.Membership{}
+	for rows.Next() {
+		var m entities.Membership
+		if err := rows.Scan(&m.IDOrganizacion, &m.IDUser, &m.Rol, &m.FechaAlta); err != nil {
+			return nil, fmt.Errorf("error scanning member: %w", err)
+		}
+		members = append(members, &m)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating members: %w", err)
+	}
+
+	return members, nil
+}
+
+// CountMembersWithRole counts the members of an organisation holding a role
+func (r *MySQLOrganizationRepository) CountMembersWithRole(ctx context.Context, orgID int64, role string) (int, error) {
+	var count int
+	err := r.db.QueryRowContext(ctx,
+		`SELECT COUNT(*) FROM miembros_organizacion WHERE idOrganizacion = ? AND rol = ?`, orgID, role).Scan(&count)
+	if err != nil {
+		return 0, fmt.Errorf("error counting %s members: %w", role, err)
+	}
+
+	return count, nil
+}
+
+// UpdateMemberRole changes the role of a member
+func (r *MySQLOrganizationRepository) UpdateMemberRole(ctx context.Context, orgID int64, userID int, role string, audit *entities.AccessAuditEntry) error {
+	return withAccessAudit(ctx, r.db, audit, func(tx *sql.Tx) error {
+		_, err := tx.ExecContext(ctx,
+			`UPDATE miembros_organizacion SET rol = ? WHERE idOrganizacion = ? AND idUser = ?`, role, orgID, userID)
+		if err != nil {
+			return fmt.Errorf("error updating role of user %d: %w", userID, err)
+		}
+		return nil
+	})
+}
+
+// RemoveMember removes a user from an organisation
+func (r *MySQLOrganizationRepository) RemoveMember(ctx context.Context, orgID int64, userID int, audit *entities.AccessAuditEntry) error {
+	return withAccessAudit(ctx, r.db, audit, func(tx *sql.Tx) error {
+		result, err := tx.ExecContext(ctx,
+			`DELETE FROM miembros_organizacion WHERE idOrganizacion = ? AND idUser = ?`, orgID, userID)
+		if err != nil {
+			return fmt.Errorf("error removing user %d: %w", userID, err)
+		}
+		return expectOneRow(result, entities.ErrNotFound)
+	})
+}
+
+// CreateInvitation inserts a pending invitation and sets its ID
+func (r *MySQLOrganizationRepository) CreateInvitation(ctx context.Context, invitation *entities.Invitation, audit *entities.AccessAuditEntry) error {
+	return withAccessAudit(ctx, r.db, audit, func(tx *sql.Tx) error {
+		result, err := tx.ExecContext(ctx,
+			`INSERT INTO invitaciones (idOrganizacion, idUserInvitado, rol, estado, invitado_por, fecha_creacion, fecha_expiracion)
+			VALUES (?, ?, ?, ?, ?, ?, ?)`,
+			invitation.IDOrganizacion, invitation.IDUserInvitado, invitation.Rol, invitation.Estado,
+			invitation.InvitadoPor, invitation.FechaCreacion, invitation.FechaExpiracion)
+		if err != nil {
+			return fmt.Errorf("error creating invitation: %w", err)
+		}
+		if invitation.ID, err = result.LastInsertId(); err != nil {
+			return fmt.Errorf("error reading invitation ID: %w", err)
+		}
+		audit.Objetivo = fmt.Sprintf("invitation:%d", invitation.ID)
+		return nil
+	})
+}
+
+// GetInvitation returns an invitation by ID or entities.ErrNotFound
+func (r *MySQLOrganizationRepository) GetInvitation(ctx context.Context, id int64) (*entities.Invitation, error) {
+	query := `SELECT ` + invitationColumns + ` FROM invitaciones WHERE idInvitacion = ?`
+
+	invitation, err := scanInvitation(r.db.QueryRowContext(ctx, query, id))
+	if err == sql.ErrNoRows {
+		return nil, entities.ErrNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error fetching invitation %d: %w", id, err)
+	}
+
+	return invitation, nil
+}
+
+// ListPendingInvitations returns the invitations a user has not answered yet
+func (r *MySQLOrganizationRepository) ListPendingInvitations(ctx context.Context, userID int) ([]*entities.Invitation, error) {
+	query := `SELECT ` + invitationColumns + ` FROM invitaciones
+		WHERE idUserInvitado = ? AND estado = ? ORDER BY fecha_creacion DESC`
+
+	return r.queryInvitations(ctx, query, userID, entities.InvitationPending)
+}
+
+// ListOrganizationInvitations returns every invitation sent by an organisation
+func (r *MySQLOrganizationRepository) ListOrganizationInvitations(ctx context.Context, orgID int64) ([]*entities.Invitation, error) {
+	query := `SELECT ` + invitationColumns + ` FROM invitaciones
+		WHERE idOrganizacion = ? ORDER BY fecha_creacion DESC`
+
+	return r.queryInvitations(ctx, query, orgID)
+}
+
+// AcceptInvitation marks a pending invitation as accepted and adds the member.
+// It returns entities.ErrConflict if the invitation is no longer pending.
+func (r *MySQLOrganizationRepository) AcceptInvitation(ctx context.Context, invitation *entities.Invitation, member *entities.Membership, audit *entities.AccessAuditEntry) error {
+	return withAccessAudit(ctx, r.db, audit, func(tx *sql.Tx) error {
+		if err := closeInvitation(ctx, tx, invitation.ID, entities.InvitationAccepted); err != nil {
+			return err
+		}
+
+		// Accepting an invitation into an organisation the user already belongs to
+		// changes the user's role instead
+		_, err := tx.ExecContext(ctx,
+			`INSERT INTO miembros_organizacion (idOrganizacion, idUser, rol, fecha_alta) VALUES (?, ?, ?, ?)
+			ON DUPLICATE KEY UPDATE rol = VALUES(rol)`,
+			member.IDOrganizacion, member.IDUser, member.Rol, member.FechaAlta)
+		if err != nil {
+			return fmt.Errorf("error adding member %d: %w", member.IDUser, err)
+		}
+		return nil
+	})
+}
+
+// CloseInvitation moves a pending invitation to a final state.
+// It returns entities.ErrConflict if the invitation is no longer pending.
+func (r *MySQLOrganizationRepository) CloseInvitation(ctx context.Context, id int64, state string, audit *entities.AccessAuditEntry) error {
+	return withAccessAudit(ctx, r.db, audit, func(tx *sql.Tx) error {
+		return closeInvitation(ctx, tx, id, state)
+	})
+}
+
+// ShareDevice makes a device visible to the members of an organisation
+func (r *MySQLOrganizationRepository) ShareDevice(ctx context.Context, shared *entities.SharedDevice, audit *entities.AccessAuditEntry) error {
+	return withAccessAudit(ctx, r.db, audit, func(tx *sql.Tx) error {
+		result, err := tx.ExecContext(ctx,
+			`INSERT IGNORE INTO dispositivos_compartidos (idOrganizacion, numero_serie, compartido_por, fecha_alta)
+			VALUES (?, ?, ?, ?)`,
+			shared.IDOrganizacion, shared.NumeroSerie, shared.CompartidoPor, shared.FechaAlta)
+		if err != nil {
+			return fmt.Errorf("error sharing device %s: %w", shared.NumeroSerie, err)
+		}
+		return expectOneRow(result, entities.ErrConflict)
+	})
+}
+
+// UnshareDevice stops sharing a device with an organisation
+func (r *MySQLOrganizationRepository) UnshareDevice(ctx context.Context, orgID int64, numeroSerie string, audit *entities.AccessAuditEntry) error {
+	return withAccessAudit(ctx, r.db, audit, func(tx *sql.Tx) error {
+		result, err := tx.ExecContext(ctx,
+			`DELETE FROM dispositivos_compartidos WHERE idOrganizacion = ? AND numero_serie = ?`, orgID, numeroSerie)
+		if err != nil {
+			return fmt.Errorf("error unsharing device %s: %w", numeroSerie, err)
+		}
+		return expectOneRow(result, entities.ErrNotFound)
+	})
+}
+
+// ListSharedDevices returns the devices shared with an organisation
+func (r *MySQLOrganizationRepository) ListSharedDevices(ctx context.Context, orgID int64) ([]*entities.SharedDevice, error) {
+	rows, err := r.db.QueryContext(ctx,
+		`SELECT idOrganizacion, numero_serie, compartido_por, fecha_alta
+		FROM dispositivos_compartidos WHERE idOrganizacion = ? ORDER BY numero_serie`, orgID)
+	if err != nil {
+		return nil, fmt.Errorf("error fetching shared devices of organization %d: %w", orgID, err)
+	}
+	defer rows.Close()
+
+	devices := []*entities.SharedDevice{}
+	for rows.Next() {
+		var d entities.SharedDevice
+		if err := rows.Scan(&d.IDOrganizacion, &d.NumeroSerie, &d.CompartidoPor, &d.FechaAlta); err != nil {
+			return nil, fmt.Errorf("error scanning shared device: %w", err)
+		}
+		devices = append(devices, &d)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating shared devices: %w", err)
+	}
+
+	return devices, nil
+}
+
+// ListAccessAudit returns the latest access changes of an organisation
+func (r *MySQLOrganizationRepository) ListAccessAudit(ctx context.Context, orgID int64, limit int) ([]*entities.AccessAuditEntry, error) {
+	rows, err := r.db.QueryContext(ctx,
+		`SELECT idAuditoria, idOrganizacion, actor, accion, objetivo, detalle, fecha
+		FROM auditoria_accesos WHERE idOrganizacion = ? ORDER BY fecha DESC, idAuditoria DESC LIMIT ?`, orgID, limit)
+	if err != nil {
+		return nil, fmt.Errorf("error fetching access audit of organization %d: %w", orgID, err)
+	}
+	defer rows.Close()
+
+	entries := []*entities.AccessAuditEntry{}
+	for rows.Next() {
+		var e entities.AccessAuditEntry
+		var detalle sql.NullString
+		if err := rows.Scan(&e.ID, &e.IDOrganizacion, &e.Actor, &e.Accion, &e.Objetivo, &detalle, &e.Fecha); err != nil {
+			return nil, fmt.Errorf("error scanning access audit entry: %w", err)
+		}
+		e.Detalle = detalle.String
+		entries = append(entries, &e)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating access audit: %w", err)
+	}
+
+	return entries, nil
+}
+
+// withAccessAudit runs fn in a transaction and stores the access audit entry in the same transaction
+func withAccessAudit(ctx context.Context, db *sql.DB, audit *entities.AccessAuditEntry, fn func(tx *sql.Tx) error) error {
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("error starting transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if err := fn(tx); err != nil {
+		return err
+	}
+
+	result, err := tx.ExecContext(ctx,
+		`INSERT INTO auditoria_accesos (idOrganizacion, actor, accion, objetivo, detalle, fecha) VALUES (?, ?, ?, ?, ?, ?)`,
+		audit.IDOrganizacion, audit.Actor, audit.Accion, audit.Objetivo, nullString(audit.Detalle), audit.Fecha)
+	if err != nil {
+		return fmt.Errorf("error recording access audit: %w", err)
+	}
+	if audit.ID, err = result.LastInsertId(); err != nil {
+		return fmt.Errorf("error reading access audit ID: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("error committing transaction: %w", err)
+	}
+	return nil
+}
+
+func (r *MySQLOrganizationRepository) queryInvitations(ctx context.Context, query string, args ...interface{}) ([]*entities.Invitation, error) {
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("error fetching invitations: %w", err)
+	}
+	defer rows.Close()
+
+	invitations := []*entities.Invitation{}
+	for rows.Next() {
+		invitation, err := scanInvitation(rows)
+		if err != nil {
+			return nil, fmt.Errorf("error scanning invitation: %w", err)
+		}
+		invitations = append(invitations, invitation)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating invitations: %w", err)
+	}
+
+	return invitations, nil
+}
+
+func insertMember(ctx context.Context, tx *sql.Tx, m *entities.Membership) error {
+	_, err := tx.ExecContext(ctx,
+		`INSERT INTO miembros_organizacion (idOrganizacion, idUser, rol, fecha_alta) VALUES (?, ?, ?, ?)`,
+		m.IDOrganizacion, m.IDUser, m.Rol, m.FechaAlta)
+	if err != nil {
+		return fmt.Errorf("error adding member %d: %w", m.IDUser, err)
+	}
+	return nil
+}
+
+func closeInvitation(ctx context.Context, tx *sql.Tx, id int64, state string) error {
+	result, err := tx.ExecContext(ctx,
+		`UPDATE invitaciones SET estado = ? WHERE idInvitacion = ? AND estado = ?`, state, id, entities.InvitationPending)
+	if err != nil {
+		return fmt.Errorf("error updating invitation %d: %w", id, err)
+	}
+	return expectOneRow(result, entities.ErrConflict)
+}
+
+func scanInvitation(row rowScanner) (*entities.Invitation, error) {
+	var i entities.Invitation
+	err := row.Scan(&i.ID, &i.IDOrganizacion, &i.IDUserInvitado, &i.Rol, &i.Estado, &i.InvitadoPor,
+		&i.FechaCreacion, &i.FechaExpiracion)
+	if err != nil {
+		return nil, err
+	}
+	return &i, nil
+}
+
+// Verify interface implementation
+var _ ports.OrganizationRepositoryPort = (*MySQLOrganizationRepository)(nil)
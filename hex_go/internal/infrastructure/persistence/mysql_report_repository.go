@@ -0,0 +1,204 @@
+package persistence
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"hex_go/internal/domain/entities"
+	"hex_go/internal/domain/ports"
+)
+
+// siteAlertsJoin selects the alerts of the devices below a site path, raised in a range
+// and not suppressed. It takes the path, the start and the end.
+const siteAlertsJoin = ` FROM alertas a
+	JOIN ESP32 e ON e.numero_serie = a.numero_serie
+	JOIN ubicaciones u ON u.idUbicacion = e.idUbicacion
+	WHERE u.ruta LIKE CONCAT(?, '%') AND a.fecha_creacion >= ? AND a.fecha_creacion < ? AND a.suprimida = 0`
+
+// MySQLReportRepository implements the ReportRepositoryPort
+type MySQLReportRepository struct {
+	db *sql.DB
+}
+
+// NewMySQLReportRepository creates a new MySQL report repository
+func NewMySQLReportRepository(db *sql.DB) *MySQLReportRepository {
+	return &MySQLReportRepository{
+		db: db,
+	}
+}
+
+// ListSites returns the top locations of every user
+func (r *MySQLReportRepository) ListSites(ctx context.Context) ([]*entities.Location, error) {
+	rows, err := r.db.QueryContext(ctx,
+		`SELECT `+locationColumns+` FROM ubicaciones WHERE nivel = ? ORDER BY idUbicacion`, entities.LocationLevelSite)
+	if err != nil {
+		return nil, fmt.Errorf("error fetching sites: %w", err)
+	}
+	defer rows.Close()
+
+	var sites []*entities.Location
+	for rows.Next() {
+		site, err := scanLocation(rows)
+		if err != nil {
+			return nil, fmt.Errorf("error scanning site: %w", err)
+		}
+		sites = append(sites, site)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating sites: %w", err)
+	}
+
+	return sites, nil
+}
+
+// CountAlarms counts the alerts and activations of each type
+func (r *MySQLReportRepository) CountAlarms(ctx context.Context, sitePath string, desde, hasta time.Time) ([]*entities.ReportAlarmCount, error) {
+	rows, err := r.db.QueryContext(ctx,
+		`SELECT a.tipo, COUNT(*), COALESCE(SUM(a.ocurrencias), 0), COALESCE(SUM(a.severidad = ?), 0)`+siteAlertsJoin+`
+		GROUP BY a.tipo ORDER BY COUNT(*) DESC, a.tipo`,
+		entities.SeverityCritical, sitePath, desde, hasta)
+	if err != nil {
+		return nil, fmt.Errorf("error counting alarms: %w", err)
+	}
+	defer rows.Close()
+
+	counts := []*entities.ReportAlarmCount{}
+	for rows.Next() {
+		var c entities.ReportAlarmCount
+		if err := rows.Scan(&c.Tipo, &c.Alertas, &c.Activaciones, &c.Criticas); err != nil {
+			return nil, fmt.Errorf("error scanning alarm count: %w", err)
+		}
+		counts = append(counts, &c)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating alarm counts: %w", err)
+	}
+
+	return counts, nil
+}
+
+// ListResponseTimes returns the acknowledgement delay of every acknowledged alert and
+// counts the others
+func (r *MySQLReportRepository) ListResponseTimes(ctx context.Context, sitePath string, desde, hasta time.Time) ([]time.Duration, int, error) {
+	rows, err := r.db.QueryContext(ctx,
+		`SELECT TIMESTAMPDIFF(SECOND, a.fecha_creacion, a.fecha_reconocimiento)`+siteAlertsJoin,
+		sitePath, desde, hasta)
+	if err != nil {
+		return nil, 0, fmt.Errorf("error fetching response times: %w", err)
+	}
+	defer rows.Close()
+
+	var acknowledged []time.Duration
+	pending := 0
+	for rows.Next() {
+		var seconds sql.NullInt64
+		if err := rows.Scan(&seconds); err != nil {
+			return nil, 0, fmt.Errorf("error scanning response time: %w", err)
+		}
+		if !seconds.Valid {
+			pending++
+			continue
+		}
+		acknowledged = append(acknowledged, time.Duration(seconds.Int64)*time.Second)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, 0, fmt.Errorf("error iterating response times: %w", err)
+	}
+
+	return acknowledged, pending, nil
+}
+
+// ListDeviceUptime returns the time observed and offline of every device below the site.
+// Periods offline are clipped to the range; the time before registration is not observed.
+func (r *MySQLReportRepository) ListDeviceUptime(ctx context.Context, sitePath string, desde, hasta time.Time) ([]*entities.ReportDeviceUptime, error) {
+	rows, err := r.db.QueryContext(ctx,
+		`SELECT e.numero_serie, COALESCE(e.nombre, ''), u.nombre,
+			GREATEST(TIMESTAMPDIFF(SECOND, GREATEST(?, COALESCE(e.fecha_registro, ?)), ?), 0),
+			COALESCE((SELECT SUM(TIMESTAMPDIFF(SECOND, GREATEST(d.inicio, ?), LEAST(COALESCE(d.fin, ?), ?)))
+				FROM desconexiones d
+				WHERE d.numero_serie = e.numero_serie AND d.inicio < ? AND (d.fin IS NULL OR d.fin > ?)), 0)
+		FROM ESP32 e
+		JOIN ubicaciones u ON u.idUbicacion = e.idUbicacion
+		WHERE u.ruta LIKE CONCAT(?, '%')
+		ORDER BY e.numero_serie`,
+		desde, desde, hasta, desde, hasta, hasta, hasta, desde, sitePath)
+	if err != nil {
+		return nil, fmt.Errorf("error fetching device uptime: %w", err)
+	}
+	defer rows.Close()
+
+	devices := []*entities.ReportDeviceUptime{}
+	for rows.Next() {
+		var u entities.ReportDeviceUptime
+		var observed, offline int64
+		if err := rows.Scan(&u.NumeroSerie, &u.Nombre, &u.Ubicacion, &observed, &offline); err != nil {
+			return nil, fmt.Errorf("error scanning device uptime: %w", err)
+		}
+		u.Observado = time.Duration(observed) * time.Second
+		u.SinConexion = time.Duration(offline) * time.Second
+		devices = append(devices, &u)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating device uptime: %w", err)
+	}
+
+	return devices, nil
+}
+
+// ListNoisyDevices returns the devices with most activations, most first
+func (r *MySQLReportRepository) ListNoisyDevices(ctx context.Context, sitePath string, desde, hasta time.Time, limit int) ([]*entities.ReportNoisyDevice, error) {
+	rows, err := r.db.QueryContext(ctx,
+		`SELECT a.numero_serie, COALESCE(e.nombre, ''), COUNT(*), COALESCE(SUM(a.ocurrencias), 0),
+			COALESCE(SUM(a.oscilante), 0)`+siteAlertsJoin+`
+		GROUP BY a.numero_serie, e.nombre
+		ORDER BY SUM(a.ocurrencias) DESC, COUNT(*) DESC, a.numero_serie
+		LIMIT ?`,
+		sitePath, desde, hasta, limit)
+	if err != nil {
+		return nil, fmt.Errorf("error fetching noisy devices: %w", err)
+	}
+	defer rows.Close()
+
+	devices := []*entities.ReportNoisyDevice{}
+	for rows.Next() {
+		var d entities.ReportNoisyDevice
+		if err := rows.Scan(&d.NumeroSerie, &d.Nombre, &d.Alertas, &d.Activaciones, &d.Oscilantes); err != nil {
+			return nil, fmt.Errorf("error scanning noisy device: %w", err)
+		}
+		devices = append(devices, &d)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating noisy devices: %w", err)
+	}
+
+	return devices, nil
+}
+
+// ClaimReport records the delivery of a report unless it was already recorded
+func (r *MySQLReportRepository) ClaimReport(ctx context.Context, siteID int64, month string, at time.Time) (bool, error) {
+	result, err := r.db.ExecContext(ctx,
+		`INSERT IGNORE INTO informes_mensuales (idUbicacion, mes, fecha_envio) VALUES (?, ?, ?)`, siteID, month, at)
+	if err != nil {
+		return false, fmt.Errorf("error claiming %s report of site %d: %w", month, siteID, err)
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("error reading affected rows: %w", err)
+	}
+	return affected > 0, nil
+}
+
+// ReleaseReport removes the delivery record of a report
+func (r *MySQLReportRepository) ReleaseReport(ctx context.Context, siteID int64, month string) error {
+	_, err := r.db.ExecContext(ctx, `DELETE FROM informes_mensuales WHERE idUbicacion = ? AND mes = ?`, siteID, month)
+	if err != nil {
+		return fmt.Errorf("error releasing %s report of site %d: %w", month, siteID, err)
+	}
+	return nil
+}
+
+// Verify interface implementation
+var _ ports.ReportRepositoryPort = (*MySQLReportRepository)(nil)
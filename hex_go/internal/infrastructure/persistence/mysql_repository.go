@@ -1,23 +1,79 @@
 package persistence
 
 import (
+	"context"
 	"database/sql"
 	"fmt"
-	"strings" // Add this import
+	"strings"
+	"time"
 
 	_ "github.com/go-sql-driver/mysql"
+	"golang.org/x/sync/errgroup"
 	"hex_go/internal/domain/entities"
 )
 
+// sensorTables lists the tables holding the readings of each sensor type
+var sensorTables = []string{"KY_026", "MQ_2", "MQ_135", "DHT_22"}
+
+// sensorTableColumns lists the typed columns some sensor tables hold besides estado
+var sensorTableColumns = map[string][]string{
+	"DHT_22": {"temperatura", "humedad", "indice_calor", "punto_rocio"},
+	"MQ_2":   {"ppm"},
+	"MQ_135": {"ppm"},
+}
+
+// accessibleDevicesQuery selects the serial numbers a user can see: the devices they own
+// and the devices shared with any organisation they belong to. It takes the user ID twice.
+const accessibleDevicesQuery = `SELECT numero_serie FROM ESP32 WHERE idUser = ?
+	UNION
+	SELECT dc.numero_serie FROM dispositivos_compartidos dc
+	JOIN miembros_organizacion m ON m.idOrganizacion = dc.idOrganizacion
+	WHERE m.idUser = ?`
+
+// deviceRolesQuery selects the devices a user can see with the role they hold on each:
+// owner of their own devices and their member role on the devices shared with their
+// organisations. A device shared with several organisations appears once per role. It
+// takes the user ID twice.
+const deviceRolesQuery = `SELECT numero_serie, 'owner' AS rol FROM ESP32 WHERE idUser = ?
+	UNION
+	SELECT dc.numero_serie, m.rol FROM dispositivos_compartidos dc
+	JOIN miembros_organizacion m ON m.idOrganizacion = dc.idOrganizacion
+	WHERE m.idUser = ?`
+
+// strongestRoleFirst orders the rows of deviceRolesQuery, aliased r, by decreasing rights
+const strongestRoleFirst = `FIELD(r.rol, 'owner', 'admin', 'responder', 'viewer')`
+
+// roleScanner scans the role of the user that follows the columns of a row
+type roleScanner struct {
+	row  rowScanner
+	role *string
+}
+
+func (s roleScanner) Scan(dest ...interface{}) error {
+	return s.row.Scan(append(dest, s.role)...)
+}
+
+// organizationDevicesQuery selects the serial numbers shared with an organisation
+const organizationDevicesQuery = `SELECT numero_serie FROM dispositivos_compartidos WHERE idOrganizacion = ?`
+
+// defaultFetchConcurrency is used when no positive concurrency is configured
+const defaultFetchConcurrency = 4
+
 // MySQLRepository implements the SensorRepository interface
 type MySQLRepository struct {
-	db *sql.DB
+	db               *sql.DB
+	fetchConcurrency int
 }
 
-// NewMySQLRepository creates a new MySQL repository
-func NewMySQLRepository(db *sql.DB) *MySQLRepository {
+// NewMySQLRepository creates a new MySQL repository. fetchConcurrency bounds how many
+// sensor tables are queried at the same time when reading alerts.
+func NewMySQLRepository(db *sql.DB, fetchConcurrency int) *MySQLRepository {
+	if fetchConcurrency <= 0 {
+		fetchConcurrency = defaultFetchConcurrency
+	}
 	return &MySQLRepository{
-		db: db,
+		db:               db,
+		fetchConcurrency: fetchConcurrency,
 	}
 }
 
@@ -36,10 +92,10 @@ func (r *MySQLRepository) CreateKY026(sensor *entities.SensorKY026) error {
 
 // CreateMQ2 inserts a new MQ_2 sensor record
 func (r *MySQLRepository) CreateMQ2(sensor *entities.SensorMQ2) error {
-	query := `INSERT INTO MQ_2 (fecha_activacion, fecha_desactivacion, estado, numero_serie) 
-              VALUES (?, ?, ?, ?)`
+	query := `INSERT INTO MQ_2 (fecha_activacion, fecha_desactivacion, estado, numero_serie, ppm) 
+              VALUES (?, ?, ?, ?, ?)`
 	
-	_, err := r.db.Exec(query, sensor.FechaActivacion, sensor.FechaDesactivacion, sensor.Estado, sensor.NumeroSerie)
+	_, err := r.db.Exec(query, sensor.FechaActivacion, sensor.FechaDesactivacion, sensor.Estado, sensor.NumeroSerie, sensor.PPM)
 	if err != nil {
 		return fmt.Errorf("error creating MQ_2 sensor: %w", err)
 	}
@@ -49,10 +105,10 @@ func (r *MySQLRepository) CreateMQ2(sensor *entities.SensorMQ2) error {
 
 // CreateMQ135 inserts a new MQ_135 sensor record
 func (r *MySQLRepository) CreateMQ135(sensor *entities.SensorMQ135) error {
-	query := `INSERT INTO MQ_135 (fecha_activacion, fecha_desactivacion, estado, numero_serie) 
-              VALUES (?, ?, ?, ?)`
+	query := `INSERT INTO MQ_135 (fecha_activacion, fecha_desactivacion, estado, numero_serie, ppm) 
+              VALUES (?, ?, ?, ?, ?)`
 	
-	_, err := r.db.Exec(query, sensor.FechaActivacion, sensor.FechaDesactivacion, sensor.Estado, sensor.NumeroSerie)
+	_, err := r.db.Exec(query, sensor.FechaActivacion, sensor.FechaDesactivacion, sensor.Estado, sensor.NumeroSerie, sensor.PPM)
 	if err != nil {
 		return fmt.Errorf("error creating MQ_135 sensor: %w", err)
 	}
@@ -61,12 +117,14 @@ func (r *MySQLRepository) CreateMQ135(sensor *entities.SensorMQ135) error {
 }
 
 
-// CreateDHT22 inserts a new DHT_22 sensor record
+// CreateDHT22 inserts a new DHT_22 sensor record with its numeric readings
 func (r *MySQLRepository) CreateDHT22(sensor *entities.SensorDHT22) error {
-	query := `INSERT INTO DHT_22 (fecha_activacion, fecha_desactivacion, estado, numero_serie) 
-              VALUES (?, ?, ?, ?)`
+	query := `INSERT INTO DHT_22 (fecha_activacion, fecha_desactivacion, estado, numero_serie,
+                  temperatura, humedad, indice_calor, punto_rocio) 
+              VALUES (?, ?, ?, ?, ?, ?, ?, ?)`
 	
-	_, err := r.db.Exec(query, sensor.FechaActivacion, sensor.FechaDesactivacion, sensor.Estado, sensor.NumeroSerie)
+	_, err := r.db.Exec(query, sensor.FechaActivacion, sensor.FechaDesactivacion, sensor.Estado, sensor.NumeroSerie,
+		sensor.Temperatura, sensor.Humedad, sensor.IndiceCalor, sensor.PuntoRocio)
 	if err != nil {
 		return fmt.Errorf("error creating DHT_22 sensor: %w", err)
 	}
@@ -75,14 +133,47 @@ func (r *MySQLRepository) CreateDHT22(sensor *entities.SensorDHT22) error {
 }
 
 
+// GetDeviceTimezone returns the timezone configured for an ESP32 board. Unknown devices
+// and devices without a timezone yield an empty string.
+func (r *MySQLRepository) GetDeviceTimezone(ctx context.Context, numeroSerie string) (string, error) {
+	query := `SELECT zona_horaria FROM ESP32 WHERE numero_serie = ?`
+	
+	var zonaHoraria sql.NullString
+	err := r.db.QueryRowContext(ctx, query, numeroSerie).Scan(&zonaHoraria)
+	if err == sql.ErrNoRows {
+		return "", nil
+	}
+	if err != nil {
+		return "", fmt.Errorf("error fetching timezone for device %s: %w", numeroSerie, err)
+	}
+	
+	return zonaHoraria.String, nil
+}
 
-func (r *MySQLRepository) GetUserAlerts(userID int) (map[string]interface{}, error) {
+// GetUserAlerts retrieves the alerts of every sensor table for the devices a user can access,
+// either as owner or through an organisation membership.
+// The sensor tables are queried concurrently; the first failing query cancels the others.
+// With filter.LocationID only the devices below that location are included, and with
+// filter.GroupBy the alerts are also counted per location of that level.
+func (r *MySQLRepository) GetUserAlerts(ctx context.Context, userID int, filter *entities.AlertFilter) (map[string]interface{}, error) {
 	
-	query := `SELECT numero_serie FROM ESP32 WHERE idUser = ?`
+	devicesQuery, devicesArgs := visibleDevices(userID, filter)
+	query := `SELECT e.numero_serie FROM ESP32 e`
+	args := []interface{}{}
+	if filter != nil && filter.LocationID != nil {
+		query += `
+		JOIN ubicaciones u ON u.idUbicacion = e.idUbicacion
+		JOIN ubicaciones f ON f.idUbicacion = ?
+		WHERE e.numero_serie IN (` + devicesQuery + `) AND u.ruta LIKE CONCAT(f.ruta, '%')`
+		args = append(args, *filter.LocationID)
+	} else {
+		query += ` WHERE e.numero_serie IN (` + devicesQuery + `)`
+	}
+	args = append(args, devicesArgs...)
 	
 	fmt.Printf("Executing query for user ID %d: %s\n", userID, query)
 	
-	rows, err := r.db.Query(query, userID)
+	rows, err := r.db.QueryContext(ctx, query, args...)
 	if err != nil {
 		return nil, fmt.Errorf("error fetching user ESP32 devices: %w", err)
 	}
@@ -113,47 +204,121 @@ func (r *MySQLRepository) GetUserAlerts(userID int) (map[string]interface{}, err
 	}
 	
 	// Now fetch alerts from all sensor tables for these serial numbers
+	alertsMap, err := r.fetchAlertsFromTables(ctx, sensorTables, serialNumbers)
+	if err != nil {
+		return nil, err
+	}
+	
 	result := map[string]interface{}{
 		"user_id": userID,
 		"devices": serialNumbers,
-		"alerts": map[string]interface{}{},
+		"alerts":  alertsMap,
+	}
+	if filter != nil && filter.OrganizationID != nil {
+		delete(result, "user_id")
+		result["organization_id"] = *filter.OrganizationID
 	}
 	
-	alertsMap := result["alerts"].(map[string]interface{})
+	if filter != nil && filter.GroupBy != "" {
+		groups, err := r.aggregateAlertsByLocation(ctx, userID, filter)
+		if err != nil {
+			return nil, err
+		}
+		result["groups"] = groups
+	}
 	
-	// Fetch KY_026 alerts
-	ky026Alerts, err := r.fetchAlertsFromTable("KY_026", serialNumbers)
-	if err != nil {
-		return nil, err
+	return result, nil
+}
+
+// aggregateAlertsByLocation counts the alerts of the user's devices per location of the
+// filter.GroupBy level, restricted to the subtree of filter.LocationID when it is set
+func (r *MySQLRepository) aggregateAlertsByLocation(ctx context.Context, userID int, filter *entities.AlertFilter) ([]*entities.AlertGroup, error) {
+	devicesQuery, devicesArgs := visibleDevices(userID, filter)
+	query := `SELECT g.idUbicacion, g.idPadre, g.nivel, g.nombre, g.idUser, a.tipo,
+			COUNT(*), SUM(a.estado = ?)
+		FROM alertas a
+		JOIN ESP32 e ON e.numero_serie = a.numero_serie
+		JOIN ubicaciones u ON u.idUbicacion = e.idUbicacion
+		JOIN ubicaciones g ON g.nivel = ? AND u.ruta LIKE CONCAT(g.ruta, '%')
+		WHERE e.numero_serie IN (` + devicesQuery + `)`
+	args := append([]interface{}{entities.AlertStateActive, filter.GroupBy}, devicesArgs...)
+	if filter.LocationID != nil {
+		query += ` AND u.ruta LIKE CONCAT((SELECT ruta FROM ubicaciones WHERE idUbicacion = ?), '%')`
+		args = append(args, *filter.LocationID)
 	}
-	alertsMap["KY_026"] = ky026Alerts
+	query += `
+		GROUP BY g.idUbicacion, g.idPadre, g.nivel, g.nombre, g.idUser, a.tipo
+		ORDER BY g.nombre`
 	
-	// Fetch MQ_2 alerts
-	mq2Alerts, err := r.fetchAlertsFromTable("MQ_2", serialNumbers)
+	rows, err := r.db.QueryContext(ctx, query, args...)
 	if err != nil {
-		return nil, err
+		return nil, fmt.Errorf("error aggregating alerts by %s: %w", filter.GroupBy, err)
 	}
-	alertsMap["MQ_2"] = mq2Alerts
+	defer rows.Close()
 	
-	// Fetch MQ_135 alerts
-	mq135Alerts, err := r.fetchAlertsFromTable("MQ_135", serialNumbers)
-	if err != nil {
-		return nil, err
+	groups := []*entities.AlertGroup{}
+	byLocation := make(map[int64]*entities.AlertGroup)
+	for rows.Next() {
+		var location entities.Location
+		var idPadre sql.NullInt64
+		var tipo string
+		var total, activas int
+		if err := rows.Scan(&location.ID, &idPadre, &location.Nivel, &location.Nombre, &location.IDUser, &tipo, &total, &activas); err != nil {
+			return nil, fmt.Errorf("error scanning alert group: %w", err)
+		}
+		
+		group, ok := byLocation[location.ID]
+		if !ok {
+			if idPadre.Valid {
+				location.IDPadre = &idPadre.Int64
+			}
+			group = &entities.AlertGroup{Ubicacion: &location, PorTipo: map[string]int{}}
+			byLocation[location.ID] = group
+			groups = append(groups, group)
+		}
+		group.Total += total
+		group.Activas += activas
+		group.PorTipo[tipo] += total
 	}
-	alertsMap["MQ_135"] = mq135Alerts
 	
-	// Fetch DHT_22 alerts
-	dht22Alerts, err := r.fetchAlertsFromTable("DHT_22", serialNumbers)
-	if err != nil {
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating alert groups: %w", err)
+	}
+	
+	return groups, nil
+}
+
+// fetchAlertsFromTables queries several sensor tables in parallel, bounded by fetchConcurrency
+func (r *MySQLRepository) fetchAlertsFromTables(ctx context.Context, tables []string, serialNumbers []string) (map[string]interface{}, error) {
+	results := make([][]map[string]interface{}, len(tables))
+	
+	// The first failing query cancels the others
+	group, ctx := errgroup.WithContext(ctx)
+	group.SetLimit(r.fetchConcurrency)
+	for i, tableName := range tables {
+		i, tableName := i, tableName
+		group.Go(func() error {
+			alerts, err := r.fetchAlertsFromTable(ctx, tableName, serialNumbers)
+			if err != nil {
+				return err
+			}
+			results[i] = alerts
+			return nil
+		})
+	}
+	if err := group.Wait(); err != nil {
 		return nil, err
 	}
-	alertsMap["DHT_22"] = dht22Alerts
 	
-	return result, nil
+	alertsMap := make(map[string]interface{}, len(tables))
+	for i, tableName := range tables {
+		alertsMap[tableName] = results[i]
+	}
+	return alertsMap, nil
 }
 
 // Helper method to fetch alerts from a specific table
-func (r *MySQLRepository) fetchAlertsFromTable(tableName string, serialNumbers []string) ([]map[string]interface{}, error) {
+func (r *MySQLRepository) fetchAlertsFromTable(ctx context.Context, tableName string, serialNumbers []string) ([]map[string]interface{}, error) {
 	// Create placeholders for the IN clause
 	placeholders := make([]string, len(serialNumbers))
 	args := make([]interface{}, len(serialNumbers))
@@ -164,20 +329,27 @@ func (r *MySQLRepository) fetchAlertsFromTable(tableName string, serialNumbers [
 	
 	
 	idColumn := fmt.Sprintf("id%s", tableName)
+	extraColumns := sensorTableColumns[tableName]
+	
+	var extraSelect string
+	if len(extraColumns) > 0 {
+		extraSelect = ", " + strings.Join(extraColumns, ", ")
+	}
 	
 	query := fmt.Sprintf(
-		`SELECT %s, fecha_activacion, fecha_desactivacion, estado, numero_serie 
+		`SELECT %s, fecha_activacion, fecha_desactivacion, estado, numero_serie%s 
 		FROM %s 
 		WHERE numero_serie IN (%s)
 		ORDER BY fecha_activacion DESC`,
 		idColumn,
+		extraSelect,
 		tableName,
 		strings.Join(placeholders, ","),
 	)
 	
 	fmt.Printf("Executing query for %s: %s\n", tableName, query)
 	
-	rows, err := r.db.Query(query, args...)
+	rows, err := r.db.QueryContext(ctx, query, args...)
 	if err != nil {
 		return nil, fmt.Errorf("error fetching alerts from %s: %w", tableName, err)
 	}
@@ -186,20 +358,39 @@ func (r *MySQLRepository) fetchAlertsFromTable(tableName string, serialNumbers [
 	var alerts []map[string]interface{}
 	for rows.Next() {
 		var id int
-		var fechaActivacion, fechaDesactivacion, numeroSerie string
+		var fechaActivacion time.Time
+		var fechaDesactivacion sql.NullTime
+		var numeroSerie string
 		var estado interface{}
+		extraValues := make([]sql.NullFloat64, len(extraColumns))
 		
-		if err := rows.Scan(&id, &fechaActivacion, &fechaDesactivacion, &estado, &numeroSerie); err != nil {
+		dest := []interface{}{&id, &fechaActivacion, &fechaDesactivacion, &estado, &numeroSerie}
+		for i := range extraValues {
+			dest = append(dest, &extraValues[i])
+		}
+		if err := rows.Scan(dest...); err != nil {
 			return nil, fmt.Errorf("error scanning alert from %s: %w", tableName, err)
 		}
 		
+		// Text columns come back as raw bytes, which would be encoded as base64
+		if b, ok := estado.([]byte); ok {
+			estado = string(b)
+		}
+		
 		alert := map[string]interface{}{
 			"id":                  id,
 			"fecha_activacion":    fechaActivacion,
-			"fecha_desactivacion": fechaDesactivacion,
+			"fecha_desactivacion": nullTimeValue(fechaDesactivacion),
 			"estado":              estado,
 			"numero_serie":        numeroSerie,
 		}
+		for i, column := range extraColumns {
+			if extraValues[i].Valid {
+				alert[column] = extraValues[i].Float64
+			} else {
+				alert[column] = nil
+			}
+		}
 		
 		alerts = append(alerts, alert)
 	}
@@ -211,7 +402,24 @@ func (r *MySQLRepository) fetchAlertsFromTable(tableName string, serialNumbers [
 	return alerts, nil
 }
 
+// nullTimeValue converts a nullable column into a value that encodes as null or a timestamp
+func nullTimeValue(t sql.NullTime) interface{} {
+	if !t.Valid {
+		return nil
+	}
+	return t.Time
+}
+
 // DB returns the database connection
 func (r *MySQLRepository) DB() *sql.DB {
     return r.db
-}
\ No newline at end of file
+}
+
+// visibleDevices returns the subquery selecting the serial numbers whose alerts are read,
+// with its arguments: the devices of the user, or those shared with filter.OrganizationID
+func visibleDevices(userID int, filter *entities.AlertFilter) (string, []interface{}) {
+	if filter != nil && filter.OrganizationID != nil {
+		return organizationDevicesQuery, []interface{}{*filter.OrganizationID}
+	}
+	return accessibleDevicesQuery, []interface{}{userID, userID}
+}
@@ -0,0 +1,145 @@
+package persistence
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"io"
+	"os"
+	"strings"
+	"testing"
+	"time"
+)
+
+// latencyDriver is a database/sql driver that answers every query after a fixed
+// delay with a fixed number of rows, so the table fan-out can be measured without MySQL
+type latencyDriver struct {
+	latency time.Duration
+	rows    int
+}
+
+func (d *latencyDriver) Open(string) (driver.Conn, error) {
+	return &latencyConn{driver: d}, nil
+}
+
+type latencyConn struct {
+	driver *latencyDriver
+}
+
+func (c *latencyConn) Prepare(string) (driver.Stmt, error) {
+	return nil, driver.ErrSkip
+}
+
+func (c *latencyConn) Close() error { return nil }
+
+func (c *latencyConn) Begin() (driver.Tx, error) {
+	return nil, driver.ErrSkip
+}
+
+func (c *latencyConn) QueryContext(ctx context.Context, query string, _ []driver.NamedValue) (driver.Rows, error) {
+	select {
+	case <-time.After(c.driver.latency):
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+	return &latencyRows{columns: selectedColumns(query), remaining: c.driver.rows}, nil
+}
+
+// selectedColumns returns the column names between SELECT and FROM
+func selectedColumns(query string) []string {
+	upper := strings.ToUpper(query)
+	start := strings.Index(upper, "SELECT") + len("SELECT")
+	end := strings.Index(upper, "FROM")
+	var columns []string
+	for _, column := range strings.Split(query[start:end], ",") {
+		fields := strings.Fields(column)
+		columns = append(columns, fields[len(fields)-1])
+	}
+	return columns
+}
+
+type latencyRows struct {
+	columns   []string
+	remaining int
+}
+
+func (r *latencyRows) Columns() []string { return r.columns }
+
+func (r *latencyRows) Close() error { return nil }
+
+func (r *latencyRows) Next(dest []driver.Value) error {
+	if r.remaining == 0 {
+		return io.EOF
+	}
+	r.remaining--
+	for i, column := range r.columns {
+		switch {
+		case strings.HasPrefix(column, "fecha_"):
+			dest[i] = time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+		case column == "numero_serie":
+			dest[i] = "ESP32-0001"
+		case column == "estado" || strings.HasPrefix(column, "id"):
+			dest[i] = int64(1)
+		default:
+			dest[i] = float64(1)
+		}
+	}
+	return nil
+}
+
+func init() {
+	sql.Register("latency", &latencyDriver{latency: 2 * time.Millisecond, rows: 50})
+}
+
+// benchmarkDB opens the MySQL database named by BENCH_MYSQL_DSN, or the simulated
+// latency driver when it is unset
+func benchmarkDB(b *testing.B) *sql.DB {
+	b.Helper()
+	driverName, dsn := "latency", ""
+	if env := os.Getenv("BENCH_MYSQL_DSN"); env != "" {
+		driverName, dsn = "mysql", env
+	}
+	db, err := sql.Open(driverName, dsn)
+	if err != nil {
+		b.Fatalf("open %s: %v", driverName, err)
+	}
+	b.Cleanup(func() { db.Close() })
+	return db
+}
+
+// silenceStdout discards the query logging of the repository for the rest of the benchmark
+func silenceStdout(b *testing.B) {
+	b.Helper()
+	devNull, err := os.OpenFile(os.DevNull, os.O_WRONLY, 0)
+	if err != nil {
+		b.Fatalf("open %s: %v", os.DevNull, err)
+	}
+	stdout := os.Stdout
+	os.Stdout = devNull
+	b.Cleanup(func() {
+		os.Stdout = stdout
+		devNull.Close()
+	})
+}
+
+func benchmarkFetchAlerts(b *testing.B, concurrency int) {
+	repo := NewMySQLRepository(benchmarkDB(b), concurrency)
+	silenceStdout(b)
+	serialNumbers := []string{"ESP32-0001", "ESP32-0002"}
+	ctx := context.Background()
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, err := repo.fetchAlertsFromTables(ctx, sensorTables, serialNumbers); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkFetchAlertsSequential(b *testing.B) {
+	benchmarkFetchAlerts(b, 1)
+}
+
+func BenchmarkFetchAlertsConcurrent(b *testing.B) {
+	benchmarkFetchAlerts(b, defaultFetchConcurrency)
+}
@@ -0,0 +1,318 @@
+package persistence
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+
+	"hex_go/internal/domain/entities"
+	"hex_go/internal/domain/ports"
+)
+
+// webhookColumns are the webhooks columns read into a Webhook, in scan order
+const webhookColumns = `w.idWebhook, w.idUser, w.url, w.secreto, w.numero_serie, w.idUbicacion, w.tipos,
+	w.activo, w.fallos_consecutivos, w.fecha_creacion`
+
+// deliveryColumns are the entregas_webhook columns read into a WebhookDelivery, in scan order
+const deliveryColumns = `idEntrega, idWebhook, evento, payload, estado, intentos, proximo_intento,
+	codigo_http, error, fecha_creacion, fecha_entrega`
+
+// maxDeliveryErrorLength is the size of the entregas_webhook.error column
+const maxDeliveryErrorLength = 500
+
+// MySQLWebhookRepository implements the WebhookRepositoryPort
+type MySQLWebhookRepository struct {
+	db *sql.DB
+}
+
+// NewMySQLWebhookRepository creates a new MySQL webhook repository
+func NewMySQLWebhookRepository(db *sql.DB) *MySQLWebhookRepository {
+	return &MySQLWebhookRepository{
+		db: db,
+	}
+}
+
+// CreateWebhook inserts a webhook and sets its ID
+func (r *MySQLWebhookRepository) CreateWebhook(ctx context.Context, webhook *entities.Webhook) error {
+	result, err := r.db.ExecContext(ctx,
+		`INSERT INTO webhooks (idUser, url, secreto, numero_serie, idUbicacion, tipos, activo, fallos_consecutivos, fecha_creacion)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+		webhook.IDUser, webhook.URL, webhook.Secreto, webhook.NumeroSerie, webhook.IDUbicacion,
+		strings.Join(webhook.Tipos, ","), webhook.Activo, webhook.FallosConsecutivos, webhook.FechaCreacion)
+	if err != nil {
+		return fmt.Errorf("error creating webhook: %w", err)
+	}
+	if webhook.ID, err = result.LastInsertId(); err != nil {
+		return fmt.Errorf("error reading webhook ID: %w", err)
+	}
+
+	return nil
+}
+
+// GetWebhook returns a webhook by ID or entities.ErrNotFound
+func (r *MySQLWebhookRepository) GetWebhook(ctx context.Context, id int64) (*entities.Webhook, error) {
+	query := `SELECT ` + webhookColumns + ` FROM webhooks w WHERE w.idWebhook = ?`
+
+	webhook, err := scanWebhook(r.db.QueryRowContext(ctx, query, id))
+	if err == sql.ErrNoRows {
+		return nil, entities.ErrNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error fetching webhook %d: %w", id, err)
+	}
+
+	return webhook, nil
+}
+
+// ListUserWebhooks returns every webhook of a user
+func (r *MySQLWebhookRepository) ListUserWebhooks(ctx context.Context, userID int) ([]*entities.Webhook, error) {
+	query := `SELECT ` + webhookColumns + ` FROM webhooks w WHERE w.idUser = ? ORDER BY w.fecha_creacion`
+
+	return r.queryWebhooks(ctx, query, userID)
+}
+
+// UpdateWebhook stores the URL, alert types, state and failure count of a webhook
+func (r *MySQLWebhookRepository) UpdateWebhook(ctx context.Context, webhook *entities.Webhook) error {
+	_, err := r.db.ExecContext(ctx,
+		`UPDATE webhooks SET url = ?, tipos = ?, activo = ?, fallos_consecutivos = ? WHERE idWebhook = ?`,
+		webhook.URL, strings.Join(webhook.Tipos, ","), webhook.Activo, webhook.FallosConsecutivos, webhook.ID)
+	if err != nil {
+		return fmt.Errorf("error updating webhook %d: %w", webhook.ID, err)
+	}
+
+	return nil
+}
+
+// DeleteWebhook removes a webhook; its deliveries are removed by the foreign key
+func (r *MySQLWebhookRepository) DeleteWebhook(ctx context.Context, id int64) error {
+	result, err := r.db.ExecContext(ctx, `DELETE FROM webhooks WHERE idWebhook = ?`, id)
+	if err != nil {
+		return fmt.Errorf("error deleting webhook %d: %w", id, err)
+	}
+
+	return expectOneRow(result, entities.ErrNotFound)
+}
+
+// ListWebhooksForDevice returns the active webhooks of the device owner that watch the
+// device or a location whose subtree contains it
+func (r *MySQLWebhookRepository) ListWebhooksForDevice(ctx context.Context, numeroSerie string) ([]*entities.Webhook, error) {
+	query := `SELECT ` + webhookColumns + ` FROM webhooks w
+		JOIN ESP32 e ON e.numero_serie = ? AND e.idUser = w.idUser
+		LEFT JOIN ubicaciones u ON u.idUbicacion = e.idUbicacion
+		LEFT JOIN ubicaciones wu ON wu.idUbicacion = w.idUbicacion
+		WHERE w.activo = TRUE
+			AND (w.numero_serie = e.numero_serie OR (u.ruta IS NOT NULL AND u.ruta LIKE CONCAT(wu.ruta, '%')))`
+
+	return r.queryWebhooks(ctx, query, numeroSerie)
+}
+
+// RecordWebhookResult updates the failure count of a webhook after a delivery attempt
+func (r *MySQLWebhookRepository) RecordWebhookResult(ctx context.Context, id int64, success bool, disableAfter int) (bool, error) {
+	if success {
+		_, err := r.db.ExecContext(ctx, `UPDATE webhooks SET fallos_consecutivos = 0 WHERE idWebhook = ?`, id)
+		if err != nil {
+			return false, fmt.Errorf("error resetting failures of webhook %d: %w", id, err)
+		}
+		return false, nil
+	}
+
+	_, err := r.db.ExecContext(ctx,
+		`UPDATE webhooks SET fallos_consecutivos = fallos_consecutivos + 1 WHERE idWebhook = ?`, id)
+	if err != nil {
+		return false, fmt.Errorf("error counting failure of webhook %d: %w", id, err)
+	}
+
+	// Only the update that turns the webhook off reports it as disabled
+	result, err := r.db.ExecContext(ctx,
+		`UPDATE webhooks SET activo = FALSE WHERE idWebhook = ? AND activo = TRUE AND fallos_consecutivos >= ?`,
+		id, disableAfter)
+	if err != nil {
+		return false, fmt.Errorf("error disabling webhook %d: %w", id, err)
+	}
+	if err := expectOneRow(result, entities.ErrNotFound); err != nil {
+		if err == entities.ErrNotFound {
+			return false, nil
+		}
+		return false, err
+	}
+
+	return true, nil
+}
+
+// CreateDelivery inserts a delivery and sets its ID
+func (r *MySQLWebhookRepository) CreateDelivery(ctx context.Context, delivery *entities.WebhookDelivery) error {
+	result, err := r.db.ExecContext(ctx,
+		`INSERT INTO entregas_webhook (idWebhook, evento, payload, estado, intentos, proximo_intento, fecha_creacion)
+		VALUES (?, ?, ?, ?, ?, ?, ?)`,
+		delivery.IDWebhook, delivery.Evento, delivery.Payload, delivery.Estado, delivery.Intentos,
+		delivery.ProximoIntento, delivery.FechaCreacion)
+	if err != nil {
+		return fmt.Errorf("error creating webhook delivery: %w", err)
+	}
+	if delivery.ID, err = result.LastInsertId(); err != nil {
+		return fmt.Errorf("error reading webhook delivery ID: %w", err)
+	}
+
+	return nil
+}
+
+// ListDeliveries returns the latest deliveries of a webhook, newest first
+func (r *MySQLWebhookRepository) ListDeliveries(ctx context.Context, webhookID int64, limit int) ([]*entities.WebhookDelivery, error) {
+	query := `SELECT ` + deliveryColumns + ` FROM entregas_webhook
+		WHERE idWebhook = ? ORDER BY idEntrega DESC LIMIT ?`
+
+	return r.queryDeliveries(ctx, query, webhookID, limit)
+}
+
+// ListDueDeliveries returns the pending deliveries whose next attempt is due, oldest first
+func (r *MySQLWebhookRepository) ListDueDeliveries(ctx context.Context, now time.Time, limit int) ([]*entities.WebhookDelivery, error) {
+	query := `SELECT ` + deliveryColumns + ` FROM entregas_webhook
+		WHERE estado = ? AND proximo_intento <= ? ORDER BY proximo_intento LIMIT ?`
+
+	return r.queryDeliveries(ctx, query, entities.DeliveryPending, now, limit)
+}
+
+// ClaimDelivery moves the next attempt of a delivery forward if no one else did it first
+func (r *MySQLWebhookRepository) ClaimDelivery(ctx context.Context, delivery *entities.WebhookDelivery, leaseUntil time.Time) (bool, error) {
+	result, err := r.db.ExecContext(ctx,
+		`UPDATE entregas_webhook SET proximo_intento = ?
+		WHERE idEntrega = ? AND estado = ? AND proximo_intento = ?`,
+		leaseUntil, delivery.ID, entities.DeliveryPending, delivery.ProximoIntento)
+	if err != nil {
+		return false, fmt.Errorf("error claiming webhook delivery %d: %w", delivery.ID, err)
+	}
+	if err := expectOneRow(result, entities.ErrConflict); err != nil {
+		if err == entities.ErrConflict {
+			return false, nil
+		}
+		return false, err
+	}
+
+	delivery.ProximoIntento = &leaseUntil
+	return true, nil
+}
+
+// UpdateDelivery stores the outcome of a delivery attempt
+func (r *MySQLWebhookRepository) UpdateDelivery(ctx context.Context, delivery *entities.WebhookDelivery) error {
+	_, err := r.db.ExecContext(ctx,
+		`UPDATE entregas_webhook SET estado = ?, intentos = ?, proximo_intento = ?, codigo_http = ?, error = ?, fecha_entrega = ?
+		WHERE idEntrega = ?`,
+		delivery.Estado, delivery.Intentos, delivery.ProximoIntento, delivery.CodigoHTTP,
+		nullString(truncate(delivery.Error, maxDeliveryErrorLength)), delivery.FechaEntrega, delivery.ID)
+	if err != nil {
+		return fmt.Errorf("error updating webhook delivery %d: %w", delivery.ID, err)
+	}
+
+	return nil
+}
+
+// FailPendingDeliveries marks every pending delivery of a webhook as failed
+func (r *MySQLWebhookRepository) FailPendingDeliveries(ctx context.Context, webhookID int64, reason string) error {
+	_, err := r.db.ExecContext(ctx,
+		`UPDATE entregas_webhook SET estado = ?, proximo_intento = NULL, error = ? WHERE idWebhook = ? AND estado = ?`,
+		entities.DeliveryFailed, truncate(reason, maxDeliveryErrorLength), webhookID, entities.DeliveryPending)
+	if err != nil {
+		return fmt.Errorf("error failing deliveries of webhook %d: %w", webhookID, err)
+	}
+
+	return nil
+}
+
+func (r *MySQLWebhookRepository) queryWebhooks(ctx context.Context, query string, args ...interface{}) ([]*entities.Webhook, error) {
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("error fetching webhooks: %w", err)
+	}
+	defer rows.Close()
+
+	webhooks := []*entities.Webhook{}
+	for rows.Next() {
+		webhook, err := scanWebhook(rows)
+		if err != nil {
+			return nil, fmt.Errorf("error scanning webhook: %w", err)
+		}
+		webhooks = append(webhooks, webhook)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating webhooks: %w", err)
+	}
+
+	return webhooks, nil
+}
+
+func (r *MySQLWebhookRepository) queryDeliveries(ctx context.Context, query string, args ...interface{}) ([]*entities.WebhookDelivery, error) {
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("error fetching webhook deliveries: %w", err)
+	}
+	defer rows.Close()
+
+	deliveries := []*entities.WebhookDelivery{}
+	for rows.Next() {
+		var d entities.WebhookDelivery
+		var proximoIntento, fechaEntrega sql.NullTime
+		var codigoHTTP sql.NullInt64
+		var deliveryErr sql.NullString
+		err := rows.Scan(&d.ID, &d.IDWebhook, &d.Evento, &d.Payload, &d.Estado, &d.Intentos, &proximoIntento,
+			&codigoHTTP, &deliveryErr, &d.FechaCreacion, &fechaEntrega)
+		if err != nil {
+			return nil, fmt.Errorf("error scanning webhook delivery: %w", err)
+		}
+		if proximoIntento.Valid {
+			d.ProximoIntento = &proximoIntento.Time
+		}
+		if codigoHTTP.Valid {
+			code := int(codigoHTTP.Int64)
+			d.CodigoHTTP = &code
+		}
+		d.Error = deliveryErr.String
+		if fechaEntrega.Valid {
+			d.FechaEntrega = &fechaEntrega.Time
+		}
+		deliveries = append(deliveries, &d)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating webhook deliveries: %w", err)
+	}
+
+	return deliveries, nil
+}
+
+func scanWebhook(row rowScanner) (*entities.Webhook, error) {
+	var w entities.Webhook
+	var numeroSerie sql.NullString
+	var idUbicacion sql.NullInt64
+	var tipos string
+	err := row.Scan(&w.ID, &w.IDUser, &w.URL, &w.Secreto, &numeroSerie, &idUbicacion, &tipos,
+		&w.Activo, &w.FallosConsecutivos, &w.FechaCreacion)
+	if err != nil {
+		return nil, err
+	}
+	if numeroSerie.Valid {
+		w.NumeroSerie = &numeroSerie.String
+	}
+	if idUbicacion.Valid {
+		w.IDUbicacion = &idUbicacion.Int64
+	}
+	w.Tipos = []string{}
+	if tipos != "" {
+		w.Tipos = strings.Split(tipos, ",")
+	}
+	return &w, nil
+}
+
+// truncate cuts a message to fit a column of max characters
+func truncate(message string, max int) string {
+	runes := []rune(message)
+	if len(runes) <= max {
+		return message
+	}
+	return string(runes[:max])
+}
+
+// Verify interface implementation
+var _ ports.WebhookRepositoryPort = (*MySQLWebhookRepository)(nil)
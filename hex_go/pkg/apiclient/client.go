@@ -0,0 +1,158 @@
+// Package apiclient is a typed client of the StopFire API. Its types and operations are
+// generated from api/openapi.json; run go generate in this directory after changing the
+// specification.
+package apiclient
+
+//go:generate go run ../../cmd/apiclient-gen -spec ../../api/openapi.json -out generated.go
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Client calls the API as one caller: a user, an organisation's API key or a device
+type Client struct {
+	baseURL    string
+	httpClient *http.Client
+	header     http.Header
+}
+
+// Option configures a Client
+type Option func(*Client)
+
+// WithHTTPClient sends the requests with c instead of a client with a 30 second timeout
+func WithHTTPClient(c *http.Client) Option {
+	return func(client *Client) {
+		client.httpClient = c
+	}
+}
+
+// WithUserID calls the API as a user, as the gateway in front of it does
+func WithUserID(userID int) Option {
+	return WithHeader("X-User-ID", strconv.Itoa(userID))
+}
+
+// WithAPIKey calls the API with an organisation's API key
+func WithAPIKey(key string) Option {
+	return WithHeader("X-API-Key", key)
+}
+
+// WithDevice calls the API as a device, with the token returned when it was claimed
+func WithDevice(numeroSerie, token string) Option {
+	return func(client *Client) {
+		client.header.Set("X-Device-Serial", numeroSerie)
+		client.header.Set("X-Device-Token", token)
+	}
+}
+
+// WithHeader sends a header with every request, such as Accept-Language
+func WithHeader(name, value string) Option {
+	return func(client *Client) {
+		client.header.Set(name, value)
+	}
+}
+
+// New creates a client of the API at baseURL, such as "https://stopfire.example.com"
+func New(baseURL string, opts ...Option) *Client {
+	c := &Client{
+		baseURL:    strings.TrimSuffix(baseURL, "/"),
+		httpClient: &http.Client{Timeout: 30 * time.Second},
+		header:     make(http.Header),
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+	return c
+}
+
+// Error is returned for responses with an error status, with the problem details the
+// API sent
+type Error struct {
+	StatusCode int
+	Problem    *Problem
+}
+
+func (e *Error) Error() string {
+	if e.Problem == nil || e.Problem.Detail == "" {
+		return fmt.Sprintf("stopfire: %d %s", e.StatusCode, http.StatusText(e.StatusCode))
+	}
+	return fmt.Sprintf("stopfire: %d %s", e.StatusCode, e.Problem.Detail)
+}
+
+// do sends a request with body encoded as JSON, if any, and decodes the JSON response
+// into out unless it is nil
+func (c *Client) do(ctx context.Context, method, path string, query url.Values, body, out interface{}) error {
+	resp, err := c.send(ctx, method, path, query, body)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if out == nil {
+		io.Copy(io.Discard, resp.Body)
+		return nil
+	}
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		return fmt.Errorf("failed to decode response of %s %s: %w", method, path, err)
+	}
+	return nil
+}
+
+// download sends a request and returns the body of a file response
+func (c *Client) download(ctx context.Context, method, path string, query url.Values) (io.ReadCloser, error) {
+	resp, err := c.send(ctx, method, path, query, nil)
+	if err != nil {
+		return nil, err
+	}
+	return resp.Body, nil
+}
+
+// send sends a request, turning error statuses into an *Error
+func (c *Client) send(ctx context.Context, method, path string, query url.Values, body interface{}) (*http.Response, error) {
+	target := c.baseURL + path
+	if len(query) > 0 {
+		target += "?" + query.Encode()
+	}
+
+	var reader io.Reader
+	if body != nil {
+		data, err := json.Marshal(body)
+		if err != nil {
+			return nil, fmt.Errorf("failed to encode request of %s %s: %w", method, path, err)
+		}
+		reader = bytes.NewReader(data)
+	}
+	req, err := http.NewRequestWithContext(ctx, method, target, reader)
+	if err != nil {
+		return nil, err
+	}
+	for name, values := range c.header {
+		req.Header[name] = values
+	}
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode >= http.StatusBadRequest {
+		defer resp.Body.Close()
+		apiErr := &Error{StatusCode: resp.StatusCode}
+		var problem Problem
+		if json.NewDecoder(resp.Body).Decode(&problem) == nil {
+			apiErr.Problem = &problem
+		}
+		return nil, apiErr
+	}
+	return resp, nil
+}
@@ -0,0 +1,1565 @@
+// Code generated by apiclient-gen from ../../api/openapi.json. DO NOT EDIT.
+
+package apiclient
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/url"
+	"strconv"
+	"time"
+)
+
+// Problem is an RFC 7807 problem details body, returned by every error
+type Problem struct {
+	Type     string             `json:"type"`
+	Title    string             `json:"title"`
+	Status   int                `json:"status"`
+	Detail   string             `json:"detail,omitempty"`
+	Instance string             `json:"instance,omitempty"`
+	Errors   []*ValidationError `json:"errors,omitempty"`
+}
+
+// ValidationError is an invalid field of a request
+type ValidationError struct {
+	Field   string `json:"field"`
+	Message string `json:"message"`
+}
+
+// Message is a confirmation message
+type Message struct {
+	Message string `json:"message"`
+}
+
+// SensorDataRequest is a reading sent by a device. The serial number is numeroSerie here,
+// unlike numero_serie everywhere else.
+type SensorDataRequest struct {
+	NumeroSerie string `json:"numeroSerie"`
+	// One of KY_026, MQ_2, MQ_135, DHT_22
+	Sensor string `json:"sensor"`
+	// RFC 3339 or "2006-01-02 15:04:05" text, or Unix seconds or milliseconds
+	FechaActivacion interface{} `json:"fecha_activacion"`
+	// Same formats as fecha_activacion
+	FechaDesactivacion interface{} `json:"fecha_desactivacion,omitempty"`
+	// 0 or 1 for KY_026, MQ_2 and MQ_135; "temperatura,humedad" text for DHT_22 unless sent in temperatura and humedad
+	Estado      interface{} `json:"estado"`
+	Temperatura *float64    `json:"temperatura,omitempty"`
+	Humedad     *float64    `json:"humedad,omitempty"`
+	// Computed by the server; ignored when sent
+	IndiceCalor *float64 `json:"indice_calor,omitempty"`
+	// Computed by the server; ignored when sent
+	PuntoRocio *float64 `json:"punto_rocio,omitempty"`
+	// Computed by the server; ignored when sent
+	PPM *float64 `json:"ppm,omitempty"`
+}
+
+// UserAlerts is the readings of the caller's devices per sensor table. Users get user_id
+// and API keys organization_id; without devices only message and an empty alerts are
+// sent.
+type UserAlerts struct {
+	UserID         int      `json:"user_id,omitempty"`
+	OrganizationID int64    `json:"organization_id,omitempty"`
+	Devices        []string `json:"devices,omitempty"`
+	// Readings keyed by sensor table
+	Alerts map[string][]*SensorAlert `json:"alerts"`
+	// Only with group_by
+	Groups  []*AlertGroup `json:"groups,omitempty"`
+	Message string        `json:"message,omitempty"`
+}
+
+// SensorAlert is a stored reading of a sensor table; DHT_22 rows carry the climate values
+// and calibrated MQ_2 and MQ_135 rows ppm
+type SensorAlert struct {
+	ID                 int        `json:"id"`
+	FechaActivacion    time.Time  `json:"fecha_activacion"`
+	FechaDesactivacion *time.Time `json:"fecha_desactivacion"`
+	// An integer, or "temperatura,humedad" text for DHT_22
+	Estado      interface{} `json:"estado"`
+	NumeroSerie string      `json:"numero_serie"`
+	Temperatura *float64    `json:"temperatura,omitempty"`
+	Humedad     *float64    `json:"humedad,omitempty"`
+	IndiceCalor *float64    `json:"indice_calor,omitempty"`
+	PuntoRocio  *float64    `json:"punto_rocio,omitempty"`
+	PPM         *float64    `json:"ppm,omitempty"`
+}
+
+// Alert is an alert raised by a reading, an anomaly or a device going offline. Repeats of
+// an active alert raise ocurrencias instead of a new alert.
+type Alert struct {
+	ID          int64  `json:"id"`
+	NumeroSerie string `json:"numero_serie"`
+	// One of KY_026, MQ_2, MQ_135, DHT_22, DEVICE_OFFLINE, ANOMALY
+	Tipo string `json:"tipo"`
+	// One of critical, warning, info
+	Severidad           string     `json:"severidad"`
+	Mensaje             string     `json:"mensaje"`
+	Valor               *float64   `json:"valor,omitempty"`
+	Estado              string     `json:"estado"`
+	FechaCreacion       time.Time  `json:"fecha_creacion"`
+	ReconocidaPor       *int       `json:"reconocida_por,omitempty"`
+	FechaReconocimiento *time.Time `json:"fecha_reconocimiento,omitempty"`
+	Ocurrencias         int        `json:"ocurrencias"`
+	FechaUltima         time.Time  `json:"fecha_ultima"`
+	Oscilante           bool       `json:"oscilante"`
+	IDIncidente         *int64     `json:"id_incidente,omitempty"`
+	Suprimida           bool       `json:"suprimida"`
+	IDMantenimiento     *int64     `json:"id_mantenimiento,omitempty"`
+}
+
+// AlertGroup is the alerts of the devices under one location of the group_by level
+type AlertGroup struct {
+	Ubicacion *Location      `json:"ubicacion"`
+	Total     int            `json:"total"`
+	Activas   int            `json:"activas"`
+	PorTipo   map[string]int `json:"por_tipo"`
+}
+
+// Incident is a group of alerts of nearby devices raised close in time
+type Incident struct {
+	ID          int64   `json:"id"`
+	IDUbicacion *int64  `json:"id_ubicacion,omitempty"`
+	NumeroSerie *string `json:"numero_serie,omitempty"`
+	// One of critical, warning, info
+	Severidad string `json:"severidad"`
+	// One of abierto, reconocido, resuelto
+	Estado              string           `json:"estado"`
+	TotalAlertas        int              `json:"total_alertas"`
+	FechaInicio         time.Time        `json:"fecha_inicio"`
+	FechaUltima         time.Time        `json:"fecha_ultima"`
+	ReconocidoPor       *int             `json:"reconocido_por,omitempty"`
+	FechaReconocimiento *time.Time       `json:"fecha_reconocimiento,omitempty"`
+	ResueltoPor         *int             `json:"resuelto_por,omitempty"`
+	FechaResolucion     *time.Time       `json:"fecha_resolucion,omitempty"`
+	Alertas             []*Alert         `json:"alertas,omitempty"`
+	Eventos             []*IncidentEvent `json:"eventos,omitempty"`
+}
+
+// IncidentEvent is an event in the life of an incident
+type IncidentEvent struct {
+	ID       int64     `json:"id"`
+	Tipo     string    `json:"tipo"`
+	IDAlerta *int64    `json:"id_alerta,omitempty"`
+	IDUser   *int      `json:"id_user,omitempty"`
+	Detalle  string    `json:"detalle,omitempty"`
+	Fecha    time.Time `json:"fecha"`
+}
+
+// Device is an ESP32 device
+type Device struct {
+	NumeroSerie string `json:"numero_serie"`
+	Nombre      string `json:"nombre"`
+	Ubicacion   string `json:"ubicacion"`
+	// One of KY_026, MQ_2, MQ_135, DHT_22
+	SensoresInstalados []string   `json:"sensores_instalados"`
+	VersionFirmware    string     `json:"version_firmware"`
+	ZonaHoraria        string     `json:"zona_horaria"`
+	IDUbicacion        *int64     `json:"id_ubicacion"`
+	IDUser             *int       `json:"id_user"`
+	FechaRegistro      *time.Time `json:"fecha_registro"`
+	UltimaConexion     *time.Time `json:"ultima_conexion"`
+	EnLinea            bool       `json:"en_linea"`
+}
+
+// ClaimedDevice is a newly claimed device, with the only copy of its token
+type ClaimedDevice struct {
+	NumeroSerie string `json:"numero_serie"`
+	Nombre      string `json:"nombre"`
+	Ubicacion   string `json:"ubicacion"`
+	// One of KY_026, MQ_2, MQ_135, DHT_22
+	SensoresInstalados []string   `json:"sensores_instalados"`
+	VersionFirmware    string     `json:"version_firmware"`
+	ZonaHoraria        string     `json:"zona_horaria"`
+	IDUbicacion        *int64     `json:"id_ubicacion"`
+	IDUser             *int       `json:"id_user"`
+	FechaRegistro      *time.Time `json:"fecha_registro"`
+	UltimaConexion     *time.Time `json:"ultima_conexion"`
+	EnLinea            bool       `json:"en_linea"`
+	// Sent by the board in X-Device-Token
+	Token string `json:"token"`
+}
+
+// ClaimDeviceRequest is a device to claim, with the claim code printed on it and its
+// initial settings
+type ClaimDeviceRequest struct {
+	NumeroSerie   string `json:"numero_serie"`
+	CodigoReclamo string `json:"codigo_reclamo"`
+	Nombre        string `json:"nombre,omitempty"`
+	Ubicacion     string `json:"ubicacion,omitempty"`
+	// One of KY_026, MQ_2, MQ_135, DHT_22
+	SensoresInstalados []string `json:"sensores_instalados,omitempty"`
+	VersionFirmware    string   `json:"version_firmware,omitempty"`
+	ZonaHoraria        string   `json:"zona_horaria,omitempty"`
+}
+
+// UpdateDeviceRequest is the settings of a device to change; missing fields are left as
+// they are
+type UpdateDeviceRequest struct {
+	Nombre    *string `json:"nombre,omitempty"`
+	Ubicacion *string `json:"ubicacion,omitempty"`
+	// One of KY_026, MQ_2, MQ_135, DHT_22
+	SensoresInstalados []string `json:"sensores_instalados,omitempty"`
+	VersionFirmware    *string  `json:"version_firmware,omitempty"`
+	ZonaHoraria        *string  `json:"zona_horaria,omitempty"`
+}
+
+// TransferDeviceRequest is the user a device is handed over to
+type TransferDeviceRequest struct {
+	NuevoIDUser int `json:"nuevo_id_user"`
+}
+
+// HeartbeatRequest is the optional body of a heartbeat
+type HeartbeatRequest struct {
+	VersionFirmware string `json:"version_firmware,omitempty"`
+}
+
+// AggregateSeries: Readings of one sensor summarised per bucket; fuente tells whether raw
+// readings or hourly rollups were read
+type AggregateSeries struct {
+	NumeroSerie string `json:"numero_serie"`
+	// One of KY_026, MQ_2, MQ_135, DHT_22
+	Sensor string `json:"sensor"`
+	// One of 1m, 5m, 15m, 1h, 6h, 1d
+	Bucket  string             `json:"bucket"`
+	Desde   time.Time          `json:"desde"`
+	Hasta   time.Time          `json:"hasta"`
+	Campos  []string           `json:"campos"`
+	Fuente  string             `json:"fuente"`
+	Buckets []*AggregateBucket `json:"buckets"`
+}
+
+// AggregateBucket is the readings of one bucket; empty buckets have no lecturas
+type AggregateBucket struct {
+	Inicio   time.Time                  `json:"inicio"`
+	Lecturas int                        `json:"lecturas"`
+	Valores  map[string]*AggregateStats `json:"valores"`
+}
+
+// AggregateStats is the summary of one field within a bucket
+type AggregateStats struct {
+	Cantidad int      `json:"cantidad"`
+	Minimo   *float64 `json:"minimo"`
+	Maximo   *float64 `json:"maximo"`
+	Media    *float64 `json:"media"`
+}
+
+// SensorCalibration is the calibration and learned baseline of a gas sensor of a device
+type SensorCalibration struct {
+	NumeroSerie string `json:"numero_serie"`
+	// One of KY_026, MQ_2, MQ_135, DHT_22
+	Sensor             string    `json:"sensor"`
+	Offset             float64   `json:"offset"`
+	Ganancia           float64   `json:"ganancia"`
+	LineaBase          float64   `json:"linea_base"`
+	Muestras           int       `json:"muestras"`
+	InicioAprendizaje  time.Time `json:"inicio_aprendizaje"`
+	FinAprendizaje     time.Time `json:"fin_aprendizaje"`
+	FechaActualizacion time.Time `json:"fecha_actualizacion"`
+}
+
+// CalibrationRequest is the offset and gain applied to the raw readings of a sensor
+type CalibrationRequest struct {
+	Offset   float64 `json:"offset"`
+	Ganancia float64 `json:"ganancia"`
+}
+
+// Location is a node of the location tree: site, building, floor or room
+type Location struct {
+	ID      int64  `json:"id"`
+	IDPadre *int64 `json:"id_padre"`
+	// One of sitio, edificio, piso, habitacion
+	Nivel     string `json:"nivel"`
+	Nombre    string `json:"nombre"`
+	Direccion string `json:"direccion,omitempty"`
+	IDUser    int    `json:"id_user"`
+}
+
+// CreateLocationRequest is a location to create under id_padre, or a site without it
+type CreateLocationRequest struct {
+	IDPadre *int64 `json:"id_padre,omitempty"`
+	// One of sitio, edificio, piso, habitacion
+	Nivel     string `json:"nivel"`
+	Nombre    string `json:"nombre"`
+	Direccion string `json:"direccion,omitempty"`
+}
+
+// UpdateLocationRequest is the fields of a location to change; missing fields are left as
+// they are
+type UpdateLocationRequest struct {
+	Nombre    *string `json:"nombre,omitempty"`
+	Direccion *string `json:"direccion,omitempty"`
+}
+
+// AssignLocationRequest is the location a device is placed in, or null to take it out
+type AssignLocationRequest struct {
+	IDUbicacion *int64 `json:"id_ubicacion,omitempty"`
+}
+
+// LocationStatus is the devices and active alerts under a location
+type LocationStatus struct {
+	Ubicacion                *Location       `json:"ubicacion"`
+	TotalDispositivos        int             `json:"total_dispositivos"`
+	DispositivosFueraDeLinea int             `json:"dispositivos_fuera_de_linea"`
+	AlertasActivas           map[string]int  `json:"alertas_activas"`
+	PeorSeveridad            string          `json:"peor_severidad,omitempty"`
+	Dispositivos             []*DeviceStatus `json:"dispositivos"`
+}
+
+// DeviceStatus is the connectivity and active alerts of one device of a location status
+type DeviceStatus struct {
+	NumeroSerie    string         `json:"numero_serie"`
+	Nombre         string         `json:"nombre"`
+	IDUbicacion    *int64         `json:"id_ubicacion"`
+	EnLinea        bool           `json:"en_linea"`
+	AlertasActivas map[string]int `json:"alertas_activas"`
+	PeorSeveridad  string         `json:"peor_severidad,omitempty"`
+}
+
+// Organization is an organisation devices are shared with; rol is the role of the caller
+type Organization struct {
+	ID            int64     `json:"id"`
+	Nombre        string    `json:"nombre"`
+	FechaCreacion time.Time `json:"fecha_creacion"`
+	Rol           string    `json:"rol,omitempty"`
+}
+
+// CreateOrganizationRequest is an organisation to create, owned by the caller
+type CreateOrganizationRequest struct {
+	Nombre string `json:"nombre"`
+}
+
+// Membership is a member of an organisation
+type Membership struct {
+	IDOrganizacion int64 `json:"id_organizacion"`
+	IDUser         int   `json:"id_user"`
+	// One of owner, admin, viewer, responder
+	Rol       string    `json:"rol"`
+	FechaAlta time.Time `json:"fecha_alta"`
+}
+
+// UpdateMemberRequest is the new role of a member
+type UpdateMemberRequest struct {
+	// One of owner, admin, viewer, responder
+	Rol string `json:"rol"`
+}
+
+// Invitation is an invitation to join an organisation
+type Invitation struct {
+	ID             int64 `json:"id"`
+	IDOrganizacion int64 `json:"id_organizacion"`
+	IDUserInvitado int   `json:"id_user_invitado"`
+	// One of owner, admin, viewer, responder
+	Rol             string    `json:"rol"`
+	Estado          string    `json:"estado"`
+	InvitadoPor     int       `json:"invitado_por"`
+	FechaCreacion   time.Time `json:"fecha_creacion"`
+	FechaExpiracion time.Time `json:"fecha_expiracion"`
+}
+
+// InviteMemberRequest is a user to invite and the role they would get
+type InviteMemberRequest struct {
+	IDUser int `json:"id_user"`
+	// One of owner, admin, viewer, responder
+	Rol string `json:"rol"`
+}
+
+// SharedDevice is a device shared with an organisation
+type SharedDevice struct {
+	IDOrganizacion int64     `json:"id_organizacion"`
+	NumeroSerie    string    `json:"numero_serie"`
+	CompartidoPor  int       `json:"compartido_por"`
+	FechaAlta      time.Time `json:"fecha_alta"`
+}
+
+// ShareDeviceRequest is a device of the caller to share
+type ShareDeviceRequest struct {
+	NumeroSerie string `json:"numero_serie"`
+}
+
+// AccessAuditEntry is a change to the members or shared devices of an organisation
+type AccessAuditEntry struct {
+	ID             int64     `json:"id"`
+	IDOrganizacion int64     `json:"id_organizacion"`
+	Actor          int       `json:"actor"`
+	Accion         string    `json:"accion"`
+	Objetivo       string    `json:"objetivo"`
+	Detalle        string    `json:"detalle,omitempty"`
+	Fecha          time.Time `json:"fecha"`
+}
+
+// APIKey is an API key of an organisation; the key itself is only shown when it is issued
+type APIKey struct {
+	ID             int64  `json:"id"`
+	IDOrganizacion int64  `json:"id_organizacion"`
+	Nombre         string `json:"nombre"`
+	Prefijo        string `json:"prefijo"`
+	// One of alerts:read, alerts:acknowledge, readings:read
+	Scopes          []string   `json:"scopes"`
+	CreadaPor       int        `json:"creada_por"`
+	FechaCreacion   time.Time  `json:"fecha_creacion"`
+	FechaExpiracion time.Time  `json:"fecha_expiracion"`
+	UltimoUso       *time.Time `json:"ultimo_uso"`
+	Revocada        bool       `json:"revocada"`
+}
+
+// IssuedAPIKey is a newly issued API key, with the only copy of the key
+type IssuedAPIKey struct {
+	ID             int64  `json:"id"`
+	IDOrganizacion int64  `json:"id_organizacion"`
+	Nombre         string `json:"nombre"`
+	Prefijo        string `json:"prefijo"`
+	// One of alerts:read, alerts:acknowledge, readings:read
+	Scopes          []string   `json:"scopes"`
+	CreadaPor       int        `json:"creada_por"`
+	FechaCreacion   time.Time  `json:"fecha_creacion"`
+	FechaExpiracion time.Time  `json:"fecha_expiracion"`
+	UltimoUso       *time.Time `json:"ultimo_uso"`
+	Revocada        bool       `json:"revocada"`
+	Clave           string     `json:"clave"`
+}
+
+// CreateAPIKeyRequest is an API key to issue; it never expires when expira_en_dias is 0
+type CreateAPIKeyRequest struct {
+	Nombre string `json:"nombre"`
+	// One of alerts:read, alerts:acknowledge, readings:read
+	Scopes       []string `json:"scopes"`
+	ExpiraEnDias int      `json:"expira_en_dias,omitempty"`
+}
+
+// Webhook is an URL alerts are posted to
+type Webhook struct {
+	ID          int64   `json:"id"`
+	IDUser      int     `json:"id_user"`
+	URL         string  `json:"url"`
+	NumeroSerie *string `json:"numero_serie,omitempty"`
+	IDUbicacion *int64  `json:"id_ubicacion,omitempty"`
+	// One of KY_026, MQ_2, MQ_135, DHT_22, DEVICE_OFFLINE, ANOMALY
+	Tipos              []string  `json:"tipos"`
+	Activo             bool      `json:"activo"`
+	FallosConsecutivos int       `json:"fallos_consecutivos"`
+	FechaCreacion      time.Time `json:"fecha_creacion"`
+}
+
+// CreatedWebhook is a newly created webhook, with the only copy of its signing secret
+type CreatedWebhook struct {
+	ID          int64   `json:"id"`
+	IDUser      int     `json:"id_user"`
+	URL         string  `json:"url"`
+	NumeroSerie *string `json:"numero_serie,omitempty"`
+	IDUbicacion *int64  `json:"id_ubicacion,omitempty"`
+	// One of KY_026, MQ_2, MQ_135, DHT_22, DEVICE_OFFLINE, ANOMALY
+	Tipos              []string  `json:"tipos"`
+	Activo             bool      `json:"activo"`
+	FallosConsecutivos int       `json:"fallos_consecutivos"`
+	FechaCreacion      time.Time `json:"fecha_creacion"`
+	Secreto            string    `json:"secreto"`
+}
+
+// CreateWebhookRequest is a webhook to create, optionally limited to a device, a location
+// or some alert types
+type CreateWebhookRequest struct {
+	URL         string  `json:"url"`
+	NumeroSerie *string `json:"numero_serie,omitempty"`
+	IDUbicacion *int64  `json:"id_ubicacion,omitempty"`
+	// One of KY_026, MQ_2, MQ_135, DHT_22, DEVICE_OFFLINE, ANOMALY
+	Tipos []string `json:"tipos,omitempty"`
+}
+
+// UpdateWebhookRequest is the fields of a webhook to change; missing fields are left as
+// they are
+type UpdateWebhookRequest struct {
+	URL *string `json:"url,omitempty"`
+	// One of KY_026, MQ_2, MQ_135, DHT_22, DEVICE_OFFLINE, ANOMALY
+	Tipos  []string `json:"tipos,omitempty"`
+	Activo *bool    `json:"activo,omitempty"`
+}
+
+// WebhookDelivery: One event posted to a webhook
+type WebhookDelivery struct {
+	ID             int64      `json:"id"`
+	IDWebhook      int64      `json:"id_webhook"`
+	Evento         string     `json:"evento"`
+	Payload        string     `json:"payload"`
+	Estado         string     `json:"estado"`
+	Intentos       int        `json:"intentos"`
+	ProximoIntento *time.Time `json:"proximo_intento,omitempty"`
+	CodigoHTTP     *int       `json:"codigo_http,omitempty"`
+	Error          string     `json:"error,omitempty"`
+	FechaCreacion  time.Time  `json:"fecha_creacion"`
+	FechaEntrega   *time.Time `json:"fecha_entrega,omitempty"`
+}
+
+// NotificationRecipient is an address alerts are sent to
+type NotificationRecipient struct {
+	ID     int64 `json:"id"`
+	IDUser int   `json:"id_user"`
+	// One of email, sms, telegram, push
+	Canal     string `json:"canal"`
+	Direccion string `json:"direccion"`
+	// One of es, en
+	Idioma        string    `json:"idioma"`
+	FechaCreacion time.Time `json:"fecha_creacion"`
+}
+
+// CreateRecipientRequest is an address to notify; idioma defaults to Spanish
+type CreateRecipientRequest struct {
+	// One of email, sms, telegram, push
+	Canal     string `json:"canal"`
+	Direccion string `json:"direccion"`
+	// One of es, en
+	Idioma string `json:"idioma,omitempty"`
+}
+
+// NotificationChannelSettings is the channels used for each severity
+type NotificationChannelSettings struct {
+	// The channels of each severity, keyed by severity
+	Canales map[string][]string `json:"canales"`
+}
+
+// NotificationDelivery: One attempt to notify a recipient
+type NotificationDelivery struct {
+	ID             int64  `json:"id"`
+	IDAlerta       int64  `json:"id_alerta"`
+	IDDestinatario *int64 `json:"id_destinatario,omitempty"`
+	IDEscalacion   *int64 `json:"id_escalacion,omitempty"`
+	IDUser         int    `json:"id_user"`
+	// One of email, sms, telegram, push
+	Canal         string    `json:"canal"`
+	Direccion     string    `json:"direccion"`
+	Estado        string    `json:"estado"`
+	Error         string    `json:"error,omitempty"`
+	FechaCreacion time.Time `json:"fecha_creacion"`
+}
+
+// NotificationSettings is the quiet hours, digests and minimum severity per channel of a
+// user
+type NotificationSettings struct {
+	ZonaHoraria   string      `json:"zona_horaria"`
+	HorasSilencio *QuietHours `json:"horas_silencio"`
+	// One of none, hourly, daily
+	Resumen     string `json:"resumen"`
+	HoraResumen int    `json:"hora_resumen"`
+	// The lowest severity notified on each channel, keyed by channel
+	SeveridadMinima map[string]string `json:"severidad_minima"`
+}
+
+// QuietHours is a daily period, in HH:MM local time, in which non-critical notifications
+// are held back
+type QuietHours struct {
+	Inicio string `json:"inicio"`
+	Fin    string `json:"fin"`
+}
+
+// EscalationPolicy is a list of steps saying who is notified, and when, while an alert
+// stays unacknowledged
+type EscalationPolicy struct {
+	ID          int64   `json:"id"`
+	IDUser      int     `json:"id_user"`
+	Nombre      string  `json:"nombre"`
+	NumeroSerie *string `json:"numero_serie,omitempty"`
+	IDUbicacion *int64  `json:"id_ubicacion,omitempty"`
+	// One of critical, warning, info
+	Severidades   []string          `json:"severidades"`
+	Activa        bool              `json:"activa"`
+	Pasos         []*EscalationStep `json:"pasos"`
+	FechaCreacion time.Time         `json:"fecha_creacion"`
+}
+
+// EscalationPolicyRequest is an escalation policy to create or replace
+type EscalationPolicyRequest struct {
+	Nombre      string  `json:"nombre"`
+	NumeroSerie *string `json:"numero_serie,omitempty"`
+	IDUbicacion *int64  `json:"id_ubicacion,omitempty"`
+	// One of critical, warning, info
+	Severidades []string          `json:"severidades,omitempty"`
+	Activa      *bool             `json:"activa,omitempty"`
+	Pasos       []*EscalationStep `json:"pasos"`
+}
+
+// EscalationStep: One step of an escalation policy
+type EscalationStep struct {
+	Nombre        string `json:"nombre"`
+	EsperaMinutos int    `json:"espera_minutos"`
+	// One of email, sms, telegram, push
+	Canal     string `json:"canal"`
+	Direccion string `json:"direccion"`
+	// One of es, en
+	Idioma string `json:"idioma"`
+}
+
+// Escalation is the escalation of one alert under a policy
+type Escalation struct {
+	ID            int64      `json:"id"`
+	IDAlerta      int64      `json:"id_alerta"`
+	IDPolitica    int64      `json:"id_politica"`
+	SiguientePaso int        `json:"siguiente_paso"`
+	ProximoPaso   *time.Time `json:"proximo_paso,omitempty"`
+	Estado        string     `json:"estado"`
+	FechaCreacion time.Time  `json:"fecha_creacion"`
+	FechaFin      *time.Time `json:"fecha_fin,omitempty"`
+}
+
+// MaintenanceWindow is a period in which the alerts of a device or location are
+// suppressed
+type MaintenanceWindow struct {
+	ID            int64     `json:"id"`
+	IDUser        int       `json:"id_user"`
+	Nombre        string    `json:"nombre"`
+	NumeroSerie   *string   `json:"numero_serie,omitempty"`
+	IDUbicacion   *int64    `json:"id_ubicacion,omitempty"`
+	Inicio        time.Time `json:"inicio"`
+	Fin           time.Time `json:"fin"`
+	FechaCreacion time.Time `json:"fecha_creacion"`
+}
+
+// MaintenanceWindowRequest is a maintenance window to schedule; either fin or
+// duracion_minutos sets the end, and inicio defaults to now
+type MaintenanceWindowRequest struct {
+	Nombre          string     `json:"nombre"`
+	NumeroSerie     *string    `json:"numero_serie,omitempty"`
+	IDUbicacion     *int64     `json:"id_ubicacion,omitempty"`
+	Inicio          *time.Time `json:"inicio,omitempty"`
+	Fin             *time.Time `json:"fin,omitempty"`
+	DuracionMinutos int        `json:"duracion_minutos,omitempty"`
+}
+
+// AuditEntry is an entry of the tamper-evident audit log
+type AuditEntry struct {
+	ID        int64  `json:"id"`
+	TipoActor string `json:"tipo_actor"`
+	IDActor   string `json:"id_actor"`
+	Accion    string `json:"accion"`
+	Recurso   string `json:"recurso"`
+	IDRecurso string `json:"id_recurso"`
+	// The resource before the change
+	Antes interface{} `json:"antes,omitempty"`
+	// The resource after the change
+	Despues      interface{} `json:"despues,omitempty"`
+	IDSolicitud  string      `json:"id_solicitud,omitempty"`
+	IP           string      `json:"ip,omitempty"`
+	Fecha        time.Time   `json:"fecha"`
+	HashAnterior string      `json:"hash_anterior"`
+	Hash         string      `json:"hash"`
+}
+
+// AuditVerification is the result of checking the hash chain of the audit log
+type AuditVerification struct {
+	Valida     bool   `json:"valida"`
+	Revisadas  int    `json:"revisadas"`
+	IDRota     *int64 `json:"id_rota,omitempty"`
+	UltimoHash string `json:"ultimo_hash,omitempty"`
+}
+
+// ListAlertsParams are the query parameters of ListAlerts
+type ListAlertsParams struct {
+	// Only the devices under this location
+	LocationID int64
+	// Also count the alerts per location of this level. One of sitio, edificio, piso, habitacion
+	GroupBy string
+}
+
+func (p *ListAlertsParams) values() url.Values {
+	query := url.Values{}
+	if p == nil {
+		return query
+	}
+	if p.LocationID != 0 {
+		query.Set("location_id", strconv.FormatInt(p.LocationID, 10))
+	}
+	if p.GroupBy != "" {
+		query.Set("group_by", p.GroupBy)
+	}
+	return query
+}
+
+// ExportAlertsParams are the query parameters of ExportAlerts
+type ExportAlertsParams struct {
+	// File format. One of csv, ndjson, xlsx
+	Format string
+	// Start of the period; RFC 3339, "2006-01-02 15:04:05" or "2006-01-02" in UTC, or Unix seconds or milliseconds
+	From string
+	// End of the period; same formats as from
+	To string
+	// Only the devices under this location
+	LocationID int64
+	// Language of the content; defaults to the Accept-Language header, else Spanish. One of es, en
+	Lang string
+}
+
+func (p *ExportAlertsParams) values() url.Values {
+	query := url.Values{}
+	if p == nil {
+		return query
+	}
+	if p.Format != "" {
+		query.Set("format", p.Format)
+	}
+	if p.From != "" {
+		query.Set("from", p.From)
+	}
+	if p.To != "" {
+		query.Set("to", p.To)
+	}
+	if p.LocationID != 0 {
+		query.Set("location_id", strconv.FormatInt(p.LocationID, 10))
+	}
+	if p.Lang != "" {
+		query.Set("lang", p.Lang)
+	}
+	return query
+}
+
+// ListIncidentsParams are the query parameters of ListIncidents
+type ListIncidentsParams struct {
+	// One of abierto, reconocido, resuelto
+	Estado string
+	// Only the devices under this location
+	LocationID int64
+	Limit      int
+}
+
+func (p *ListIncidentsParams) values() url.Values {
+	query := url.Values{}
+	if p == nil {
+		return query
+	}
+	if p.Estado != "" {
+		query.Set("estado", p.Estado)
+	}
+	if p.LocationID != 0 {
+		query.Set("location_id", strconv.FormatInt(p.LocationID, 10))
+	}
+	if p.Limit != 0 {
+		query.Set("limit", strconv.Itoa(p.Limit))
+	}
+	return query
+}
+
+// GetAggregatesParams are the query parameters of GetAggregates
+type GetAggregatesParams struct {
+	// One of 1m, 5m, 15m, 1h, 6h, 1d
+	Bucket string
+	// Start of the period; RFC 3339, "2006-01-02 15:04:05" or "2006-01-02" in UTC, or Unix seconds or milliseconds
+	From string
+	// End of the period; same formats as from
+	To string
+}
+
+func (p *GetAggregatesParams) values() url.Values {
+	query := url.Values{}
+	if p == nil {
+		return query
+	}
+	if p.Bucket != "" {
+		query.Set("bucket", p.Bucket)
+	}
+	if p.From != "" {
+		query.Set("from", p.From)
+	}
+	if p.To != "" {
+		query.Set("to", p.To)
+	}
+	return query
+}
+
+// GetReportParams are the query parameters of GetReport
+type GetReportParams struct {
+	// Language of the content; defaults to the Accept-Language header, else Spanish. One of es, en
+	Lang string
+}
+
+func (p *GetReportParams) values() url.Values {
+	query := url.Values{}
+	if p == nil {
+		return query
+	}
+	if p.Lang != "" {
+		query.Set("lang", p.Lang)
+	}
+	return query
+}
+
+// ListMaintenanceWindowsParams are the query parameters of ListMaintenanceWindows
+type ListMaintenanceWindowsParams struct {
+	// Only the windows in force now
+	Current bool
+}
+
+func (p *ListMaintenanceWindowsParams) values() url.Values {
+	query := url.Values{}
+	if p == nil {
+		return query
+	}
+	if p.Current {
+		query.Set("current", "true")
+	}
+	return query
+}
+
+// ListAuditEntriesParams are the query parameters of ListAuditEntries
+type ListAuditEntriesParams struct {
+	ActorType  string
+	ActorID    string
+	Action     string
+	Resource   string
+	ResourceID string
+	// Start of the period; RFC 3339, "2006-01-02 15:04:05" or "2006-01-02" in UTC, or Unix seconds or milliseconds
+	From string
+	// End of the period; same formats as from
+	To    string
+	Limit int
+	// Only entries older than this one, to read the next page
+	BeforeID int64
+}
+
+func (p *ListAuditEntriesParams) values() url.Values {
+	query := url.Values{}
+	if p == nil {
+		return query
+	}
+	if p.ActorType != "" {
+		query.Set("actor_type", p.ActorType)
+	}
+	if p.ActorID != "" {
+		query.Set("actor_id", p.ActorID)
+	}
+	if p.Action != "" {
+		query.Set("action", p.Action)
+	}
+	if p.Resource != "" {
+		query.Set("resource", p.Resource)
+	}
+	if p.ResourceID != "" {
+		query.Set("resource_id", p.ResourceID)
+	}
+	if p.From != "" {
+		query.Set("from", p.From)
+	}
+	if p.To != "" {
+		query.Set("to", p.To)
+	}
+	if p.Limit != 0 {
+		query.Set("limit", strconv.Itoa(p.Limit))
+	}
+	if p.BeforeID != 0 {
+		query.Set("before_id", strconv.FormatInt(p.BeforeID, 10))
+	}
+	return query
+}
+
+// CreateSensorData stores a sensor reading
+//
+// Stores a reading and raises the alerts it triggers. Devices identify themselves with
+// X-Device-Serial and may only post their own readings.
+func (c *Client) CreateSensorData(ctx context.Context, body *SensorDataRequest) (*Message, error) {
+	path := "/api/sensors"
+	out := new(Message)
+	if err := c.do(ctx, "POST", path, nil, body, out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// ListAlerts lists the readings of the caller's devices
+//
+// API keys read the devices shared with their organisation.
+func (c *Client) ListAlerts(ctx context.Context, params *ListAlertsParams) (*UserAlerts, error) {
+	path := "/api/alerts"
+	out := new(UserAlerts)
+	if err := c.do(ctx, "GET", path, params.values(), nil, out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// ExportAlerts downloads the alert history
+//
+// Streams the alerts, oldest first, with column headers in lang. API keys export the
+// devices shared with their organisation.
+// The caller must close the returned body.
+func (c *Client) ExportAlerts(ctx context.Context, params *ExportAlertsParams) (io.ReadCloser, error) {
+	path := "/api/alerts/export"
+	return c.download(ctx, "GET", path, params.values())
+}
+
+// AcknowledgeAlert acknowledges an active alert
+//
+// Stops the escalation of the alert. Viewers of a shared device get 403.
+func (c *Client) AcknowledgeAlert(ctx context.Context, id int64) (*Alert, error) {
+	path := fmt.Sprintf("/api/alerts/%d/acknowledge", id)
+	out := new(Alert)
+	if err := c.do(ctx, "POST", path, nil, nil, out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// ListAlertEscalations lists the escalations of an alert
+func (c *Client) ListAlertEscalations(ctx context.Context, id int64) ([]*Escalation, error) {
+	path := fmt.Sprintf("/api/alerts/%d/escalations", id)
+	var out []*Escalation
+	if err := c.do(ctx, "GET", path, nil, nil, &out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// ListIncidents lists incidents
+func (c *Client) ListIncidents(ctx context.Context, params *ListIncidentsParams) ([]*Incident, error) {
+	path := "/api/incidents"
+	var out []*Incident
+	if err := c.do(ctx, "GET", path, params.values(), nil, &out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// GetIncident gets an incident with its alerts and events
+func (c *Client) GetIncident(ctx context.Context, id int64) (*Incident, error) {
+	path := fmt.Sprintf("/api/incidents/%d", id)
+	out := new(Incident)
+	if err := c.do(ctx, "GET", path, nil, nil, out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// AcknowledgeIncident acknowledges an incident and its alerts
+//
+// Viewers of the devices of the incident get 403.
+func (c *Client) AcknowledgeIncident(ctx context.Context, id int64) (*Incident, error) {
+	path := fmt.Sprintf("/api/incidents/%d/acknowledge", id)
+	out := new(Incident)
+	if err := c.do(ctx, "POST", path, nil, nil, out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// ResolveIncident resolves an incident
+//
+// Viewers of the devices of the incident get 403.
+func (c *Client) ResolveIncident(ctx context.Context, id int64) (*Incident, error) {
+	path := fmt.Sprintf("/api/incidents/%d/resolve", id)
+	out := new(Incident)
+	if err := c.do(ctx, "POST", path, nil, nil, out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// ListDevices lists the caller's devices
+func (c *Client) ListDevices(ctx context.Context) ([]*Device, error) {
+	path := "/api/devices"
+	var out []*Device
+	if err := c.do(ctx, "GET", path, nil, nil, &out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// ClaimDevice claims a device with its claim code
+func (c *Client) ClaimDevice(ctx context.Context, body *ClaimDeviceRequest) (*ClaimedDevice, error) {
+	path := "/api/devices"
+	out := new(ClaimedDevice)
+	if err := c.do(ctx, "POST", path, nil, body, out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// GetDevice gets a device
+func (c *Client) GetDevice(ctx context.Context, numeroSerie string) (*Device, error) {
+	path := fmt.Sprintf("/api/devices/%s", url.PathEscape(numeroSerie))
+	out := new(Device)
+	if err := c.do(ctx, "GET", path, nil, nil, out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// UpdateDevice updates a device
+func (c *Client) UpdateDevice(ctx context.Context, numeroSerie string, body *UpdateDeviceRequest) (*Device, error) {
+	path := fmt.Sprintf("/api/devices/%s", url.PathEscape(numeroSerie))
+	out := new(Device)
+	if err := c.do(ctx, "PUT", path, nil, body, out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// DeleteDevice releases a device
+func (c *Client) DeleteDevice(ctx context.Context, numeroSerie string) error {
+	path := fmt.Sprintf("/api/devices/%s", url.PathEscape(numeroSerie))
+	return c.do(ctx, "DELETE", path, nil, nil, nil)
+}
+
+// TransferDevice transfers a device to another user
+func (c *Client) TransferDevice(ctx context.Context, numeroSerie string, body *TransferDeviceRequest) error {
+	path := fmt.Sprintf("/api/devices/%s/transfer", url.PathEscape(numeroSerie))
+	return c.do(ctx, "POST", path, nil, body, nil)
+}
+
+// RotateDeviceToken issues a new token for a device
+//
+// The previous token stops working at once. Boards claimed before tokens existed need one
+// to keep sending readings.
+func (c *Client) RotateDeviceToken(ctx context.Context, numeroSerie string) (*ClaimedDevice, error) {
+	path := fmt.Sprintf("/api/devices/%s/token", url.PathEscape(numeroSerie))
+	out := new(ClaimedDevice)
+	if err := c.do(ctx, "POST", path, nil, nil, out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// RecordHeartbeat reports that a device is online
+func (c *Client) RecordHeartbeat(ctx context.Context, numeroSerie string, body *HeartbeatRequest) error {
+	path := fmt.Sprintf("/api/devices/%s/heartbeat", url.PathEscape(numeroSerie))
+	return c.do(ctx, "POST", path, nil, body, nil)
+}
+
+// GetAggregates summarises the readings of a sensor per bucket
+//
+// Long periods are read from hourly rollups. API keys read the devices shared with their
+// organisation.
+func (c *Client) GetAggregates(ctx context.Context, numeroSerie string, typ string, params *GetAggregatesParams) (*AggregateSeries, error) {
+	path := fmt.Sprintf("/api/devices/%s/sensors/%s/aggregates", url.PathEscape(numeroSerie), url.PathEscape(typ))
+	out := new(AggregateSeries)
+	if err := c.do(ctx, "GET", path, params.values(), nil, out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// ListCalibrations lists the calibration of the gas sensors of a device
+func (c *Client) ListCalibrations(ctx context.Context, numeroSerie string) ([]*SensorCalibration, error) {
+	path := fmt.Sprintf("/api/devices/%s/calibration", url.PathEscape(numeroSerie))
+	var out []*SensorCalibration
+	if err := c.do(ctx, "GET", path, nil, nil, &out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// UpdateCalibration sets the offset and gain of a sensor
+func (c *Client) UpdateCalibration(ctx context.Context, numeroSerie string, sensor string, body *CalibrationRequest) (*SensorCalibration, error) {
+	path := fmt.Sprintf("/api/devices/%s/calibration/%s", url.PathEscape(numeroSerie), url.PathEscape(sensor))
+	out := new(SensorCalibration)
+	if err := c.do(ctx, "PUT", path, nil, body, out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// ResetCalibration forgets the baseline of a sensor and learn it again
+func (c *Client) ResetCalibration(ctx context.Context, numeroSerie string, sensor string) (*SensorCalibration, error) {
+	path := fmt.Sprintf("/api/devices/%s/calibration/%s/reset", url.PathEscape(numeroSerie), url.PathEscape(sensor))
+	out := new(SensorCalibration)
+	if err := c.do(ctx, "POST", path, nil, nil, out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// AssignDeviceLocation places a device in a location, or remove it with a null
+// id_ubicacion
+func (c *Client) AssignDeviceLocation(ctx context.Context, numeroSerie string, body *AssignLocationRequest) error {
+	path := fmt.Sprintf("/api/devices/%s/location", url.PathEscape(numeroSerie))
+	return c.do(ctx, "PUT", path, nil, body, nil)
+}
+
+// ListLocations lists the caller's locations
+func (c *Client) ListLocations(ctx context.Context) ([]*Location, error) {
+	path := "/api/locations"
+	var out []*Location
+	if err := c.do(ctx, "GET", path, nil, nil, &out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// CreateLocation creates a location
+func (c *Client) CreateLocation(ctx context.Context, body *CreateLocationRequest) (*Location, error) {
+	path := "/api/locations"
+	out := new(Location)
+	if err := c.do(ctx, "POST", path, nil, body, out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// GetLocation gets a location
+func (c *Client) GetLocation(ctx context.Context, id int64) (*Location, error) {
+	path := fmt.Sprintf("/api/locations/%d", id)
+	out := new(Location)
+	if err := c.do(ctx, "GET", path, nil, nil, out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// UpdateLocation updates a location
+func (c *Client) UpdateLocation(ctx context.Context, id int64, body *UpdateLocationRequest) (*Location, error) {
+	path := fmt.Sprintf("/api/locations/%d", id)
+	out := new(Location)
+	if err := c.do(ctx, "PUT", path, nil, body, out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// DeleteLocation deletes an empty location
+func (c *Client) DeleteLocation(ctx context.Context, id int64) error {
+	path := fmt.Sprintf("/api/locations/%d", id)
+	return c.do(ctx, "DELETE", path, nil, nil, nil)
+}
+
+// GetLocationStatus gets the devices and active alerts under a location
+func (c *Client) GetLocationStatus(ctx context.Context, id int64) (*LocationStatus, error) {
+	path := fmt.Sprintf("/api/locations/%d/status", id)
+	out := new(LocationStatus)
+	if err := c.do(ctx, "GET", path, nil, nil, out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// GetReport downloads the monthly safety report of a site
+//
+// Alarms per sensor type, response times, device uptime and the noisiest devices of a
+// calendar month in UTC. The current month is reported up to now.
+// The caller must close the returned body.
+func (c *Client) GetReport(ctx context.Context, site int64, month string, params *GetReportParams) (io.ReadCloser, error) {
+	path := fmt.Sprintf("/api/reports/%d/%s.pdf", site, url.PathEscape(month))
+	return c.download(ctx, "GET", path, params.values())
+}
+
+// ListOrganizations lists the caller's organisations
+func (c *Client) ListOrganizations(ctx context.Context) ([]*Organization, error) {
+	path := "/api/organizations"
+	var out []*Organization
+	if err := c.do(ctx, "GET", path, nil, nil, &out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// CreateOrganization creates an organisation
+func (c *Client) CreateOrganization(ctx context.Context, body *CreateOrganizationRequest) (*Organization, error) {
+	path := "/api/organizations"
+	out := new(Organization)
+	if err := c.do(ctx, "POST", path, nil, body, out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// ListMembers lists the members of an organisation
+func (c *Client) ListMembers(ctx context.Context, id int64) ([]*Membership, error) {
+	path := fmt.Sprintf("/api/organizations/%d/members", id)
+	var out []*Membership
+	if err := c.do(ctx, "GET", path, nil, nil, &out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// UpdateMember changes the role of a member
+func (c *Client) UpdateMember(ctx context.Context, id int64, memberID int, body *UpdateMemberRequest) error {
+	path := fmt.Sprintf("/api/organizations/%d/members/%d", id, memberID)
+	return c.do(ctx, "PUT", path, nil, body, nil)
+}
+
+// RemoveMember removes a member, or leave the organisation
+func (c *Client) RemoveMember(ctx context.Context, id int64, memberID int) error {
+	path := fmt.Sprintf("/api/organizations/%d/members/%d", id, memberID)
+	return c.do(ctx, "DELETE", path, nil, nil, nil)
+}
+
+// ListOrganizationInvitations lists the invitations of an organisation
+func (c *Client) ListOrganizationInvitations(ctx context.Context, id int64) ([]*Invitation, error) {
+	path := fmt.Sprintf("/api/organizations/%d/invitations", id)
+	var out []*Invitation
+	if err := c.do(ctx, "GET", path, nil, nil, &out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// InviteMember invites a user
+func (c *Client) InviteMember(ctx context.Context, id int64, body *InviteMemberRequest) (*Invitation, error) {
+	path := fmt.Sprintf("/api/organizations/%d/invitations", id)
+	out := new(Invitation)
+	if err := c.do(ctx, "POST", path, nil, body, out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// RevokeInvitation revokes an invitation
+func (c *Client) RevokeInvitation(ctx context.Context, id int64, invitationID int64) error {
+	path := fmt.Sprintf("/api/organizations/%d/invitations/%d", id, invitationID)
+	return c.do(ctx, "DELETE", path, nil, nil, nil)
+}
+
+// ListSharedDevices lists the devices shared with an organisation
+func (c *Client) ListSharedDevices(ctx context.Context, id int64) ([]*SharedDevice, error) {
+	path := fmt.Sprintf("/api/organizations/%d/devices", id)
+	var out []*SharedDevice
+	if err := c.do(ctx, "GET", path, nil, nil, &out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// ShareDevice shares a device with an organisation
+func (c *Client) ShareDevice(ctx context.Context, id int64, body *ShareDeviceRequest) error {
+	path := fmt.Sprintf("/api/organizations/%d/devices", id)
+	return c.do(ctx, "POST", path, nil, body, nil)
+}
+
+// UnshareDevice stops sharing a device
+func (c *Client) UnshareDevice(ctx context.Context, id int64, numeroSerie string) error {
+	path := fmt.Sprintf("/api/organizations/%d/devices/%s", id, url.PathEscape(numeroSerie))
+	return c.do(ctx, "DELETE", path, nil, nil, nil)
+}
+
+// ListAccessAudit lists the changes to the members and devices of an organisation
+func (c *Client) ListAccessAudit(ctx context.Context, id int64) ([]*AccessAuditEntry, error) {
+	path := fmt.Sprintf("/api/organizations/%d/access-audit", id)
+	var out []*AccessAuditEntry
+	if err := c.do(ctx, "GET", path, nil, nil, &out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// ListAPIKeys lists the API keys of an organisation
+func (c *Client) ListAPIKeys(ctx context.Context, id int64) ([]*APIKey, error) {
+	path := fmt.Sprintf("/api/organizations/%d/api-keys", id)
+	var out []*APIKey
+	if err := c.do(ctx, "GET", path, nil, nil, &out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// CreateAPIKey issues an API key
+func (c *Client) CreateAPIKey(ctx context.Context, id int64, body *CreateAPIKeyRequest) (*IssuedAPIKey, error) {
+	path := fmt.Sprintf("/api/organizations/%d/api-keys", id)
+	out := new(IssuedAPIKey)
+	if err := c.do(ctx, "POST", path, nil, body, out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// RevokeAPIKey revokes an API key
+func (c *Client) RevokeAPIKey(ctx context.Context, id int64, keyID int64) error {
+	path := fmt.Sprintf("/api/organizations/%d/api-keys/%d", id, keyID)
+	return c.do(ctx, "DELETE", path, nil, nil, nil)
+}
+
+// ListWebhooks lists the caller's webhooks
+func (c *Client) ListWebhooks(ctx context.Context) ([]*Webhook, error) {
+	path := "/api/webhooks"
+	var out []*Webhook
+	if err := c.do(ctx, "GET", path, nil, nil, &out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// CreateWebhook creates a webhook
+func (c *Client) CreateWebhook(ctx context.Context, body *CreateWebhookRequest) (*CreatedWebhook, error) {
+	path := "/api/webhooks"
+	out := new(CreatedWebhook)
+	if err := c.do(ctx, "POST", path, nil, body, out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// GetWebhook gets a webhook
+func (c *Client) GetWebhook(ctx context.Context, id int64) (*Webhook, error) {
+	path := fmt.Sprintf("/api/webhooks/%d", id)
+	out := new(Webhook)
+	if err := c.do(ctx, "GET", path, nil, nil, out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// UpdateWebhook updates a webhook
+func (c *Client) UpdateWebhook(ctx context.Context, id int64, body *UpdateWebhookRequest) (*Webhook, error) {
+	path := fmt.Sprintf("/api/webhooks/%d", id)
+	out := new(Webhook)
+	if err := c.do(ctx, "PUT", path, nil, body, out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// DeleteWebhook deletes a webhook
+func (c *Client) DeleteWebhook(ctx context.Context, id int64) error {
+	path := fmt.Sprintf("/api/webhooks/%d", id)
+	return c.do(ctx, "DELETE", path, nil, nil, nil)
+}
+
+// SendWebhookTestEvent posts a test event to a webhook
+func (c *Client) SendWebhookTestEvent(ctx context.Context, id int64) (*WebhookDelivery, error) {
+	path := fmt.Sprintf("/api/webhooks/%d/test", id)
+	out := new(WebhookDelivery)
+	if err := c.do(ctx, "POST", path, nil, nil, out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// ListWebhookDeliveries lists the recent deliveries of a webhook
+func (c *Client) ListWebhookDeliveries(ctx context.Context, id int64) ([]*WebhookDelivery, error) {
+	path := fmt.Sprintf("/api/webhooks/%d/deliveries", id)
+	var out []*WebhookDelivery
+	if err := c.do(ctx, "GET", path, nil, nil, &out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// ListRecipients lists the caller's notification recipients
+func (c *Client) ListRecipients(ctx context.Context) ([]*NotificationRecipient, error) {
+	path := "/api/me/notification-recipients"
+	var out []*NotificationRecipient
+	if err := c.do(ctx, "GET", path, nil, nil, &out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// CreateRecipient adds a notification recipient
+func (c *Client) CreateRecipient(ctx context.Context, body *CreateRecipientRequest) (*NotificationRecipient, error) {
+	path := "/api/me/notification-recipients"
+	out := new(NotificationRecipient)
+	if err := c.do(ctx, "POST", path, nil, body, out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// DeleteRecipient removes a notification recipient
+func (c *Client) DeleteRecipient(ctx context.Context, id int64) error {
+	path := fmt.Sprintf("/api/me/notification-recipients/%d", id)
+	return c.do(ctx, "DELETE", path, nil, nil, nil)
+}
+
+// GetChannelSettings gets the channels used per severity
+func (c *Client) GetChannelSettings(ctx context.Context) (*NotificationChannelSettings, error) {
+	path := "/api/me/notification-channels"
+	out := new(NotificationChannelSettings)
+	if err := c.do(ctx, "GET", path, nil, nil, out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// UpdateChannelSettings sets the channels used per severity
+func (c *Client) UpdateChannelSettings(ctx context.Context, body *NotificationChannelSettings) (*NotificationChannelSettings, error) {
+	path := "/api/me/notification-channels"
+	out := new(NotificationChannelSettings)
+	if err := c.do(ctx, "PUT", path, nil, body, out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// ListNotificationDeliveries lists the recent notifications sent to the caller
+func (c *Client) ListNotificationDeliveries(ctx context.Context) ([]*NotificationDelivery, error) {
+	path := "/api/me/notification-deliveries"
+	var out []*NotificationDelivery
+	if err := c.do(ctx, "GET", path, nil, nil, &out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// GetNotificationSettings gets quiet hours, digests and minimum severities
+func (c *Client) GetNotificationSettings(ctx context.Context) (*NotificationSettings, error) {
+	path := "/api/me/notification-settings"
+	out := new(NotificationSettings)
+	if err := c.do(ctx, "GET", path, nil, nil, out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// UpdateNotificationSettings sets quiet hours, digests and minimum severities
+func (c *Client) UpdateNotificationSettings(ctx context.Context, body *NotificationSettings) (*NotificationSettings, error) {
+	path := "/api/me/notification-settings"
+	out := new(NotificationSettings)
+	if err := c.do(ctx, "PUT", path, nil, body, out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// ListEscalationPolicies lists the caller's escalation policies
+func (c *Client) ListEscalationPolicies(ctx context.Context) ([]*EscalationPolicy, error) {
+	path := "/api/escalation-policies"
+	var out []*EscalationPolicy
+	if err := c.do(ctx, "GET", path, nil, nil, &out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// CreateEscalationPolicy creates an escalation policy
+func (c *Client) CreateEscalationPolicy(ctx context.Context, body *EscalationPolicyRequest) (*EscalationPolicy, error) {
+	path := "/api/escalation-policies"
+	out := new(EscalationPolicy)
+	if err := c.do(ctx, "POST", path, nil, body, out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// GetEscalationPolicy gets an escalation policy
+func (c *Client) GetEscalationPolicy(ctx context.Context, id int64) (*EscalationPolicy, error) {
+	path := fmt.Sprintf("/api/escalation-policies/%d", id)
+	out := new(EscalationPolicy)
+	if err := c.do(ctx, "GET", path, nil, nil, out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// UpdateEscalationPolicy replaces an escalation policy
+func (c *Client) UpdateEscalationPolicy(ctx context.Context, id int64, body *EscalationPolicyRequest) (*EscalationPolicy, error) {
+	path := fmt.Sprintf("/api/escalation-policies/%d", id)
+	out := new(EscalationPolicy)
+	if err := c.do(ctx, "PUT", path, nil, body, out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// DeleteEscalationPolicy deletes an escalation policy
+func (c *Client) DeleteEscalationPolicy(ctx context.Context, id int64) error {
+	path := fmt.Sprintf("/api/escalation-policies/%d", id)
+	return c.do(ctx, "DELETE", path, nil, nil, nil)
+}
+
+// ListMaintenanceWindows lists maintenance windows
+func (c *Client) ListMaintenanceWindows(ctx context.Context, params *ListMaintenanceWindowsParams) ([]*MaintenanceWindow, error) {
+	path := "/api/maintenance-windows"
+	var out []*MaintenanceWindow
+	if err := c.do(ctx, "GET", path, params.values(), nil, &out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// CreateMaintenanceWindow schedules a maintenance window
+func (c *Client) CreateMaintenanceWindow(ctx context.Context, body *MaintenanceWindowRequest) (*MaintenanceWindow, error) {
+	path := "/api/maintenance-windows"
+	out := new(MaintenanceWindow)
+	if err := c.do(ctx, "POST", path, nil, body, out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// GetMaintenanceWindow gets a maintenance window
+func (c *Client) GetMaintenanceWindow(ctx context.Context, id int64) (*MaintenanceWindow, error) {
+	path := fmt.Sprintf("/api/maintenance-windows/%d", id)
+	out := new(MaintenanceWindow)
+	if err := c.do(ctx, "GET", path, nil, nil, out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// UpdateMaintenanceWindow replaces a maintenance window
+func (c *Client) UpdateMaintenanceWindow(ctx context.Context, id int64, body *MaintenanceWindowRequest) (*MaintenanceWindow, error) {
+	path := fmt.Sprintf("/api/maintenance-windows/%d", id)
+	out := new(MaintenanceWindow)
+	if err := c.do(ctx, "PUT", path, nil, body, out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// DeleteMaintenanceWindow deletes a maintenance window
+func (c *Client) DeleteMaintenanceWindow(ctx context.Context, id int64) error {
+	path := fmt.Sprintf("/api/maintenance-windows/%d", id)
+	return c.do(ctx, "DELETE", path, nil, nil, nil)
+}
+
+// EndMaintenanceWindow ends a maintenance window now
+func (c *Client) EndMaintenanceWindow(ctx context.Context, id int64) (*MaintenanceWindow, error) {
+	path := fmt.Sprintf("/api/maintenance-windows/%d/end", id)
+	out := new(MaintenanceWindow)
+	if err := c.do(ctx, "POST", path, nil, nil, out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// ListAuditEntries searches the audit log
+func (c *Client) ListAuditEntries(ctx context.Context, params *ListAuditEntriesParams) ([]*AuditEntry, error) {
+	path := "/api/audit"
+	var out []*AuditEntry
+	if err := c.do(ctx, "GET", path, params.values(), nil, &out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// VerifyAuditChain checks the hash chain of the audit log
+func (c *Client) VerifyAuditChain(ctx context.Context) (*AuditVerification, error) {
+	path := "/api/audit/verify"
+	out := new(AuditVerification)
+	if err := c.do(ctx, "GET", path, nil, nil, out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// ListMyInvitations lists the invitations sent to the caller
+func (c *Client) ListMyInvitations(ctx context.Context) ([]*Invitation, error) {
+	path := "/api/invitations"
+	var out []*Invitation
+	if err := c.do(ctx, "GET", path, nil, nil, &out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// AcceptInvitation accepts an invitation
+func (c *Client) AcceptInvitation(ctx context.Context, invitationID int64) error {
+	path := fmt.Sprintf("/api/invitations/%d/accept", invitationID)
+	return c.do(ctx, "POST", path, nil, nil, nil)
+}
+
+// DeclineInvitation declines an invitation
+func (c *Client) DeclineInvitation(ctx context.Context, invitationID int64) error {
+	path := fmt.Sprintf("/api/invitations/%d/decline", invitationID)
+	return c.do(ctx, "POST", path, nil, nil, nil)
+}
+
+// GetOpenAPISpec gets this specification
+func (c *Client) GetOpenAPISpec(ctx context.Context) (map[string]interface{}, error) {
+	path := "/api/openapi.json"
+	var out map[string]interface{}
+	if err := c.do(ctx, "GET", path, nil, nil, &out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// GetAPIDocs browses this specification with Swagger UI
+// The caller must close the returned body.
+func (c *Client) GetAPIDocs(ctx context.Context) (io.ReadCloser, error) {
+	path := "/api/docs"
+	return c.download(ctx, "GET", path, nil)
+}
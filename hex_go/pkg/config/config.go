@@ -5,6 +5,8 @@ import (
 	"fmt"
 	"log"
 	"os"
+	"strconv"
+	"time"
 
 	_ "github.com/go-sql-driver/mysql"
 	"github.com/joho/godotenv"
@@ -21,6 +23,9 @@ type Config struct {
 	
 	// Server configuration
 	ServerPort string
+
+	// AlertFetchConcurrency bounds the sensor tables queried in parallel per alerts request
+	AlertFetchConcurrency int
 	
 	// RabbitMQ configuration
 	RabbitMQHost     string
@@ -32,6 +37,105 @@ type Config struct {
 	RabbitMQQueueMQ2   string
 	RabbitMQQueueMQ135 string
 	RabbitMQQueueDHT22 string
+	RabbitMQQueueAlerts string
+
+	// PolicyFile is the JSON access policy; empty uses the built-in policy
+	PolicyFile string
+
+	// LegacyUserQuery accepts the user_id query parameter from clients that do not send
+	// X-User-ID yet. It is off unless AUTH_LEGACY_USER_QUERY is set.
+	LegacyUserQuery bool
+
+	// TrustProxyHeaders takes the client IP from X-Forwarded-For when the API runs behind a proxy
+	TrustProxyHeaders bool
+
+	// Device monitoring configuration
+	DeviceOfflineAfter  time.Duration
+	DeviceSweepInterval time.Duration
+
+	// Webhook delivery configuration
+	WebhookTimeout      time.Duration
+	WebhookMaxAttempts  int
+	WebhookRetryBase    time.Duration
+	WebhookDisableAfter int
+	WebhookPollInterval time.Duration
+	// WebhookAllowPrivate lets webhooks reach loopback and private addresses, for local testing
+	WebhookAllowPrivate bool
+
+	// Repeated activations of a sensor within AlertDedupWindow are merged into one alert.
+	// An alert is flapping once AlertFlapEnter activations come within AlertFlapWindow,
+	// until a window has fewer than AlertFlapExit. ALERT_DEDUP_WINDOW=0 disables merging.
+	AlertDedupWindow time.Duration
+	AlertFlapWindow  time.Duration
+	AlertFlapEnter   int
+	AlertFlapExit    int
+
+	// IncidentWindow is how long after its latest alert an incident takes new alerts of
+	// the same room
+	IncidentWindow time.Duration
+
+	// A gas sensor learns its clean air baseline for CalibrationBurnIn after its first
+	// reading or a reset. Afterwards readings below the alarm threshold of the sensor, in
+	// ppm, move the baseline by the CalibrationDrift fraction; zero keeps it fixed.
+	CalibrationBurnIn time.Duration
+	CalibrationDrift  float64
+	MQ2AlarmPPM       float64
+	MQ135AlarmPPM     float64
+
+	// Readings more than AnomalyZScore standard deviations from the exponentially weighted
+	// mean of their sensor, smoothed with AnomalyAlpha, raise an anomaly alert once the
+	// sensor has AnomalyWarmup readings. So does a temperature rising AnomalyRiseRate °C
+	// per minute over AnomalyRiseWindow. AnomalyAlpha 0 disables the detector, whose state
+	// is stored every AnomalySnapshotInterval.
+	AnomalyAlpha            float64
+	AnomalyZScore           float64
+	AnomalyWarmup           int
+	AnomalyRiseRate         float64
+	AnomalyRiseWindow       time.Duration
+	AnomalySnapshotInterval time.Duration
+
+	// Aggregates over more than AggregateRawMaxRange are read from the hourly rollups,
+	// which are computed every AggregateRollupInterval for the hours finished
+	// AggregateRollupDelay ago
+	AggregateRawMaxRange    time.Duration
+	AggregateRollupInterval time.Duration
+	AggregateRollupDelay    time.Duration
+
+	// ReportPollInterval is how often the reports of the previous month are looked for
+	// to be emailed
+	ReportPollInterval time.Duration
+
+	// EscalationPollInterval is how often due escalation steps are looked for
+	EscalationPollInterval time.Duration
+
+	// DigestPollInterval is how often held back notifications are looked for, when quiet
+	// hours end or a digest is due
+	DigestPollInterval time.Duration
+
+	// SMTP configuration for email notifications; empty SMTPHost disables email
+	SMTPHost     string
+	SMTPPort     string
+	SMTPUser     string
+	SMTPPassword string
+	SMTPFrom     string
+	SMTPTimeout  time.Duration
+
+	// NotifierTimeout bounds each request to the SMS, Telegram and push gateways
+	NotifierTimeout time.Duration
+
+	// SMS gateway configuration; empty SMSGatewayURL disables SMS
+	SMSGatewayURL   string
+	SMSGatewayToken string
+	SMSFrom         string
+
+	// Telegram configuration; empty TelegramBotToken disables Telegram
+	TelegramBotToken string
+	TelegramAPIURL   string
+
+	// FCM configuration; empty FCMCredentialsFile disables push notifications
+	FCMCredentialsFile string
+	FCMURL             string
+	FCMTokenURL        string
 }
 
 // LoadConfig loads configuration from environment variables
@@ -71,6 +175,8 @@ func LoadConfig() *Config {
 		
 		// Server configuration
 		ServerPort: getEnv("SERVER_PORT", "8080"),
+
+		AlertFetchConcurrency: getEnvInt("ALERT_FETCH_CONCURRENCY", 4),
 		
 		// RabbitMQ configuration
 		RabbitMQHost:     getEnv("RABBITMQ_HOST", "localhost"),
@@ -82,6 +188,76 @@ func LoadConfig() *Config {
 		RabbitMQQueueMQ2:   getEnv("RABBITMQ_QUEUE_MQ2", "mq2_queue"),
 		RabbitMQQueueMQ135: getEnv("RABBITMQ_QUEUE_MQ135", "mq135_queue"),
 		RabbitMQQueueDHT22: getEnv("RABBITMQ_QUEUE_DHT22", "dht22_queue"),
+		RabbitMQQueueAlerts: getEnv("RABBITMQ_QUEUE_ALERTS", "alerts_queue"),
+
+		PolicyFile: getEnv("POLICY_FILE", ""),
+		LegacyUserQuery: getEnvBool("AUTH_LEGACY_USER_QUERY", false),
+
+		TrustProxyHeaders: getEnvBool("TRUST_PROXY_HEADERS", false),
+
+		// Device monitoring configuration
+		DeviceOfflineAfter:  getEnvDuration("DEVICE_OFFLINE_AFTER", 5*time.Minute),
+		DeviceSweepInterval: getEnvDuration("DEVICE_SWEEP_INTERVAL", time.Minute),
+
+		// Webhook delivery configuration
+		WebhookTimeout:      getEnvDuration("WEBHOOK_TIMEOUT", 10*time.Second),
+		WebhookMaxAttempts:  getEnvInt("WEBHOOK_MAX_ATTEMPTS", 6),
+		WebhookRetryBase:    getEnvDuration("WEBHOOK_RETRY_BASE", 30*time.Second),
+		WebhookDisableAfter: getEnvInt("WEBHOOK_DISABLE_AFTER", 15),
+		WebhookPollInterval: getEnvDuration("WEBHOOK_POLL_INTERVAL", 10*time.Second),
+		WebhookAllowPrivate: getEnvBool("WEBHOOK_ALLOW_PRIVATE", false),
+
+		AlertDedupWindow: getEnvDurationAllowZero("ALERT_DEDUP_WINDOW", time.Minute),
+		AlertFlapWindow:  getEnvDuration("ALERT_FLAP_WINDOW", 5*time.Minute),
+		AlertFlapEnter:   getEnvInt("ALERT_FLAP_ENTER", 10),
+		AlertFlapExit:    getEnvInt("ALERT_FLAP_EXIT", 3),
+
+		IncidentWindow: getEnvDuration("INCIDENT_WINDOW", 5*time.Minute),
+
+		CalibrationBurnIn: getEnvDuration("CALIBRATION_BURN_IN", 24*time.Hour),
+		CalibrationDrift:  getEnvFloat("CALIBRATION_DRIFT", 0.001),
+		MQ2AlarmPPM:       getEnvFloat("MQ2_ALARM_PPM", 300),
+		MQ135AlarmPPM:     getEnvFloat("MQ135_ALARM_PPM", 1000),
+
+		AnomalyAlpha:            getEnvFloat("ANOMALY_ALPHA", 0.05),
+		AnomalyZScore:           getEnvFloat("ANOMALY_ZSCORE", 4),
+		AnomalyWarmup:           getEnvInt("ANOMALY_WARMUP", 30),
+		AnomalyRiseRate:         getEnvFloat("ANOMALY_RISE_RATE", 1),
+		AnomalyRiseWindow:       getEnvDuration("ANOMALY_RISE_WINDOW", 5*time.Minute),
+		AnomalySnapshotInterval: getEnvDuration("ANOMALY_SNAPSHOT_INTERVAL", time.Minute),
+
+		AggregateRawMaxRange:    getEnvDuration("AGGREGATE_RAW_MAX_RANGE", 7*24*time.Hour),
+		AggregateRollupInterval: getEnvDuration("AGGREGATE_ROLLUP_INTERVAL", 5*time.Minute),
+		AggregateRollupDelay:    getEnvDuration("AGGREGATE_ROLLUP_DELAY", 15*time.Minute),
+
+		ReportPollInterval: getEnvDuration("REPORT_POLL_INTERVAL", time.Hour),
+
+		EscalationPollInterval: getEnvDuration("ESCALATION_POLL_INTERVAL", 30*time.Second),
+		DigestPollInterval:     getEnvDuration("DIGEST_POLL_INTERVAL", time.Minute),
+
+		// SMTP configuration
+		SMTPHost:     getEnv("SMTP_HOST", ""),
+		SMTPPort:     getEnv("SMTP_PORT", "587"),
+		SMTPUser:     getEnv("SMTP_USER", ""),
+		SMTPPassword: getEnv("SMTP_PASSWORD", ""),
+		SMTPFrom:     getEnv("SMTP_FROM", "StopFire <alertas@stopfire.local>"),
+		SMTPTimeout:  getEnvDuration("SMTP_TIMEOUT", 15*time.Second),
+
+		NotifierTimeout: getEnvDuration("NOTIFIER_TIMEOUT", 10*time.Second),
+
+		// SMS gateway configuration
+		SMSGatewayURL:   getEnv("SMS_GATEWAY_URL", ""),
+		SMSGatewayToken: getEnv("SMS_GATEWAY_TOKEN", ""),
+		SMSFrom:         getEnv("SMS_FROM", "StopFire"),
+
+		// Telegram configuration
+		TelegramBotToken: getEnv("TELEGRAM_BOT_TOKEN", ""),
+		TelegramAPIURL:   getEnv("TELEGRAM_API_URL", "https://api.telegram.org"),
+
+		// FCM configuration
+		FCMCredentialsFile: getEnv("FCM_CREDENTIALS_FILE", ""),
+		FCMURL:             getEnv("FCM_URL", ""),
+		FCMTokenURL:        getEnv("FCM_TOKEN_URL", ""),
 	}
 }
 
@@ -111,4 +287,50 @@ func getEnv(key, defaultValue string) string {
 		return defaultValue
 	}
 	return value
-}
\ No newline at end of file
+}
+
+// getEnvInt gets an integer environment variable or returns a default value
+func getEnvInt(key string, defaultValue int) int {
+	value, err := strconv.Atoi(os.Getenv(key))
+	if err != nil {
+		return defaultValue
+	}
+	return value
+}
+
+// getEnvFloat gets a decimal environment variable or returns a default value
+func getEnvFloat(key string, defaultValue float64) float64 {
+	value, err := strconv.ParseFloat(os.Getenv(key), 64)
+	if err != nil {
+		return defaultValue
+	}
+	return value
+}
+
+// getEnvDuration gets a duration environment variable such as "90s" or returns a default value
+func getEnvDuration(key string, defaultValue time.Duration) time.Duration {
+	value, err := time.ParseDuration(os.Getenv(key))
+	if err != nil || value <= 0 {
+		return defaultValue
+	}
+	return value
+}
+
+// getEnvDurationAllowZero is getEnvDuration for settings where "0" turns a feature off.
+// Negative and unparsable values still fall back to the default.
+func getEnvDurationAllowZero(key string, defaultValue time.Duration) time.Duration {
+	value, err := time.ParseDuration(os.Getenv(key))
+	if err != nil || value < 0 {
+		return defaultValue
+	}
+	return value
+}
+
+// getEnvBool gets a boolean environment variable such as "true" or returns a default value
+func getEnvBool(key string, defaultValue bool) bool {
+	value, err := strconv.ParseBool(os.Getenv(key))
+	if err != nil {
+		return defaultValue
+	}
+	return value
+}
@@ -0,0 +1,34 @@
+package config
+
+import (
+	"testing"
+	"time"
+)
+
+func TestGetEnvDuration(t *testing.T) {
+	tests := []struct {
+		value         string
+		want          time.Duration
+		wantAllowZero time.Duration
+	}{
+		{"", time.Minute, time.Minute},
+		{"90s", 90 * time.Second, 90 * time.Second},
+		{"0", time.Minute, 0},
+		{"0s", time.Minute, 0},
+		{"-1s", time.Minute, time.Minute},
+		{"soon", time.Minute, time.Minute},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			t.Setenv("TEST_DURATION", tt.value)
+
+			if got := getEnvDuration("TEST_DURATION", time.Minute); got != tt.want {
+				t.Errorf("getEnvDuration() = %s, want %s", got, tt.want)
+			}
+			if got := getEnvDurationAllowZero("TEST_DURATION", time.Minute); got != tt.wantAllowZero {
+				t.Errorf("getEnvDurationAllowZero() = %s, want %s", got, tt.wantAllowZero)
+			}
+		})
+	}
+}
@@ -0,0 +1,112 @@
+// Package export writes the alert history as CSV, NDJSON or Excel spreadsheets, one alert
+// at a time, so exports of any size stream straight to the client
+package export
+
+import (
+	"fmt"
+	"io"
+	"time"
+
+	"hex_go/internal/domain/entities"
+)
+
+// AlertWriter writes alerts in one export format. WriteHeader comes first, Close last;
+// Close does not close the underlying writer.
+type AlertWriter interface {
+	WriteHeader() error
+	WriteAlert(alert *entities.Alert) error
+	Close() error
+}
+
+// NewAlertWriter returns the writer of a format of entities.ExportFormats with the
+// column headers in lang
+func NewAlertWriter(format, lang string, w io.Writer) (AlertWriter, error) {
+	switch format {
+	case entities.ExportFormatCSV:
+		return newCSVWriter(w, lang), nil
+	case entities.ExportFormatNDJSON:
+		return newNDJSONWriter(w), nil
+	case entities.ExportFormatXLSX:
+		return newXLSXWriter(w, lang), nil
+	default:
+		return nil, fmt.Errorf("export format %q not supported", format)
+	}
+}
+
+// ContentType returns the media type of an export format
+func ContentType(format string) string {
+	switch format {
+	case entities.ExportFormatCSV:
+		return "text/csv; charset=utf-8"
+	case entities.ExportFormatNDJSON:
+		return "application/x-ndjson"
+	case entities.ExportFormatXLSX:
+		return "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet"
+	default:
+		return "application/octet-stream"
+	}
+}
+
+// FileName names an export made at the given time, such as "alertas-20261019.csv"
+func FileName(format, lang string, at time.Time) string {
+	return fmt.Sprintf("%s-%s.%s", fileNames[language(lang)], at.UTC().Format("20060102"), format)
+}
+
+var fileNames = map[string]string{
+	entities.LanguageSpanish: "alertas",
+	entities.LanguageEnglish: "alerts",
+}
+
+// alertHeaders holds the column headers of the spreadsheet formats in every language, in
+// the order of alertRow. Times are exported in UTC.
+var alertHeaders = map[string][]string{
+	entities.LanguageSpanish: {
+		"ID", "Dispositivo", "Tipo", "Severidad", "Estado", "Mensaje", "Valor", "Ocurrencias",
+		"Fecha de creación (UTC)", "Última activación (UTC)", "Reconocida por", "Fecha de reconocimiento (UTC)",
+		"Incidente", "Suprimida",
+	},
+	entities.LanguageEnglish: {
+		"ID", "Device", "Type", "Severity", "State", "Message", "Value", "Occurrences",
+		"Raised at (UTC)", "Last activation (UTC)", "Acknowledged by", "Acknowledged at (UTC)",
+		"Incident", "Suppressed",
+	},
+}
+
+// yesNo spells booleans in every language
+var yesNo = map[string][2]string{
+	entities.LanguageSpanish: {"sí", "no"},
+	entities.LanguageEnglish: {"yes", "no"},
+}
+
+// language returns lang when headers exist for it, Spanish otherwise
+func language(lang string) string {
+	if _, ok := alertHeaders[lang]; ok {
+		return lang
+	}
+	return entities.LanguageSpanish
+}
+
+// alertRow returns the cells of an alert in the order of alertHeaders: strings, float64,
+// time.Time, or nil for an empty cell
+func alertRow(a *entities.Alert, lang string) []interface{} {
+	row := []interface{}{
+		float64(a.ID), a.NumeroSerie, a.Tipo, a.Severidad, a.Estado, a.Mensaje, nil, float64(a.Ocurrencias),
+		a.FechaCreacion.UTC(), a.FechaUltima.UTC(), nil, nil, nil, yesNo[language(lang)][1],
+	}
+	if a.Valor != nil {
+		row[6] = *a.Valor
+	}
+	if a.ReconocidaPor != nil {
+		row[10] = float64(*a.ReconocidaPor)
+	}
+	if a.FechaReconocimiento != nil {
+		row[11] = a.FechaReconocimiento.UTC()
+	}
+	if a.IDIncidente != nil {
+		row[12] = float64(*a.IDIncidente)
+	}
+	if a.Suprimida {
+		row[13] = yesNo[language(lang)][0]
+	}
+	return row
+}
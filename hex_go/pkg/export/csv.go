@@ -0,0 +1,52 @@
+package export
+
+import (
+	"encoding/csv"
+	"io"
+	"strconv"
+	"time"
+
+	"hex_go/internal/domain/entities"
+)
+
+// utf8BOM lets spreadsheet programs recognise the encoding of accented headers
+const utf8BOM = "\ufeff"
+
+// csvWriter writes RFC 4180 CSV with localised headers
+type csvWriter struct {
+	w    io.Writer
+	csv  *csv.Writer
+	lang string
+}
+
+func newCSVWriter(w io.Writer, lang string) *csvWriter {
+	return &csvWriter{w: w, csv: csv.NewWriter(w), lang: language(lang)}
+}
+
+func (c *csvWriter) WriteHeader() error {
+	if _, err := io.WriteString(c.w, utf8BOM); err != nil {
+		return err
+	}
+	return c.csv.Write(alertHeaders[c.lang])
+}
+
+func (c *csvWriter) WriteAlert(alert *entities.Alert) error {
+	row := alertRow(alert, c.lang)
+	record := make([]string, len(row))
+	for i, value := range row {
+		switch v := value.(type) {
+		case string:
+			record[i] = v
+		case float64:
+			record[i] = strconv.FormatFloat(v, 'f', -1, 64)
+		case time.Time:
+			record[i] = v.Format(time.RFC3339)
+		}
+	}
+	return c.csv.Write(record)
+}
+
+func (c *csvWriter) Close() error {
+	c.csv.Flush()
+	return c.csv.Error()
+}
@@ -0,0 +1,30 @@
+package export
+
+import (
+	"encoding/json"
+	"io"
+
+	"hex_go/internal/domain/entities"
+)
+
+// ndjsonWriter writes one alert per line as returned by the API. Its keys are the API
+// field names, so it has no headers to localise.
+type ndjsonWriter struct {
+	encoder *json.Encoder
+}
+
+func newNDJSONWriter(w io.Writer) *ndjsonWriter {
+	return &ndjsonWriter{encoder: json.NewEncoder(w)}
+}
+
+func (n *ndjsonWriter) WriteHeader() error {
+	return nil
+}
+
+func (n *ndjsonWriter) WriteAlert(alert *entities.Alert) error {
+	return n.encoder.Encode(alert)
+}
+
+func (n *ndjsonWriter) Close() error {
+	return nil
+}
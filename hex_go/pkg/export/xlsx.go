@@ -0,0 +1,168 @@
+package export
+
+import (
+	"archive/zip"
+	"bufio"
+	"encoding/xml"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"hex_go/internal/domain/entities"
+)
+
+// The fixed parts of a workbook with a single sheet. Strings are written inline in the
+// sheet rather than in a shared strings table, which would need every row up front.
+const (
+	xlsxContentTypes = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<Types xmlns="http://schemas.openxmlformats.org/package/2006/content-types">
+<Default Extension="rels" ContentType="application/vnd.openxmlformats-package.relationships+xml"/>
+<Default Extension="xml" ContentType="application/xml"/>
+<Override PartName="/xl/workbook.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.sheet.main+xml"/>
+<Override PartName="/xl/worksheets/sheet1.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.worksheet+xml"/>
+<Override PartName="/xl/styles.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.styles+xml"/>
+</Types>`
+
+	xlsxRels = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">
+<Relationship Id="rId1" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/officeDocument" Target="xl/workbook.xml"/>
+</Relationships>`
+
+	xlsxWorkbookRels = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">
+<Relationship Id="rId1" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/worksheet" Target="worksheets/sheet1.xml"/>
+<Relationship Id="rId2" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/styles" Target="styles.xml"/>
+</Relationships>`
+
+	// Style 1 shows dates, style 2 bolds the header row
+	xlsxStyles = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<styleSheet xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main">
+<numFmts count="1"><numFmt numFmtId="164" formatCode="yyyy-mm-dd hh:mm:ss"/></numFmts>
+<fonts count="2"><font><sz val="11"/><name val="Calibri"/></font><font><b/><sz val="11"/><name val="Calibri"/></font></fonts>
+<fills count="2"><fill><patternFill patternType="none"/></fill><fill><patternFill patternType="gray125"/></fill></fills>
+<borders count="1"><border><left/><right/><top/><bottom/><diagonal/></border></borders>
+<cellStyleXfs count="1"><xf numFmtId="0" fontId="0" fillId="0" borderId="0"/></cellStyleXfs>
+<cellXfs count="3"><xf numFmtId="0" fontId="0" fillId="0" borderId="0" xfId="0"/><xf numFmtId="164" fontId="0" fillId="0" borderId="0" xfId="0" applyNumberFormat="1"/><xf numFmtId="0" fontId="1" fillId="0" borderId="0" xfId="0" applyFont="1"/></cellXfs>
+</styleSheet>`
+
+	xlsxSheetStart = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<worksheet xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main"><sheetData>`
+
+	xlsxSheetEnd = `</sheetData></worksheet>`
+)
+
+// xlsxSheetNames names the sheet in every language
+var xlsxSheetNames = map[string]string{
+	entities.LanguageSpanish: "Alertas",
+	entities.LanguageEnglish: "Alerts",
+}
+
+// excelEpoch is day zero of the dates of a spreadsheet
+var excelEpoch = time.Date(1899, 12, 30, 0, 0, 0, 0, time.UTC)
+
+// xlsxWriter writes an Office Open XML workbook, streaming the rows of its sheet into
+// the zip archive as they come
+type xlsxWriter struct {
+	zip   *zip.Writer
+	sheet *bufio.Writer
+	lang  string
+}
+
+func newXLSXWriter(w io.Writer, lang string) *xlsxWriter {
+	return &xlsxWriter{zip: zip.NewWriter(w), lang: language(lang)}
+}
+
+// WriteHeader writes the fixed parts of the workbook and opens the sheet with the header row
+func (x *xlsxWriter) WriteHeader() error {
+	var workbook xmlBuilder
+	workbook.WriteString(`<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<workbook xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main" xmlns:r="http://schemas.openxmlformats.org/officeDocument/2006/relationships"><sheets><sheet name="`)
+	workbook.escape(xlsxSheetNames[x.lang])
+	workbook.WriteString(`" sheetId="1" r:id="rId1"/></sheets></workbook>`)
+
+	parts := []struct{ name, content string }{
+		{"[Content_Types].xml", xlsxContentTypes},
+		{"_rels/.rels", xlsxRels},
+		{"xl/workbook.xml", workbook.String()},
+		{"xl/_rels/workbook.xml.rels", xlsxWorkbookRels},
+		{"xl/styles.xml", xlsxStyles},
+	}
+	for _, part := range parts {
+		f, err := x.zip.Create(part.name)
+		if err != nil {
+			return err
+		}
+		if _, err := io.WriteString(f, part.content); err != nil {
+			return err
+		}
+	}
+
+	f, err := x.zip.Create("xl/worksheets/sheet1.xml")
+	if err != nil {
+		return err
+	}
+	x.sheet = bufio.NewWriter(f)
+	x.sheet.WriteString(xlsxSheetStart)
+
+	headers := alertHeaders[x.lang]
+	row := make([]interface{}, len(headers))
+	for i, header := range headers {
+		row[i] = header
+	}
+	return x.writeRow(row, "2")
+}
+
+func (x *xlsxWriter) WriteAlert(alert *entities.Alert) error {
+	return x.writeRow(alertRow(alert, x.lang), "")
+}
+
+// Close ends the sheet and the archive
+func (x *xlsxWriter) Close() error {
+	if x.sheet != nil {
+		x.sheet.WriteString(xlsxSheetEnd)
+		if err := x.sheet.Flush(); err != nil {
+			return err
+		}
+	}
+	return x.zip.Close()
+}
+
+// writeRow writes the cells of one row; style applies to the text cells
+func (x *xlsxWriter) writeRow(row []interface{}, style string) error {
+	var b xmlBuilder
+	b.WriteString("<row>")
+	for _, value := range row {
+		switch v := value.(type) {
+		case string:
+			b.WriteString(`<c t="inlineStr"`)
+			if style != "" {
+				b.WriteString(` s="` + style + `"`)
+			}
+			b.WriteString("><is><t>")
+			b.escape(v)
+			b.WriteString("</t></is></c>")
+		case float64:
+			b.WriteString("<c><v>" + strconv.FormatFloat(v, 'f', -1, 64) + "</v></c>")
+		case time.Time:
+			days := v.Sub(excelEpoch).Hours() / 24
+			b.WriteString(`<c s="1"><v>` + strconv.FormatFloat(days, 'f', -1, 64) + "</v></c>")
+		default:
+			b.WriteString("<c/>")
+		}
+	}
+	b.WriteString("</row>")
+
+	_, err := x.sheet.WriteString(b.String())
+	return err
+}
+
+// xmlBuilder builds XML text with escaped content
+type xmlBuilder struct {
+	strings.Builder
+}
+
+// escape writes text with the XML special characters escaped
+func (b *xmlBuilder) escape(text string) {
+	xml.EscapeText(b, []byte(text))
+}
@@ -0,0 +1,280 @@
+package notifier
+
+import (
+	"context"
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"hex_go/internal/domain/entities"
+	"hex_go/internal/domain/ports"
+)
+
+// fcmScope is the OAuth scope needed to send messages through FCM
+const fcmScope = "https://www.googleapis.com/auth/firebase.messaging"
+
+// tokenRefreshMargin renews access tokens before they expire mid-request
+const tokenRefreshMargin = time.Minute
+
+// FCMConfig holds the settings of Firebase Cloud Messaging
+type FCMConfig struct {
+	// CredentialsFile is the service account JSON key downloaded from the Firebase console
+	CredentialsFile string
+	// URL is the send endpoint; empty uses the FCM v1 endpoint of the service account's project
+	URL string
+	// TokenURL overrides the OAuth token endpoint of the service account
+	TokenURL string
+	Timeout  time.Duration
+}
+
+// FCMNotifier sends notifications as push messages to the mobile app through FCM,
+// which also delivers to iOS devices through APNs
+type FCMNotifier struct {
+	url    string
+	client *http.Client
+	tokens *serviceAccountTokens
+}
+
+var _ ports.NotifierPort = (*FCMNotifier)(nil)
+
+// serviceAccount holds the fields used from a Google service account key
+type serviceAccount struct {
+	ProjectID   string `json:"project_id"`
+	ClientEmail string `json:"client_email"`
+	PrivateKey  string `json:"private_key"`
+	TokenURI    string `json:"token_uri"`
+}
+
+// fcmRequest is the request body of the FCM v1 send method
+type fcmRequest struct {
+	Message fcmMessage `json:"message"`
+}
+
+type fcmMessage struct {
+	Token        string            `json:"token"`
+	Notification fcmNotification   `json:"notification"`
+	Data         map[string]string `json:"data"`
+	Android      fcmAndroid        `json:"android"`
+	APNs         fcmAPNs           `json:"apns"`
+}
+
+type fcmNotification struct {
+	Title string `json:"title"`
+	Body  string `json:"body"`
+}
+
+type fcmAndroid struct {
+	Priority string `json:"priority"`
+}
+
+type fcmAPNs struct {
+	Headers map[string]string `json:"headers"`
+}
+
+// NewFCMNotifier creates a push notifier authenticated with a service account key
+func NewFCMNotifier(cfg FCMConfig) (*FCMNotifier, error) {
+	data, err := os.ReadFile(cfg.CredentialsFile)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read FCM credentials: %w", err)
+	}
+	var account serviceAccount
+	if err := json.Unmarshal(data, &account); err != nil {
+		return nil, fmt.Errorf("failed to parse FCM credentials: %w", err)
+	}
+
+	key, err := parsePrivateKey(account.PrivateKey)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse FCM credentials: %w", err)
+	}
+
+	sendURL := cfg.URL
+	if sendURL == "" {
+		if account.ProjectID == "" {
+			return nil, errors.New("FCM credentials have no project_id")
+		}
+		sendURL = "https://fcm.googleapis.com/v1/projects/" + url.PathEscape(account.ProjectID) + "/messages:send"
+	}
+	tokenURL := cfg.TokenURL
+	if tokenURL == "" {
+		tokenURL = account.TokenURI
+	}
+	if tokenURL == "" {
+		return nil, errors.New("FCM credentials have no token_uri")
+	}
+
+	client := newHTTPClient(cfg.Timeout)
+	return &FCMNotifier{
+		url:    sendURL,
+		client: client,
+		tokens: &serviceAccountTokens{
+			email:    account.ClientEmail,
+			key:      key,
+			tokenURL: tokenURL,
+			client:   client,
+		},
+	}, nil
+}
+
+// Channel returns the push channel
+func (n *FCMNotifier) Channel() string {
+	return entities.ChannelPush
+}
+
+// Notify sends the alert to the app installation registered with the recipient's token.
+// The data fields let the app open the alert when the notification is tapped.
+func (n *FCMNotifier) Notify(ctx context.Context, notification *entities.Notification) error {
+	alert := notification.Alerta
+	return n.send(ctx, notification.Destinatario.Direccion, fcmNotification{
+		Title: alertTitle(notification),
+		Body:  shortBody(notification),
+	}, map[string]string{
+		"id_alerta":    strconv.FormatInt(alert.ID, 10),
+		"numero_serie": alert.NumeroSerie,
+		"tipo":         alert.Tipo,
+		"severidad":    alert.Severidad,
+	})
+}
+
+// NotifyDigest sends the count of held back alerts per kind to the app installation.
+// The data fields let the app open its alert list when the notification is tapped.
+func (n *FCMNotifier) NotifyDigest(ctx context.Context, digest *entities.NotificationDigest) error {
+	return n.send(ctx, digest.Destinatario.Direccion, fcmNotification{
+		Title: digestTitle(digest),
+		Body:  digestSummary(digest),
+	}, map[string]string{
+		"tipo":  "resumen",
+		"total": strconv.Itoa(digest.Total),
+	})
+}
+
+// send posts one high priority message to an app installation
+func (n *FCMNotifier) send(ctx context.Context, to string, notification fcmNotification, data map[string]string) error {
+	token, err := n.tokens.Token(ctx)
+	if err != nil {
+		return err
+	}
+
+	return postJSON(ctx, n.client, n.url, map[string]string{"Authorization": "Bearer " + token}, fcmRequest{
+		Message: fcmMessage{
+			Token:        to,
+			Notification: notification,
+			Data:         data,
+			Android:      fcmAndroid{Priority: "high"},
+			APNs:         fcmAPNs{Headers: map[string]string{"apns-priority": "10"}},
+		},
+	}, nil)
+}
+
+// serviceAccountTokens exchanges signed service account assertions for OAuth access
+// tokens and reuses each token until shortly before it expires
+type serviceAccountTokens struct {
+	email    string
+	key      *rsa.PrivateKey
+	tokenURL string
+	client   *http.Client
+
+	mu      sync.Mutex
+	token   string
+	expires time.Time
+}
+
+// tokenResponse is the body of a successful token exchange
+type tokenResponse struct {
+	AccessToken string `json:"access_token"`
+	ExpiresIn   int    `json:"expires_in"`
+}
+
+// Token returns a valid access token, requesting a new one when needed
+func (t *serviceAccountTokens) Token(ctx context.Context) (string, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if t.token != "" && time.Now().Add(tokenRefreshMargin).Before(t.expires) {
+		return t.token, nil
+	}
+
+	now := time.Now()
+	assertion, err := t.assertion(now)
+	if err != nil {
+		return "", err
+	}
+
+	form := url.Values{
+		"grant_type": {"urn:ietf:params:oauth:grant-type:jwt-bearer"},
+		"assertion":  {assertion},
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, t.tokenURL, strings.NewReader(form.Encode()))
+	if err != nil {
+		return "", fmt.Errorf("failed to create token request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("User-Agent", userAgent)
+
+	var resp tokenResponse
+	if err := doRequest(t.client, req, &resp); err != nil {
+		return "", fmt.Errorf("failed to obtain FCM access token: %w", err)
+	}
+	if resp.AccessToken == "" {
+		return "", errors.New("failed to obtain FCM access token: empty token")
+	}
+
+	t.token = resp.AccessToken
+	t.expires = now.Add(time.Duration(resp.ExpiresIn) * time.Second)
+	return t.token, nil
+}
+
+// assertion builds the RS256 signed JWT that identifies the service account
+func (t *serviceAccountTokens) assertion(now time.Time) (string, error) {
+	header, _ := json.Marshal(map[string]string{"alg": "RS256", "typ": "JWT"})
+	claims, _ := json.Marshal(map[string]interface{}{
+		"iss":   t.email,
+		"scope": fcmScope,
+		"aud":   t.tokenURL,
+		"iat":   now.Unix(),
+		"exp":   now.Add(time.Hour).Unix(),
+	})
+
+	signed := base64.RawURLEncoding.EncodeToString(header) + "." + base64.RawURLEncoding.EncodeToString(claims)
+	digest := sha256.Sum256([]byte(signed))
+	signature, err := rsa.SignPKCS1v15(rand.Reader, t.key, crypto.SHA256, digest[:])
+	if err != nil {
+		return "", fmt.Errorf("failed to sign FCM assertion: %w", err)
+	}
+
+	return signed + "." + base64.RawURLEncoding.EncodeToString(signature), nil
+}
+
+// parsePrivateKey reads the PEM encoded RSA key of a service account
+func parsePrivateKey(data string) (*rsa.PrivateKey, error) {
+	block, _ := pem.Decode([]byte(data))
+	if block == nil {
+		return nil, errors.New("private_key is not PEM encoded")
+	}
+
+	if key, err := x509.ParsePKCS1PrivateKey(block.Bytes); err == nil {
+		return key, nil
+	}
+	parsed, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("invalid private_key: %w", err)
+	}
+	key, ok := parsed.(*rsa.PrivateKey)
+	if !ok {
+		return nil, errors.New("private_key is not an RSA key")
+	}
+	return key, nil
+}
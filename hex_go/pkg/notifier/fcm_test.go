@@ -0,0 +1,257 @@
+package notifier
+
+import (
+	"context"
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"hex_go/internal/domain/entities"
+)
+
+// fcmServer stands in for both the OAuth token endpoint and the FCM send endpoint. It
+// checks the signature of the service account assertions with the account's public key.
+type fcmServer struct {
+	*httptest.Server
+	key *rsa.PrivateKey
+
+	mu         sync.Mutex
+	tokenCalls int
+	sends      []gatewayRequest
+	sendStatus int
+}
+
+func newFCMServer(t *testing.T) *fcmServer {
+	t.Helper()
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	s := &fcmServer{key: key, sendStatus: http.StatusOK}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/token", func(w http.ResponseWriter, r *http.Request) {
+		if err := r.ParseForm(); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		if r.Form.Get("grant_type") != "urn:ietf:params:oauth:grant-type:jwt-bearer" {
+			http.Error(w, `{"error":"unsupported_grant_type"}`, http.StatusBadRequest)
+			return
+		}
+		if err := s.verifyAssertion(r.Form.Get("assertion")); err != nil {
+			http.Error(w, `{"error":"invalid_grant"}`, http.StatusBadRequest)
+			return
+		}
+		s.mu.Lock()
+		s.tokenCalls++
+		s.mu.Unlock()
+		io.WriteString(w, `{"access_token":"ya29.test","expires_in":3600,"token_type":"Bearer"}`)
+	})
+	mux.HandleFunc("/send", func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		s.mu.Lock()
+		s.sends = append(s.sends, gatewayRequest{method: r.Method, path: r.URL.Path, header: r.Header, body: body})
+		status := s.sendStatus
+		s.mu.Unlock()
+		w.WriteHeader(status)
+		if status != http.StatusOK {
+			io.WriteString(w, `{"error":{"status":"NOT_FOUND","message":"Requested entity was not found."}}`)
+			return
+		}
+		io.WriteString(w, `{"name":"projects/stopfire/messages/1"}`)
+	})
+	s.Server = httptest.NewServer(mux)
+	t.Cleanup(s.Close)
+	return s
+}
+
+// received returns the messages sent and how many access tokens were issued
+func (s *fcmServer) received() ([]gatewayRequest, int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.sends, s.tokenCalls
+}
+
+// verifyAssertion checks the RS256 signature and the claims of a service account JWT
+func (s *fcmServer) verifyAssertion(assertion string) error {
+	parts := strings.Split(assertion, ".")
+	if len(parts) != 3 {
+		return io.ErrUnexpectedEOF
+	}
+	signature, err := base64.RawURLEncoding.DecodeString(parts[2])
+	if err != nil {
+		return err
+	}
+	digest := sha256.Sum256([]byte(parts[0] + "." + parts[1]))
+	if err := rsa.VerifyPKCS1v15(&s.key.PublicKey, crypto.SHA256, digest[:], signature); err != nil {
+		return err
+	}
+
+	payload, err := base64.RawURLEncoding.DecodeString(parts[1])
+	if err != nil {
+		return err
+	}
+	var claims struct {
+		Iss   string `json:"iss"`
+		Scope string `json:"scope"`
+		Aud   string `json:"aud"`
+	}
+	if err := json.Unmarshal(payload, &claims); err != nil {
+		return err
+	}
+	if claims.Iss != "notifier@stopfire.iam.gserviceaccount.com" || claims.Scope != fcmScope || claims.Aud != s.URL+"/token" {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+
+// credentials writes a service account key file pointing at the server
+func (s *fcmServer) credentials(t *testing.T) string {
+	t.Helper()
+	keyPEM := pem.EncodeToMemory(&pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(s.key)})
+	data, err := json.Marshal(serviceAccount{
+		ProjectID:   "stopfire",
+		ClientEmail: "notifier@stopfire.iam.gserviceaccount.com",
+		PrivateKey:  string(keyPEM),
+		TokenURI:    s.URL + "/token",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	path := filepath.Join(t.TempDir(), "service-account.json")
+	if err := os.WriteFile(path, data, 0o600); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func newTestFCMNotifier(t *testing.T, server *fcmServer) *FCMNotifier {
+	t.Helper()
+	n, err := NewFCMNotifier(FCMConfig{CredentialsFile: server.credentials(t), URL: server.URL + "/send", Timeout: time.Second})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return n
+}
+
+func pushNotification() *entities.Notification {
+	notification := testNotification(entities.LanguageEnglish)
+	notification.Destinatario = &entities.NotificationRecipient{Canal: entities.ChannelPush, Direccion: "device-registration-token", Idioma: entities.LanguageEnglish}
+	notification.Alerta.ID = 42
+	return notification
+}
+
+func TestFCMNotifierSendsAlerts(t *testing.T) {
+	server := newFCMServer(t)
+	n := newTestFCMNotifier(t, server)
+
+	if err := n.Notify(context.Background(), pushNotification()); err != nil {
+		t.Fatal(err)
+	}
+
+	sends, _ := server.received()
+	if len(sends) != 1 {
+		t.Fatalf("FCM received %d messages, want 1", len(sends))
+	}
+	req := sends[0]
+	req.checkJSONPost(t)
+	if auth := req.header.Get("Authorization"); auth != "Bearer ya29.test" {
+		t.Errorf("Authorization = %q, want the access token", auth)
+	}
+
+	var body fcmRequest
+	req.decode(t, &body)
+	msg := body.Message
+	if msg.Token != "device-registration-token" {
+		t.Errorf("token = %q", msg.Token)
+	}
+	if msg.Notification.Title != "Smoke or combustible gas detected" {
+		t.Errorf("title = %q", msg.Notification.Title)
+	}
+	wantData := map[string]string{"id_alerta": "42", "numero_serie": "ESP32-0001", "tipo": entities.SensorTypeMQ2, "severidad": entities.SeverityCritical}
+	for k, v := range wantData {
+		if msg.Data[k] != v {
+			t.Errorf("data[%s] = %q, want %q", k, msg.Data[k], v)
+		}
+	}
+	if msg.Android.Priority != "high" || msg.APNs.Headers["apns-priority"] != "10" {
+		t.Errorf("priority = %q, apns-priority = %q, want high and 10", msg.Android.Priority, msg.APNs.Headers["apns-priority"])
+	}
+}
+
+func TestFCMNotifierReusesAccessToken(t *testing.T) {
+	server := newFCMServer(t)
+	n := newTestFCMNotifier(t, server)
+
+	for i := 0; i < 3; i++ {
+		if err := n.Notify(context.Background(), pushNotification()); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if _, tokenCalls := server.received(); tokenCalls != 1 {
+		t.Errorf("token endpoint called %d times, want 1", tokenCalls)
+	}
+}
+
+func TestFCMNotifierFailsOnErrorStatus(t *testing.T) {
+	server := newFCMServer(t)
+	server.sendStatus = http.StatusNotFound
+	n := newTestFCMNotifier(t, server)
+
+	err := n.Notify(context.Background(), pushNotification())
+	if err == nil {
+		t.Fatal("Notify() succeeded on a 404 response")
+	}
+	if !strings.Contains(err.Error(), "404") || !strings.Contains(err.Error(), "NOT_FOUND") {
+		t.Errorf("error = %v, want the status and the FCM error", err)
+	}
+}
+
+func TestFCMNotifierFailsWithoutAccessToken(t *testing.T) {
+	server := newFCMServer(t)
+	credentials := server.credentials(t)
+	// Sign the assertions with another key, which the token endpoint refuses
+	other, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	n, err := NewFCMNotifier(FCMConfig{CredentialsFile: credentials, URL: server.URL + "/send", Timeout: time.Second})
+	if err != nil {
+		t.Fatal(err)
+	}
+	n.tokens.key = other
+
+	err = n.Notify(context.Background(), pushNotification())
+	if err == nil || !strings.Contains(err.Error(), "access token") {
+		t.Fatalf("Notify() error = %v, want an access token error", err)
+	}
+	if sends, _ := server.received(); len(sends) != 0 {
+		t.Errorf("FCM received %d messages without a token", len(sends))
+	}
+}
+
+func TestNewFCMNotifierDefaultsToProjectEndpoint(t *testing.T) {
+	server := newFCMServer(t)
+	n, err := NewFCMNotifier(FCMConfig{CredentialsFile: server.credentials(t)})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := "https://fcm.googleapis.com/v1/projects/stopfire/messages:send"; n.url != want {
+		t.Errorf("url = %s, want %s", n.url, want)
+	}
+}
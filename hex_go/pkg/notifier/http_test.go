@@ -0,0 +1,79 @@
+package notifier
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+)
+
+// gatewayRequest is a request received by the test gateway
+type gatewayRequest struct {
+	method string
+	path   string
+	header http.Header
+	body   []byte
+}
+
+// gateway is an httptest server standing in for an HTTP channel provider. It keeps the
+// requests it receives and answers them with status and body.
+type gateway struct {
+	*httptest.Server
+
+	mu       sync.Mutex
+	requests []gatewayRequest
+	status   int
+	body     string
+}
+
+func newGateway(t *testing.T, status int, body string) *gateway {
+	t.Helper()
+	g := &gateway{status: status, body: body}
+	g.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		data, _ := io.ReadAll(r.Body)
+		g.mu.Lock()
+		g.requests = append(g.requests, gatewayRequest{method: r.Method, path: r.URL.Path, header: r.Header, body: data})
+		g.mu.Unlock()
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(g.status)
+		io.WriteString(w, g.body)
+	}))
+	t.Cleanup(g.Close)
+	return g
+}
+
+// only returns the single request received, failing the test otherwise
+func (g *gateway) only(t *testing.T) gatewayRequest {
+	t.Helper()
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	if len(g.requests) != 1 {
+		t.Fatalf("gateway received %d requests, want 1", len(g.requests))
+	}
+	return g.requests[0]
+}
+
+// decode reads the JSON body of a request into v
+func (r gatewayRequest) decode(t *testing.T, v interface{}) {
+	t.Helper()
+	if err := json.Unmarshal(r.body, v); err != nil {
+		t.Fatalf("body is not JSON: %v: %s", err, r.body)
+	}
+}
+
+// checkJSONPost checks the method and headers postJSON sends
+func (r gatewayRequest) checkJSONPost(t *testing.T) {
+	t.Helper()
+	if r.method != http.MethodPost {
+		t.Errorf("method = %s, want POST", r.method)
+	}
+	if ct := r.header.Get("Content-Type"); ct != "application/json" {
+		t.Errorf("Content-Type = %q, want application/json", ct)
+	}
+	if ua := r.header.Get("User-Agent"); ua != userAgent {
+		t.Errorf("User-Agent = %q, want %q", ua, userAgent)
+	}
+}
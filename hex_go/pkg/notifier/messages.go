@@ -0,0 +1,160 @@
+package notifier
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"hex_go/internal/domain/entities"
+)
+
+// alertTitles names each alert type in every notification language
+var alertTitles = map[string]map[string]string{
+	entities.LanguageSpanish: {
+		entities.SensorTypeKY026:        "Llama detectada",
+		entities.SensorTypeMQ2:          "Humo o gas combustible detectado",
+		entities.SensorTypeMQ135:        "Mala calidad del aire",
+		entities.SensorTypeDHT22:        "Temperatura o humedad fuera de rango",
+		entities.AlertTypeDeviceOffline: "Dispositivo sin conexión",
+	},
+	entities.LanguageEnglish: {
+		entities.SensorTypeKY026:        "Flame detected",
+		entities.SensorTypeMQ2:          "Smoke or combustible gas detected",
+		entities.SensorTypeMQ135:        "Poor air quality",
+		entities.SensorTypeDHT22:        "Temperature or humidity out of range",
+		entities.AlertTypeDeviceOffline: "Device offline",
+	},
+}
+
+// sensorNames describes the sensor behind each alert type
+var sensorNames = map[string]map[string]string{
+	entities.LanguageSpanish: {
+		entities.SensorTypeKY026:        "Sensor de llama KY-026",
+		entities.SensorTypeMQ2:          "Sensor de humo MQ-2",
+		entities.SensorTypeMQ135:        "Sensor de calidad del aire MQ-135",
+		entities.SensorTypeDHT22:        "Sensor de temperatura y humedad DHT22",
+		entities.AlertTypeDeviceOffline: "Conexión del dispositivo",
+	},
+	entities.LanguageEnglish: {
+		entities.SensorTypeKY026:        "KY-026 flame sensor",
+		entities.SensorTypeMQ2:          "MQ-2 smoke sensor",
+		entities.SensorTypeMQ135:        "MQ-135 air quality sensor",
+		entities.SensorTypeDHT22:        "DHT22 temperature and humidity sensor",
+		entities.AlertTypeDeviceOffline: "Device connection",
+	},
+}
+
+// valueLabels introduces the measured value in short messages
+var valueLabels = map[string]string{
+	entities.LanguageSpanish: "Valor",
+	entities.LanguageEnglish: "Value",
+}
+
+// digestTitles head digests, in singular and plural
+var digestTitles = map[string][2]string{
+	entities.LanguageSpanish: {"Resumen de %d alerta", "Resumen de %d alertas"},
+	entities.LanguageEnglish: {"Digest of %d alert", "Digest of %d alerts"},
+}
+
+// moreLabels count the alerts of a digest that are not described
+var moreLabels = map[string]string{
+	entities.LanguageSpanish: "y %d más",
+	entities.LanguageEnglish: "and %d more",
+}
+
+// reportTitles head the emails carrying the monthly reports
+var reportTitles = map[string]string{
+	entities.LanguageSpanish: "Informe mensual de seguridad",
+	entities.LanguageEnglish: "Monthly safety report",
+}
+
+// language returns the language of the recipient of a notification
+func language(n *entities.Notification) string {
+	return recipientLanguage(n.Destinatario)
+}
+
+// recipientLanguage returns the language of a recipient, falling back to Spanish
+func recipientLanguage(r *entities.NotificationRecipient) string {
+	if r != nil && entities.IsNotificationLanguage(r.Idioma) {
+		return r.Idioma
+	}
+	return entities.LanguageSpanish
+}
+
+// alertTitle returns the headline of a notification, such as "Llama detectada"
+func alertTitle(n *entities.Notification) string {
+	if title, ok := alertTitles[language(n)][n.Alerta.Tipo]; ok {
+		return title
+	}
+	return n.Alerta.Mensaje
+}
+
+// sensorName returns the sensor that raised the alert, or its type when unknown
+func sensorName(n *entities.Notification) string {
+	if name, ok := sensorNames[language(n)][n.Alerta.Tipo]; ok {
+		return name
+	}
+	return n.Alerta.Tipo
+}
+
+// alertValue formats the value measured by the sensor, or "" when there is none
+func alertValue(n *entities.Notification) string {
+	if n.Alerta.Valor == nil {
+		return ""
+	}
+	return strconv.FormatFloat(*n.Alerta.Valor, 'f', -1, 64)
+}
+
+// shortTime is the time layout of messages with little room, such as SMS and push
+const shortTime = "02/01 15:04 MST"
+
+// shortBody summarises a notification in one line for channels with little room:
+// device and location, value and time, such as "Cocina (Casa / Planta baja) · Valor: 1 · 19/10 14:03 CEST"
+func shortBody(n *entities.Notification) string {
+	parts := []string{n.Dispositivo}
+	if n.Ubicacion != "" {
+		parts[0] += " (" + n.Ubicacion + ")"
+	}
+	if value := alertValue(n); value != "" {
+		parts = append(parts, valueLabels[language(n)]+": "+value)
+	}
+	parts = append(parts, n.Fecha.Format(shortTime))
+	return strings.Join(parts, " · ")
+}
+
+// digestTitle heads a digest, such as "Resumen de 3 alertas"
+func digestTitle(d *entities.NotificationDigest) string {
+	forms := digestTitles[recipientLanguage(d.Destinatario)]
+	if d.Total == 1 {
+		return fmt.Sprintf(forms[0], d.Total)
+	}
+	return fmt.Sprintf(forms[1], d.Total)
+}
+
+// digestSummary counts the alerts of a digest per title in one line, such as
+// "Mala calidad del aire ×3, Temperatura o humedad fuera de rango ×1"
+func digestSummary(d *entities.NotificationDigest) string {
+	var titles []string
+	counts := make(map[string]int)
+	for _, n := range d.Notificaciones {
+		title := alertTitle(n)
+		if counts[title] == 0 {
+			titles = append(titles, title)
+		}
+		counts[title]++
+	}
+
+	parts := make([]string, len(titles))
+	for i, title := range titles {
+		parts[i] = fmt.Sprintf("%s ×%d", title, counts[title])
+	}
+	if more := digestRemaining(d); more > 0 {
+		parts = append(parts, fmt.Sprintf(moreLabels[recipientLanguage(d.Destinatario)], more))
+	}
+	return strings.Join(parts, ", ")
+}
+
+// digestRemaining returns how many alerts of a digest are counted but not described
+func digestRemaining(d *entities.NotificationDigest) int {
+	return d.Total - len(d.Notificaciones)
+}
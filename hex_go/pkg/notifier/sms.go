@@ -0,0 +1,74 @@
+package notifier
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"hex_go/internal/domain/entities"
+	"hex_go/internal/domain/ports"
+)
+
+// SMSConfig holds the settings of the HTTP SMS gateway
+type SMSConfig struct {
+	// URL receives a POST with {"to", "from", "text"} for every message
+	URL string
+	// Token is sent as a bearer token when set
+	Token   string
+	From    string
+	Timeout time.Duration
+}
+
+// SMSNotifier sends notifications as text messages through an HTTP SMS gateway
+type SMSNotifier struct {
+	cfg    SMSConfig
+	client *http.Client
+}
+
+var _ ports.NotifierPort = (*SMSNotifier)(nil)
+
+// smsMessage is the request body of the SMS gateway
+type smsMessage struct {
+	To   string `json:"to"`
+	From string `json:"from,omitempty"`
+	Text string `json:"text"`
+}
+
+// NewSMSNotifier creates an SMS notifier posting to the given gateway
+func NewSMSNotifier(cfg SMSConfig) *SMSNotifier {
+	return &SMSNotifier{
+		cfg:    cfg,
+		client: newHTTPClient(cfg.Timeout),
+	}
+}
+
+// Channel returns the SMS channel
+func (n *SMSNotifier) Channel() string {
+	return entities.ChannelSMS
+}
+
+// Notify sends a one-line summary of the alert to the recipient's phone number
+func (n *SMSNotifier) Notify(ctx context.Context, notification *entities.Notification) error {
+	return n.send(ctx, notification.Destinatario.Direccion,
+		"StopFire: "+alertTitle(notification)+". "+shortBody(notification))
+}
+
+// NotifyDigest sends the count of held back alerts per kind to the recipient's phone number
+func (n *SMSNotifier) NotifyDigest(ctx context.Context, digest *entities.NotificationDigest) error {
+	return n.send(ctx, digest.Destinatario.Direccion,
+		"StopFire: "+digestTitle(digest)+". "+digestSummary(digest))
+}
+
+// send posts one text message to the gateway
+func (n *SMSNotifier) send(ctx context.Context, to, text string) error {
+	headers := map[string]string{}
+	if n.cfg.Token != "" {
+		headers["Authorization"] = "Bearer " + n.cfg.Token
+	}
+
+	return postJSON(ctx, n.client, n.cfg.URL, headers, smsMessage{
+		To:   to,
+		From: n.cfg.From,
+		Text: text,
+	}, nil)
+}
@@ -0,0 +1,87 @@
+package notifier
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"hex_go/internal/domain/entities"
+)
+
+func TestSMSNotifierSendsAlerts(t *testing.T) {
+	tests := []struct {
+		name     string
+		token    string
+		wantAuth string
+	}{
+		{"with token", "sms-secret", "Bearer sms-secret"},
+		{"without token", "", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gw := newGateway(t, http.StatusOK, `{}`)
+			n := NewSMSNotifier(SMSConfig{URL: gw.URL + "/messages", Token: tt.token, From: "StopFire", Timeout: time.Second})
+
+			notification := testNotification(entities.LanguageEnglish)
+			notification.Destinatario = &entities.NotificationRecipient{Canal: entities.ChannelSMS, Direccion: "+34600000000", Idioma: entities.LanguageEnglish}
+			if err := n.Notify(context.Background(), notification); err != nil {
+				t.Fatal(err)
+			}
+
+			req := gw.only(t)
+			req.checkJSONPost(t)
+			if req.path != "/messages" {
+				t.Errorf("path = %s, want /messages", req.path)
+			}
+			if auth := req.header.Get("Authorization"); auth != tt.wantAuth {
+				t.Errorf("Authorization = %q, want %q", auth, tt.wantAuth)
+			}
+
+			var msg smsMessage
+			req.decode(t, &msg)
+			if msg.To != "+34600000000" || msg.From != "StopFire" {
+				t.Errorf("to, from = %q, %q", msg.To, msg.From)
+			}
+			if !strings.HasPrefix(msg.Text, "StopFire: Smoke or combustible gas detected. Baño <planta baja> (Casa / Planta baja)") {
+				t.Errorf("text = %q", msg.Text)
+			}
+		})
+	}
+}
+
+func TestSMSNotifierSendsDigests(t *testing.T) {
+	gw := newGateway(t, http.StatusOK, `{}`)
+	n := NewSMSNotifier(SMSConfig{URL: gw.URL, Timeout: time.Second})
+
+	recipient := &entities.NotificationRecipient{Canal: entities.ChannelSMS, Direccion: "+34600000000", Idioma: entities.LanguageEnglish}
+	digest := &entities.NotificationDigest{
+		Destinatario:   recipient,
+		Notificaciones: []*entities.Notification{testNotification(entities.LanguageEnglish)},
+		Total:          4,
+	}
+	if err := n.NotifyDigest(context.Background(), digest); err != nil {
+		t.Fatal(err)
+	}
+
+	var msg smsMessage
+	gw.only(t).decode(t, &msg)
+	if msg.To != "+34600000000" || !strings.Contains(msg.Text, "Digest of 4 alerts") {
+		t.Errorf("message = %+v", msg)
+	}
+}
+
+func TestSMSNotifierFailsOnErrorStatus(t *testing.T) {
+	gw := newGateway(t, http.StatusBadRequest, `{"error":"invalid number"}`)
+	n := NewSMSNotifier(SMSConfig{URL: gw.URL, Timeout: time.Second})
+
+	err := n.Notify(context.Background(), testNotification(entities.LanguageEnglish))
+	if err == nil {
+		t.Fatal("Notify() succeeded on a 400 response")
+	}
+	if !strings.Contains(err.Error(), "400") || !strings.Contains(err.Error(), "invalid number") {
+		t.Errorf("error = %v, want the status and the gateway message", err)
+	}
+}
@@ -0,0 +1,386 @@
+package notifier
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"crypto/tls"
+	"embed"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	htmltemplate "html/template"
+	"mime"
+	"mime/multipart"
+	"mime/quotedprintable"
+	"net"
+	"net/mail"
+	"net/smtp"
+	"net/textproto"
+	"strings"
+	texttemplate "text/template"
+	"time"
+
+	"hex_go/internal/domain/entities"
+	"hex_go/internal/domain/ports"
+)
+
+//go:embed templates/alert_* templates/report_*
+var templateFiles embed.FS
+
+// emailTemplates are the templates of each kind of email, in every language
+var emailTemplates = []string{"alert_email", "alert_digest", "report_email"}
+
+// SMTPConfig holds the settings of the outgoing mail server
+type SMTPConfig struct {
+	Host     string
+	Port     string
+	User     string
+	Password string
+	From     string
+	Timeout  time.Duration
+}
+
+// SMTPNotifier sends notifications as multipart emails with an HTML and a plain text
+// version, in the language of each recipient. Templates are keyed by name and language,
+// such as "alert_email_es".
+type SMTPNotifier struct {
+	cfg  SMTPConfig
+	from *mail.Address
+	html map[string]*htmltemplate.Template
+	text map[string]*texttemplate.Template
+}
+
+var _ ports.NotifierPort = (*SMTPNotifier)(nil)
+var _ ports.ReportMailerPort = (*SMTPNotifier)(nil)
+
+// emailData is what the email templates render
+type emailData struct {
+	Titulo      string
+	Dispositivo string
+	NumeroSerie string
+	Ubicacion   string
+	Sensor      string
+	Valor       string
+	Fecha       string
+	Severidad   string
+	Mensaje     string
+}
+
+// digestData is what the digest templates render
+type digestData struct {
+	Titulo    string
+	Alertas   []emailData
+	Restantes int
+}
+
+// reportData is what the report templates render
+type reportData struct {
+	Titulo       string
+	Sitio        string
+	Mes          string
+	Activaciones int
+	Criticas     int
+	SinReconocer int
+}
+
+// attachment is a file attached to an email
+type attachment struct {
+	name        string
+	contentType string
+	content     []byte
+}
+
+// NewSMTPNotifier creates an email notifier sending through the given server
+func NewSMTPNotifier(cfg SMTPConfig) (*SMTPNotifier, error) {
+	from, err := mail.ParseAddress(cfg.From)
+	if err != nil {
+		return nil, fmt.Errorf("invalid sender address %q: %w", cfg.From, err)
+	}
+
+	n := &SMTPNotifier{
+		cfg:  cfg,
+		from: from,
+		html: make(map[string]*htmltemplate.Template),
+		text: make(map[string]*texttemplate.Template),
+	}
+	for _, name := range emailTemplates {
+		for _, lang := range entities.NotificationLanguages {
+			key := name + "_" + lang
+			html, err := htmltemplate.ParseFS(templateFiles, "templates/"+key+".html")
+			if err != nil {
+				return nil, fmt.Errorf("failed to parse email template %s: %w", key, err)
+			}
+			text, err := texttemplate.ParseFS(templateFiles, "templates/"+key+".txt")
+			if err != nil {
+				return nil, fmt.Errorf("failed to parse email template %s: %w", key, err)
+			}
+			n.html[key] = html
+			n.text[key] = text
+		}
+	}
+	return n, nil
+}
+
+// Channel returns the email channel
+func (n *SMTPNotifier) Channel() string {
+	return entities.ChannelEmail
+}
+
+// Notify renders the notification and sends it to the recipient's address
+func (n *SMTPNotifier) Notify(ctx context.Context, notification *entities.Notification) error {
+	to, err := mail.ParseAddress(notification.Destinatario.Direccion)
+	if err != nil {
+		return fmt.Errorf("invalid recipient address: %w", err)
+	}
+
+	subject := fmt.Sprintf("[StopFire] %s: %s", alertTitle(notification), notification.Dispositivo)
+	msg, err := n.render("alert_email_"+language(notification), emailContent(notification), subject, to)
+	if err != nil {
+		return err
+	}
+	return n.send(ctx, to.Address, msg)
+}
+
+// NotifyDigest sends the held back notifications of a recipient as one email
+func (n *SMTPNotifier) NotifyDigest(ctx context.Context, digest *entities.NotificationDigest) error {
+	to, err := mail.ParseAddress(digest.Destinatario.Direccion)
+	if err != nil {
+		return fmt.Errorf("invalid recipient address: %w", err)
+	}
+
+	data := digestData{
+		Titulo:    digestTitle(digest),
+		Restantes: digestRemaining(digest),
+	}
+	for _, notification := range digest.Notificaciones {
+		data.Alertas = append(data.Alertas, emailContent(notification))
+	}
+
+	subject := "[StopFire] " + data.Titulo
+	msg, err := n.render("alert_digest_"+recipientLanguage(digest.Destinatario), data, subject, to)
+	if err != nil {
+		return err
+	}
+	return n.send(ctx, to.Address, msg)
+}
+
+// SendReport emails a monthly site report with its PDF attached
+func (n *SMTPNotifier) SendReport(ctx context.Context, recipient *entities.NotificationRecipient, report *entities.SiteReport, pdf []byte) error {
+	to, err := mail.ParseAddress(recipient.Direccion)
+	if err != nil {
+		return fmt.Errorf("invalid recipient address: %w", err)
+	}
+
+	lang := recipientLanguage(recipient)
+	data := reportData{
+		Titulo:       reportTitles[lang],
+		Sitio:        report.Sitio.Nombre,
+		Mes:          report.Mes,
+		SinReconocer: report.Respuesta.SinReconocer,
+	}
+	for _, count := range report.Alarmas {
+		data.Activaciones += count.Activaciones
+		data.Criticas += count.Criticas
+	}
+
+	subject := fmt.Sprintf("[StopFire] %s: %s %s", data.Titulo, data.Sitio, data.Mes)
+	file := attachment{
+		name:        fmt.Sprintf("informe-%d-%s.pdf", report.Sitio.ID, report.Mes),
+		contentType: "application/pdf",
+		content:     pdf,
+	}
+	msg, err := n.render("report_email_"+lang, data, subject, to, file)
+	if err != nil {
+		return err
+	}
+	return n.send(ctx, to.Address, msg)
+}
+
+// emailContent is what the templates show of a notification
+func emailContent(notification *entities.Notification) emailData {
+	return emailData{
+		Titulo:      alertTitle(notification),
+		Dispositivo: notification.Dispositivo,
+		NumeroSerie: notification.Alerta.NumeroSerie,
+		Ubicacion:   notification.Ubicacion,
+		Sensor:      sensorName(notification),
+		Valor:       alertValue(notification),
+		Fecha:       notification.Fecha.Format("2006-01-02 15:04:05 MST"),
+		Severidad:   notification.Alerta.Severidad,
+		Mensaje:     notification.Alerta.Mensaje,
+	}
+}
+
+// render builds the MIME message of an email from the named templates. With attachments
+// the alternative text and HTML parts are wrapped in a multipart/mixed message.
+func (n *SMTPNotifier) render(name string, data interface{}, subject string, to *mail.Address, attachments ...attachment) ([]byte, error) {
+	var text, html bytes.Buffer
+	if err := n.text[name].Execute(&text, data); err != nil {
+		return nil, fmt.Errorf("failed to render email: %w", err)
+	}
+	if err := n.html[name].Execute(&html, data); err != nil {
+		return nil, fmt.Errorf("failed to render email: %w", err)
+	}
+
+	var body bytes.Buffer
+	parts := multipart.NewWriter(&body)
+	for _, part := range []struct {
+		contentType string
+		content     []byte
+	}{
+		{"text/plain; charset=utf-8", text.Bytes()},
+		{"text/html; charset=utf-8", html.Bytes()},
+	} {
+		w, err := parts.CreatePart(textproto.MIMEHeader{
+			"Content-Type":              {part.contentType},
+			"Content-Transfer-Encoding": {"quoted-printable"},
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to build email: %w", err)
+		}
+		qp := quotedprintable.NewWriter(w)
+		if _, err := qp.Write(part.content); err != nil {
+			return nil, fmt.Errorf("failed to build email: %w", err)
+		}
+		if err := qp.Close(); err != nil {
+			return nil, fmt.Errorf("failed to build email: %w", err)
+		}
+	}
+	if err := parts.Close(); err != nil {
+		return nil, fmt.Errorf("failed to build email: %w", err)
+	}
+	contentType := "multipart/alternative; boundary=" + parts.Boundary()
+	if len(attachments) > 0 {
+		mixed, err := withAttachments(contentType, body.Bytes(), attachments)
+		if err != nil {
+			return nil, fmt.Errorf("failed to build email: %w", err)
+		}
+		body.Reset()
+		body.Write(mixed.body)
+		contentType = mixed.contentType
+	}
+
+	var msg bytes.Buffer
+	headers := [][2]string{
+		{"From", n.from.String()},
+		{"To", to.String()},
+		{"Subject", mime.QEncoding.Encode("utf-8", subject)},
+		{"Date", time.Now().Format(time.RFC1123Z)},
+		{"Message-ID", n.messageID()},
+		{"MIME-Version", "1.0"},
+		{"Content-Type", contentType},
+	}
+	for _, h := range headers {
+		fmt.Fprintf(&msg, "%s: %s\r\n", h[0], h[1])
+	}
+	msg.WriteString("\r\n")
+	msg.Write(body.Bytes())
+	return msg.Bytes(), nil
+}
+
+// mixedBody is a multipart/mixed body and its content type
+type mixedBody struct {
+	contentType string
+	body        []byte
+}
+
+// withAttachments wraps a body in a multipart/mixed one followed by the attachments,
+// encoded in base64 with lines of 76 characters
+func withAttachments(contentType string, content []byte, attachments []attachment) (*mixedBody, error) {
+	var body bytes.Buffer
+	parts := multipart.NewWriter(&body)
+	w, err := parts.CreatePart(textproto.MIMEHeader{"Content-Type": {contentType}})
+	if err != nil {
+		return nil, err
+	}
+	if _, err := w.Write(content); err != nil {
+		return nil, err
+	}
+
+	for _, a := range attachments {
+		w, err := parts.CreatePart(textproto.MIMEHeader{
+			"Content-Type":              {mime.FormatMediaType(a.contentType, map[string]string{"name": a.name})},
+			"Content-Disposition":       {mime.FormatMediaType("attachment", map[string]string{"filename": a.name})},
+			"Content-Transfer-Encoding": {"base64"},
+		})
+		if err != nil {
+			return nil, err
+		}
+		encoded := base64.StdEncoding.EncodeToString(a.content)
+		for len(encoded) > 76 {
+			if _, err := fmt.Fprintf(w, "%s\r\n", encoded[:76]); err != nil {
+				return nil, err
+			}
+			encoded = encoded[76:]
+		}
+		if _, err := fmt.Fprintf(w, "%s\r\n", encoded); err != nil {
+			return nil, err
+		}
+	}
+	if err := parts.Close(); err != nil {
+		return nil, err
+	}
+	return &mixedBody{contentType: "multipart/mixed; boundary=" + parts.Boundary(), body: body.Bytes()}, nil
+}
+
+// messageID returns a unique Message-ID in the domain of the sender
+func (n *SMTPNotifier) messageID() string {
+	b := make([]byte, 12)
+	rand.Read(b)
+	domain := n.cfg.Host
+	if at := strings.LastIndex(n.from.Address, "@"); at >= 0 {
+		domain = n.from.Address[at+1:]
+	}
+	return fmt.Sprintf("<%s@%s>", hex.EncodeToString(b), domain)
+}
+
+// send delivers msg to one recipient, upgrading to TLS when the server offers it
+func (n *SMTPNotifier) send(ctx context.Context, to string, msg []byte) error {
+	addr := net.JoinHostPort(n.cfg.Host, n.cfg.Port)
+	dialer := net.Dialer{Timeout: n.cfg.Timeout}
+	conn, err := dialer.DialContext(ctx, "tcp", addr)
+	if err != nil {
+		return fmt.Errorf("failed to connect to %s: %w", addr, err)
+	}
+	deadline := time.Now().Add(n.cfg.Timeout)
+	if d, ok := ctx.Deadline(); ok && d.Before(deadline) {
+		deadline = d
+	}
+	conn.SetDeadline(deadline)
+
+	client, err := smtp.NewClient(conn, n.cfg.Host)
+	if err != nil {
+		conn.Close()
+		return fmt.Errorf("failed to start SMTP session: %w", err)
+	}
+	defer client.Close()
+
+	if ok, _ := client.Extension("STARTTLS"); ok {
+		if err := client.StartTLS(&tls.Config{ServerName: n.cfg.Host}); err != nil {
+			return fmt.Errorf("failed to start TLS: %w", err)
+		}
+	}
+	if n.cfg.User != "" {
+		if err := client.Auth(smtp.PlainAuth("", n.cfg.User, n.cfg.Password, n.cfg.Host)); err != nil {
+			return fmt.Errorf("failed to authenticate: %w", err)
+		}
+	}
+	if err := client.Mail(n.from.Address); err != nil {
+		return fmt.Errorf("sender rejected: %w", err)
+	}
+	if err := client.Rcpt(to); err != nil {
+		return fmt.Errorf("recipient rejected: %w", err)
+	}
+	w, err := client.Data()
+	if err != nil {
+		return fmt.Errorf("failed to send message: %w", err)
+	}
+	if _, err := w.Write(msg); err != nil {
+		return fmt.Errorf("failed to send message: %w", err)
+	}
+	if err := w.Close(); err != nil {
+		return fmt.Errorf("failed to send message: %w", err)
+	}
+	return client.Quit()
+}
@@ -0,0 +1,394 @@
+package notifier
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"io"
+	"mime"
+	"mime/multipart"
+	"net"
+	"net/mail"
+	"net/textproto"
+	"strings"
+	"testing"
+	"time"
+	"unicode"
+
+	"hex_go/internal/domain/entities"
+)
+
+// capturedMail is a message received by the capture server
+type capturedMail struct {
+	from string
+	to   []string
+	data []byte
+}
+
+// captureServer is a minimal SMTP server keeping the messages it receives. It offers
+// neither STARTTLS nor AUTH, like a local development relay.
+type captureServer struct {
+	listener net.Listener
+	messages chan capturedMail
+}
+
+func newCaptureServer(t *testing.T) *captureServer {
+	t.Helper()
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	s := &captureServer{listener: listener, messages: make(chan capturedMail, 10)}
+	t.Cleanup(func() { listener.Close() })
+
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			go s.serve(conn)
+		}
+	}()
+	return s
+}
+
+// serve runs one SMTP session
+func (s *captureServer) serve(conn net.Conn) {
+	defer conn.Close()
+	tp := textproto.NewConn(conn)
+	tp.PrintfLine("220 localhost ESMTP capture")
+
+	var current capturedMail
+	for {
+		line, err := tp.ReadLine()
+		if err != nil {
+			return
+		}
+		verb := strings.ToUpper(strings.SplitN(line, " ", 2)[0])
+		switch verb {
+		case "EHLO", "HELO":
+			tp.PrintfLine("250-localhost")
+			tp.PrintfLine("250 8BITMIME")
+		case "MAIL":
+			current = capturedMail{from: addressOf(line)}
+			tp.PrintfLine("250 OK")
+		case "RCPT":
+			current.to = append(current.to, addressOf(line))
+			tp.PrintfLine("250 OK")
+		case "DATA":
+			tp.PrintfLine("354 End data with <CR><LF>.<CR><LF>")
+			data, err := tp.ReadDotBytes()
+			if err != nil {
+				return
+			}
+			current.data = data
+			s.messages <- current
+			tp.PrintfLine("250 OK: queued")
+		case "QUIT":
+			tp.PrintfLine("221 Bye")
+			return
+		default:
+			tp.PrintfLine("250 OK")
+		}
+	}
+}
+
+// addressOf returns the address of a MAIL FROM or RCPT TO command
+func addressOf(line string) string {
+	start, end := strings.Index(line, "<"), strings.Index(line, ">")
+	if start < 0 || end < start {
+		return ""
+	}
+	return line[start+1 : end]
+}
+
+// receive waits for the next captured message
+func (s *captureServer) receive(t *testing.T) capturedMail {
+	t.Helper()
+	select {
+	case msg := <-s.messages:
+		return msg
+	case <-time.After(5 * time.Second):
+		t.Fatal("no message reached the capture server")
+		return capturedMail{}
+	}
+}
+
+func newTestSMTPNotifier(t *testing.T, server *captureServer) *SMTPNotifier {
+	t.Helper()
+	host, port, err := net.SplitHostPort(server.listener.Addr().String())
+	if err != nil {
+		t.Fatal(err)
+	}
+	n, err := NewSMTPNotifier(SMTPConfig{
+		Host:    host,
+		Port:    port,
+		From:    "StopFire <alertas@stopfire.example>",
+		Timeout: 5 * time.Second,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return n
+}
+
+// parsedMail is a received message with its decoded parts, keyed by content type
+type parsedMail struct {
+	header mail.Header
+	parts  map[string]string
+	files  map[string][]byte
+}
+
+// parseMail reads a message, descending into nested multipart bodies. The multipart
+// reader undoes the quoted-printable encoding of the text parts.
+func parseMail(t *testing.T, data []byte) *parsedMail {
+	t.Helper()
+	msg, err := mail.ReadMessage(bytes.NewReader(data))
+	if err != nil {
+		t.Fatal(err)
+	}
+	parsed := &parsedMail{header: msg.Header, parts: map[string]string{}, files: map[string][]byte{}}
+	parsed.readParts(t, msg.Header.Get("Content-Type"), msg.Body)
+	return parsed
+}
+
+func (p *parsedMail) readParts(t *testing.T, contentType string, body io.Reader) {
+	t.Helper()
+	mediaType, params, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		t.Fatalf("invalid Content-Type %q: %v", contentType, err)
+	}
+	if !strings.HasPrefix(mediaType, "multipart/") {
+		t.Fatalf("Content-Type = %s, want a multipart body", mediaType)
+	}
+
+	reader := multipart.NewReader(body, params["boundary"])
+	for {
+		part, err := reader.NextPart()
+		if err == io.EOF {
+			return
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+		partType := part.Header.Get("Content-Type")
+		if strings.HasPrefix(partType, "multipart/") {
+			p.readParts(t, partType, part)
+			continue
+		}
+		content, err := io.ReadAll(part)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if filename := part.FileName(); filename != "" {
+			if part.Header.Get("Content-Transfer-Encoding") == "base64" {
+				if content, err = base64.StdEncoding.DecodeString(strings.ReplaceAll(string(content), "\r\n", "")); err != nil {
+					t.Fatalf("attachment %s is not base64: %v", filename, err)
+				}
+			}
+			p.files[filename] = content
+			continue
+		}
+		p.parts[partType] = string(content)
+	}
+}
+
+// subject decodes the Subject header, which must be plain ASCII or Q-encoded
+func (p *parsedMail) subject(t *testing.T) string {
+	t.Helper()
+	raw := p.header.Get("Subject")
+	for _, r := range raw {
+		if r > unicode.MaxASCII {
+			t.Errorf("Subject %q is not encoded", raw)
+			break
+		}
+	}
+	if strings.Contains(raw, "=?") && !strings.HasPrefix(strings.ToLower(raw), "=?utf-8?q?") {
+		t.Errorf("Subject %q is not Q-encoded", raw)
+	}
+	decoded, err := new(mime.WordDecoder).DecodeHeader(raw)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return decoded
+}
+
+func testNotification(lang string) *entities.Notification {
+	valor := 412.0
+	return &entities.Notification{
+		Destinatario: &entities.NotificationRecipient{Canal: entities.ChannelEmail, Direccion: "Ana <ana@example.com>", Idioma: lang},
+		Alerta: &entities.Alert{
+			NumeroSerie: "ESP32-0001",
+			Tipo:        entities.SensorTypeMQ2,
+			Severidad:   entities.SeverityCritical,
+			Mensaje:     "Smoke or combustible gas detected (412 ppm)",
+			Valor:       &valor,
+		},
+		Dispositivo: "Baño <planta baja>",
+		Ubicacion:   "Casa / Planta baja",
+		Fecha:       time.Date(2026, 10, 19, 14, 3, 0, 0, time.UTC),
+	}
+}
+
+func TestSMTPNotifierSendsAlerts(t *testing.T) {
+	tests := []struct {
+		lang        string
+		subject     string
+		textPhrases []string
+		htmlPhrases []string
+	}{
+		{
+			lang:        entities.LanguageSpanish,
+			subject:     "[StopFire] Humo o gas combustible detectado: Baño <planta baja>",
+			textPhrases: []string{"Humo o gas combustible detectado en Baño <planta baja>", "Ubicación: Casa / Planta baja", "Sensor: Sensor de humo MQ-2", "Valor: 412", "Si hay fuego"},
+			htmlPhrases: []string{`<html lang="es">`, "Baño &lt;planta baja&gt;", "Ubicación", "Si hay fuego"},
+		},
+		{
+			lang:        entities.LanguageEnglish,
+			subject:     "[StopFire] Smoke or combustible gas detected: Baño <planta baja>",
+			textPhrases: []string{"Smoke or combustible gas detected at Baño <planta baja>", "Location: Casa / Planta baja", "Sensor: MQ-2 smoke sensor", "Value: 412", "If there is a fire"},
+			htmlPhrases: []string{`<html lang="en">`, "Baño &lt;planta baja&gt;", "Location", "If there is a fire"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.lang, func(t *testing.T) {
+			server := newCaptureServer(t)
+			n := newTestSMTPNotifier(t, server)
+
+			if err := n.Notify(context.Background(), testNotification(tt.lang)); err != nil {
+				t.Fatal(err)
+			}
+
+			captured := server.receive(t)
+			if captured.from != "alertas@stopfire.example" {
+				t.Errorf("MAIL FROM = %q", captured.from)
+			}
+			if len(captured.to) != 1 || captured.to[0] != "ana@example.com" {
+				t.Errorf("RCPT TO = %v", captured.to)
+			}
+
+			msg := parseMail(t, captured.data)
+			if raw := msg.header.Get("Subject"); !strings.HasPrefix(strings.ToLower(raw), "=?utf-8?q?") {
+				t.Errorf("Subject %q is not Q-encoded", raw)
+			}
+			if subject := msg.subject(t); subject != tt.subject {
+				t.Errorf("subject = %q, want %q", subject, tt.subject)
+			}
+			if to := msg.header.Get("To"); to != `"Ana" <ana@example.com>` {
+				t.Errorf("To = %q", to)
+			}
+			if id := msg.header.Get("Message-ID"); !strings.HasSuffix(id, "@stopfire.example>") {
+				t.Errorf("Message-ID = %q, want one in the domain of the sender", id)
+			}
+
+			text, ok := msg.parts["text/plain; charset=utf-8"]
+			if !ok {
+				t.Fatalf("no text part among %v", keys(msg.parts))
+			}
+			for _, phrase := range tt.textPhrases {
+				if !strings.Contains(text, phrase) {
+					t.Errorf("text part lacks %q:\n%s", phrase, text)
+				}
+			}
+			html, ok := msg.parts["text/html; charset=utf-8"]
+			if !ok {
+				t.Fatalf("no HTML part among %v", keys(msg.parts))
+			}
+			for _, phrase := range tt.htmlPhrases {
+				if !strings.Contains(html, phrase) {
+					t.Errorf("HTML part lacks %q:\n%s", phrase, html)
+				}
+			}
+		})
+	}
+}
+
+func TestSMTPNotifierSendsDigests(t *testing.T) {
+	server := newCaptureServer(t)
+	n := newTestSMTPNotifier(t, server)
+
+	recipient := &entities.NotificationRecipient{Canal: entities.ChannelEmail, Direccion: "ana@example.com", Idioma: entities.LanguageEnglish}
+	first, second := testNotification(entities.LanguageEnglish), testNotification(entities.LanguageEnglish)
+	second.Alerta.Tipo = entities.SensorTypeMQ135
+	digest := &entities.NotificationDigest{Destinatario: recipient, Notificaciones: []*entities.Notification{first, second}, Total: 5}
+
+	if err := n.NotifyDigest(context.Background(), digest); err != nil {
+		t.Fatal(err)
+	}
+
+	msg := parseMail(t, server.receive(t).data)
+	if subject := msg.subject(t); subject != "[StopFire] Digest of 5 alerts" {
+		t.Errorf("subject = %q", subject)
+	}
+	text := msg.parts["text/plain; charset=utf-8"]
+	for _, phrase := range []string{"Smoke or combustible gas detected at Baño", "Poor air quality at Baño", "and 3 more alerts"} {
+		if !strings.Contains(text, phrase) {
+			t.Errorf("text part lacks %q:\n%s", phrase, text)
+		}
+	}
+	if _, ok := msg.parts["text/html; charset=utf-8"]; !ok {
+		t.Errorf("no HTML part among %v", keys(msg.parts))
+	}
+}
+
+func TestSMTPNotifierSendsReports(t *testing.T) {
+	server := newCaptureServer(t)
+	n := newTestSMTPNotifier(t, server)
+
+	recipient := &entities.NotificationRecipient{Canal: entities.ChannelEmail, Direccion: "ana@example.com", Idioma: entities.LanguageSpanish}
+	report := &entities.SiteReport{
+		Sitio: &entities.Location{ID: 3, Nombre: "Casa Ñuñoa"},
+		Mes:   "2026-09",
+		Alarmas: []*entities.ReportAlarmCount{
+			{Tipo: entities.SensorTypeKY026, Activaciones: 2, Criticas: 2},
+			{Tipo: entities.SensorTypeMQ2, Activaciones: 5, Criticas: 1},
+		},
+		Respuesta: entities.ReportResponseTimes{SinReconocer: 1},
+	}
+	pdf := bytes.Repeat([]byte("%PDF-1.4 report "), 20)
+
+	if err := n.SendReport(context.Background(), recipient, report, pdf); err != nil {
+		t.Fatal(err)
+	}
+
+	msg := parseMail(t, server.receive(t).data)
+	if subject := msg.subject(t); subject != "[StopFire] Informe mensual de seguridad: Casa Ñuñoa 2026-09" {
+		t.Errorf("subject = %q", subject)
+	}
+	if mediaType, _, _ := mime.ParseMediaType(msg.header.Get("Content-Type")); mediaType != "multipart/mixed" {
+		t.Errorf("Content-Type = %s, want multipart/mixed", mediaType)
+	}
+	text := msg.parts["text/plain; charset=utf-8"]
+	for _, phrase := range []string{"informe de seguridad de Casa Ñuñoa del mes 2026-09", "Activaciones: 7", "Críticas: 3", "Sin reconocer: 1"} {
+		if !strings.Contains(text, phrase) {
+			t.Errorf("text part lacks %q:\n%s", phrase, text)
+		}
+	}
+	if _, ok := msg.parts["text/html; charset=utf-8"]; !ok {
+		t.Errorf("no HTML part among %v", keys(msg.parts))
+	}
+	if got, ok := msg.files["informe-3-2026-09.pdf"]; !ok || !bytes.Equal(got, pdf) {
+		t.Errorf("attachment informe-3-2026-09.pdf missing or altered, files %v", len(msg.files))
+	}
+}
+
+func TestSMTPNotifierRejectsInvalidRecipients(t *testing.T) {
+	server := newCaptureServer(t)
+	n := newTestSMTPNotifier(t, server)
+
+	notification := testNotification(entities.LanguageSpanish)
+	notification.Destinatario.Direccion = "not an address"
+	if err := n.Notify(context.Background(), notification); err == nil {
+		t.Error("Notify() accepted an invalid address")
+	}
+}
+
+func keys(m map[string]string) []string {
+	var out []string
+	for k := range m {
+		out = append(out, k)
+	}
+	return out
+}
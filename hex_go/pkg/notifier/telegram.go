@@ -0,0 +1,89 @@
+package notifier
+
+import (
+	"context"
+	"fmt"
+	"html"
+	"net/http"
+	"strings"
+	"time"
+
+	"hex_go/internal/domain/entities"
+	"hex_go/internal/domain/ports"
+)
+
+// TelegramConfig holds the settings of the Telegram bot
+type TelegramConfig struct {
+	// APIURL is the Bot API server, https://api.telegram.org unless a local server is used
+	APIURL  string
+	Token   string
+	Timeout time.Duration
+}
+
+// TelegramNotifier sends notifications as messages of a Telegram bot
+type TelegramNotifier struct {
+	url    string
+	client *http.Client
+}
+
+var _ ports.NotifierPort = (*TelegramNotifier)(nil)
+
+// telegramMessage is the request body of the sendMessage method
+type telegramMessage struct {
+	ChatID                string `json:"chat_id"`
+	Text                  string `json:"text"`
+	ParseMode             string `json:"parse_mode"`
+	DisableWebPagePreview bool   `json:"disable_web_page_preview"`
+}
+
+// NewTelegramNotifier creates a Telegram notifier sending through the given bot
+func NewTelegramNotifier(cfg TelegramConfig) *TelegramNotifier {
+	return &TelegramNotifier{
+		url:    strings.TrimRight(cfg.APIURL, "/") + "/bot" + cfg.Token + "/sendMessage",
+		client: newHTTPClient(cfg.Timeout),
+	}
+}
+
+// Channel returns the Telegram channel
+func (n *TelegramNotifier) Channel() string {
+	return entities.ChannelTelegram
+}
+
+// Notify sends the alert to the recipient's chat. The chat must have started a
+// conversation with the bot, or added it to the group or channel.
+func (n *TelegramNotifier) Notify(ctx context.Context, notification *entities.Notification) error {
+	text := fmt.Sprintf("<b>%s</b>\n%s\n%s\n%s",
+		html.EscapeString(alertTitle(notification)),
+		html.EscapeString(shortBody(notification)),
+		html.EscapeString(sensorName(notification)),
+		html.EscapeString(notification.Alerta.Mensaje))
+
+	return n.send(ctx, notification.Destinatario.Direccion, text)
+}
+
+// NotifyDigest sends the held back alerts to the recipient's chat, one line each
+func (n *TelegramNotifier) NotifyDigest(ctx context.Context, digest *entities.NotificationDigest) error {
+	lines := []string{"<b>" + html.EscapeString(digestTitle(digest)) + "</b>"}
+	for _, notification := range digest.Notificaciones {
+		lines = append(lines, html.EscapeString(alertTitle(notification)+" · "+shortBody(notification)))
+	}
+	if more := digestRemaining(digest); more > 0 {
+		lines = append(lines, html.EscapeString(fmt.Sprintf(moreLabels[recipientLanguage(digest.Destinatario)], more)))
+	}
+	return n.send(ctx, digest.Destinatario.Direccion, strings.Join(lines, "\n"))
+}
+
+// send posts one HTML formatted message to a chat
+func (n *TelegramNotifier) send(ctx context.Context, chatID, text string) error {
+	err := postJSON(ctx, n.client, n.url, nil, telegramMessage{
+		ChatID:                chatID,
+		Text:                  text,
+		ParseMode:             "HTML",
+		DisableWebPagePreview: true,
+	}, nil)
+	if err != nil {
+		// The bot token is part of the URL; keep it out of the logs
+		return fmt.Errorf("telegram: %s", strings.ReplaceAll(err.Error(), n.url, "sendMessage"))
+	}
+	return nil
+}
@@ -0,0 +1,97 @@
+package notifier
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"hex_go/internal/domain/entities"
+)
+
+func telegramNotification() *entities.Notification {
+	notification := testNotification(entities.LanguageEnglish)
+	notification.Destinatario = &entities.NotificationRecipient{Canal: entities.ChannelTelegram, Direccion: "-1001234", Idioma: entities.LanguageEnglish}
+	return notification
+}
+
+func TestTelegramNotifierSendsAlerts(t *testing.T) {
+	gw := newGateway(t, http.StatusOK, `{"ok":true}`)
+	n := NewTelegramNotifier(TelegramConfig{APIURL: gw.URL + "/", Token: "123:bot-secret", Timeout: time.Second})
+
+	if err := n.Notify(context.Background(), telegramNotification()); err != nil {
+		t.Fatal(err)
+	}
+
+	req := gw.only(t)
+	req.checkJSONPost(t)
+	if req.path != "/bot123:bot-secret/sendMessage" {
+		t.Errorf("path = %s, want the bot token and sendMessage", req.path)
+	}
+
+	var msg telegramMessage
+	req.decode(t, &msg)
+	if msg.ChatID != "-1001234" || msg.ParseMode != "HTML" || !msg.DisableWebPagePreview {
+		t.Errorf("message = %+v", msg)
+	}
+	if !strings.HasPrefix(msg.Text, "<b>Smoke or combustible gas detected</b>\n") {
+		t.Errorf("text does not start with the bold title: %q", msg.Text)
+	}
+	// The device name is user input and must not break the HTML
+	if !strings.Contains(msg.Text, "Baño &lt;planta baja&gt;") {
+		t.Errorf("device name is not escaped: %q", msg.Text)
+	}
+}
+
+func TestTelegramNotifierSendsDigests(t *testing.T) {
+	gw := newGateway(t, http.StatusOK, `{"ok":true}`)
+	n := NewTelegramNotifier(TelegramConfig{APIURL: gw.URL, Token: "123:bot-secret", Timeout: time.Second})
+
+	notification := telegramNotification()
+	digest := &entities.NotificationDigest{
+		Destinatario:   notification.Destinatario,
+		Notificaciones: []*entities.Notification{notification},
+		Total:          3,
+	}
+	if err := n.NotifyDigest(context.Background(), digest); err != nil {
+		t.Fatal(err)
+	}
+
+	var msg telegramMessage
+	gw.only(t).decode(t, &msg)
+	lines := strings.Split(msg.Text, "\n")
+	if len(lines) != 3 || lines[0] != "<b>Digest of 3 alerts</b>" || lines[2] != "and 2 more" {
+		t.Errorf("text = %q", msg.Text)
+	}
+}
+
+func TestTelegramNotifierFailsOnErrorStatus(t *testing.T) {
+	gw := newGateway(t, http.StatusForbidden, `{"ok":false,"description":"Forbidden: bot was blocked by the user"}`)
+	n := NewTelegramNotifier(TelegramConfig{APIURL: gw.URL, Token: "123:bot-secret", Timeout: time.Second})
+
+	err := n.Notify(context.Background(), telegramNotification())
+	if err == nil {
+		t.Fatal("Notify() succeeded on a 403 response")
+	}
+	if !strings.Contains(err.Error(), "403") || !strings.Contains(err.Error(), "bot was blocked") {
+		t.Errorf("error = %v, want the status and the Bot API description", err)
+	}
+	if strings.Contains(err.Error(), "bot-secret") {
+		t.Errorf("error leaks the bot token: %v", err)
+	}
+}
+
+func TestTelegramNotifierHidesTokenWhenUnreachable(t *testing.T) {
+	gw := newGateway(t, http.StatusOK, `{"ok":true}`)
+	gw.Close()
+	n := NewTelegramNotifier(TelegramConfig{APIURL: gw.URL, Token: "123:bot-secret", Timeout: time.Second})
+
+	err := n.Notify(context.Background(), telegramNotification())
+	if err == nil {
+		t.Fatal("Notify() succeeded with the Bot API down")
+	}
+	if strings.Contains(err.Error(), "bot-secret") {
+		t.Errorf("error leaks the bot token: %v", err)
+	}
+}
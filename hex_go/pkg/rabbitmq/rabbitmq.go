@@ -20,8 +20,12 @@ type RabbitMQClient struct {
 	queueMQ2     string
 	queueMQ135   string
 	queueDHT22   string
+	queueAlerts  string
 }
 
+// AlertRoutingKey is the routing key used for every alert published to the exchange
+const AlertRoutingKey = "ALERT"
+
 // NewRabbitMQClient creates a new RabbitMQ client
 func NewRabbitMQClient(cfg *config.Config) (*RabbitMQClient, error) {
 	// Create connection string
@@ -67,6 +71,7 @@ func NewRabbitMQClient(cfg *config.Config) (*RabbitMQClient, error) {
 		"MQ_2":   cfg.RabbitMQQueueMQ2,
 		"MQ_135": cfg.RabbitMQQueueMQ135,
 		"DHT_22": cfg.RabbitMQQueueDHT22,
+		AlertRoutingKey: cfg.RabbitMQQueueAlerts,
 	}
 
 	log.Printf("Creating and binding queues to exchange: %s", cfg.RabbitMQExchange)
@@ -118,6 +123,7 @@ func NewRabbitMQClient(cfg *config.Config) (*RabbitMQClient, error) {
 		queueMQ2:     cfg.RabbitMQQueueMQ2,
 		queueMQ135:   cfg.RabbitMQQueueMQ135,
 		queueDHT22:   cfg.RabbitMQQueueDHT22,
+		queueAlerts:  cfg.RabbitMQQueueAlerts,
 	}, nil
 }
 
@@ -151,6 +157,30 @@ func (c *RabbitMQClient) PublishSensorData(data *entities.SensorDataRequest) err
 	return nil
 }
 
+// PublishAlert publishes an alert to the alerts queue
+func (c *RabbitMQClient) PublishAlert(alert *entities.Alert) error {
+	body, err := json.Marshal(alert)
+	if err != nil {
+		return fmt.Errorf("failed to marshal alert: %w", err)
+	}
+
+	err = c.channel.Publish(
+		c.exchangeName,  // exchange
+		AlertRoutingKey, // routing key
+		false,           // mandatory
+		false,           // immediate
+		amqp.Publishing{
+			ContentType: "application/json",
+			Type:        alert.Tipo,
+			Body:        body,
+		})
+	if err != nil {
+		return fmt.Errorf("failed to publish alert: %w", err)
+	}
+
+	log.Printf("Published %s alert to %s queue: %s", alert.Tipo, c.queueAlerts, string(body))
+	return nil
+}
 
 func getQueueNameForSensor(sensorType string, c *RabbitMQClient) string {
 	switch sensorType {
@@ -0,0 +1,262 @@
+// Package report renders the monthly site reports as PDF documents
+package report
+
+import (
+	"bytes"
+	"fmt"
+	"time"
+
+	"github.com/go-pdf/fpdf"
+
+	"hex_go/internal/domain/entities"
+	"hex_go/internal/domain/ports"
+)
+
+// Page layout in millimetres
+const (
+	pageMargin = 15.0
+	lineHeight = 6.0
+	barHeight  = 4.0
+)
+
+// PDFRenderer renders reports on A4 pages with the core fonts, so no font files are needed
+type PDFRenderer struct{}
+
+var _ ports.ReportRendererPort = (*PDFRenderer)(nil)
+
+// NewPDFRenderer creates a PDF report renderer
+func NewPDFRenderer() *PDFRenderer {
+	return &PDFRenderer{}
+}
+
+// RenderReport returns the report as a PDF with its text in lang
+func (p *PDFRenderer) RenderReport(report *entities.SiteReport, lang string) ([]byte, error) {
+	t, ok := texts[lang]
+	if !ok {
+		t = texts[entities.LanguageSpanish]
+	}
+
+	pdf := fpdf.New("P", "mm", "A4", "")
+	pdf.SetMargins(pageMargin, pageMargin, pageMargin)
+	pdf.SetAutoPageBreak(true, pageMargin)
+	pdf.SetCreationDate(report.GeneradoEn)
+	pdf.SetModificationDate(report.GeneradoEn)
+	pdf.SetTitle(t.title+" - "+report.Sitio.Nombre, true)
+	pdf.SetCreator("StopFire", true)
+	pdf.AliasNbPages("")
+
+	// The core fonts are in Windows-1252, which covers Spanish
+	r := &renderer{pdf: pdf, t: t, tr: pdf.UnicodeTranslatorFromDescriptor("")}
+	pdf.SetFooterFunc(r.footer)
+	pdf.AddPage()
+
+	r.header(report)
+	r.alarms(report.Alarmas)
+	r.responseTimes(report.Respuesta)
+	r.uptime(report.Dispositivos)
+	r.noisyDevices(report.Ruidosos)
+
+	var buf bytes.Buffer
+	if err := pdf.Output(&buf); err != nil {
+		return nil, fmt.Errorf("failed to render report: %w", err)
+	}
+	return buf.Bytes(), nil
+}
+
+// renderer draws one report
+type renderer struct {
+	pdf *fpdf.Fpdf
+	t   reportTexts
+	tr  func(string) string
+}
+
+func (r *renderer) header(report *entities.SiteReport) {
+	r.pdf.SetFont("Helvetica", "B", 18)
+	r.pdf.CellFormat(0, 10, r.tr(r.t.title), "", 1, "L", false, 0, "")
+	r.pdf.SetFont("Helvetica", "B", 13)
+	r.pdf.CellFormat(0, 8, r.tr(report.Sitio.Nombre), "", 1, "L", false, 0, "")
+
+	r.pdf.SetFont("Helvetica", "", 10)
+	if report.Sitio.Direccion != "" {
+		r.pdf.CellFormat(0, lineHeight, r.tr(report.Sitio.Direccion), "", 1, "L", false, 0, "")
+	}
+	period := r.t.monthName(report.Desde)
+	if report.Hasta.Before(report.Desde.AddDate(0, 1, 0)) {
+		period += " " + fmt.Sprintf(r.t.untilFormat, report.Hasta.Format("2006-01-02 15:04"))
+	}
+	r.pdf.CellFormat(0, lineHeight, r.tr(r.t.period+": "+period), "", 1, "L", false, 0, "")
+	r.pdf.CellFormat(0, lineHeight, r.tr(r.t.generated+": "+report.GeneradoEn.Format("2006-01-02 15:04 UTC")), "", 1, "L", false, 0, "")
+	r.pdf.Ln(4)
+}
+
+func (r *renderer) alarms(counts []*entities.ReportAlarmCount) {
+	r.section(r.t.alarmsTitle)
+	if len(counts) == 0 {
+		r.note(r.t.noAlarms)
+		return
+	}
+
+	widths := []float64{70, 30, 35, 30}
+	r.tableHeader(widths, r.t.alarmsColumns)
+	most := 0
+	for _, c := range counts {
+		if c.Activaciones > most {
+			most = c.Activaciones
+		}
+	}
+	for _, c := range counts {
+		r.row(widths, []string{r.t.alertType(c.Tipo), itoa(c.Alertas), itoa(c.Activaciones), itoa(c.Criticas)})
+	}
+	r.pdf.Ln(3)
+
+	// Bars of the activations per type
+	for _, c := range counts {
+		r.bar(r.t.alertType(c.Tipo), float64(c.Activaciones)/float64(max(most, 1)), itoa(c.Activaciones))
+	}
+	r.pdf.Ln(4)
+}
+
+func (r *renderer) responseTimes(times entities.ReportResponseTimes) {
+	r.section(r.t.responseTitle)
+	widths := []float64{70, 40}
+	rows := [][]string{
+		{r.t.acknowledged, itoa(times.Reconocidas)},
+		{r.t.pending, itoa(times.SinReconocer)},
+	}
+	if times.Reconocidas > 0 {
+		rows = append(rows,
+			[]string{r.t.mean, r.t.duration(times.Media)},
+			[]string{r.t.median, r.t.duration(times.Mediana)},
+			[]string{r.t.p90, r.t.duration(times.P90)},
+		)
+	}
+	for _, row := range rows {
+		r.row(widths, row)
+	}
+	r.pdf.Ln(4)
+}
+
+func (r *renderer) uptime(devices []*entities.ReportDeviceUptime) {
+	r.section(r.t.uptimeTitle)
+	if len(devices) == 0 {
+		r.note(r.t.noDevices)
+		return
+	}
+
+	widths := []float64{55, 50, 35, 40}
+	r.tableHeader(widths, r.t.uptimeColumns)
+	for _, d := range devices {
+		r.row(widths, []string{deviceName(d.Nombre, d.NumeroSerie), d.Ubicacion,
+			fmt.Sprintf("%.2f %%", d.Disponibilidad*100), r.t.duration(d.SinConexion)})
+	}
+	r.pdf.Ln(4)
+}
+
+func (r *renderer) noisyDevices(devices []*entities.ReportNoisyDevice) {
+	r.section(fmt.Sprintf(r.t.noisyTitle, entities.ReportNoisyDevices))
+	if len(devices) == 0 {
+		r.note(r.t.noAlarms)
+		return
+	}
+
+	widths := []float64{70, 30, 35, 30}
+	r.tableHeader(widths, r.t.noisyColumns)
+	for _, d := range devices {
+		r.row(widths, []string{deviceName(d.Nombre, d.NumeroSerie), itoa(d.Alertas), itoa(d.Activaciones), itoa(d.Oscilantes)})
+	}
+}
+
+func (r *renderer) footer() {
+	r.pdf.SetY(-pageMargin)
+	r.pdf.SetFont("Helvetica", "I", 8)
+	r.pdf.SetTextColor(120, 120, 120)
+	r.pdf.CellFormat(0, 8, r.tr(fmt.Sprintf(r.t.pageFormat, r.pdf.PageNo(), "{nb}")), "", 0, "C", false, 0, "")
+	r.pdf.SetTextColor(0, 0, 0)
+}
+
+func (r *renderer) section(title string) {
+	r.pdf.SetFont("Helvetica", "B", 12)
+	r.pdf.SetTextColor(180, 30, 30)
+	r.pdf.CellFormat(0, 8, r.tr(title), "B", 1, "L", false, 0, "")
+	r.pdf.SetTextColor(0, 0, 0)
+	r.pdf.Ln(2)
+}
+
+func (r *renderer) note(text string) {
+	r.pdf.SetFont("Helvetica", "I", 10)
+	r.pdf.CellFormat(0, lineHeight, r.tr(text), "", 1, "L", false, 0, "")
+	r.pdf.Ln(4)
+}
+
+func (r *renderer) tableHeader(widths []float64, columns []string) {
+	r.pdf.SetFont("Helvetica", "B", 10)
+	r.pdf.SetFillColor(235, 235, 235)
+	for i, column := range columns {
+		r.pdf.CellFormat(widths[i], lineHeight+1, r.tr(column), "1", 0, align(i), true, 0, "")
+	}
+	r.pdf.Ln(-1)
+}
+
+func (r *renderer) row(widths []float64, cells []string) {
+	r.pdf.SetFont("Helvetica", "", 10)
+	for i, cell := range cells {
+		r.pdf.CellFormat(widths[i], lineHeight, r.tr(cell), "1", 0, align(i), false, 0, "")
+	}
+	r.pdf.Ln(-1)
+}
+
+// bar draws a labelled horizontal bar filled to share, between 0 and 1
+func (r *renderer) bar(label string, share float64, value string) {
+	const labelWidth, barWidth = 70.0, 90.0
+
+	r.pdf.SetFont("Helvetica", "", 9)
+	r.pdf.CellFormat(labelWidth, lineHeight, r.tr(label), "", 0, "L", false, 0, "")
+	x, y := r.pdf.GetXY()
+	r.pdf.SetFillColor(220, 80, 60)
+	if share > 0 {
+		r.pdf.Rect(x, y+(lineHeight-barHeight)/2, barWidth*share, barHeight, "F")
+	}
+	r.pdf.SetX(x + barWidth + 2)
+	r.pdf.CellFormat(0, lineHeight, value, "", 1, "L", false, 0, "")
+}
+
+// align puts the first column to the left and the figures to the right
+func align(column int) string {
+	if column == 0 {
+		return "L"
+	}
+	return "R"
+}
+
+func deviceName(nombre, numeroSerie string) string {
+	if nombre == "" {
+		return numeroSerie
+	}
+	return nombre
+}
+
+func itoa(n int) string {
+	return fmt.Sprintf("%d", n)
+}
+
+func max(a, b int) int {
+	if a > b {
+		return a
+	}
+	return b
+}
+
+// formatDuration writes a duration with the largest two units, such as "2 h 05 min"
+func formatDuration(d time.Duration, day, hour, minute, second string) string {
+	d = d.Round(time.Second)
+	switch {
+	case d >= 24*time.Hour:
+		return fmt.Sprintf("%d %s %02d %s", d/(24*time.Hour), day, (d%(24*time.Hour))/time.Hour, hour)
+	case d >= time.Hour:
+		return fmt.Sprintf("%d %s %02d %s", d/time.Hour, hour, (d%time.Hour)/time.Minute, minute)
+	case d >= time.Minute:
+		return fmt.Sprintf("%d %s %02d %s", d/time.Minute, minute, (d%time.Minute)/time.Second, second)
+	default:
+		return fmt.Sprintf("%d %s", d/time.Second, second)
+	}
+}
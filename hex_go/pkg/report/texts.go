@@ -0,0 +1,111 @@
+package report
+
+import (
+	"fmt"
+	"time"
+
+	"hex_go/internal/domain/entities"
+)
+
+// reportTexts holds the wording of a report in one language
+type reportTexts struct {
+	title, period, untilFormat, generated, pageFormat       string
+	alarmsTitle, noAlarms                                   string
+	alarmsColumns                                           []string
+	responseTitle, acknowledged, pending, mean, median, p90 string
+	uptimeTitle, noDevices                                  string
+	uptimeColumns                                           []string
+	noisyTitle                                              string
+	noisyColumns                                            []string
+	months                                                  [12]string
+	monthFormat                                             string
+	alertTypes                                              map[string]string
+	day, hour, minute, second                               string
+}
+
+var texts = map[string]reportTexts{
+	entities.LanguageSpanish: {
+		title:         "Informe mensual de seguridad",
+		period:        "Periodo",
+		untilFormat:   "(hasta el %s UTC)",
+		generated:     "Generado",
+		pageFormat:    "Página %d de %s",
+		alarmsTitle:   "Alarmas por tipo de sensor",
+		noAlarms:      "No hubo alarmas en el periodo.",
+		alarmsColumns: []string{"Tipo", "Alertas", "Activaciones", "Críticas"},
+		responseTitle: "Tiempos de respuesta",
+		acknowledged:  "Alertas reconocidas",
+		pending:       "Alertas sin reconocer",
+		mean:          "Tiempo medio hasta el reconocimiento",
+		median:        "Mediana",
+		p90:           "Percentil 90",
+		uptimeTitle:   "Disponibilidad de los dispositivos",
+		noDevices:     "El sitio no tiene dispositivos.",
+		uptimeColumns: []string{"Dispositivo", "Ubicación", "Disponibilidad", "Sin conexión"},
+		noisyTitle:    "Los %d dispositivos con más activaciones",
+		noisyColumns:  []string{"Dispositivo", "Alertas", "Activaciones", "Oscilantes"},
+		months: [12]string{"enero", "febrero", "marzo", "abril", "mayo", "junio", "julio", "agosto",
+			"septiembre", "octubre", "noviembre", "diciembre"},
+		monthFormat: "%s de %d",
+		alertTypes: map[string]string{
+			entities.SensorTypeKY026:        "Llama (KY-026)",
+			entities.SensorTypeMQ2:          "Humo o gas (MQ-2)",
+			entities.SensorTypeMQ135:        "Calidad del aire (MQ-135)",
+			entities.SensorTypeDHT22:        "Temperatura y humedad (DHT22)",
+			entities.AlertTypeDeviceOffline: "Dispositivo sin conexión",
+			entities.AlertTypeAnomaly:       "Anomalía",
+		},
+		day: "d", hour: "h", minute: "min", second: "s",
+	},
+	entities.LanguageEnglish: {
+		title:         "Monthly safety report",
+		period:        "Period",
+		untilFormat:   "(until %s UTC)",
+		generated:     "Generated",
+		pageFormat:    "Page %d of %s",
+		alarmsTitle:   "Alarms per sensor type",
+		noAlarms:      "There were no alarms in the period.",
+		alarmsColumns: []string{"Type", "Alerts", "Activations", "Critical"},
+		responseTitle: "Response times",
+		acknowledged:  "Acknowledged alerts",
+		pending:       "Unacknowledged alerts",
+		mean:          "Mean time to acknowledge",
+		median:        "Median",
+		p90:           "90th percentile",
+		uptimeTitle:   "Device uptime",
+		noDevices:     "The site has no devices.",
+		uptimeColumns: []string{"Device", "Location", "Uptime", "Offline"},
+		noisyTitle:    "Top %d devices by activations",
+		noisyColumns:  []string{"Device", "Alerts", "Activations", "Flapping"},
+		months: [12]string{"January", "February", "March", "April", "May", "June", "July", "August",
+			"September", "October", "November", "December"},
+		monthFormat: "%s %d",
+		alertTypes: map[string]string{
+			entities.SensorTypeKY026:        "Flame (KY-026)",
+			entities.SensorTypeMQ2:          "Smoke or gas (MQ-2)",
+			entities.SensorTypeMQ135:        "Air quality (MQ-135)",
+			entities.SensorTypeDHT22:        "Temperature and humidity (DHT22)",
+			entities.AlertTypeDeviceOffline: "Device offline",
+			entities.AlertTypeAnomaly:       "Anomaly",
+		},
+		day: "d", hour: "h", minute: "min", second: "s",
+	},
+}
+
+// monthName names the month of t, such as "septiembre de 2026"
+func (t reportTexts) monthName(at time.Time) string {
+	return fmt.Sprintf(t.monthFormat, t.months[at.Month()-1], at.Year())
+}
+
+// alertType names an alert type, or returns it as is when unknown
+func (t reportTexts) alertType(tipo string) string {
+	if name, ok := t.alertTypes[tipo]; ok {
+		return name
+	}
+	return tipo
+}
+
+// duration formats a duration with the units of the language
+func (t reportTexts) duration(d time.Duration) string {
+	return formatDuration(d, t.day, t.hour, t.minute, t.second)
+}
@@ -0,0 +1,100 @@
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"syscall"
+	"time"
+
+	"hex_go/internal/domain/ports"
+)
+
+// userAgent identifies the deliveries in the logs of the receiving systems
+const userAgent = "StopFire-Webhooks/1.0"
+
+// maxResponseBody bounds how much of a response is read before the connection is reused
+const maxResponseBody = 64 << 10
+
+// Client posts webhook deliveries over HTTP
+type Client struct {
+	httpClient *http.Client
+}
+
+// ErrPrivateAddress is returned for deliveries to loopback, private or link-local
+// addresses, such as the metadata service of the cloud provider
+var ErrPrivateAddress = errors.New("webhook address is not public")
+
+// NewClient creates a webhook client whose requests give up after timeout. Redirects
+// are not followed so a delivery only ever reaches the registered URL. Unless
+// allowPrivate is set, as for local testing, connections to addresses that are not
+// public are refused once the host name is resolved.
+func NewClient(timeout time.Duration, allowPrivate bool) *Client {
+	dialer := &net.Dialer{Timeout: timeout}
+	if !allowPrivate {
+		dialer.Control = refusePrivate
+	}
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.Proxy = nil
+	transport.DialContext = dialer.DialContext
+
+	return &Client{
+		httpClient: &http.Client{
+			Timeout:   timeout,
+			Transport: transport,
+			CheckRedirect: func(req *http.Request, via []*http.Request) error {
+				return http.ErrUseLastResponse
+			},
+		},
+	}
+}
+
+// refusePrivate is a dialer Control hook failing connections to addresses that are not
+// public. It sees the resolved address, so host names pointing inside are refused too.
+func refusePrivate(network, address string, c syscall.RawConn) error {
+	host, _, err := net.SplitHostPort(address)
+	if err != nil {
+		return err
+	}
+	ip := net.ParseIP(host)
+	if ip == nil || !isPublic(ip) {
+		return fmt.Errorf("%w: %s", ErrPrivateAddress, host)
+	}
+	return nil
+}
+
+// isPublic reports whether an address is reachable on the internet
+func isPublic(ip net.IP) bool {
+	return !ip.IsLoopback() && !ip.IsPrivate() && !ip.IsLinkLocalUnicast() &&
+		!ip.IsLinkLocalMulticast() && !ip.IsInterfaceLocalMulticast() && !ip.IsMulticast() &&
+		!ip.IsUnspecified()
+}
+
+// Send posts body as JSON with the given headers and returns the response status
+func (c *Client) Send(ctx context.Context, url string, headers map[string]string, body []byte) (int, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return 0, fmt.Errorf("error building webhook request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", userAgent)
+	for name, value := range headers {
+		req.Header.Set(name, value)
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return 0, fmt.Errorf("error sending webhook: %w", err)
+	}
+	defer resp.Body.Close()
+	io.Copy(io.Discard, io.LimitReader(resp.Body, maxResponseBody))
+
+	return resp.StatusCode, nil
+}
+
+// Verify interface implementation
+var _ ports.WebhookSenderPort = (*Client)(nil)
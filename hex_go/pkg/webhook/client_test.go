@@ -0,0 +1,116 @@
+package webhook
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestSend(t *testing.T) {
+	var got *http.Request
+	var body []byte
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got = r
+		body, _ = io.ReadAll(r.Body)
+		w.WriteHeader(http.StatusAccepted)
+	}))
+	defer server.Close()
+
+	client := NewClient(time.Second, true)
+	status, err := client.Send(context.Background(), server.URL, map[string]string{"X-StopFire-Event": "alert.created"}, []byte(`{"evento":"alert.created"}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if status != http.StatusAccepted {
+		t.Errorf("status = %d, want %d", status, http.StatusAccepted)
+	}
+	if got.Method != http.MethodPost {
+		t.Errorf("method = %s, want POST", got.Method)
+	}
+	if ct := got.Header.Get("Content-Type"); ct != "application/json" {
+		t.Errorf("Content-Type = %q, want application/json", ct)
+	}
+	if ua := got.Header.Get("User-Agent"); ua != userAgent {
+		t.Errorf("User-Agent = %q, want %q", ua, userAgent)
+	}
+	if event := got.Header.Get("X-StopFire-Event"); event != "alert.created" {
+		t.Errorf("X-StopFire-Event = %q, want alert.created", event)
+	}
+	if string(body) != `{"evento":"alert.created"}` {
+		t.Errorf("body = %s", body)
+	}
+}
+
+func TestSendDoesNotFollowRedirects(t *testing.T) {
+	followed := false
+	target := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		followed = true
+	}))
+	defer target.Close()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Redirect(w, r, target.URL, http.StatusTemporaryRedirect)
+	}))
+	defer server.Close()
+
+	status, err := NewClient(time.Second, true).Send(context.Background(), server.URL, nil, []byte(`{}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if status != http.StatusTemporaryRedirect {
+		t.Errorf("status = %d, want %d", status, http.StatusTemporaryRedirect)
+	}
+	if followed {
+		t.Error("the redirect was followed")
+	}
+}
+
+func TestSendRefusesPrivateAddresses(t *testing.T) {
+	reached := false
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		reached = true
+	}))
+	defer server.Close()
+
+	_, err := NewClient(time.Second, false).Send(context.Background(), server.URL, nil, []byte(`{}`))
+	if !errors.Is(err, ErrPrivateAddress) {
+		t.Fatalf("Send() error = %v, want ErrPrivateAddress", err)
+	}
+	if reached {
+		t.Error("the loopback server was reached")
+	}
+}
+
+func TestIsPublic(t *testing.T) {
+	tests := []struct {
+		ip   string
+		want bool
+	}{
+		{"8.8.8.8", true},
+		{"2606:4700:4700::1111", true},
+		{"127.0.0.1", false},
+		{"::1", false},
+		{"10.1.2.3", false},
+		{"172.16.0.1", false},
+		{"192.168.1.10", false},
+		{"169.254.169.254", false},
+		{"fe80::1", false},
+		{"fd00::1", false},
+		{"0.0.0.0", false},
+		{"224.0.0.1", false},
+		{"::ffff:127.0.0.1", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.ip, func(t *testing.T) {
+			if got := isPublic(net.ParseIP(tt.ip)); got != tt.want {
+				t.Errorf("isPublic(%s) = %v, want %v", tt.ip, got, tt.want)
+			}
+		})
+	}
+}